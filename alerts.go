@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/alert"
+	"github.com/benstraw/whoop-garden/internal/client"
+	"github.com/benstraw/whoop-garden/internal/notify"
+	"github.com/benstraw/whoop-garden/internal/period"
+	"github.com/benstraw/whoop-garden/internal/store"
+)
+
+// alertHistoryDays is how much history alert rules are evaluated over. It
+// covers the 28-day ACWR window plus slack for consecutive-day rules.
+const alertHistoryDays = 35
+
+func runAlerts(args []string) {
+	fs := flag.NewFlagSet("alerts", flag.ExitOnError)
+	addGlobalFlags(fs)
+	_ = fs.Parse(args)
+
+	if len(cfg.Alerts.Rules) == 0 {
+		fmt.Println("No alert rules configured (see \"alerts\" in config.json).")
+		return
+	}
+
+	c, err := getClient()
+	if err != nil {
+		fatal(err)
+	}
+	st, err := openStore()
+	if err != nil {
+		fatal(err)
+	}
+
+	n, err := checkAlerts(c, st)
+	if err != nil {
+		fatal(err)
+	}
+	if n == 0 {
+		fmt.Println("No new alerts.")
+	}
+}
+
+// alertAfterWrite checks the alert rules once new daily notes are written,
+// as catch-up, sync, and daemon refreshes do. Failures are only logged.
+func alertAfterWrite(c *client.Client) {
+	if len(cfg.Alerts.Rules) == 0 || opts.dryRun {
+		return
+	}
+	st, err := openStore()
+	if err == nil {
+		_, err = checkAlerts(c, st)
+	}
+	if err != nil {
+		slog.Warn("alert check failed", "err", err)
+	}
+}
+
+// checkAlerts evaluates the configured rules over recent history, delivers
+// any that fire, and persists rule state. It returns the number that fired.
+func checkAlerts(c *client.Client, st *store.Store) (int, error) {
+	if len(cfg.Alerts.Rules) == 0 {
+		return 0, nil
+	}
+	today := time.Now()
+	days := fetchRange(c, st, period.Range(today.AddDate(0, 0, -(alertHistoryDays-1)), today))
+
+	statePath := filepath.Join(cacheDir(), "alert-state.json")
+	state, err := alert.LoadState(statePath)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	fired := alert.Evaluate(cfg.Alerts.Rules, days, state, now)
+	// Offline, or on demo data, alerts are only printed. Their state is not
+	// saved, so the webhook still gets them on the next real run.
+	held := cfg.Alerts.WebhookURL != "" && (offline || opts.demo)
+	for _, a := range fired {
+		if cfg.Alerts.WebhookURL == "" || held {
+			fmt.Printf("%s: %s\n", a.Title(), a.Message())
+			state.Delivered(a, now)
+			continue
+		}
+		// An alert that could not be delivered is left out of the state,
+		// so the next check sends it again.
+		if err := notify.NewWebhook(cfg.Alerts.WebhookURL).Notify(a.Title(), a.Message()); err != nil {
+			slog.Warn("could not deliver alert", "rule", a.Rule.Name, "err", err)
+			continue
+		}
+		state.Delivered(a, now)
+	}
+
+	if held {
+		return len(fired), nil
+	}
+	if err := alert.SaveState(statePath, state); err != nil {
+		return len(fired), fmt.Errorf("save alert state: %w", err)
+	}
+	return len(fired), nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/alert"
+	"github.com/benstraw/whoop-garden/internal/client"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/models"
+	"github.com/benstraw/whoop-garden/internal/store"
+)
+
+func TestCheckAlerts_FailedDelivery(t *testing.T) {
+	useMemoryNotes(t)
+	status, hits := http.StatusInternalServerError, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+	cfg.Alerts.WebhookURL = srv.URL
+	cfg.Alerts.Rules = []alert.Rule{{Name: "low", Metric: "recovery", Op: "below", Threshold: 34}}
+
+	st, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	st.Offline = true
+	today := time.Now().UTC()
+	for i := 0; i < alertHistoryDays; i++ {
+		d := today.AddDate(0, 0, -i)
+		st.Save(fetch.DayData{
+			Date:     time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC),
+			Recovery: &models.Recovery{ScoreState: "SCORED", Score: models.RecoveryScore{RecoveryScore: 20}},
+		})
+	}
+	c := client.NewClient("tok")
+
+	for run, want := range []int{1, 2} {
+		if _, err := checkAlerts(c, st); err != nil {
+			t.Fatal(err)
+		}
+		if hits != want {
+			t.Errorf("run %d with a failing webhook: %d deliveries, want %d", run+1, hits, want)
+		}
+	}
+	status = http.StatusOK
+	for run, want := range []int{3, 3} {
+		if _, err := checkAlerts(c, st); err != nil {
+			t.Fatal(err)
+		}
+		if hits != want {
+			t.Errorf("run %d once the webhook works: %d deliveries, want %d", run+3, hits, want)
+		}
+	}
+}
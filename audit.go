@@ -0,0 +1,142 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/analytics"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+)
+
+// auditRow is a run of consecutive days with the same problems.
+type auditRow struct {
+	from, to time.Time
+	problems []string
+	fixable  bool // refetching or rewriting the notes can help
+}
+
+// runAudit lists days in a range whose data or notes are incomplete, read
+// from the local store and the output directory without API calls.
+func runAudit(args []string) {
+	fs := flag.NewFlagSet("audit", flag.ExitOnError)
+	addGlobalFlags(fs)
+	days := fs.Int("days", 30, "number of days to check, ending yesterday")
+	from := fs.String("from", "", "first day to check (YYYY-MM-DD)")
+	to := fs.String("to", "", "last day to check (YYYY-MM-DD, default: yesterday)")
+	_ = fs.Parse(args)
+
+	p, err := resolveRange(*days, *from, *to)
+	if err != nil {
+		fatal(err)
+	}
+	st, err := openStore()
+	if err != nil {
+		fatal(err)
+	}
+	dir := outputDir()
+
+	var rows []auditRow
+	incomplete := 0
+	for d := p.Start; d.Before(p.End); d = d.AddDate(0, 0, 1) {
+		day, _, stored, err := st.Load(d)
+		if err != nil {
+			fatal(err)
+		}
+		date := d.Format("2006-01-02")
+		noted, err := noteExists(notePath(dir, "daily", date))
+		if err != nil {
+			fatal(err)
+		}
+		problems, fixable := auditDay(day, stored, noted)
+		if len(problems) == 0 {
+			continue
+		}
+		incomplete++
+		if n := len(rows); n > 0 && rows[n-1].to.AddDate(0, 0, 1).Equal(d) && slices.Equal(rows[n-1].problems, problems) {
+			rows[n-1].to = d
+			continue
+		}
+		rows = append(rows, auditRow{from: d, to: d, problems: problems, fixable: fixable})
+	}
+
+	if len(rows) == 0 {
+		fmt.Printf("All %d days from %s to %s are complete.\n", p.Days(), p.Start.Format("2006-01-02"), p.Last().Format("2006-01-02"))
+		return
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "Dates\tProblems\tSuggested fix")
+	for _, r := range rows {
+		dates, fix := r.from.Format("2006-01-02"), "—"
+		if !r.to.Equal(r.from) {
+			dates += " → " + r.to.Format("2006-01-02")
+		}
+		if r.fixable {
+			fix = "whoop-garden daily --date " + r.from.Format("2006-01-02")
+			if !r.to.Equal(r.from) {
+				fix = fmt.Sprintf("whoop-garden fetch-all --from %s --to %s", r.from.Format("2006-01-02"), r.to.Format("2006-01-02"))
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", dates, strings.Join(r.problems, ", "), fix)
+	}
+	tw.Flush()
+	fmt.Printf("\n%d of %d days incomplete.\n", incomplete, p.Days())
+}
+
+// auditDay lists what is missing from a day: stored reports whether the
+// store has it and noted whether its daily note exists. fixable is true
+// when fetching the day again or rewriting its note can help; days without
+// a cycle or with unscorable records stay as they are.
+func auditDay(d fetch.DayData, stored, noted bool) (problems []string, fixable bool) {
+	if !stored {
+		problems = append(problems, "not fetched")
+		fixable = true
+	} else {
+		if d.Cycle == nil {
+			problems = append(problems, "no cycle")
+		}
+		var pending, unscorable []string
+		state := func(name, s string) {
+			switch s {
+			case "PENDING_SCORE":
+				pending = append(pending, name)
+			case "UNSCORABLE":
+				unscorable = append(unscorable, name)
+			}
+		}
+		if d.Cycle != nil {
+			state("cycle", d.Cycle.ScoreState)
+		}
+		if d.Recovery != nil {
+			state("recovery", d.Recovery.ScoreState)
+		}
+		for _, s := range d.Sleeps {
+			state("sleep", s.ScoreState)
+		}
+		for _, w := range d.Workouts {
+			state("workout", w.ScoreState)
+		}
+		slices.Sort(pending)
+		slices.Sort(unscorable)
+		if pending = slices.Compact(pending); len(pending) > 0 {
+			problems = append(problems, "pending "+strings.Join(pending, "/"))
+			fixable = true
+		}
+		if unscorable = slices.Compact(unscorable); len(unscorable) > 0 {
+			problems = append(problems, "unscorable "+strings.Join(unscorable, "/"))
+		}
+		if d.Cycle != nil && analytics.PrimarySleep(d.Sleeps) == nil {
+			problems = append(problems, "no sleep")
+		}
+	}
+	// Notes are never written for days without a cycle.
+	if !noted && (!stored || d.Cycle != nil) {
+		problems = append(problems, "no note")
+		fixable = true
+	}
+	return problems, fixable
+}
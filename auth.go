@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/auth"
+	"github.com/benstraw/whoop-garden/internal/client"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+)
+
+// Exit codes of auth status.
+const (
+	authOK        = 0
+	authNeeded    = 1 // run 'whoop-garden auth' again
+	authUnchecked = 2 // WHOOP could not be reached to confirm the token
+)
+
+func runAuth(args []string) {
+	if len(args) == 0 {
+		if err := auth.StartAuthFlow(); err != nil {
+			fatalf("auth failed: %w", err)
+		}
+		return
+	}
+	switch args[0] {
+	case "status":
+		runAuthStatus(args[1:])
+	case "logout":
+		runAuthLogout(args[1:])
+	default:
+		fatalf("unknown auth command %q (want status or logout)", args[0])
+	}
+}
+
+// runAuthStatus prints the state of the stored tokens and exits with
+// authNeeded when they can no longer be used, so health checks can script
+// it.
+func runAuthStatus(args []string) {
+	fs := flag.NewFlagSet("auth status", flag.ExitOnError)
+	addGlobalFlags(fs)
+	_ = fs.Parse(args)
+
+	tokens, err := auth.LoadTokens()
+	if err != nil {
+		fmt.Printf("Tokens:  none in %s\n", auth.TokenPath())
+		authStatusExit(authNeeded, "run 'whoop-garden auth'")
+	}
+	fmt.Printf("Tokens:  %s\n", auth.TokenPath())
+
+	// Refreshing proves the refresh token still works, and gives the
+	// profile request below a live access token.
+	token, err := auth.RefreshIfNeeded()
+	if err != nil {
+		fmt.Printf("Refresh: failed: %v\n", err)
+		authStatusExit(authNeeded, "run 'whoop-garden auth' to get a new refresh token")
+	}
+	if tokens, err = auth.LoadTokens(); err != nil {
+		fatal(err)
+	}
+
+	granted := strings.Fields(tokens.Scope)
+	var missing []string
+	for _, s := range auth.Scopes {
+		if !slices.Contains(granted, s) {
+			missing = append(missing, s)
+		}
+	}
+	fmt.Printf("Scopes:  %s\n", tokens.Scope)
+	fmt.Printf("Expires: %s (%s)\n", tokens.ExpiresAt.Local().Format("2006-01-02 15:04"), expiresIn(time.Until(tokens.ExpiresAt)))
+
+	c := client.NewClient(token)
+	c.SetTrace(opts.traceHTTP)
+	profile, err := fetch.GetUserProfile(c)
+	switch {
+	case errors.Is(err, client.ErrUnauthorized):
+		fmt.Println("User:    token rejected by WHOOP")
+		authStatusExit(authNeeded, "run 'whoop-garden auth'")
+	case err != nil:
+		fmt.Printf("User:    could not check: %v\n", err)
+		authStatusExit(authUnchecked, "check your network connection and try again")
+	}
+	fmt.Printf("User:    %s %s <%s> (id %d)\n", profile.FirstName, profile.LastName, profile.Email, profile.UserID)
+
+	if len(missing) > 0 {
+		fmt.Printf("Missing: %s\n", strings.Join(missing, " "))
+		authStatusExit(authNeeded, "run 'whoop-garden auth' and approve every requested permission")
+	}
+	authStatusExit(authOK, "")
+}
+
+// runAuthLogout revokes the stored tokens at WHOOP and deletes them. The
+// local tokens are deleted even when revoking fails, which exits 1.
+func runAuthLogout(args []string) {
+	fs := flag.NewFlagSet("auth logout", flag.ExitOnError)
+	addGlobalFlags(fs)
+	local := fs.Bool("local", false, "only delete the local tokens, without revoking them at WHOOP")
+	_ = fs.Parse(args)
+
+	if _, err := auth.LoadTokens(); err != nil {
+		fmt.Printf("Not logged in: no tokens in %s.\n", auth.TokenPath())
+		return
+	}
+	revoke := !*local && !offline
+	if opts.dryRun {
+		if revoke {
+			fmt.Println("Would revoke whoop-garden's access at WHOOP.")
+		}
+		fmt.Printf("Would delete %s.\n", auth.TokenPath())
+		return
+	}
+
+	var revokeErr error
+	if revoke {
+		token, err := auth.RefreshIfNeeded()
+		if err == nil {
+			err = auth.Revoke(token)
+		}
+		if revokeErr = err; err == nil {
+			fmt.Println("Revoked whoop-garden's access at WHOOP.")
+		}
+	}
+	if err := auth.DeleteTokens(); err != nil {
+		fatalf("could not delete %s: %w", auth.TokenPath(), err)
+	}
+	fmt.Printf("Deleted %s.\n", auth.TokenPath())
+	if revokeErr != nil {
+		fatalf("could not revoke the tokens at WHOOP, so they stay valid until they expire: %w", revokeErr)
+	}
+}
+
+// expiresIn describes how long until a token expires.
+func expiresIn(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "expired"
+	case d < time.Hour:
+		return fmt.Sprintf("in %d min", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("in %dh %02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("in %d days", int(d.Hours()/24))
+}
+
+// authStatusExit prints the verdict of auth status and exits with code.
+func authStatusExit(code int, fix string) {
+	switch code {
+	case authOK:
+		fmt.Println("Status:  OK")
+	case authNeeded:
+		fmt.Printf("Status:  re-authentication needed; %s\n", fix)
+	default:
+		fmt.Printf("Status:  unknown; %s\n", fix)
+	}
+	os.Exit(code)
+}
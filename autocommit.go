@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// pendingCommit holds the notes written since the last git auto-commit.
+var pendingCommit struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+// queueCommit records that the note at path was written, for commitNotes.
+func queueCommit(path string) {
+	if !cfg.Git.AutoCommit || cfg.Storage.Remote() || opts.demo {
+		return
+	}
+	pendingCommit.mu.Lock()
+	pendingCommit.paths = append(pendingCommit.paths, path)
+	pendingCommit.mu.Unlock()
+}
+
+// commitNotes stages and commits the notes written since the last call in
+// the git repository containing them, as "whoop-garden: 2026-02-10 daily".
+// Only those notes are committed; anything else staged is left alone. It
+// does nothing unless git.auto_commit is set, and only warns when the
+// notes are not in a repository or git fails.
+func commitNotes() {
+	pendingCommit.mu.Lock()
+	paths := pendingCommit.paths
+	pendingCommit.paths = nil
+	pendingCommit.mu.Unlock()
+	if len(paths) == 0 {
+		return
+	}
+
+	for i, p := range paths {
+		if abs, err := filepath.Abs(p); err == nil {
+			paths[i] = abs
+		}
+	}
+	top, err := git(filepath.Dir(paths[0]), "rev-parse", "--show-toplevel")
+	if err != nil {
+		slog.Warn("git auto-commit skipped: notes are not in a git repository", "dir", filepath.Dir(paths[0]), "err", err)
+		return
+	}
+	top = strings.TrimSpace(top)
+	if _, err := git(top, append([]string{"add", "--"}, paths...)...); err != nil {
+		slog.Warn("git auto-commit failed", "err", err)
+		return
+	}
+	// diff --quiet exits 1 when there are staged changes to commit.
+	_, err = git(top, append([]string{"diff", "--cached", "--quiet", "--"}, paths...)...)
+	var exit *exec.ExitError
+	if err == nil {
+		slog.Debug("git auto-commit: notes unchanged", "notes", len(paths))
+		return
+	}
+	if !errors.As(err, &exit) || exit.ExitCode() != 1 {
+		slog.Warn("git auto-commit failed", "err", err)
+		return
+	}
+	msg := commitMessage(paths)
+	if _, err := git(top, append([]string{"commit", "--quiet", "-m", msg, "--"}, paths...)...); err != nil {
+		slog.Warn("git auto-commit failed", "err", err)
+		return
+	}
+	infof("Committed: %s\n", msg)
+}
+
+// commitMessage describes the notes at paths, e.g. "whoop-garden:
+// 2026-02-10 daily" or "whoop-garden: 7 notes, 2026-02-04 daily to
+// 2026-02-10 daily".
+func commitMessage(paths []string) string {
+	if len(paths) == 1 {
+		return "whoop-garden: " + noteLabel(paths[0])
+	}
+	return fmt.Sprintf("whoop-garden: %d notes, %s to %s", len(paths), noteLabel(paths[0]), noteLabel(paths[len(paths)-1]))
+}
+
+// noteLabel turns "daily-2026-02-10.md" into "2026-02-10 daily".
+func noteLabel(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), ".md")
+	if kind, rest, ok := strings.Cut(name, "-"); ok {
+		return rest + " " + kind
+	}
+	return name
+}
+
+// git runs git in dir and returns its stdout. Errors include stderr.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil && stderr.Len() > 0 {
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			return string(out), fmt.Errorf("git %s: %s: %w", args[0], strings.TrimSpace(stderr.String()), err)
+		}
+	}
+	return string(out), err
+}
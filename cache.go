@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// defaultHTTPCacheTTL is how long API responses are reused when config
+// does not set http_cache_ttl.
+const defaultHTTPCacheTTL = time.Hour
+
+// httpCacheDir is where client.Cache keeps API responses.
+func httpCacheDir() string { return filepath.Join(cacheDir(), "http") }
+
+// httpCacheTTL returns the configured response cache TTL; zero means the
+// cache is off. config.LoadFile has already validated it.
+func httpCacheTTL() time.Duration {
+	if cfg.HTTPCacheTTL == "" {
+		return defaultHTTPCacheTTL
+	}
+	d, _ := time.ParseDuration(cfg.HTTPCacheTTL)
+	return d
+}
+
+func runCacheCmd(args []string) {
+	if len(args) == 0 {
+		fatal(errors.New("usage: whoop-garden cache info|clear"))
+	}
+	switch args[0] {
+	case "info":
+		runCacheInfo(args[1:])
+	case "clear":
+		runCacheClear(args[1:])
+	default:
+		fatalf("unknown cache command %q (want info or clear)", args[0])
+	}
+}
+
+// runCacheInfo prints where the response cache is and how much it holds.
+func runCacheInfo(args []string) {
+	fs := flag.NewFlagSet("cache info", flag.ExitOnError)
+	addGlobalFlags(fs)
+	_ = fs.Parse(args)
+
+	n, size, err := dirUsage(httpCacheDir())
+	if err != nil {
+		fatal(err)
+	}
+	ttl := cfg.HTTPCacheTTL
+	switch {
+	case httpCacheTTL() == 0:
+		ttl = "off"
+	case ttl == "":
+		ttl = "1h"
+	}
+	fmt.Printf("Response cache: %s\n", httpCacheDir())
+	fmt.Printf("  %d response(s), %.1f MB, TTL %s\n", n, float64(size)/1e6, ttl)
+}
+
+// runCacheClear deletes every cached API response. Stored days and other
+// local state are kept; purge --cache removes those too.
+func runCacheClear(args []string) {
+	fs := flag.NewFlagSet("cache clear", flag.ExitOnError)
+	addGlobalFlags(fs)
+	_ = fs.Parse(args)
+
+	n, _, err := dirUsage(httpCacheDir())
+	if err != nil {
+		fatal(err)
+	}
+	if opts.dryRun {
+		fmt.Printf("Would delete %d cached response(s) in %s\n", n, httpCacheDir())
+		return
+	}
+	if err := os.RemoveAll(httpCacheDir()); err != nil {
+		fatal(err)
+	}
+	infof("Deleted %d cached response(s).\n", n)
+}
+
+// dirUsage counts the files under dir and their total size. A missing dir
+// is empty.
+func dirUsage(dir string) (files int, size int64, err error) {
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files++
+		size += info.Size()
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, 0, nil
+	}
+	return files, size, err
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/benstraw/whoop-garden/internal/links"
+)
+
+func runCheckLinks(args []string) {
+	fs := flag.NewFlagSet("check-links", flag.ExitOnError)
+	addGlobalFlags(fs)
+	fix := fs.Bool("fix", false, "rewrite broken prev/next links to the nearest existing note")
+	_ = fs.Parse(args)
+
+	if cfg.Storage.Remote() {
+		fatalf("check-links scans a local directory and does not support %s storage", cfg.Storage)
+	}
+
+	dir := outputDir()
+	broken, err := links.Check(dir)
+	if err != nil {
+		fatalf("check error: %w", err)
+	}
+	if len(broken) == 0 {
+		fmt.Println("All links between generated notes resolve.")
+		return
+	}
+
+	for _, b := range broken {
+		rel, err := filepath.Rel(dir, b.Note.Path)
+		if err != nil {
+			rel = b.Note.Path
+		}
+		msg := fmt.Sprintf("%s:%d: broken link [[%s]]", rel, b.Link.Line, b.Link.Target)
+		if b.Suggest != "" {
+			msg += fmt.Sprintf(" → nearest %s-%s", b.Kind, b.Suggest)
+		}
+		fmt.Println(msg)
+	}
+
+	if !*fix || opts.dryRun {
+		fmt.Printf("\n%d broken link(s). Run with --fix to repair navigation links.\n", len(broken))
+		os.Exit(1)
+	}
+
+	n, err := links.Fix(broken)
+	if err != nil {
+		fatalf("fix error: %w", err)
+	}
+	fmt.Printf("\nFixed %d of %d broken link(s).\n", n, len(broken))
+	if n < len(broken) {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// fetchCheckpoint records a fetch-all run's progress so --resume can pick
+// it up after a crash, a network outage, or an exhausted call budget.
+type fetchCheckpoint struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// LastCompleted is the last day of the range the run got through,
+	// whether or not that day succeeded.
+	LastCompleted string `json:"last_completed,omitempty"`
+	// Failed lists days that could not be fetched, rendered, or written.
+	Failed []string `json:"failed,omitempty"`
+}
+
+func fetchCheckpointPath() string { return filepath.Join(cacheDir(), "fetch-all.json") }
+
+func loadFetchCheckpoint() (fetchCheckpoint, bool, error) {
+	var cp fetchCheckpoint
+	data, err := os.ReadFile(fetchCheckpointPath())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return cp, false, nil
+		}
+		return cp, false, fmt.Errorf("read checkpoint: %w", err)
+	}
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return cp, false, fmt.Errorf("parse checkpoint: %w", err)
+	}
+	return cp, true, nil
+}
+
+// dates returns the days left to fetch: failed days first, then the rest of
+// the range after LastCompleted.
+func (cp fetchCheckpoint) dates() ([]time.Time, error) {
+	var out []time.Time
+	for _, s := range cp.Failed {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid failed day %q in %s", s, fetchCheckpointPath())
+		}
+		out = append(out, d)
+	}
+	next := cp.From
+	if cp.LastCompleted != "" {
+		next = cp.LastCompleted
+	}
+	from, err := time.Parse("2006-01-02", next)
+	if err != nil {
+		return nil, fmt.Errorf("invalid day %q in %s", next, fetchCheckpointPath())
+	}
+	if cp.LastCompleted != "" {
+		from = from.AddDate(0, 0, 1)
+	}
+	to, err := time.Parse("2006-01-02", cp.To)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to %q in %s", cp.To, fetchCheckpointPath())
+	}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+// done records the outcome of day and saves the checkpoint.
+func (cp *fetchCheckpoint) done(d time.Time, ok bool) {
+	day := d.Format("2006-01-02")
+	cp.Failed = slices.DeleteFunc(cp.Failed, func(s string) bool { return s == day })
+	if !ok {
+		cp.Failed = append(cp.Failed, day)
+	}
+	if day > cp.LastCompleted {
+		cp.LastCompleted = day
+	}
+	cp.save()
+}
+
+// save writes the checkpoint, or removes it once every day has succeeded.
+func (cp fetchCheckpoint) save() {
+	if opts.dryRun || opts.stdout {
+		return
+	}
+	var err error
+	if cp.LastCompleted == cp.To && len(cp.Failed) == 0 {
+		if err = os.Remove(fetchCheckpointPath()); errors.Is(err, fs.ErrNotExist) {
+			err = nil
+		}
+	} else if err = os.MkdirAll(cacheDir(), 0700); err == nil {
+		var data []byte
+		if data, err = json.MarshalIndent(cp, "", "  "); err == nil {
+			err = os.WriteFile(fetchCheckpointPath(), data, 0600)
+		}
+	}
+	if err != nil {
+		slog.Warn("could not save checkpoint", "err", err)
+	}
+}
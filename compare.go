@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/benstraw/whoop-garden/internal/period"
+	"github.com/benstraw/whoop-garden/internal/render"
+)
+
+func runCompare(args []string) {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	addGlobalFlags(fs)
+	aSpec := fs.String("a", "", "first period: YYYY-MM-DD, YYYY-Www, YYYY-MM, YYYY, or FROM:TO")
+	bSpec := fs.String("b", "", "second period (same formats as --a)")
+	_ = fs.Parse(args)
+
+	if *aSpec == "" || *bSpec == "" {
+		fatal(errors.New("compare requires both --a and --b"))
+	}
+	pa, err := period.Parse(*aSpec)
+	if err != nil {
+		fatal(err)
+	}
+	pb, err := period.Parse(*bSpec)
+	if err != nil {
+		fatal(err)
+	}
+
+	c, err := getClient()
+	if err != nil {
+		fatal(err)
+	}
+	st, err := openStore()
+	if err != nil {
+		fatal(err)
+	}
+
+	sides := make([]render.CompareSide, 0, 2)
+	for _, p := range []period.Period{pa, pb} {
+		infof("Loading %s (%d days)...\n", p.Label, p.Days())
+		days := fetchRange(c, st, p)
+		sides = append(sides, render.CompareSide{
+			Label: p.Label,
+			Start: p.Start.Format("2006-01-02"),
+			End:   p.Last().Format("2006-01-02"),
+			Stats: render.BuildWeekStats(days),
+		})
+	}
+
+	tmplPath := templatePath("compare.md.tmpl")
+	content, err := render.RenderCompare(sides[0], sides[1], tmplPath)
+	if err != nil {
+		fatalf("render error: %w", err)
+	}
+
+	dir, err := ensureOutputDir()
+	if err != nil {
+		fatal(err)
+	}
+
+	name := fmt.Sprintf("compare-%s-vs-%s.md", specFileName(*aSpec), specFileName(*bSpec))
+	outPath := filepath.Join(dir, name)
+	if err := writeNote(outPath, content); err != nil {
+		fatalf("write error: %w", err)
+	}
+}
+
+// specFileName makes a period spec safe for use in a filename.
+func specFileName(spec string) string {
+	return strings.ReplaceAll(strings.TrimSpace(spec), ":", "_")
+}
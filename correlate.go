@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/analytics"
+	"github.com/benstraw/whoop-garden/internal/render"
+)
+
+// runCorrelate writes a report of how sleep, bedtime, strain, and recovery
+// metrics move together over the last N days.
+func runCorrelate(args []string) {
+	fs := flag.NewFlagSet("correlate", flag.ExitOnError)
+	addGlobalFlags(fs)
+	days := fs.Int("days", 90, "number of days to analyse, ending yesterday")
+	_ = fs.Parse(args)
+
+	if *days < 7 {
+		fatal(errors.New("--days must be at least 7"))
+	}
+	p, err := resolveRange(*days, "", "")
+	if err != nil {
+		fatal(err)
+	}
+
+	c, err := getClient()
+	if err != nil {
+		fatal(err)
+	}
+	st, err := openStore()
+	if err != nil {
+		fatal(err)
+	}
+
+	infof("Loading %d days...\n", p.Days())
+	data := fetchRange(c, st, p)
+
+	rep := render.CorrelationReport{
+		GeneratedDate: time.Now().Format("2006-01-02"),
+		Start:         p.Start.Format("2006-01-02"),
+		End:           p.Last().Format("2006-01-02"),
+		Days:          len(data),
+		Correlations:  analytics.Correlations(data),
+	}
+	content, err := render.RenderCorrelations(rep, templatePath("correlate.md.tmpl"))
+	if err != nil {
+		fatalf("render error: %w", err)
+	}
+
+	dir, err := ensureOutputDir()
+	if err != nil {
+		fatal(err)
+	}
+	outPath := filepath.Join(dir, fmt.Sprintf("correlations-%s.md", rep.End))
+	if err := writeNote(outPath, content); err != nil {
+		fatalf("write error: %w", err)
+	}
+}
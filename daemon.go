@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/rpc"
+)
+
+// runDaemon keeps today's daily note fresh and serves the JSON-RPC endpoint
+// used by the Obsidian companion plugin. See docs/companion-protocol.md.
+func runDaemon(args []string) {
+	fs := flag.NewFlagSet("daemon", flag.ExitOnError)
+	addGlobalFlags(fs)
+	addr := fs.String("addr", "127.0.0.1:8766", "address for the JSON-RPC endpoint")
+	interval := fs.Duration("interval", time.Hour, "refresh today's note this often (0: only on request)")
+	_ = fs.Parse(args)
+
+	token, tokenPath, err := daemonToken()
+	if err != nil {
+		fatal(err)
+	}
+	d := &daemon{started: time.Now(), interval: *interval}
+	srv := &rpc.Server{Token: token}
+	srv.Handle("status", func(json.RawMessage) (any, error) { return d.status(), nil })
+	srv.Handle("refresh", func(params json.RawMessage) (any, error) {
+		var p struct {
+			Date string `json:"date"`
+		}
+		if err := rpc.DecodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		date, err := parseDate(p.Date)
+		if err != nil {
+			return nil, &rpc.Error{Code: rpc.InvalidParams, Message: err.Error()}
+		}
+		return d.refresh(date)
+	})
+
+	activeMetrics = &daemonMetrics{token: token}
+	mux := http.NewServeMux()
+	mux.Handle("/rpc", srv)
+	mux.Handle("/metrics", activeMetrics)
+	server := &http.Server{Addr: *addr, Handler: localOnly(*addr, mux)}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go d.loop(ctx)
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+
+	infof("Daemon listening on http://%s/rpc, metrics on /metrics (Ctrl-C to stop)\n", *addr)
+	if tokenPath != "" {
+		infof("Bearer token for /rpc and /metrics is in %s\n", tokenPath)
+	}
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fatal(err)
+	}
+}
+
+// daemonToken returns the bearer token /rpc and /metrics require: daemon.token from
+// config, or else a random token generated on first start and kept in the
+// cache directory, whose path is returned too. Reaching localhost is then
+// not enough to trigger refreshes and writes.
+func daemonToken() (token, path string, err error) {
+	if cfg.Daemon.Token != "" {
+		return cfg.Daemon.Token, "", nil
+	}
+	path = filepath.Join(cacheDir(), "daemon-token")
+	if data, err := os.ReadFile(path); err == nil && len(bytes.TrimSpace(data)) > 0 {
+		return string(bytes.TrimSpace(data)), path, nil
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token = hex.EncodeToString(buf)
+	if err := os.MkdirAll(cacheDir(), 0700); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+		return "", "", fmt.Errorf("save daemon token: %w", err)
+	}
+	return token, path, nil
+}
+
+// daemon tracks refresh state for the status method. Refreshes are
+// serialized so a plugin click cannot race the periodic refresh.
+type daemon struct {
+	started  time.Time
+	interval time.Duration
+
+	refreshMu sync.Mutex // held for the duration of a refresh
+
+	mu   sync.Mutex // guards the fields below
+	last *refreshResult
+	next time.Time
+	busy bool
+}
+
+// refreshResult describes the most recent refresh, successful or not.
+type refreshResult struct {
+	Date  string    `json:"date"`
+	Path  string    `json:"path,omitempty"`
+	At    time.Time `json:"at"`
+	Error string    `json:"error,omitempty"`
+}
+
+// daemonStatus is the result of the status method.
+type daemonStatus struct {
+	Version     string         `json:"version"`
+	StartedAt   time.Time      `json:"started_at"`
+	OutputDir   string         `json:"output_dir"`
+	Refreshing  bool           `json:"refreshing"`
+	LastRefresh *refreshResult `json:"last_refresh"`
+	NextRefresh *time.Time     `json:"next_refresh"`
+}
+
+func (d *daemon) status() daemonStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	s := daemonStatus{
+		Version:     version,
+		StartedAt:   d.started,
+		OutputDir:   outputDir(),
+		Refreshing:  d.busy,
+		LastRefresh: d.last,
+	}
+	if !d.next.IsZero() {
+		next := d.next
+		s.NextRefresh = &next
+	}
+	return s
+}
+
+// refresh regenerates the daily note for date.
+func (d *daemon) refresh(date time.Time) (*refreshResult, error) {
+	d.refreshMu.Lock()
+	defer d.refreshMu.Unlock()
+	d.setBusy(true)
+	defer d.setBusy(false)
+
+	res := &refreshResult{Date: date.Format("2006-01-02")}
+	start := time.Now()
+	c, err := getClient()
+	if err == nil {
+		if res.Path, err = writeDaily(c, date); err == nil {
+			alertAfterWrite(c)
+		}
+		flushRecords()
+		flushIndex()
+		commitNotes()
+	}
+	res.At = time.Now()
+	activeMetrics.observeRefresh(c, err)
+	if err != nil {
+		res.Error = err.Error()
+		slog.Error("refresh failed", "date", res.Date, "err", err)
+	} else {
+		slog.Debug("refreshed", "date", res.Date, "path", res.Path, "calls", c.Calls(), "took", time.Since(start).Round(time.Millisecond))
+	}
+
+	d.mu.Lock()
+	d.last = res
+	d.mu.Unlock()
+	return res, err
+}
+
+func (d *daemon) setBusy(b bool) {
+	d.mu.Lock()
+	d.busy = b
+	d.mu.Unlock()
+}
+
+// loop refreshes today's note every interval until ctx is cancelled.
+func (d *daemon) loop(ctx context.Context) {
+	if d.interval <= 0 {
+		return
+	}
+	for {
+		d.refresh(time.Now())
+		d.mu.Lock()
+		d.next = time.Now().Add(d.interval)
+		d.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d.interval):
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/auth"
+	"github.com/benstraw/whoop-garden/internal/client"
+	"github.com/benstraw/whoop-garden/internal/config"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/render"
+	"github.com/benstraw/whoop-garden/internal/storage"
+)
+
+// whoopHost is probed for reachability and its Date header for clock skew.
+const whoopHost = "https://api.prod.whoop.com"
+
+// maxClockSkew is how far the local clock may drift from WHOOP's before
+// token expiry checks become unreliable.
+const maxClockSkew = 2 * time.Minute
+
+// checkStatus is the outcome of one doctor check.
+type checkStatus int
+
+const (
+	checkOK checkStatus = iota
+	checkWarn
+	checkFail
+)
+
+// checkResult is one line of doctor output. fix tells the user what to do
+// when the check did not pass.
+type checkResult struct {
+	name   string
+	status checkStatus
+	detail string
+	fix    string
+}
+
+// runDoctor checks the local setup and prints a fix for each problem found.
+// It exits 1 if any check failed.
+func runDoctor(args []string) {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	addGlobalFlags(fs)
+	_ = fs.Parse(args)
+
+	var results []checkResult
+	results = append(results, checkEnv()...)
+	results = append(results, checkConfig())
+	token, tokenResult := checkToken()
+	results = append(results, tokenResult)
+	results = append(results, checkAPI(token))
+	results = append(results, checkClock())
+	results = append(results, checkTemplates()...)
+	results = append(results, checkOutputDir())
+
+	failed := 0
+	for _, r := range results {
+		mark := "✓"
+		switch r.status {
+		case checkWarn:
+			mark = "!"
+		case checkFail:
+			mark = "✗"
+			failed++
+		}
+		fmt.Printf("%s %-20s %s\n", mark, r.name, r.detail)
+		if r.status != checkOK && r.fix != "" {
+			fmt.Printf("  %-20s → %s\n", "", r.fix)
+		}
+	}
+	if failed > 0 {
+		fmt.Printf("\n%d check(s) failed.\n", failed)
+		os.Exit(1)
+	}
+	fmt.Println("\nAll checks passed.")
+}
+
+// checkEnv reports whether .env exists and the OAuth client settings are set.
+func checkEnv() []checkResult {
+	env := checkResult{name: ".env", detail: "found"}
+	if _, err := os.Stat(".env"); err != nil {
+		env.status = checkWarn
+		env.detail = "not found in the current directory"
+		env.fix = "copy .env.example to .env, or export the WHOOP_* variables in your shell"
+	}
+
+	var missing []string
+	for _, key := range []string{"WHOOP_CLIENT_ID", "WHOOP_CLIENT_SECRET", "WHOOP_REDIRECT_URI"} {
+		if os.Getenv(key) == "" {
+			missing = append(missing, key)
+		}
+	}
+	vars := checkResult{name: "client credentials", detail: "WHOOP_CLIENT_ID, WHOOP_CLIENT_SECRET, WHOOP_REDIRECT_URI set"}
+	if len(missing) > 0 {
+		vars.status = checkFail
+		vars.detail = "missing " + strings.Join(missing, ", ")
+		vars.fix = "set them from your app at https://developer.whoop.com (redirect URI: http://localhost:3000/callback)"
+	}
+	return []checkResult{env, vars}
+}
+
+// checkConfig reports which config file is in use. A config that fails to
+// parse never gets here: main exits with the parse error first.
+func checkConfig() checkResult {
+	path := config.Path()
+	if _, err := os.Stat(path); err != nil {
+		return checkResult{name: "config", detail: path + " not found, using defaults"}
+	}
+	return checkResult{name: "config", detail: path + " loaded"}
+}
+
+// checkToken loads tokens.json, refreshing it if needed, and verifies the
+// granted scopes. It returns the access token for checkAPI.
+func checkToken() (string, checkResult) {
+	r := checkResult{name: "token"}
+	tokens, err := auth.LoadTokens()
+	if err != nil {
+		r.status, r.detail, r.fix = checkFail, err.Error(), "run 'whoop-garden auth'"
+		return "", r
+	}
+	token, err := auth.RefreshIfNeeded()
+	if err != nil {
+		r.status, r.detail = checkFail, err.Error()
+		r.fix = "run 'whoop-garden auth' to get a new refresh token"
+		return "", r
+	}
+	if tokens, err = auth.LoadTokens(); err != nil {
+		r.status, r.detail = checkFail, err.Error()
+		return "", r
+	}
+
+	granted := strings.Fields(tokens.Scope)
+	var missing []string
+	for _, s := range auth.Scopes {
+		if !slices.Contains(granted, s) {
+			missing = append(missing, s)
+		}
+	}
+	if len(missing) > 0 {
+		r.status = checkFail
+		r.detail = "missing scopes: " + strings.Join(missing, " ")
+		r.fix = "run 'whoop-garden auth' and approve every requested permission"
+		return token, r
+	}
+	r.detail = fmt.Sprintf("valid until %s", tokens.ExpiresAt.Local().Format("2006-01-02 15:04"))
+	return token, r
+}
+
+// checkAPI makes one authenticated request to confirm the API accepts the
+// token.
+func checkAPI(token string) checkResult {
+	r := checkResult{name: "WHOOP API"}
+	if token == "" {
+		r.status, r.detail = checkWarn, "skipped (no valid token)"
+		return r
+	}
+	profile, err := fetch.GetUserProfile(client.NewClient(token))
+	if err != nil {
+		r.status, r.detail = checkFail, err.Error()
+		r.fix = "check your network connection; if the error is 401, run 'whoop-garden auth'"
+		return r
+	}
+	r.detail = fmt.Sprintf("reachable, signed in as %s %s", profile.FirstName, profile.LastName)
+	return r
+}
+
+// checkClock compares the local clock with the Date header from WHOOP.
+func checkClock() checkResult {
+	r := checkResult{name: "clock"}
+	hc := &http.Client{Timeout: 10 * time.Second}
+	resp, err := hc.Head(whoopHost)
+	if err != nil {
+		r.status, r.detail = checkWarn, "could not reach "+whoopHost+": "+err.Error()
+		r.fix = "check your network connection or proxy settings"
+		return r
+	}
+	resp.Body.Close()
+	server, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		r.status, r.detail = checkWarn, "no Date header in WHOOP response"
+		return r
+	}
+	skew := time.Since(server).Round(time.Second)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > maxClockSkew {
+		r.status = checkFail
+		r.detail = fmt.Sprintf("local clock is off by %s", skew)
+		r.fix = "enable automatic time sync (NTP); token expiry checks depend on it"
+		return r
+	}
+	r.detail = fmt.Sprintf("within %s of WHOOP", maxClockSkew)
+	return r
+}
+
+// checkTemplates renders each file template with empty data, which catches
+// both syntax errors and references to fields that do not exist.
+func checkTemplates() []checkResult {
+	week := make([]fetch.DayData, 7)
+	for i := range week {
+		week[i].Date = time.Now().AddDate(0, 0, i)
+	}
+	empty := render.BuildWeekStats(week)
+	renders := []struct {
+		name   string
+		render func(path string) error
+	}{
+		{"daily.md.tmpl", func(p string) error {
+			_, err := render.RenderDaily(render.Day{DayData: fetch.DayData{Date: time.Now()}}, p)
+			return err
+		}},
+		{"weekly.md.tmpl", func(p string) error {
+			_, err := render.RenderWeeklyFromStats(empty, p)
+			return err
+		}},
+		{"monthly.md.tmpl", func(p string) error {
+			_, err := render.RenderMonthly(render.MonthlyData{Month: time.Now().Format("2006-01"), Start: time.Now(), Stats: empty}, p)
+			return err
+		}},
+		{"summary.txt.tmpl", func(p string) error {
+			_, err := render.RenderSummary(render.Day{DayData: fetch.DayData{Date: time.Now()}}, p)
+			return err
+		}},
+		{"compare.md.tmpl", func(p string) error {
+			_, err := render.RenderCompare(render.CompareSide{Stats: empty}, render.CompareSide{Stats: empty}, p)
+			return err
+		}},
+		{"correlate.md.tmpl", func(p string) error {
+			_, err := render.RenderCorrelations(render.CorrelationReport{}, p)
+			return err
+		}},
+		{"records.md.tmpl", func(p string) error {
+			_, err := render.RenderRecords(render.RecordsData{}, p)
+			return err
+		}},
+	}
+
+	var results []checkResult
+	for _, t := range renders {
+		path := templatePath(t.name)
+		r := checkResult{name: t.name, detail: path}
+		if err := t.render(path); err != nil {
+			r.status, r.detail = checkFail, err.Error()
+			r.fix = "fix the template, or unset WHOOP_TEMPLATES_DIR to use the bundled templates"
+			if errors.Is(err, fs.ErrNotExist) {
+				r.fix = "set WHOOP_TEMPLATES_DIR, or keep templates/ next to the binary or in the current directory"
+			}
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// checkOutputDir verifies notes can be written where they will go.
+func checkOutputDir() checkResult {
+	dir := outputDir()
+	r := checkResult{name: "output dir", detail: dir}
+	if cfg.Storage.Remote() {
+		targets := cfg.Storage.Targets
+		if len(targets) == 0 {
+			targets = []storage.Config{cfg.Storage}
+		}
+		for _, t := range targets {
+			s, err := storage.New(t, dir)
+			if err == nil {
+				_, err = s.Exists("doctor-probe.md")
+			}
+			if err != nil {
+				r.status, r.detail = checkFail, fmt.Sprintf("%s storage: %v", t, err)
+				r.fix = "check the storage URL and credentials in config.json"
+				return r
+			}
+		}
+		r.detail = fmt.Sprintf("%s storage reachable", cfg.Storage)
+		return r
+	}
+
+	if vault := os.Getenv("OBSIDIAN_VAULT_PATH"); vault != "" && opts.output == "" && cfg.OutputDir == "" {
+		if _, err := os.Stat(vault); errors.Is(err, fs.ErrNotExist) {
+			r.status, r.detail = checkFail, "OBSIDIAN_VAULT_PATH does not exist: "+vault
+			r.fix = "point OBSIDIAN_VAULT_PATH at your vault's root folder"
+			return r
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		r.status, r.detail = checkFail, err.Error()
+		r.fix = "create the directory or choose another with --output or output_dir"
+		return r
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		r.status, r.detail = checkFail, "not writable: "+err.Error()
+		r.fix = "fix the directory permissions or choose another with --output or output_dir"
+		return r
+	}
+	f.Close()
+	os.Remove(f.Name())
+	r.detail = filepath.Clean(dir) + " writable"
+	return r
+}
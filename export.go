@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/benstraw/whoop-garden/internal/export"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+)
+
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	addGlobalFlags(fs)
+	format := fs.String("format", "json", "export format: json, csv, or parquet")
+	records := fs.String("records", "days", "row level for csv and parquet: days or workouts")
+	days := fs.Int("days", 30, "number of days to export (ignored with --from)")
+	fromStr := fs.String("from", "", "first date to export, YYYY-MM-DD")
+	toStr := fs.String("to", "", "last date to export, YYYY-MM-DD (default: yesterday; requires --from)")
+	outFile := fs.String("file", "", "write to this file instead of stdout")
+	columns := fs.String("columns", "", "comma-separated columns for csv and parquet (default: all)")
+	_ = fs.Parse(args)
+
+	p, err := resolveRange(*days, *fromStr, *toStr)
+	if err != nil {
+		fatal(err)
+	}
+
+	var names []string
+	if *columns != "" {
+		names = strings.Split(*columns, ",")
+	}
+	var encode func(io.Writer, []fetch.DayData) error
+	switch {
+	case *format == "json":
+		if *records != "days" {
+			err = fmt.Errorf("--records %s is only supported for csv and parquet", *records)
+		}
+		encode = export.WriteJSON
+	case *records == "days":
+		encode, err = tableEncoder(*format, export.DayColumns, names, func(days []fetch.DayData) []fetch.DayData { return days })
+	case *records == "workouts":
+		encode, err = tableEncoder(*format, export.WorkoutColumns, names, export.WorkoutRows)
+	default:
+		err = fmt.Errorf("unknown --records %q: want days or workouts", *records)
+	}
+	if err != nil {
+		fatal(err)
+	}
+
+	c, err := getClient()
+	if err != nil {
+		fatal(err)
+	}
+	st, err := openStore()
+	if err != nil {
+		fatal(err)
+	}
+
+	slog.Info(fmt.Sprintf("Exporting %d days (%s)...", p.Days(), p.Label))
+	data := fetchRange(c, st, p)
+
+	var w io.Writer = os.Stdout
+	if *outFile != "" {
+		f, err := os.Create(*outFile)
+		if err != nil {
+			fatalf("export error: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	bw := bufio.NewWriter(w)
+	if err := encode(bw, data); err != nil {
+		fatalf("export error: %w", err)
+	}
+	if err := bw.Flush(); err != nil {
+		fatalf("export error: %w", err)
+	}
+	if *outFile != "" {
+		slog.Info("Written: " + *outFile)
+	}
+}
+
+// tableEncoder returns an encoder that writes the selected columns of
+// rows(days) in a tabular format.
+func tableEncoder[T any](format string, all []export.Column[T], names []string, rows func([]fetch.DayData) []T) (func(io.Writer, []fetch.DayData) error, error) {
+	cols, err := export.Select(all, names)
+	if err != nil {
+		return nil, err
+	}
+	var write func(io.Writer, []T, []export.Column[T]) error
+	switch format {
+	case "csv":
+		write = export.WriteCSV[T]
+	case "parquet":
+		write = export.WriteParquet[T]
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+	return func(w io.Writer, days []fetch.DayData) error {
+		return write(w, rows(days), cols)
+	}, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/links"
+	"github.com/benstraw/whoop-garden/internal/note"
+	"github.com/benstraw/whoop-garden/internal/period"
+)
+
+// addFrontmatter adds the configured frontmatter fields, tags, cssclasses,
+// and aliases to content when it is a generated note.
+func addFrontmatter(path, content string) string {
+	fm := cfg.Frontmatter
+	values := map[string]any{}
+	for k, v := range fm.Fields {
+		values[k] = v
+	}
+	if len(fm.CSSClasses) > 0 {
+		values["cssclasses"] = fm.CSSClasses
+	}
+	if aliases := noteAliases(path); len(aliases) > 0 {
+		values["aliases"] = aliases
+	}
+	if (len(values) == 0 && len(fm.Tags) == 0) || !note.Generated(content) {
+		return content
+	}
+	fields := map[string]string{}
+	for k, v := range values {
+		// JSON values are valid YAML: strings stay quoted, lists inline.
+		b, err := json.Marshal(v)
+		if err != nil {
+			continue
+		}
+		fields[k] = string(b)
+	}
+	return note.AddFrontmatter(content, fields, fm.Tags)
+}
+
+// noteAliases returns the aliases configured for the daily or weekly note
+// at path, or nil for other notes.
+func noteAliases(path string) []string {
+	var patterns []string
+	kind, key, ok := links.NoteOf(path)
+	switch {
+	case !ok:
+	case kind == "daily":
+		patterns = cfg.Frontmatter.Aliases.Daily
+	case kind == "weekly":
+		patterns = cfg.Frontmatter.Aliases.Weekly
+	}
+	if len(patterns) == 0 {
+		return nil
+	}
+	p, err := period.Parse(key)
+	if err != nil {
+		return nil
+	}
+	var aliases []string
+	for _, pattern := range patterns {
+		aliases = append(aliases, expandAlias(pattern, p.Start))
+	}
+	return aliases
+}
+
+// expandAlias replaces YYYY, MMM, MM, DD, and WW in pattern with date's
+// year, month name, month, day, and ISO week; WWW is the week with its W.
+// With a week in the pattern, YYYY is the ISO year.
+func expandAlias(pattern string, date time.Time) string {
+	year, week := date.ISOWeek()
+	if !strings.Contains(pattern, "WW") {
+		year = date.Year()
+	}
+	return strings.NewReplacer(
+		"YYYY", fmt.Sprint(year),
+		"MMM", date.Format("Jan"),
+		"MM", date.Format("01"),
+		"DD", date.Format("02"),
+		"WWW", fmt.Sprintf("W%02d", week),
+		"WW", fmt.Sprintf("%02d", week),
+	).Replace(pattern)
+}
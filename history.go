@@ -0,0 +1,52 @@
+package main
+
+import (
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+)
+
+var (
+	seenOnce sync.Once
+	seenDays map[string]fetch.DayData // stored history plus days seen this run
+)
+
+// seeDay adds a freshly fetched day to the history that streaks and
+// records are computed from, replacing any stored copy.
+func seeDay(day fetch.DayData) {
+	loadSeenDays()
+	seenDays[day.Date.Format("2006-01-02")] = day
+}
+
+// knownDays returns every stored day and every day seen this run, oldest
+// first.
+func knownDays() []fetch.DayData {
+	loadSeenDays()
+	days := make([]fetch.DayData, 0, len(seenDays))
+	for _, d := range seenDays {
+		days = append(days, d)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date.Before(days[j].Date) })
+	return days
+}
+
+// loadSeenDays reads every stored day, once per run.
+func loadSeenDays() {
+	seenOnce.Do(func() {
+		seenDays = map[string]fetch.DayData{}
+		st, err := openStore()
+		if err == nil {
+			var days []fetch.DayData
+			if days, err = st.All(); err == nil {
+				for _, d := range days {
+					seenDays[d.Date.Format("2006-01-02")] = d
+				}
+			}
+		}
+		if err != nil {
+			slog.Warn("could not read the local store; streaks and records cover this run only", "err", err)
+		}
+	})
+}
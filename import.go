@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"log/slog"
+
+	"github.com/benstraw/whoop-garden/internal/archive"
+)
+
+// runImport writes daily notes from a WHOOP account data export, without
+// any API calls.
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	addGlobalFlags(fs)
+	file := fs.String("file", "", "path to the WHOOP data export ZIP (required)")
+	fromStr := fs.String("from", "", "first date to import, YYYY-MM-DD (default: the whole export)")
+	toStr := fs.String("to", "", "last date to import, YYYY-MM-DD (default: yesterday; requires --from)")
+	force := fs.Bool("force", false, "overwrite notes that already exist")
+	_ = fs.Parse(args)
+
+	if *file == "" {
+		fatal(errors.New("--file is required: download the export from the WHOOP app under Settings → Data Export"))
+	}
+	days, err := archive.ReadFile(*file)
+	if err != nil {
+		fatalf("read export: %w", err)
+	}
+	if *fromStr != "" || *toStr != "" {
+		p, err := parseDateRange(*fromStr, *toStr)
+		if err != nil {
+			fatal(err)
+		}
+		kept := days[:0]
+		for _, d := range days {
+			if !d.Date.Before(p.Start) && d.Date.Before(p.End) {
+				kept = append(kept, d)
+			}
+		}
+		days = kept
+	}
+	if len(days) == 0 {
+		infof("No days to import.\n")
+		return
+	}
+
+	dir, err := ensureOutputDir()
+	if err != nil {
+		fatal(err)
+	}
+	st, err := openStore()
+	if err != nil {
+		fatal(err)
+	}
+	// Baselines and comparisons come from the store, which the export
+	// fills as it goes; nothing is fetched.
+	storeOnly = true
+	c := cacheClient()
+
+	infof("Importing %s (%s to %s)...\n", plural(len(days), "day"), days[0].Date.Format("2006-01-02"), days[len(days)-1].Date.Format("2006-01-02"))
+	b := startBackfill(nil, len(days))
+	for _, day := range days {
+		date := day.Date.Format("2006-01-02")
+		outPath := notePath(dir, "daily", date)
+		if !*force {
+			exists, err := noteExists(outPath)
+			if err != nil {
+				fatal(err)
+			}
+			if exists {
+				b.skip()
+				continue
+			}
+		}
+
+		// Days already fetched from the API are more complete; keep them.
+		if !opts.dryRun && !opts.stdout {
+			if _, _, ok, err := st.Load(day.Date); err == nil && !ok {
+				if err := st.Save(day); err != nil {
+					slog.Warn("could not store", "date", date, "err", err)
+				}
+			}
+		}
+
+		if _, err := writeDay(c, day); err != nil {
+			slog.Warn("could not import", "date", date, "err", err)
+			b.fail()
+			continue
+		}
+		b.wrote()
+	}
+	b.finish()
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/links"
+	"github.com/benstraw/whoop-garden/internal/period"
+	"github.com/benstraw/whoop-garden/internal/render"
+)
+
+// indexDue is set when notes were written and the index notes should be
+// brought up to date before the run ends.
+var indexDue bool
+
+// runIndex writes WHOOP.md and an index note per year from the notes in the
+// output directory.
+func runIndex(args []string) {
+	fs := flag.NewFlagSet("index", flag.ExitOnError)
+	addGlobalFlags(fs)
+	_ = fs.Parse(args)
+
+	if err := writeIndex(); err != nil {
+		fatal(err)
+	}
+}
+
+// flushIndex rewrites the index notes when notes were written since the
+// last call. Failures are only logged.
+func flushIndex() {
+	if !indexDue {
+		return
+	}
+	indexDue = false
+	if err := writeIndex(); err != nil {
+		slog.Warn("could not update index", "err", err)
+	}
+}
+
+// writeIndex renders an index note for every year with daily, weekly, or
+// monthly notes in the output directory, and WHOOP.md linking the years,
+// the persona, and the records.
+func writeIndex() error {
+	if cfg.Storage.Remote() {
+		return fmt.Errorf("index lists the notes in a local directory and does not support %s storage", cfg.Storage)
+	}
+	dir, err := ensureOutputDir()
+	if err != nil {
+		return err
+	}
+	notes, err := links.Scan(dir)
+	if err != nil {
+		return fmt.Errorf("scan notes: %w", err)
+	}
+
+	today := time.Now().Format("2006-01-02")
+	l := noteLinker()
+	homePath := l.Path("WHOOP")
+	home := render.HomeData{GeneratedDate: today}
+	if p := personaVaultPath(); p != "" {
+		home.Persona = l.Link(homePath, p)
+	}
+	if exists(filepath.Join(dir, "Records.md")) {
+		home.Records = l.Link(homePath, l.Path("Records"))
+	}
+	years := map[string]*render.YearIndexData{}
+	counts := map[string]*render.IndexYear{}
+	for _, n := range notes {
+		if n.Kind == "index" {
+			continue
+		}
+		year := n.Key[:4]
+		if years[year] == nil {
+			years[year] = &render.YearIndexData{GeneratedDate: today, Year: year, Home: l.Link(l.Path(render.NoteRel("index", year)), homePath)}
+			counts[year] = &render.IndexYear{Year: year}
+		}
+		y := years[year]
+		switch n.Kind {
+		case "daily":
+			counts[year].Days++
+		case "weekly":
+			p, err := period.Parse(n.Key)
+			if err != nil {
+				continue
+			}
+			y.Weeks = append(y.Weeks, render.IndexWeek{Key: n.Key, Start: p.Start, Last: p.Last()})
+			counts[year].Weeks++
+		case "monthly":
+			if p, err := period.Parse(n.Key); err == nil {
+				y.Months = append(y.Months, p.Start)
+			}
+		}
+	}
+
+	var order []string
+	for year := range years {
+		order = append(order, year)
+	}
+	sort.Strings(order)
+	tmplPath := templatePath("index.md.tmpl")
+	for i, year := range order {
+		y := years[year]
+		if i > 0 {
+			y.Prev = order[i-1]
+		}
+		if i+1 < len(order) {
+			y.Next = order[i+1]
+		}
+		sort.Slice(y.Weeks, func(a, b int) bool { return y.Weeks[a].Key < y.Weeks[b].Key })
+		sort.Slice(y.Months, func(a, b int) bool { return y.Months[a].Before(y.Months[b]) })
+		content, err := render.RenderYearIndex(*y, tmplPath)
+		if err != nil {
+			return fmt.Errorf("render error: %w", err)
+		}
+		if err := writeNote(notePath(dir, "index", year), content); err != nil {
+			return fmt.Errorf("write error: %w", err)
+		}
+		home.Years = append([]render.IndexYear{*counts[year]}, home.Years...)
+	}
+
+	content, err := render.RenderHome(home, templatePath("home.md.tmpl"))
+	if err != nil {
+		return fmt.Errorf("render error: %w", err)
+	}
+	if err := writeNote(filepath.Join(dir, "WHOOP.md"), content); err != nil {
+		return fmt.Errorf("write error: %w", err)
+	}
+	return nil
+}
+
+// personaVaultPath returns the persona note's path in the vault without
+// .md, or "" when it has not been written inside the vault.
+func personaVaultPath() string {
+	p, err := personaPath()
+	if err != nil || !exists(p) {
+		return ""
+	}
+	rel, err := filepath.Rel(os.Getenv("OBSIDIAN_VAULT_PATH"), p)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	return strings.TrimSuffix(filepath.ToSlash(rel), ".md")
+}
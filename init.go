@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/benstraw/whoop-garden/internal/auth"
+	"github.com/benstraw/whoop-garden/internal/config"
+)
+
+// defaultRedirectURI matches the callback server started by auth.
+const defaultRedirectURI = "http://localhost:3000/callback"
+
+// runInit walks through first-time setup: WHOOP app credentials, the vault,
+// optional template export and language, then the OAuth flow. Answers are
+// saved to .env (and config.json for the template set) before OAuth runs,
+// so a failed login can be retried with 'auth' without answering again.
+func runInit(args []string) {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	skipAuth := fs.Bool("skip-auth", false, "write the configuration but do not start the OAuth flow")
+	_ = fs.Parse(args)
+
+	p := newPrompter()
+
+	fmt.Println("whoop-garden setup")
+	fmt.Println()
+	fmt.Println("1. WHOOP app credentials")
+	fmt.Println("   Create an app at https://developer.whoop.com and add this redirect URI:")
+	fmt.Println("   " + defaultRedirectURI)
+	env := map[string]string{}
+	env["WHOOP_CLIENT_ID"] = p.ask("Client ID", os.Getenv("WHOOP_CLIENT_ID"), true)
+	env["WHOOP_CLIENT_SECRET"] = p.ask("Client secret", os.Getenv("WHOOP_CLIENT_SECRET"), true)
+	redirect := os.Getenv("WHOOP_REDIRECT_URI")
+	if redirect == "" {
+		redirect = defaultRedirectURI
+	}
+	env["WHOOP_REDIRECT_URI"] = p.ask("Redirect URI", redirect, true)
+
+	fmt.Println()
+	fmt.Println("2. Obsidian vault")
+	fmt.Println("   Notes go to <vault>/Health/WHOOP/. Leave empty to write to ./output.")
+	for {
+		vault := expandHome(p.ask("Vault path", os.Getenv("OBSIDIAN_VAULT_PATH"), false))
+		if vault == "" {
+			break
+		}
+		if info, err := os.Stat(vault); err != nil || !info.IsDir() {
+			fmt.Printf("   %s is not a directory.\n", vault)
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(vault, ".obsidian")); err != nil {
+			fmt.Printf("   Note: %s has no .obsidian folder; is it the vault root?\n", vault)
+		}
+		env["OBSIDIAN_VAULT_PATH"] = vault
+		break
+	}
+
+	fmt.Println()
+	fmt.Println("3. Templates")
+	lang := p.ask("Template language (en, de, es)", templateSetOrDefault(), false)
+	if lang != "en" {
+		if _, err := os.Stat(filepath.Join(templatesDir(), lang)); err != nil {
+			fmt.Printf("   No %q template set found; the English templates will be used for anything it lacks.\n", lang)
+		}
+	}
+	if p.confirm("Copy the templates somewhere you can edit them?", false) {
+		def := "./whoop-templates"
+		if v := env["OBSIDIAN_VAULT_PATH"]; v != "" {
+			def = filepath.Join(v, "Health", "WHOOP", "templates")
+		}
+		dst := expandHome(p.ask("Copy templates to", def, true))
+		n, err := exportTemplates(templatesDir(), dst)
+		if err != nil {
+			fatalf("template export failed: %w", err)
+		}
+		fmt.Printf("   Copied %d templates to %s (existing files were kept).\n", n, dst)
+		env["WHOOP_TEMPLATES_DIR"] = dst
+	}
+
+	if err := updateDotEnv(".env", env); err != nil {
+		fatalf("write .env: %w", err)
+	}
+	fmt.Println()
+	fmt.Println("Written: .env")
+	if lang == "en" {
+		lang = ""
+	}
+	if lang != cfg.TemplateSet {
+		if err := updateConfig(config.Path(), "template_set", lang); err != nil {
+			fatalf("write config: %w", err)
+		}
+		fmt.Println("Written:", config.Path())
+	}
+	for k, v := range env {
+		os.Setenv(k, v)
+	}
+
+	if *skipAuth {
+		fmt.Println("\nRun 'whoop-garden auth' to log in, then 'whoop-garden doctor' to check the setup.")
+		return
+	}
+	fmt.Println()
+	fmt.Println("4. Log in to WHOOP")
+	if err := auth.StartAuthFlow(); err != nil {
+		fatalf("auth failed: %w\nYour answers are saved; run 'whoop-garden auth' to retry.", err)
+	}
+	fmt.Println("\nSetup complete. Try 'whoop-garden daily', or 'whoop-garden doctor' to check everything.")
+}
+
+// prompter reads answers to setup questions.
+type prompter struct {
+	r *bufio.Reader
+	w io.Writer
+}
+
+// stdin is shared by every prompter so buffered input is not lost between
+// them.
+var stdin = bufio.NewReader(os.Stdin)
+
+func newPrompter() prompter { return prompter{r: stdin, w: os.Stdout} }
+
+// ask prints question with def as the default and returns the answer.
+// A required question is repeated until it has a non-empty answer. EOF on
+// input aborts setup.
+func (p prompter) ask(question, def string, required bool) string {
+	for {
+		if def != "" {
+			fmt.Fprintf(p.w, "   %s [%s]: ", question, def)
+		} else {
+			fmt.Fprintf(p.w, "   %s: ", question)
+		}
+		line, err := p.r.ReadString('\n')
+		if err != nil && line == "" {
+			fmt.Fprintln(p.w)
+			fatal(errors.New("setup aborted"))
+		}
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = def
+		}
+		if answer != "" || !required {
+			return answer
+		}
+	}
+}
+
+// confirm asks a yes/no question.
+func (p prompter) confirm(question string, def bool) bool {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	answer := strings.ToLower(p.ask(question+" ("+hint+")", "", false))
+	if answer == "" {
+		return def
+	}
+	return strings.HasPrefix(answer, "y")
+}
+
+// templateSetOrDefault returns the configured template set, or "en".
+func templateSetOrDefault() string {
+	if cfg.TemplateSet != "" {
+		return cfg.TemplateSet
+	}
+	return "en"
+}
+
+// expandHome replaces a leading ~ with the user's home directory.
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
+
+// exportTemplates copies every .tmpl file under src to dst, keeping the
+// directory layout. Files that already exist in dst are left untouched.
+// It returns the number of files copied.
+func exportTemplates(src, dst string) (int, error) {
+	n := 0
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !strings.HasSuffix(path, ".tmpl") {
+			return nil
+		}
+		if _, err := os.Stat(target); err == nil {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			return err
+		}
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// updateDotEnv sets values in the .env file at path. Existing lines for
+// those keys are replaced in place; comments and other keys are kept, and
+// new keys are appended.
+func updateDotEnv(path string, values map[string]string) error {
+	var lines []string
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if len(data) > 0 {
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	}
+
+	seen := map[string]bool{}
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		key, _, ok := strings.Cut(trimmed, "=")
+		key = strings.TrimSpace(key)
+		if v, set := values[key]; ok && set {
+			lines[i] = key + "=" + v
+			seen[key] = true
+		}
+	}
+	for _, key := range []string{"WHOOP_CLIENT_ID", "WHOOP_CLIENT_SECRET", "WHOOP_REDIRECT_URI", "OBSIDIAN_VAULT_PATH", "WHOOP_TEMPLATES_DIR"} {
+		if v, set := values[key]; set && !seen[key] {
+			lines = append(lines, key+"="+v)
+		}
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600)
+}
+
+// updateConfig sets key in the JSON config file at path, preserving every
+// other setting. An empty value removes the key.
+func updateConfig(path, key, value string) error {
+	settings := map[string]json.RawMessage{}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+	}
+	if value == "" {
+		delete(settings, key)
+	} else {
+		v, _ := json.Marshal(value)
+		settings[key] = v
+	}
+	out, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(out, '\n'), 0644)
+}
@@ -0,0 +1,260 @@
+// Package alert evaluates user-defined metric rules against recent history.
+// Rules use hysteresis and cooldowns so an ongoing condition notifies once
+// rather than on every run.
+package alert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/analytics"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+)
+
+// defaultCooldown is the minimum gap between notifications for a rule.
+const defaultCooldown = 24 * time.Hour
+
+// rhrBaselineDays is the window used by the rhr_above_baseline metric.
+const rhrBaselineDays = 30
+
+// Rule is a user-defined alert condition.
+type Rule struct {
+	Name   string `json:"name"`
+	Metric string `json:"metric"`
+	// Op is "above" (default) or "below".
+	Op        string  `json:"op"`
+	Threshold float64 `json:"threshold"`
+	// Clear is the value an active alert must cross back over before it can
+	// fire again. Defaults to Threshold (no hysteresis band).
+	Clear *float64 `json:"clear"`
+	// ConsecutiveDays is how many days in a row the condition must hold.
+	ConsecutiveDays int `json:"consecutive_days"`
+	// CooldownHours is the minimum time between notifications (default 24).
+	CooldownHours float64 `json:"cooldown_hours"`
+}
+
+// metricFuncs computes each metric at index i using history up to i.
+var metricFuncs = map[string]func(days []fetch.DayData, i int) (float64, bool){
+	"recovery": func(days []fetch.DayData, i int) (float64, bool) {
+		if r := analytics.ScoredRecovery(days[i]); r != nil {
+			return r.Score.RecoveryScore, true
+		}
+		return 0, false
+	},
+	"hrv": func(days []fetch.DayData, i int) (float64, bool) {
+		if r := analytics.ScoredRecovery(days[i]); r != nil {
+			return r.Score.HrvRmssdMilli, true
+		}
+		return 0, false
+	},
+	"rhr": func(days []fetch.DayData, i int) (float64, bool) {
+		if r := analytics.ScoredRecovery(days[i]); r != nil {
+			return r.Score.RestingHeartRate, true
+		}
+		return 0, false
+	},
+	"strain": func(days []fetch.DayData, i int) (float64, bool) {
+		if c := days[i].Cycle; c != nil && c.ScoreState == "SCORED" {
+			return c.Score.Strain, true
+		}
+		return 0, false
+	},
+	"rhr_above_baseline": func(days []fetch.DayData, i int) (float64, bool) {
+		return analytics.RHRAboveBaseline(days[:i+1], rhrBaselineDays)
+	},
+	"sleep_debt_hours": func(days []fetch.DayData, i int) (float64, bool) {
+		from := i - 6
+		if from < 0 {
+			from = 0
+		}
+		return float64(analytics.SleepDebtMillis(days[from:i+1])) / 3_600_000, true
+	},
+	"acwr": func(days []fetch.DayData, i int) (float64, bool) {
+		return analytics.ACWR(days[:i+1])
+	},
+}
+
+// Validate reports the first rule with an unknown metric or operator.
+func Validate(rules []Rule) error {
+	for _, r := range rules {
+		if _, ok := metricFuncs[r.Metric]; !ok {
+			return fmt.Errorf("alert rule %q: unknown metric %q", r.Name, r.Metric)
+		}
+		if r.Op != "" && r.Op != "above" && r.Op != "below" {
+			return fmt.Errorf("alert rule %q: op must be \"above\" or \"below\"", r.Name)
+		}
+	}
+	return nil
+}
+
+// String describes the rule's condition, e.g. "low-recovery: recovery below
+// 34 for 3 days".
+func (r Rule) String() string {
+	s := fmt.Sprintf("%s: %s %s %g", r.Name, r.Metric, r.op(), r.Threshold)
+	if n := r.days(); n > 1 {
+		s += fmt.Sprintf(" for %d days", n)
+	}
+	return s
+}
+
+func (r Rule) op() string {
+	if r.Op == "" {
+		return "above"
+	}
+	return r.Op
+}
+
+func (r Rule) days() int {
+	if r.ConsecutiveDays < 1 {
+		return 1
+	}
+	return r.ConsecutiveDays
+}
+
+func (r Rule) cooldown() time.Duration {
+	if r.CooldownHours <= 0 {
+		return defaultCooldown
+	}
+	return time.Duration(r.CooldownHours * float64(time.Hour))
+}
+
+// beyond reports whether v is past limit in the rule's direction.
+func (r Rule) beyond(v, limit float64) bool {
+	if r.op() == "below" {
+		return v < limit
+	}
+	return v > limit
+}
+
+func (r Rule) clearLevel() float64 {
+	if r.Clear != nil {
+		return *r.Clear
+	}
+	return r.Threshold
+}
+
+// RuleState tracks whether a rule is currently active and when it last
+// notified.
+type RuleState struct {
+	Active    bool      `json:"active"`
+	LastFired time.Time `json:"last_fired"`
+}
+
+// State maps rule names to their state between runs.
+type State map[string]RuleState
+
+// LoadState reads state from path. A missing file yields an empty State.
+func LoadState(path string) (State, error) {
+	st := State{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return st, nil
+		}
+		return st, fmt.Errorf("read alert state: %w", err)
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return State{}, fmt.Errorf("parse alert state: %w", err)
+	}
+	return st, nil
+}
+
+// SaveState writes state to path.
+func SaveState(path string, st State) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Alert is a rule that fired on the latest day.
+type Alert struct {
+	Rule  Rule
+	Value float64
+	Date  time.Time
+}
+
+// Title returns a short notification title.
+func (a Alert) Title() string {
+	return fmt.Sprintf("WHOOP alert: %s", a.Rule.Name)
+}
+
+// Message returns a one-line description of the triggering condition.
+func (a Alert) Message() string {
+	msg := fmt.Sprintf("%s %s is %.1f (%s %.1f)", a.Date.Format("2006-01-02"), a.Rule.Metric, a.Value, a.Rule.op(), a.Rule.Threshold)
+	if n := a.Rule.days(); n > 1 {
+		msg += fmt.Sprintf(" for %d consecutive days", n)
+	}
+	return msg
+}
+
+// Evaluate checks each rule at the last day in days (chronological order)
+// and returns the alerts that should notify now. st is updated in place as
+// rules clear or re-trigger within the cooldown, which is recorded but not
+// notified. The returned alerts change nothing until they are passed to
+// Delivered, so one that could not be sent fires again on the next run.
+func Evaluate(rules []Rule, days []fetch.DayData, st State, now time.Time) []Alert {
+	if len(days) == 0 {
+		return nil
+	}
+	last := len(days) - 1
+	var fired []Alert
+	for _, r := range rules {
+		fn, ok := metricFuncs[r.Metric]
+		if !ok {
+			continue
+		}
+		latest, ok := fn(days, last)
+		if !ok {
+			continue // no fresh value; leave state as is
+		}
+		rs := st[r.Name]
+
+		if rs.Active {
+			if !r.beyond(latest, r.clearLevel()) {
+				rs.Active = false
+			}
+			st[r.Name] = rs
+			continue
+		}
+
+		if !holds(r, fn, days) {
+			continue
+		}
+		if rs.LastFired.IsZero() || now.Sub(rs.LastFired) >= r.cooldown() {
+			fired = append(fired, Alert{Rule: r, Value: latest, Date: days[last].Date})
+			continue
+		}
+		rs.Active = true
+		st[r.Name] = rs
+	}
+	return fired
+}
+
+// Delivered records that a was sent at now: its rule becomes active, and
+// stays silent until its value crosses back over the clear level, and its
+// cooldown starts.
+func (st State) Delivered(a Alert, now time.Time) {
+	st[a.Rule.Name] = RuleState{Active: true, LastFired: now}
+}
+
+// holds reports whether r's condition is met on each of the last
+// ConsecutiveDays days.
+func holds(r Rule, fn func([]fetch.DayData, int) (float64, bool), days []fetch.DayData) bool {
+	n := r.days()
+	if len(days) < n {
+		return false
+	}
+	for i := len(days) - n; i < len(days); i++ {
+		v, ok := fn(days, i)
+		if !ok || !r.beyond(v, r.Threshold) {
+			return false
+		}
+	}
+	return true
+}
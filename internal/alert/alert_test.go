@@ -0,0 +1,131 @@
+package alert
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/models"
+)
+
+func recoveryDays(scores ...float64) []fetch.DayData {
+	var days []fetch.DayData
+	start := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	for i, s := range scores {
+		days = append(days, fetch.DayData{
+			Date:     start.AddDate(0, 0, i),
+			Recovery: &models.Recovery{ScoreState: "SCORED", Score: models.RecoveryScore{RecoveryScore: s}},
+		})
+	}
+	return days
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate([]Rule{{Name: "ok", Metric: "acwr"}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := Validate([]Rule{{Name: "bad", Metric: "mood"}}); err == nil {
+		t.Error("expected error for unknown metric")
+	}
+	if err := Validate([]Rule{{Name: "bad", Metric: "hrv", Op: "equals"}}); err == nil {
+		t.Error("expected error for unknown op")
+	}
+}
+
+func TestEvaluate_ConsecutiveDays(t *testing.T) {
+	rule := Rule{Name: "low", Metric: "recovery", Op: "below", Threshold: 34, ConsecutiveDays: 3}
+	now := time.Date(2026, 2, 10, 8, 0, 0, 0, time.UTC)
+
+	if got := Evaluate([]Rule{rule}, recoveryDays(20, 50, 20, 20), State{}, now); len(got) != 0 {
+		t.Errorf("two red days should not fire a 3-day rule, got %d alerts", len(got))
+	}
+	got := Evaluate([]Rule{rule}, recoveryDays(20, 20, 20), State{}, now)
+	if len(got) != 1 || got[0].Value != 20 {
+		t.Fatalf("expected one alert with value 20, got %+v", got)
+	}
+}
+
+// evaluate runs Evaluate and delivers every alert it returns.
+func evaluate(rules []Rule, days []fetch.DayData, st State, now time.Time) []Alert {
+	fired := Evaluate(rules, days, st, now)
+	for _, a := range fired {
+		st.Delivered(a, now)
+	}
+	return fired
+}
+
+func TestEvaluate_Hysteresis(t *testing.T) {
+	clear := 50.0
+	rule := Rule{Name: "low", Metric: "recovery", Op: "below", Threshold: 34, Clear: &clear}
+	st := State{}
+	now := time.Date(2026, 2, 10, 8, 0, 0, 0, time.UTC)
+
+	if got := evaluate([]Rule{rule}, recoveryDays(20), st, now); len(got) != 1 {
+		t.Fatalf("first red day should fire, got %d", len(got))
+	}
+	// Still red two days later: active, no repeat notification.
+	if got := evaluate([]Rule{rule}, recoveryDays(20, 25), st, now.Add(48*time.Hour)); len(got) != 0 {
+		t.Errorf("ongoing condition should not re-notify, got %d", len(got))
+	}
+	// Yellow but under the clear level: still active.
+	evaluate([]Rule{rule}, recoveryDays(20, 40), st, now.Add(72*time.Hour))
+	if !st["low"].Active {
+		t.Error("value inside hysteresis band should keep the rule active")
+	}
+	// Above clear: resets, and the next red day fires again.
+	evaluate([]Rule{rule}, recoveryDays(20, 60), st, now.Add(96*time.Hour))
+	if st["low"].Active {
+		t.Error("value past clear level should reset the rule")
+	}
+	if got := evaluate([]Rule{rule}, recoveryDays(60, 20), st, now.Add(120*time.Hour)); len(got) != 1 {
+		t.Errorf("re-trigger after clearing should fire, got %d", len(got))
+	}
+}
+
+func TestEvaluate_Cooldown(t *testing.T) {
+	rule := Rule{Name: "low", Metric: "recovery", Op: "below", Threshold: 34, CooldownHours: 72}
+	st := State{}
+	now := time.Date(2026, 2, 10, 8, 0, 0, 0, time.UTC)
+
+	evaluate([]Rule{rule}, recoveryDays(20), st, now)
+	evaluate([]Rule{rule}, recoveryDays(60), st, now.Add(24*time.Hour))
+	if got := evaluate([]Rule{rule}, recoveryDays(20), st, now.Add(48*time.Hour)); len(got) != 0 {
+		t.Errorf("re-trigger inside cooldown should be silent, got %d", len(got))
+	}
+	if !st["low"].Active {
+		t.Error("silent re-trigger should still mark the rule active")
+	}
+}
+
+func TestEvaluate_Undelivered(t *testing.T) {
+	rule := Rule{Name: "low", Metric: "recovery", Op: "below", Threshold: 34}
+	st := State{}
+	now := time.Date(2026, 2, 10, 8, 0, 0, 0, time.UTC)
+
+	if got := Evaluate([]Rule{rule}, recoveryDays(20), st, now); len(got) != 1 {
+		t.Fatalf("first red day should fire, got %d", len(got))
+	}
+	if st["low"].Active {
+		t.Error("an alert not yet delivered should leave the rule inactive")
+	}
+	if got := Evaluate([]Rule{rule}, recoveryDays(20), st, now.Add(time.Hour)); len(got) != 1 {
+		t.Errorf("an undelivered alert should fire again, got %d", len(got))
+	}
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	st, err := LoadState(path)
+	if err != nil || len(st) != 0 {
+		t.Fatalf("missing state: %v, %v", st, err)
+	}
+	st["x"] = RuleState{Active: true, LastFired: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)}
+	if err := SaveState(path, st); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadState(path)
+	if err != nil || !got["x"].Active {
+		t.Errorf("round trip failed: %v, %v", got, err)
+	}
+}
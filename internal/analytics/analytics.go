@@ -0,0 +1,685 @@
+// Package analytics derives multi-day metrics (baselines, sleep debt,
+// training load) from fetched DayData. Functions are pure and expect days in
+// chronological order.
+package analytics
+
+import (
+	"math"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/models"
+)
+
+const (
+	// acuteDays and chronicDays are the windows for the acute:chronic
+	// workload ratio.
+	acuteDays   = 7
+	chronicDays = 28
+
+	// minChronicDays is the fewest scored days needed in the chronic window
+	// before ACWR is reported.
+	minChronicDays = 14
+
+	// spikeRatio is the ACWR above which a load spike is flagged.
+	spikeRatio = 1.5
+
+	// A day is overreached when its strain reaches overreachedStrain on
+	// red recovery, and undertrained when it stays under
+	// undertrainedStrain on green recovery.
+	overreachedStrain  = 14
+	undertrainedStrain = 10
+
+	// minBaselineDays is the fewest scored days needed for a baseline.
+	minBaselineDays = 14
+
+	// SkinTempThreshold is how far, in °C, skin temperature may stray from
+	// its baseline before it is flagged.
+	SkinTempThreshold = 1.0
+
+	// SpO2Threshold is how many points SpO2 may fall below its baseline
+	// before it is flagged.
+	SpO2Threshold = 3.0
+
+	// streakSleepMillis is the sleep a night needs to extend a sleep
+	// streak: seven hours asleep.
+	streakSleepMillis = 7 * 3_600_000
+
+	// Sleep debt is paid off with at most payoffMillis of extra sleep a
+	// night over at most maxPayoffNights nights. Debts under
+	// minPayoffMillis get no suggestion.
+	payoffMillis    = 3_600_000
+	maxPayoffNights = 7
+	minPayoffMillis = 30 * 60_000
+)
+
+// PrimarySleep returns the longest non-nap sleep from a slice, or nil if none.
+func PrimarySleep(sleeps []models.Sleep) *models.Sleep {
+	var best *models.Sleep
+	for i := range sleeps {
+		s := &sleeps[i]
+		if s.Nap {
+			continue
+		}
+		if best == nil || s.Score.StageSummary.TotalInBedTimeMilli > best.Score.StageSummary.TotalInBedTimeMilli {
+			best = s
+		}
+	}
+	return best
+}
+
+// AsleepMillis returns time actually asleep: in-bed time minus awake and
+// no-data time.
+func AsleepMillis(s models.Sleep) int64 {
+	ss := s.Score.StageSummary
+	return ss.TotalInBedTimeMilli - ss.TotalAwakeTimeMilli - ss.TotalNoDataTimeMilli
+}
+
+// ScoredRecovery returns d's recovery if it is scored and WHOOP was not
+// still calibrating to the user. Calibrating scores are unreliable, so
+// aggregates leave them out.
+func ScoredRecovery(d fetch.DayData) *models.Recovery {
+	if d.Recovery != nil && d.Recovery.ScoreState == "SCORED" && !d.Recovery.Score.UserCalibrating {
+		return d.Recovery
+	}
+	return nil
+}
+
+// Calibrating reports whether d has a recovery scored while WHOOP was still
+// calibrating to the user.
+func Calibrating(d fetch.DayData) bool {
+	return d.Recovery != nil && d.Recovery.ScoreState == "SCORED" && d.Recovery.Score.UserCalibrating
+}
+
+// MeanStd returns the mean and population standard deviation of vals.
+func MeanStd(vals []float64) (mean, std float64) {
+	if len(vals) == 0 {
+		return 0, 0
+	}
+	for _, v := range vals {
+		mean += v
+	}
+	mean /= float64(len(vals))
+	for _, v := range vals {
+		std += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(std / float64(len(vals)))
+}
+
+// RHRAboveBaseline returns how far the last day's resting heart rate sits
+// above the mean of the preceding window days. ok is false when the last day
+// is unscored or fewer than 7 baseline values exist.
+func RHRAboveBaseline(days []fetch.DayData, window int) (delta float64, ok bool) {
+	if len(days) == 0 {
+		return 0, false
+	}
+	today := ScoredRecovery(days[len(days)-1])
+	if today == nil {
+		return 0, false
+	}
+	from := len(days) - 1 - window
+	if from < 0 {
+		from = 0
+	}
+	var vals []float64
+	for _, d := range days[from : len(days)-1] {
+		if r := ScoredRecovery(d); r != nil {
+			vals = append(vals, r.Score.RestingHeartRate)
+		}
+	}
+	if len(vals) < 7 {
+		return 0, false
+	}
+	mean, _ := MeanStd(vals)
+	return today.Score.RestingHeartRate - mean, true
+}
+
+// Bedtime returns when a sleep started, in local hours from midnight:
+// 23:30 is -0.5 and 00:45 is 0.75. Times from noon on count as the
+// evening before midnight.
+func Bedtime(s models.Sleep) (float64, bool) {
+	t, ok := LocalTime(s.Start, s.TimezoneOffset)
+	if !ok {
+		return 0, false
+	}
+	h := float64(t.Hour()) + float64(t.Minute())/60
+	if h >= 12 {
+		h -= 24
+	}
+	return h, true
+}
+
+// WakeTime returns when a sleep ended, in local hours from midnight:
+// 06:30 is 6.5. Times from 18:00 on count as the evening before.
+func WakeTime(s models.Sleep) (float64, bool) {
+	t, ok := LocalTime(s.End, s.TimezoneOffset)
+	if !ok {
+		return 0, false
+	}
+	h := float64(t.Hour()) + float64(t.Minute())/60
+	if h >= 18 {
+		h -= 24
+	}
+	return h, true
+}
+
+// Midpoint returns the local time halfway between a sleep's start and
+// end, in hours from midnight: 03:15 is 3.25. Times from noon on count as
+// the evening before.
+func Midpoint(s models.Sleep) (float64, bool) {
+	start, ok1 := LocalTime(s.Start, s.TimezoneOffset)
+	end, ok2 := LocalTime(s.End, s.TimezoneOffset)
+	if !ok1 || !ok2 || !end.After(start) {
+		return 0, false
+	}
+	t := start.Add(end.Sub(start) / 2)
+	h := float64(t.Hour()) + float64(t.Minute())/60
+	if h >= 12 {
+		h -= 24
+	}
+	return h, true
+}
+
+// LocalTime parses a WHOOP timestamp and moves it to offset ("-05:00").
+// An unparseable offset leaves the time in UTC.
+func LocalTime(ts, offset string) (time.Time, bool) {
+	t, err := fetch.ParseWhoopTime(ts)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if off, err := time.Parse("-07:00", offset); err == nil {
+		_, secs := off.Zone()
+		t = t.In(time.FixedZone(offset, secs))
+	}
+	return t, true
+}
+
+// SleepShortfallMillis returns the primary sleep's shortfall against the
+// night's need (baseline + recent strain + recent naps). The need from
+// existing sleep debt is left out so that cumulating shortfalls does not
+// count the same debt twice. Negative values mean the need was exceeded.
+func SleepShortfallMillis(d fetch.DayData) (int64, bool) {
+	s := PrimarySleep(d.Sleeps)
+	if s == nil || s.ScoreState != "SCORED" {
+		return 0, false
+	}
+	n := s.Score.SleepNeeded
+	need := n.BaselineMillis + n.NeedFromRecentStrainMillis + n.NeedFromRecentNapMillis
+	return need - AsleepMillis(*s), true
+}
+
+// NightSleepNeed returns the full need WHOOP computed for s, including the
+// need from existing debt, against the time asleep. ok is false when s is
+// unscored or has no need.
+func NightSleepNeed(s models.Sleep) (fetch.SleepNeed, bool) {
+	n := s.Score.SleepNeeded
+	need := n.BaselineMillis + n.NeedFromSleepDebtMillis + n.NeedFromRecentStrainMillis + n.NeedFromRecentNapMillis
+	if s.ScoreState != "SCORED" || need <= 0 {
+		return fetch.SleepNeed{}, false
+	}
+	asleep := AsleepMillis(s)
+	return fetch.SleepNeed{
+		Nights:      1,
+		Baseline:    n.BaselineMillis,
+		Debt:        n.NeedFromSleepDebtMillis,
+		Strain:      n.NeedFromRecentStrainMillis,
+		Nap:         n.NeedFromRecentNapMillis,
+		Need:        need,
+		Asleep:      asleep,
+		Fulfillment: float64(asleep) / float64(need) * 100,
+	}, true
+}
+
+// AvgSleepNeed averages NightSleepNeed over the primary sleeps of days. ok
+// is false when no night has a need.
+func AvgSleepNeed(days []fetch.DayData) (avg fetch.SleepNeed, ok bool) {
+	for _, d := range days {
+		s := PrimarySleep(d.Sleeps)
+		if s == nil {
+			continue
+		}
+		n, ok := NightSleepNeed(*s)
+		if !ok {
+			continue
+		}
+		avg.Nights++
+		avg.Baseline += n.Baseline
+		avg.Debt += n.Debt
+		avg.Strain += n.Strain
+		avg.Nap += n.Nap
+		avg.Need += n.Need
+		avg.Asleep += n.Asleep
+		avg.Fulfillment += n.Fulfillment
+	}
+	if avg.Nights == 0 {
+		return avg, false
+	}
+	k := int64(avg.Nights)
+	avg.Baseline /= k
+	avg.Debt /= k
+	avg.Strain /= k
+	avg.Nap /= k
+	avg.Need /= k
+	avg.Asleep /= k
+	avg.Fulfillment /= float64(avg.Nights)
+	return avg, true
+}
+
+// bedtimeNights is how many recent nights set the sleep efficiency and,
+// without a target, the wake time of a bedtime recommendation.
+const bedtimeNights = 7
+
+// RecommendBedtime recommends a bedtime for the night after the last of
+// days. The latest night's full need (see NightSleepNeed) is stretched to
+// time in bed by the mean efficiency of the last seven primary sleeps and
+// counted back from wake, the target wake time in local hours from
+// midnight. A negative wake uses the nights' average wake time instead.
+// ok is false when none of the nights has a need or, without a target, a
+// wake time.
+func RecommendBedtime(days []fetch.DayData, wake float64) (b fetch.Bedtime, ok bool) {
+	recent := tail(days, bedtimeNights)
+	var effs []float64
+	for i := len(recent) - 1; i >= 0; i-- {
+		s := PrimarySleep(recent[i].Sleeps)
+		if s == nil || s.ScoreState != "SCORED" {
+			continue
+		}
+		if n, ok := NightSleepNeed(*s); ok && b.Need == 0 {
+			b.Need = n.Need
+		}
+		if inBed := s.Score.StageSummary.TotalInBedTimeMilli; inBed > 0 {
+			effs = append(effs, float64(AsleepMillis(*s))/float64(inBed))
+		}
+	}
+	if b.Need == 0 {
+		return b, false
+	}
+	if wake < 0 {
+		sched, ok := SleepSchedule(recent)
+		if !ok {
+			return b, false
+		}
+		wake = sched.AvgWake
+	}
+	eff := 1.0
+	if len(effs) > 0 {
+		eff, _ = MeanStd(effs)
+	}
+	if eff <= 0 {
+		eff = 1
+	}
+	b.Wake = wake
+	b.Efficiency = eff * 100
+	b.InBed = int64(math.Round(float64(b.Need) / eff))
+	b.Bedtime = wake - float64(b.InBed)/3_600_000
+	return b, true
+}
+
+// SleepDebtMillis cumulates nightly shortfalls across days. Surplus sleep
+// pays debt down but debt never goes below zero.
+func SleepDebtMillis(days []fetch.DayData) int64 {
+	var debt int64
+	for _, d := range days {
+		short, ok := SleepShortfallMillis(d)
+		if !ok {
+			continue
+		}
+		debt += short
+		if debt < 0 {
+			debt = 0
+		}
+	}
+	return debt
+}
+
+// SleepDebt tracks nightly need against time asleep across days, as
+// SleepDebtMillis does, and suggests how to pay the remaining debt off.
+// ok is false when no night in days is scored.
+func SleepDebt(days []fetch.DayData) (debt fetch.SleepDebt, ok bool) {
+	for i, d := range days {
+		short, scored := SleepShortfallMillis(d)
+		if !scored {
+			continue
+		}
+		debt.Debt = max(debt.Debt+short, 0)
+		s := PrimarySleep(d.Sleeps)
+		debt.Nights = append(debt.Nights, fetch.SleepNight{
+			Date:   d.Date,
+			Need:   short + AsleepMillis(*s),
+			Asleep: AsleepMillis(*s),
+			Debt:   debt.Debt,
+		})
+		if i == len(days)-1 {
+			debt.Latest = &debt.Nights[len(debt.Nights)-1]
+		}
+	}
+	if len(debt.Nights) == 0 {
+		return debt, false
+	}
+	if debt.Debt >= minPayoffMillis {
+		debt.PayoffNights = min(int((debt.Debt+payoffMillis-1)/payoffMillis), maxPayoffNights)
+		debt.PayoffMillis = debt.Debt / int64(debt.PayoffNights)
+	}
+	return debt, true
+}
+
+// Balance classifications returned by Balance.
+const (
+	Overreached  = "overreached"
+	Balanced     = "balanced"
+	Undertrained = "undertrained"
+)
+
+// Balance classifies a day's strain against its recovery: Overreached for
+// strain of 14 or more on red recovery (under 34%), Undertrained for
+// strain under 10 on green recovery (67% or more), and Balanced otherwise.
+// It returns "" unless both recovery and strain are scored.
+func Balance(d fetch.DayData) string {
+	r := ScoredRecovery(d)
+	if r == nil || d.Cycle == nil || d.Cycle.ScoreState != "SCORED" {
+		return ""
+	}
+	score, strain := r.Score.RecoveryScore, d.Cycle.Score.Strain
+	switch {
+	case score < 34 && strain >= overreachedStrain:
+		return Overreached
+	case score >= 67 && strain < undertrainedStrain:
+		return Undertrained
+	}
+	return Balanced
+}
+
+// Schedule summarises when primary sleeps started, ended, and were
+// halfway through, in local hours from midnight (see Bedtime, WakeTime,
+// and Midpoint), with standard deviations in milliseconds.
+//
+// SocialJetlagMillis is how much later the average midpoint is on free
+// days (nights before a Saturday or Sunday wake-up) than on workdays; it is
+// zero unless both kinds of night are present. MidpointDriftMillis is the
+// least-squares change in midpoint per week; it is zero with fewer than
+// seven nights.
+type Schedule struct {
+	Nights              int
+	AvgBedtime          float64
+	AvgWake             float64
+	AvgMidpoint         float64
+	BedtimeStdMillis    int64
+	WakeStdMillis       int64
+	SocialJetlagMillis  int64
+	MidpointDriftMillis int64
+}
+
+// minDriftNights is the fewest nights a midpoint drift is computed from.
+const minDriftNights = 7
+
+// SleepSchedule computes the Schedule of the primary sleeps in days. ok is
+// false when no primary sleep has parseable times.
+func SleepSchedule(days []fetch.DayData) (s Schedule, ok bool) {
+	var beds, wakes, mids, free, work, dayNums []float64
+	for _, d := range days {
+		sl := PrimarySleep(d.Sleeps)
+		if sl == nil {
+			continue
+		}
+		bed, ok1 := Bedtime(*sl)
+		wake, ok2 := WakeTime(*sl)
+		mid, ok3 := Midpoint(*sl)
+		end, ok4 := LocalTime(sl.End, sl.TimezoneOffset)
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			continue
+		}
+		beds = append(beds, bed)
+		wakes = append(wakes, wake)
+		mids = append(mids, mid)
+		dayNums = append(dayNums, d.Date.Sub(days[0].Date).Hours()/24)
+		if wd := end.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			free = append(free, mid)
+		} else {
+			work = append(work, mid)
+		}
+	}
+	if len(beds) == 0 {
+		return s, false
+	}
+	var bedStd, wakeStd float64
+	s.Nights = len(beds)
+	s.AvgBedtime, bedStd = MeanStd(beds)
+	s.AvgWake, wakeStd = MeanStd(wakes)
+	s.AvgMidpoint, _ = MeanStd(mids)
+	s.BedtimeStdMillis = hoursToMillis(bedStd)
+	s.WakeStdMillis = hoursToMillis(wakeStd)
+	if len(free) > 0 && len(work) > 0 {
+		freeMid, _ := MeanStd(free)
+		workMid, _ := MeanStd(work)
+		s.SocialJetlagMillis = hoursToMillis(freeMid - workMid)
+	}
+	if len(mids) >= minDriftNights {
+		s.MidpointDriftMillis = hoursToMillis(slope(dayNums, mids) * 7)
+	}
+	return s, true
+}
+
+// slope returns the least-squares slope of ys against xs, or 0 when xs
+// are all equal.
+func slope(xs, ys []float64) float64 {
+	mx, _ := MeanStd(xs)
+	my, _ := MeanStd(ys)
+	var sxy, sxx float64
+	for i := range xs {
+		sxy += (xs[i] - mx) * (ys[i] - my)
+		sxx += (xs[i] - mx) * (xs[i] - mx)
+	}
+	if sxx == 0 {
+		return 0
+	}
+	return sxy / sxx
+}
+
+func hoursToMillis(h float64) int64 { return int64(math.Round(h * 3_600_000)) }
+
+// ACWR returns the acute:chronic workload ratio at the last day: mean day
+// strain over the last 7 days divided by mean day strain over the last 28.
+// ok is false when there is too little scored history.
+func ACWR(days []fetch.DayData) (ratio float64, ok bool) {
+	l, ok := TrainingLoad(days)
+	return l.Ratio, ok
+}
+
+// TrainingLoad returns the acute and chronic load at the last day and
+// their ratio, as ACWR does, flagging ratios above 1.5 as a spike.
+func TrainingLoad(days []fetch.DayData) (l fetch.TrainingLoad, ok bool) {
+	acute, acuteN := meanStrain(tail(days, acuteDays))
+	chronic, chronicN := meanStrain(tail(days, chronicDays))
+	if acuteN == 0 || chronicN < minChronicDays || chronic == 0 {
+		return l, false
+	}
+	l = fetch.TrainingLoad{Acute: acute, Chronic: chronic, Ratio: acute / chronic}
+	l.Spike = l.Ratio > spikeRatio
+	return l, true
+}
+
+// RollingAverages averages recovery, HRV, resting heart rate, day strain,
+// and primary sleep over days, skipping days without each metric. AvgSleep
+// is the time in bed.
+func RollingAverages(days []fetch.DayData) fetch.Rolling {
+	var r fetch.Rolling
+	var recovery, hrv, rhr []float64
+	var sleepTotal, asleepTotal int64
+	var sleepN int
+	for _, d := range days {
+		if d.Cycle != nil || d.Recovery != nil || len(d.Sleeps) > 0 {
+			r.Days++
+		}
+		if rec := ScoredRecovery(d); rec != nil {
+			recovery = append(recovery, rec.Score.RecoveryScore)
+			hrv = append(hrv, rec.Score.HrvRmssdMilli)
+			rhr = append(rhr, rec.Score.RestingHeartRate)
+		}
+		if s := PrimarySleep(d.Sleeps); s != nil && s.ScoreState == "SCORED" {
+			sleepTotal += s.Score.StageSummary.TotalInBedTimeMilli
+			asleepTotal += AsleepMillis(*s)
+			sleepN++
+		}
+	}
+	r.AvgRecovery, _ = MeanStd(recovery)
+	r.AvgHRV, _ = MeanStd(hrv)
+	r.AvgRHR, _ = MeanStd(rhr)
+	r.AvgStrain, _ = meanStrain(days)
+	if sleepN > 0 {
+		r.AvgInBed = sleepTotal / int64(sleepN)
+		r.AvgAsleep = asleepTotal / int64(sleepN)
+	}
+	r.AvgSleep = r.AvgInBed
+	return r
+}
+
+// HRVBaseline compares the last day's HRV with the up to window days before
+// it. Ties count as half below for the percentile. ok is false when the last
+// day is unscored, fewer than 14 earlier days are scored, or HRV never
+// varied.
+func HRVBaseline(days []fetch.DayData, window int) (b fetch.Baseline, ok bool) {
+	return baseline(days, window, func(d fetch.DayData) (float64, bool) {
+		if r := ScoredRecovery(d); r != nil {
+			return r.Score.HrvRmssdMilli, true
+		}
+		return 0, false
+	})
+}
+
+// RespiratoryBaseline compares the last night's respiratory rate with the
+// up to window nights before it, as HRVBaseline does for HRV. The baseline
+// is Flagged when the rate is more than threshold breaths per minute from
+// the mean.
+func RespiratoryBaseline(days []fetch.DayData, window int, threshold float64) (b fetch.Baseline, ok bool) {
+	b, ok = baseline(days, window, func(d fetch.DayData) (float64, bool) {
+		s := PrimarySleep(d.Sleeps)
+		if s == nil || s.ScoreState != "SCORED" || s.Score.RespiratoryRate == 0 {
+			return 0, false
+		}
+		return s.Score.RespiratoryRate, true
+	})
+	b.Flagged = ok && math.Abs(b.Value-b.Mean) > threshold
+	return b, ok
+}
+
+// SkinTempBaseline compares the last day's skin temperature with the up to
+// window days before it. The baseline is Flagged when the temperature is
+// more than SkinTempThreshold °C from the mean either way.
+func SkinTempBaseline(days []fetch.DayData, window int) (b fetch.Baseline, ok bool) {
+	b, ok = baseline(days, window, func(d fetch.DayData) (float64, bool) {
+		if r := ScoredRecovery(d); r != nil && r.Score.SkinTempCelsius != 0 {
+			return r.Score.SkinTempCelsius, true
+		}
+		return 0, false
+	})
+	b.Flagged = ok && math.Abs(b.Value-b.Mean) > SkinTempThreshold
+	return b, ok
+}
+
+// SpO2Baseline compares the last day's blood oxygen with the up to window
+// days before it. The baseline is Flagged when SpO2 is more than
+// SpO2Threshold points below the mean; a rise is never flagged.
+func SpO2Baseline(days []fetch.DayData, window int) (b fetch.Baseline, ok bool) {
+	b, ok = baseline(days, window, func(d fetch.DayData) (float64, bool) {
+		if r := ScoredRecovery(d); r != nil && r.Score.Spo2Percentage != 0 {
+			return r.Score.Spo2Percentage, true
+		}
+		return 0, false
+	})
+	b.Flagged = ok && b.Mean-b.Value > SpO2Threshold
+	return b, ok
+}
+
+// baseline places the last day's value, as read by value, against the up to
+// window days before it.
+func baseline(days []fetch.DayData, window int, value func(fetch.DayData) (float64, bool)) (b fetch.Baseline, ok bool) {
+	if len(days) == 0 {
+		return b, false
+	}
+	today, ok := value(days[len(days)-1])
+	if !ok {
+		return b, false
+	}
+	var vals []float64
+	for _, d := range tail(days[:len(days)-1], window) {
+		if v, ok := value(d); ok {
+			vals = append(vals, v)
+		}
+	}
+	if len(vals) < minBaselineDays {
+		return b, false
+	}
+	b.Days = len(vals)
+	b.Mean, b.Std = MeanStd(vals)
+	if b.Std == 0 {
+		return b, false
+	}
+	b.Value = today
+	b.Z = (b.Value - b.Mean) / b.Std
+	var below float64
+	for _, v := range vals {
+		switch {
+		case v < b.Value:
+			below++
+		case v == b.Value:
+			below += 0.5
+		}
+	}
+	b.Percentile = below / float64(len(vals)) * 100
+	return b, true
+}
+
+// Streaks computes the streaks running on the last of days. A date missing
+// from days breaks every streak, as does a day that does not qualify: green
+// needs a scored recovery of 67% or more, sleep a scored primary sleep of
+// seven hours asleep, and workout at least one workout.
+func Streaks(days []fetch.DayData) fetch.Streaks {
+	var s fetch.Streaks
+	var prev time.Time
+	for i, d := range days {
+		if i > 0 && !d.Date.Equal(prev.AddDate(0, 0, 1)) {
+			s.Green.Current, s.Sleep.Current, s.Workout.Current = 0, 0, 0
+		}
+		prev = d.Date
+		r := ScoredRecovery(d)
+		extend(&s.Green, r != nil && r.Score.RecoveryScore >= 67)
+		sl := PrimarySleep(d.Sleeps)
+		extend(&s.Sleep, sl != nil && sl.ScoreState == "SCORED" && AsleepMillis(*sl) >= streakSleepMillis)
+		extend(&s.Workout, len(d.Workouts) > 0)
+	}
+	return s
+}
+
+// extend continues s when ok and resets it otherwise.
+func extend(s *fetch.Streak, ok bool) {
+	if !ok {
+		s.Current = 0
+		return
+	}
+	s.Current++
+	s.Best = max(s.Best, s.Current)
+}
+
+// meanStrain returns the mean scored day strain and how many days had one.
+func meanStrain(days []fetch.DayData) (float64, int) {
+	var total float64
+	var n int
+	for _, d := range days {
+		if d.Cycle != nil && d.Cycle.ScoreState == "SCORED" {
+			total += d.Cycle.Score.Strain
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, 0
+	}
+	return total / float64(n), n
+}
+
+// tail returns the last n elements of days (or all of them).
+func tail(days []fetch.DayData, n int) []fetch.DayData {
+	if len(days) <= n {
+		return days
+	}
+	return days[len(days)-n:]
+}
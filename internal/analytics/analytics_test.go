@@ -0,0 +1,521 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/models"
+)
+
+// --- helpers for constructing test data ---
+
+func day(i int) time.Time { return time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i) }
+
+func withRHR(i int, rhr float64) fetch.DayData {
+	return fetch.DayData{
+		Date:     day(i),
+		Recovery: &models.Recovery{ScoreState: "SCORED", Score: models.RecoveryScore{RestingHeartRate: rhr}},
+	}
+}
+
+func withStrain(i int, strain float64) fetch.DayData {
+	return fetch.DayData{
+		Date:  day(i),
+		Cycle: &models.Cycle{ScoreState: "SCORED", Score: models.CycleScore{Strain: strain}},
+	}
+}
+
+func withSleep(i int, needMs, asleepMs int64) fetch.DayData {
+	return fetch.DayData{
+		Date: day(i),
+		Sleeps: []models.Sleep{{
+			ScoreState: "SCORED",
+			Score: models.SleepScore{
+				StageSummary: models.SleepStageSummary{TotalInBedTimeMilli: asleepMs},
+				SleepNeeded:  models.SleepNeeded{BaselineMillis: needMs},
+			},
+		}},
+	}
+}
+
+func TestAsleepMillis(t *testing.T) {
+	s := models.Sleep{Score: models.SleepScore{StageSummary: models.SleepStageSummary{
+		TotalInBedTimeMilli:  28_800_000,
+		TotalAwakeTimeMilli:  1_800_000,
+		TotalNoDataTimeMilli: 600_000,
+	}}}
+	if got := AsleepMillis(s); got != 26_400_000 {
+		t.Errorf("AsleepMillis = %d, want 26_400_000", got)
+	}
+}
+
+func TestMeanStd(t *testing.T) {
+	mean, std := MeanStd([]float64{2, 4, 4, 4, 5, 5, 7, 9})
+	if mean != 5 || std != 2 {
+		t.Errorf("MeanStd = %.2f, %.2f; want 5, 2", mean, std)
+	}
+	if m, s := MeanStd(nil); m != 0 || s != 0 {
+		t.Errorf("MeanStd(nil) = %.2f, %.2f; want 0, 0", m, s)
+	}
+}
+
+func TestRHRAboveBaseline(t *testing.T) {
+	var days []fetch.DayData
+	for i := 0; i < 10; i++ {
+		days = append(days, withRHR(i, 50))
+	}
+	days = append(days, withRHR(10, 56))
+
+	got, ok := RHRAboveBaseline(days, 30)
+	if !ok || got != 6 {
+		t.Errorf("RHRAboveBaseline = %.1f, %v; want 6, true", got, ok)
+	}
+	if _, ok := RHRAboveBaseline(days[5:], 30); ok {
+		t.Error("expected ok=false with fewer than 7 baseline days")
+	}
+}
+
+func TestSleepDebtMillis(t *testing.T) {
+	hour := int64(3_600_000)
+	days := []fetch.DayData{
+		withSleep(0, 8*hour, 6*hour), // +2h
+		withSleep(1, 8*hour, 7*hour), // +1h → 3h
+		withSleep(2, 8*hour, 9*hour), // -1h → 2h
+	}
+	if got := SleepDebtMillis(days); got != 2*hour {
+		t.Errorf("SleepDebtMillis = %d, want %d", got, 2*hour)
+	}
+
+	surplus := []fetch.DayData{withSleep(0, 8*hour, 10*hour), withSleep(1, 8*hour, 7*hour)}
+	if got := SleepDebtMillis(surplus); got != hour {
+		t.Errorf("debt should floor at zero before accruing, got %d, want %d", got, hour)
+	}
+}
+
+func TestSleepDebt(t *testing.T) {
+	hour := int64(3_600_000)
+	days := []fetch.DayData{
+		withSleep(0, 8*hour, 6*hour), // +2h
+		withSleep(1, 8*hour, 7*hour), // +1h → 3h
+		{Date: day(2)},
+		withSleep(3, 8*hour, 7*hour+30*60_000), // +30m → 3h 30m
+	}
+	got, ok := SleepDebt(days)
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	if len(got.Nights) != 3 || got.Debt != 3*hour+30*60_000 {
+		t.Fatalf("got %d nights, debt %d", len(got.Nights), got.Debt)
+	}
+	if got.Latest == nil || got.Latest.Need != 8*hour || got.Latest.Debt != got.Debt {
+		t.Errorf("Latest = %+v", got.Latest)
+	}
+	if got.PayoffNights != 4 || got.PayoffMillis != got.Debt/4 {
+		t.Errorf("payoff = %d nights of %d, want 4 of %d", got.PayoffNights, got.PayoffMillis, got.Debt/4)
+	}
+
+	got, _ = SleepDebt(days[:3])
+	if got.Latest != nil {
+		t.Error("Latest should be nil when the last day has no scored night")
+	}
+
+	got, _ = SleepDebt([]fetch.DayData{withSleep(0, 8*hour, 7*hour+40*60_000)})
+	if got.PayoffNights != 0 {
+		t.Errorf("no payoff expected under 30m of debt, got %d nights", got.PayoffNights)
+	}
+	if _, ok := SleepDebt(days[2:3]); ok {
+		t.Error("expected ok=false without scored nights")
+	}
+}
+
+func TestBedtime(t *testing.T) {
+	tests := []struct {
+		start, offset string
+		want          float64
+	}{
+		{"2026-02-10T04:30:00.000Z", "-05:00", -0.5},
+		{"2026-02-09T23:45:00.000Z", "+01:00", 0.75},
+		{"2026-02-09T22:00:00.000Z", "", -2},
+	}
+	for _, tt := range tests {
+		got, ok := Bedtime(models.Sleep{Start: tt.start, TimezoneOffset: tt.offset})
+		if !ok || got != tt.want {
+			t.Errorf("Bedtime(%s %s) = %v, %v; want %v", tt.start, tt.offset, got, ok, tt.want)
+		}
+	}
+}
+
+func TestSleepSchedule(t *testing.T) {
+	night := func(i int, start, end string) fetch.DayData {
+		return fetch.DayData{Date: day(i), Sleeps: []models.Sleep{{Start: start, End: end, TimezoneOffset: "-05:00"}}}
+	}
+	days := []fetch.DayData{
+		night(0, "2026-02-01T04:00:00Z", "2026-02-01T12:00:00Z"), // 23:00 → 07:00
+		night(1, "2026-02-02T05:00:00Z", "2026-02-02T12:00:00Z"), // 00:00 → 07:00
+		{Date: day(2)},
+	}
+	s, ok := SleepSchedule(days)
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	if s.Nights != 2 || s.AvgBedtime != -0.5 || s.AvgWake != 7 {
+		t.Errorf("schedule = %+v, want 2 nights, bedtime -0.5, wake 7", s)
+	}
+	if s.BedtimeStdMillis != 30*60_000 || s.WakeStdMillis != 0 {
+		t.Errorf("spread = %d / %d ms, want 30m / 0", s.BedtimeStdMillis, s.WakeStdMillis)
+	}
+	if s.AvgMidpoint != 3.25 {
+		t.Errorf("AvgMidpoint = %v, want 3.25", s.AvgMidpoint)
+	}
+	// The first night ends on a Sunday: its midpoint, 03:00, is half an
+	// hour earlier than Monday's.
+	if s.SocialJetlagMillis != -30*60_000 || s.MidpointDriftMillis != 0 {
+		t.Errorf("jetlag %d, drift %d; want -30m and none from two nights", s.SocialJetlagMillis, s.MidpointDriftMillis)
+	}
+	if _, ok := SleepSchedule(days[2:]); ok {
+		t.Error("expected ok=false without sleeps")
+	}
+}
+
+func TestSleepSchedule_JetlagAndDrift(t *testing.T) {
+	// 2026-02-02 is a Monday. Workday nights run 23:00 → 07:00 local
+	// (midpoint 03:00); free nights, waking Saturday and Sunday, run two
+	// hours later. Each week shifts everything 10 minutes later.
+	var days []fetch.DayData
+	for i := 0; i < 14; i++ {
+		date := time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i)
+		wake := date.Add(12 * time.Hour) // 07:00 at -05:00
+		if wd := date.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			wake = wake.Add(2 * time.Hour)
+		}
+		wake = wake.Add(time.Duration(i/7) * 10 * time.Minute)
+		days = append(days, fetch.DayData{Date: date, Sleeps: []models.Sleep{{
+			Start:          wake.Add(-8 * time.Hour).Format(time.RFC3339),
+			End:            wake.Format(time.RFC3339),
+			TimezoneOffset: "-05:00",
+		}}})
+	}
+	s, _ := SleepSchedule(days)
+	if s.SocialJetlagMillis != 2*3_600_000 {
+		t.Errorf("SocialJetlagMillis = %d, want 2h", s.SocialJetlagMillis)
+	}
+	if s.MidpointDriftMillis <= 0 {
+		t.Errorf("MidpointDriftMillis = %d, want a later drift", s.MidpointDriftMillis)
+	}
+}
+
+func TestACWR(t *testing.T) {
+	var days []fetch.DayData
+	for i := 0; i < 21; i++ {
+		days = append(days, withStrain(i, 10))
+	}
+	for i := 21; i < 28; i++ {
+		days = append(days, withStrain(i, 20))
+	}
+	ratio, ok := ACWR(days)
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	// acute 20, chronic (21*10 + 7*20)/28 = 12.5 → 1.6
+	if math.Abs(ratio-1.6) > 1e-9 {
+		t.Errorf("ACWR = %.3f, want 1.6", ratio)
+	}
+
+	if _, ok := ACWR(days[:10]); ok {
+		t.Error("expected ok=false with under 14 chronic days")
+	}
+}
+
+func TestTrainingLoad(t *testing.T) {
+	var days []fetch.DayData
+	for i := 0; i < 21; i++ {
+		days = append(days, withStrain(i, 10))
+	}
+	for i := 21; i < 28; i++ {
+		days = append(days, withStrain(i, 20))
+	}
+	l, ok := TrainingLoad(days)
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	if l.Acute != 20 || l.Chronic != 12.5 || !l.Spike {
+		t.Errorf("TrainingLoad = %+v, want acute 20, chronic 12.5, spike", l)
+	}
+
+	for i := 21; i < 28; i++ {
+		days[i] = withStrain(i, 15)
+	}
+	if l, _ := TrainingLoad(days); l.Spike {
+		t.Errorf("ratio %.2f should not be a spike", l.Ratio)
+	}
+}
+
+func TestBalance(t *testing.T) {
+	tests := []struct {
+		recovery, strain float64
+		want             string
+	}{
+		{20, 15, Overreached},
+		{20, 13.9, Balanced},
+		{50, 19, Balanced},
+		{80, 8, Undertrained},
+		{80, 10, Balanced},
+		{66, 5, Balanced},
+	}
+	for _, tt := range tests {
+		d := withStrain(0, tt.strain)
+		d.Recovery = &models.Recovery{ScoreState: "SCORED", Score: models.RecoveryScore{RecoveryScore: tt.recovery}}
+		if got := Balance(d); got != tt.want {
+			t.Errorf("Balance(recovery %.0f, strain %.1f) = %q, want %q", tt.recovery, tt.strain, got, tt.want)
+		}
+	}
+	if got := Balance(withStrain(0, 15)); got != "" {
+		t.Errorf("Balance without recovery = %q, want empty", got)
+	}
+}
+
+func TestRollingAverages(t *testing.T) {
+	days := []fetch.DayData{
+		withRHR(0, 50),
+		{Date: day(1)}, // no data
+		withRHR(2, 56),
+		withStrain(3, 12),
+		withSleep(4, 0, 7*3_600_000),
+		withSleep(5, 0, 8*3_600_000),
+	}
+	days[0].Recovery.Score.RecoveryScore = 60
+	days[2].Recovery.Score.RecoveryScore = 70
+	days[2].Recovery.Score.HrvRmssdMilli = 44
+	days[5].Sleeps[0].Score.StageSummary.TotalAwakeTimeMilli = 3_600_000
+
+	got := RollingAverages(days)
+	want := fetch.Rolling{Days: 5, AvgRecovery: 65, AvgHRV: 22, AvgRHR: 53, AvgStrain: 12, AvgSleep: 7.5 * 3_600_000, AvgInBed: 7.5 * 3_600_000, AvgAsleep: 7 * 3_600_000}
+	if got != want {
+		t.Errorf("RollingAverages = %+v, want %+v", got, want)
+	}
+	if got := RollingAverages(nil); got != (fetch.Rolling{}) {
+		t.Errorf("RollingAverages(nil) = %+v, want zero", got)
+	}
+}
+
+func TestHRVBaseline(t *testing.T) {
+	withHRV := func(i int, hrv float64) fetch.DayData {
+		d := withRHR(i, 55)
+		d.Recovery.Score.HrvRmssdMilli = hrv
+		return d
+	}
+	var days []fetch.DayData
+	for i := 0; i < 20; i++ {
+		days = append(days, withHRV(i, float64(40+i%2*10))) // alternating 40, 50
+	}
+	days = append(days, withHRV(20, 36))
+
+	b, ok := HRVBaseline(days, 60)
+	if !ok {
+		t.Fatal("want a baseline from 20 scored days")
+	}
+	if b.Days != 20 || b.Mean != 45 || b.Std != 5 || b.Value != 36 {
+		t.Errorf("baseline = %+v, want 20 days, mean 45, std 5, value 36", b)
+	}
+	if math.Abs(b.Z-(-1.8)) > 1e-9 || b.Percentile != 0 {
+		t.Errorf("Z = %v, Percentile = %v; want -1.8, 0", b.Z, b.Percentile)
+	}
+
+	// Ties count half: 45 sits above the ten 40s and below the ten 50s.
+	days[20] = withHRV(20, 45)
+	if b, _ := HRVBaseline(days, 60); b.Percentile != 50 {
+		t.Errorf("Percentile = %v, want 50", b.Percentile)
+	}
+	if _, ok := HRVBaseline(days, 10); ok {
+		t.Error("a 10-day window has too few days for a baseline")
+	}
+	days[20] = fetch.DayData{Date: day(20)}
+	if _, ok := HRVBaseline(days, 60); ok {
+		t.Error("an unscored last day has no baseline comparison")
+	}
+}
+
+func TestRespiratoryBaseline(t *testing.T) {
+	withRate := func(i int, rpm float64) fetch.DayData {
+		d := withSleep(i, 0, 0)
+		d.Sleeps[0].Score.RespiratoryRate = rpm
+		return d
+	}
+	var days []fetch.DayData
+	for i := 0; i < 30; i++ {
+		days = append(days, withRate(i, 14+float64(i%2))) // alternating 14, 15
+	}
+	days = append(days, withRate(30, 16.2))
+
+	b, ok := RespiratoryBaseline(days, 30, 1)
+	if !ok {
+		t.Fatal("want a baseline from 30 scored nights")
+	}
+	if b.Days != 30 || b.Mean != 14.5 || b.Value != 16.2 || !b.Flagged {
+		t.Errorf("baseline = %+v, want 30 nights, mean 14.5, value 16.2, flagged", b)
+	}
+	if b, _ := RespiratoryBaseline(days, 30, 2); b.Flagged {
+		t.Error("a 1.7 rpm shift should not be flagged at a 2 rpm threshold")
+	}
+	days[30] = withRate(30, 0)
+	if _, ok := RespiratoryBaseline(days, 30, 1); ok {
+		t.Error("a night without a rate has no baseline comparison")
+	}
+}
+
+func TestScoredRecovery_Calibrating(t *testing.T) {
+	d := withRHR(0, 55)
+	if ScoredRecovery(d) == nil || Calibrating(d) {
+		t.Error("a scored day should count")
+	}
+	d.Recovery.Score.UserCalibrating = true
+	if ScoredRecovery(d) != nil || !Calibrating(d) {
+		t.Error("a calibrating day should be left out")
+	}
+}
+
+func TestVitalsBaselines(t *testing.T) {
+	withVitals := func(i int, temp, spo2 float64) fetch.DayData {
+		d := withRHR(i, 55)
+		d.Recovery.Score.SkinTempCelsius = temp
+		d.Recovery.Score.Spo2Percentage = spo2
+		return d
+	}
+	var days []fetch.DayData
+	for i := 0; i < 20; i++ {
+		days = append(days, withVitals(i, 33.5+float64(i%2)*0.2, 96+float64(i%2))) // 33.5/33.7 °C, 96/97%
+	}
+	days = append(days, withVitals(20, 34.8, 97.5))
+
+	temp, ok := SkinTempBaseline(days, 30)
+	if !ok || temp.Days != 20 || !temp.Flagged {
+		t.Errorf("SkinTemp = %+v, %v; want 20 days, flagged", temp, ok)
+	}
+	spo2, ok := SpO2Baseline(days, 30)
+	if !ok || spo2.Flagged {
+		t.Errorf("SpO2 = %+v, %v; a rise should not be flagged", spo2, ok)
+	}
+
+	days[20] = withVitals(20, 33.9, 92)
+	if b, _ := SkinTempBaseline(days, 30); b.Flagged {
+		t.Errorf("SkinTemp %+v should be within the threshold", b)
+	}
+	if b, _ := SpO2Baseline(days, 30); !b.Flagged {
+		t.Errorf("SpO2 %+v should be flagged", b)
+	}
+	days[20] = withVitals(20, 0, 0)
+	if _, ok := SpO2Baseline(days, 30); ok {
+		t.Error("a day without SpO2 has no baseline comparison")
+	}
+}
+
+func TestStreaks(t *testing.T) {
+	green := func(i int) fetch.DayData {
+		d := withRHR(i, 55)
+		d.Recovery.Score.RecoveryScore = 80
+		d.Workouts = []models.Workout{{}}
+		return d
+	}
+	days := []fetch.DayData{
+		green(0), green(1), green(2),
+		withRHR(3, 55), // red, no workout
+		green(4),
+		// day 5 is missing
+		green(6), green(7),
+	}
+	days[3].Workouts = []models.Workout{{}}
+	for _, i := range []int{6, 7} {
+		days[i-1].Sleeps = withSleep(i, 0, 7*3_600_000).Sleeps
+	}
+
+	got := Streaks(days)
+	want := fetch.Streaks{
+		Green:   fetch.Streak{Current: 2, Best: 3},
+		Sleep:   fetch.Streak{Current: 2, Best: 2},
+		Workout: fetch.Streak{Current: 2, Best: 5},
+	}
+	if got != want {
+		t.Errorf("Streaks = %+v, want %+v", got, want)
+	}
+	if got := Streaks(days[:4]); got.Green.Current != 0 || got.Green.Best != 3 {
+		t.Errorf("after a red day: Green = %+v, want current 0, best 3", got.Green)
+	}
+}
+
+func TestNightSleepNeed(t *testing.T) {
+	s := models.Sleep{ScoreState: "SCORED", Score: models.SleepScore{
+		SleepNeeded: models.SleepNeeded{
+			BaselineMillis:             27_000_000,
+			NeedFromSleepDebtMillis:    1_800_000,
+			NeedFromRecentStrainMillis: 900_000,
+			NeedFromRecentNapMillis:    -900_000,
+		},
+		StageSummary: models.SleepStageSummary{TotalInBedTimeMilli: 27_900_000, TotalAwakeTimeMilli: 1_800_000},
+	}}
+	n, ok := NightSleepNeed(s)
+	if !ok {
+		t.Fatal("want a need")
+	}
+	want := fetch.SleepNeed{Nights: 1, Baseline: 27_000_000, Debt: 1_800_000, Strain: 900_000, Nap: -900_000, Need: 28_800_000, Asleep: 26_100_000, Fulfillment: 90.625}
+	if n != want {
+		t.Errorf("NightSleepNeed = %+v, want %+v", n, want)
+	}
+	if _, ok := NightSleepNeed(models.Sleep{ScoreState: "SCORED"}); ok {
+		t.Error("want no need when WHOOP computed none")
+	}
+}
+
+func TestAvgSleepNeed(t *testing.T) {
+	night := func(i int, need, asleep int64) fetch.DayData {
+		return fetch.DayData{Date: day(i), Sleeps: []models.Sleep{{ScoreState: "SCORED", Score: models.SleepScore{
+			SleepNeeded:  models.SleepNeeded{BaselineMillis: need},
+			StageSummary: models.SleepStageSummary{TotalInBedTimeMilli: asleep},
+		}}}}
+	}
+	days := []fetch.DayData{night(0, 8*3_600_000, 8*3_600_000), {Date: day(1)}, night(2, 8*3_600_000, 6*3_600_000)}
+	avg, ok := AvgSleepNeed(days)
+	if !ok {
+		t.Fatal("want an average")
+	}
+	if avg.Nights != 2 || avg.Need != 8*3_600_000 || avg.Asleep != 7*3_600_000 || avg.Fulfillment != 87.5 {
+		t.Errorf("AvgSleepNeed = %+v", avg)
+	}
+	if _, ok := AvgSleepNeed(days[1:2]); ok {
+		t.Error("want no average without a need")
+	}
+}
+
+func TestRecommendBedtime(t *testing.T) {
+	night := func(i int, need int64) fetch.DayData {
+		return fetch.DayData{Date: day(i), Sleeps: []models.Sleep{{
+			Start: "2026-02-01T04:00:00Z", End: "2026-02-01T12:00:00Z", TimezoneOffset: "-05:00", // 23:00 → 07:00
+			ScoreState: "SCORED",
+			Score: models.SleepScore{
+				SleepNeeded:  models.SleepNeeded{BaselineMillis: need},
+				StageSummary: models.SleepStageSummary{TotalInBedTimeMilli: 28_800_000, TotalAwakeTimeMilli: 2_880_000},
+			},
+		}}}
+	}
+	// Both nights are slept at 90% efficiency; the latest needs 8h, which
+	// takes 8h 53m 20s in bed.
+	days := []fetch.DayData{night(0, 25_200_000), night(1, 28_800_000), {Date: day(2)}}
+	b, ok := RecommendBedtime(days, 6.5)
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	if b.Need != 28_800_000 || b.InBed != 32_000_000 || math.Abs(b.Efficiency-90) > 1e-9 {
+		t.Errorf("bedtime = %+v, want need 8h, 8h 53m 20s in bed at 90%%", b)
+	}
+	if math.Abs(b.Bedtime-(6.5-32_000_000.0/3_600_000)) > 1e-9 || b.Wake != 6.5 {
+		t.Errorf("bedtime %v for wake %v, want 21:36:40 for 06:30", b.Bedtime, b.Wake)
+	}
+	b, _ = RecommendBedtime(days, -1)
+	if b.Wake != 7 {
+		t.Errorf("Wake = %v, want the average wake time, 7", b.Wake)
+	}
+	if _, ok := RecommendBedtime(days[2:], 6.5); ok {
+		t.Error("expected ok=false without a need")
+	}
+}
@@ -0,0 +1,176 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+)
+
+// minCorrelationPoints is the fewest pairs a correlation is reported for.
+const minCorrelationPoints = 7
+
+// Point is one (x, y) observation of a correlation.
+type Point struct {
+	X, Y float64
+}
+
+// Correlation is the Pearson correlation between two daily variables.
+type Correlation struct {
+	X, Y   string // variable names with units, e.g. "Sleep (h)"
+	R      float64
+	Points []Point
+}
+
+// Strength describes |R|: "strong" from 0.5, "moderate" from 0.3, "weak"
+// from 0.1, and "none" below.
+func (c Correlation) Strength() string {
+	r := math.Abs(c.R)
+	switch {
+	case r >= 0.5:
+		return "strong"
+	case r >= 0.3:
+		return "moderate"
+	case r >= 0.1:
+		return "weak"
+	}
+	return "none"
+}
+
+// Direction is "positive" or "negative" by the sign of R.
+func (c Correlation) Direction() string {
+	if c.R < 0 {
+		return "negative"
+	}
+	return "positive"
+}
+
+// variable extracts one value from a day; ok is false when it is missing.
+type variable struct {
+	name  string
+	value func(fetch.DayData) (float64, bool)
+}
+
+var (
+	sleepHours = variable{"Sleep (h)", func(d fetch.DayData) (float64, bool) {
+		s := PrimarySleep(d.Sleeps)
+		if s == nil || s.ScoreState != "SCORED" {
+			return 0, false
+		}
+		return float64(AsleepMillis(*s)) / 3_600_000, true
+	}}
+	bedtime = variable{"Bedtime (h from midnight)", func(d fetch.DayData) (float64, bool) {
+		s := PrimarySleep(d.Sleeps)
+		if s == nil {
+			return 0, false
+		}
+		return Bedtime(*s)
+	}}
+	strain = variable{"Day strain", func(d fetch.DayData) (float64, bool) {
+		if d.Cycle == nil || d.Cycle.ScoreState != "SCORED" {
+			return 0, false
+		}
+		return d.Cycle.Score.Strain, true
+	}}
+	recovery = variable{"Recovery (%)", func(d fetch.DayData) (float64, bool) {
+		r := ScoredRecovery(d)
+		if r == nil {
+			return 0, false
+		}
+		return r.Score.RecoveryScore, true
+	}}
+	hrv = variable{"HRV (ms)", func(d fetch.DayData) (float64, bool) {
+		r := ScoredRecovery(d)
+		if r == nil {
+			return 0, false
+		}
+		return r.Score.HrvRmssdMilli, true
+	}}
+	rhr = variable{"RHR (bpm)", func(d fetch.DayData) (float64, bool) {
+		r := ScoredRecovery(d)
+		if r == nil {
+			return 0, false
+		}
+		return r.Score.RestingHeartRate, true
+	}}
+)
+
+// correlationPairs are the relationships Correlations looks at. A day's
+// sleep is the night before its recovery, so sleep pairs with the same
+// day; strain pairs with the next day's recovery, named by nextDay.
+var correlationPairs = []struct {
+	x, y    variable
+	nextDay string
+}{
+	{sleepHours, recovery, ""},
+	{sleepHours, hrv, ""},
+	{bedtime, hrv, ""},
+	{bedtime, recovery, ""},
+	{strain, recovery, "Next-day recovery (%)"},
+	{strain, hrv, "Next-day HRV (ms)"},
+	{strain, rhr, "Next-day RHR (bpm)"},
+}
+
+// Correlations computes each relationship in correlationPairs across days,
+// strongest first. Relationships with fewer than seven pairs, or with a
+// constant variable, are left out.
+func Correlations(days []fetch.DayData) []Correlation {
+	var out []Correlation
+	for _, p := range correlationPairs {
+		c := Correlation{X: p.x.name, Y: p.y.name}
+		if p.nextDay != "" {
+			c.Y = p.nextDay
+		}
+		for i, d := range days {
+			yd := d
+			if p.nextDay != "" {
+				if i+1 == len(days) || !days[i+1].Date.Equal(d.Date.AddDate(0, 0, 1)) {
+					continue
+				}
+				yd = days[i+1]
+			}
+			x, okX := p.x.value(d)
+			y, okY := p.y.value(yd)
+			if okX && okY {
+				c.Points = append(c.Points, Point{x, y})
+			}
+		}
+		if len(c.Points) < minCorrelationPoints {
+			continue
+		}
+		r, ok := Pearson(c.Points)
+		if !ok {
+			continue
+		}
+		c.R = r
+		out = append(out, c)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return math.Abs(out[i].R) > math.Abs(out[j].R) })
+	return out
+}
+
+// Pearson returns the Pearson correlation coefficient of points. ok is
+// false with fewer than two points or when either variable is constant.
+func Pearson(points []Point) (r float64, ok bool) {
+	if len(points) < 2 {
+		return 0, false
+	}
+	var mx, my float64
+	for _, p := range points {
+		mx += p.X
+		my += p.Y
+	}
+	mx /= float64(len(points))
+	my /= float64(len(points))
+	var sxy, sxx, syy float64
+	for _, p := range points {
+		dx, dy := p.X-mx, p.Y-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return 0, false
+	}
+	return sxy / math.Sqrt(sxx*syy), true
+}
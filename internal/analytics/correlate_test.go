@@ -0,0 +1,62 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/models"
+)
+
+func TestPearson(t *testing.T) {
+	r, ok := Pearson([]Point{{1, 2}, {2, 4}, {3, 6}})
+	if !ok || math.Abs(r-1) > 1e-9 {
+		t.Errorf("Pearson = %v, %v; want 1", r, ok)
+	}
+	r, _ = Pearson([]Point{{1, 3}, {2, 2}, {3, 1}})
+	if math.Abs(r+1) > 1e-9 {
+		t.Errorf("Pearson = %v, want -1", r)
+	}
+	if _, ok := Pearson([]Point{{1, 2}, {2, 2}, {3, 2}}); ok {
+		t.Error("expected ok=false for a constant variable")
+	}
+}
+
+func TestCorrelations(t *testing.T) {
+	var days []fetch.DayData
+	for i := 0; i < 10; i++ {
+		d := withStrain(i, float64(8+i))
+		// Higher strain, lower recovery the next day.
+		d.Recovery = &models.Recovery{ScoreState: "SCORED", Score: models.RecoveryScore{
+			RecoveryScore: 90 - float64(i*5), HrvRmssdMilli: 50, RestingHeartRate: 50 + float64(i%3),
+		}}
+		days = append(days, d)
+	}
+	got := Correlations(days)
+	if len(got) == 0 {
+		t.Fatal("no correlations")
+	}
+	top := got[0]
+	if top.X != "Day strain" || top.Y != "Next-day recovery (%)" {
+		t.Errorf("strongest = %s vs %s", top.X, top.Y)
+	}
+	if len(top.Points) != 9 || math.Abs(top.R+1) > 1e-9 {
+		t.Errorf("got %d points, r = %v; want 9, -1", len(top.Points), top.R)
+	}
+	if top.Strength() != "strong" || top.Direction() != "negative" {
+		t.Errorf("described as %s %s", top.Strength(), top.Direction())
+	}
+	for _, c := range got {
+		if c.Y == "Next-day HRV (ms)" {
+			t.Error("constant HRV should be left out")
+		}
+	}
+
+	// A gap in the dates breaks next-day pairs.
+	gapped := append(days[:5:5], days[6:]...)
+	for _, c := range Correlations(gapped) {
+		if c.Y == "Next-day recovery (%)" && len(c.Points) != 7 {
+			t.Errorf("got %d next-day points across a gap, want 7", len(c.Points))
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/models"
+)
+
+// Record is an all-time best: the day it was set and its value. Durations
+// are in Millis; Workout is set for single-workout records.
+type Record struct {
+	Date    time.Time
+	Value   float64
+	Millis  int64
+	Workout *models.Workout
+}
+
+// Records are the all-time bests across a history of days. Each is nil
+// when no day has the metric. WeekTraining's Date is the Monday of the
+// ISO week with the most workout time.
+type Records struct {
+	Days          int // days with any data
+	DayStrain     *Record
+	Sleep         *Record // most time asleep in a primary sleep
+	HRV           *Record
+	RHR           *Record // lowest
+	WorkoutStrain *Record
+	WorkoutTime   *Record
+	WeekTraining  *Record
+}
+
+// ComputeRecords finds the all-time bests in days. When a record is tied,
+// the earlier day keeps it.
+func ComputeRecords(days []fetch.DayData) Records {
+	var r Records
+	weeks := map[time.Time]int64{}
+	better := func(rec *Record, v float64) bool { return rec == nil || v > rec.Value }
+	for _, d := range days {
+		if d.Cycle == nil && d.Recovery == nil && len(d.Sleeps) == 0 {
+			continue
+		}
+		r.Days++
+		if d.Cycle != nil && d.Cycle.ScoreState == "SCORED" && better(r.DayStrain, d.Cycle.Score.Strain) {
+			r.DayStrain = &Record{Date: d.Date, Value: d.Cycle.Score.Strain}
+		}
+		if s := PrimarySleep(d.Sleeps); s != nil && s.ScoreState == "SCORED" {
+			if ms := AsleepMillis(*s); r.Sleep == nil || ms > r.Sleep.Millis {
+				r.Sleep = &Record{Date: d.Date, Millis: ms}
+			}
+		}
+		if rec := ScoredRecovery(d); rec != nil {
+			if better(r.HRV, rec.Score.HrvRmssdMilli) {
+				r.HRV = &Record{Date: d.Date, Value: rec.Score.HrvRmssdMilli}
+			}
+			if rhr := rec.Score.RestingHeartRate; rhr > 0 && (r.RHR == nil || rhr < r.RHR.Value) {
+				r.RHR = &Record{Date: d.Date, Value: rhr}
+			}
+		}
+		monday := d.Date.AddDate(0, 0, -(int(d.Date.Weekday())+6)%7)
+		for i := range d.Workouts {
+			w := &d.Workouts[i]
+			if better(r.WorkoutStrain, w.Score.Strain) {
+				r.WorkoutStrain = &Record{Date: d.Date, Value: w.Score.Strain, Workout: w}
+			}
+			ms := WorkoutMillis(*w)
+			if ms > 0 && (r.WorkoutTime == nil || ms > r.WorkoutTime.Millis) {
+				r.WorkoutTime = &Record{Date: d.Date, Millis: ms, Workout: w}
+			}
+			weeks[monday] += ms
+		}
+	}
+	for monday, ms := range weeks {
+		best := r.WeekTraining
+		if ms > 0 && (best == nil || ms > best.Millis || ms == best.Millis && monday.Before(best.Date)) {
+			r.WeekTraining = &Record{Date: monday, Millis: ms}
+		}
+	}
+	return r
+}
+
+// WorkoutMillis returns a workout's duration, or 0 when its times do not
+// parse.
+func WorkoutMillis(w models.Workout) int64 {
+	start, err1 := fetch.ParseWhoopTime(w.Start)
+	end, err2 := fetch.ParseWhoopTime(w.End)
+	if err1 != nil || err2 != nil || !end.After(start) {
+		return 0
+	}
+	return end.Sub(start).Milliseconds()
+}
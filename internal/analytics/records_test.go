@@ -0,0 +1,53 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/models"
+)
+
+func workout(sport string, strain float64, start, end string) models.Workout {
+	return models.Workout{SportName: sport, Start: start, End: end, Score: models.WorkoutScore{Strain: strain}}
+}
+
+func TestComputeRecords(t *testing.T) {
+	hour := int64(3_600_000)
+	d0 := withStrain(0, 15) // Sunday 2026-02-01
+	d0.Recovery = &models.Recovery{ScoreState: "SCORED", Score: models.RecoveryScore{HrvRmssdMilli: 60, RestingHeartRate: 52}}
+	d0.Workouts = []models.Workout{workout("Running", 12, "2026-02-01T07:00:00Z", "2026-02-01T08:30:00Z")}
+	d1 := withSleep(1, 8*hour, 9*hour) // Monday, a new ISO week
+	d1.Cycle = withStrain(1, 15).Cycle
+	d1.Recovery = &models.Recovery{ScoreState: "SCORED", Score: models.RecoveryScore{HrvRmssdMilli: 55, RestingHeartRate: 49}}
+	d1.Workouts = []models.Workout{
+		workout("Cycling", 14, "2026-02-02T07:00:00Z", "2026-02-02T08:00:00Z"),
+		workout("Yoga", 4, "2026-02-02T18:00:00Z", "2026-02-02T19:00:00Z"),
+	}
+	r := ComputeRecords([]fetch.DayData{d0, d1, {Date: day(2)}})
+
+	if r.Days != 2 {
+		t.Errorf("Days = %d, want 2", r.Days)
+	}
+	if r.DayStrain == nil || !r.DayStrain.Date.Equal(day(0)) {
+		t.Errorf("DayStrain = %+v, want the earlier of the tied days", r.DayStrain)
+	}
+	if r.Sleep == nil || r.Sleep.Millis != 9*hour {
+		t.Errorf("Sleep = %+v", r.Sleep)
+	}
+	if r.HRV == nil || r.HRV.Value != 60 || r.RHR == nil || r.RHR.Value != 49 {
+		t.Errorf("HRV = %+v, RHR = %+v", r.HRV, r.RHR)
+	}
+	if r.WorkoutStrain == nil || r.WorkoutStrain.Workout.SportName != "Cycling" {
+		t.Errorf("WorkoutStrain = %+v", r.WorkoutStrain)
+	}
+	if r.WorkoutTime == nil || r.WorkoutTime.Workout.SportName != "Running" || r.WorkoutTime.Millis != 90*60_000 {
+		t.Errorf("WorkoutTime = %+v", r.WorkoutTime)
+	}
+	if r.WeekTraining == nil || !r.WeekTraining.Date.Equal(day(1)) || r.WeekTraining.Millis != 2*hour {
+		t.Errorf("WeekTraining = %+v, want 2h in the week of %s", r.WeekTraining, day(1).Format("2006-01-02"))
+	}
+
+	if r := ComputeRecords(nil); r.DayStrain != nil || r.WeekTraining != nil {
+		t.Errorf("records from no days: %+v", r)
+	}
+}
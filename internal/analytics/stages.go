@@ -0,0 +1,58 @@
+package analytics
+
+import (
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/models"
+)
+
+// Stages is how a night divides among sleep stages, as percentages of the
+// time in bed. Time without data makes the four sum to less than 100.
+type Stages struct {
+	Light float64
+	Deep  float64 // slow wave sleep
+	REM   float64
+	Awake float64
+}
+
+// SleepStages returns the stage percentages of s, or false when it is
+// unscored or has no time in bed.
+func SleepStages(s models.Sleep) (Stages, bool) {
+	ss := s.Score.StageSummary
+	if s.ScoreState != "SCORED" || ss.TotalInBedTimeMilli <= 0 {
+		return Stages{}, false
+	}
+	pct := func(ms int64) float64 { return float64(ms) / float64(ss.TotalInBedTimeMilli) * 100 }
+	return Stages{
+		Light: pct(ss.TotalLightSleepTimeMilli),
+		Deep:  pct(ss.TotalSlowWaveSleepTimeMilli),
+		REM:   pct(ss.TotalRemSleepTimeMilli),
+		Awake: pct(ss.TotalAwakeTimeMilli),
+	}, true
+}
+
+// AvgStages averages the stage percentages of each day's primary sleep,
+// or returns false when no night is scored.
+func AvgStages(days []fetch.DayData) (Stages, bool) {
+	var sum Stages
+	n := 0
+	for _, d := range days {
+		s := PrimarySleep(d.Sleeps)
+		if s == nil {
+			continue
+		}
+		st, ok := SleepStages(*s)
+		if !ok {
+			continue
+		}
+		sum.Light += st.Light
+		sum.Deep += st.Deep
+		sum.REM += st.REM
+		sum.Awake += st.Awake
+		n++
+	}
+	if n == 0 {
+		return Stages{}, false
+	}
+	f := float64(n)
+	return Stages{Light: sum.Light / f, Deep: sum.Deep / f, REM: sum.REM / f, Awake: sum.Awake / f}, true
+}
@@ -0,0 +1,56 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/models"
+)
+
+func stagedSleep(inBed, light, deep, rem, awake int64) models.Sleep {
+	return models.Sleep{ScoreState: "SCORED", Score: models.SleepScore{StageSummary: models.SleepStageSummary{
+		TotalInBedTimeMilli: inBed, TotalLightSleepTimeMilli: light, TotalSlowWaveSleepTimeMilli: deep,
+		TotalRemSleepTimeMilli: rem, TotalAwakeTimeMilli: awake,
+	}}}
+}
+
+func TestSleepStages(t *testing.T) {
+	st, ok := SleepStages(stagedSleep(1000, 450, 250, 200, 100))
+	if !ok {
+		t.Fatal("want stages for a scored sleep")
+	}
+	if st != (Stages{Light: 45, Deep: 25, REM: 20, Awake: 10}) {
+		t.Errorf("got %+v", st)
+	}
+	if _, ok := SleepStages(stagedSleep(0, 0, 0, 0, 0)); ok {
+		t.Error("want no stages without time in bed")
+	}
+	if _, ok := SleepStages(models.Sleep{ScoreState: "PENDING_SCORE"}); ok {
+		t.Error("want no stages for an unscored sleep")
+	}
+}
+
+func TestAvgStages(t *testing.T) {
+	nap := stagedSleep(100, 0, 100, 0, 0)
+	nap.Nap = true
+	days := []fetch.DayData{
+		{Sleeps: []models.Sleep{stagedSleep(1000, 500, 200, 200, 100), nap}},
+		{Sleeps: []models.Sleep{stagedSleep(1000, 400, 300, 200, 100)}},
+		{},
+	}
+	st, ok := AvgStages(days)
+	if !ok {
+		t.Fatal("want average stages")
+	}
+	want := Stages{Light: 45, Deep: 25, REM: 20, Awake: 10}
+	for _, c := range []struct{ got, want float64 }{{st.Light, want.Light}, {st.Deep, want.Deep}, {st.REM, want.REM}, {st.Awake, want.Awake}} {
+		if math.Abs(c.got-c.want) > 1e-9 {
+			t.Errorf("got %+v, want %+v", st, want)
+			break
+		}
+	}
+	if _, ok := AvgStages(days[2:]); ok {
+		t.Error("want no average without a scored night")
+	}
+}
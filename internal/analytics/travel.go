@@ -0,0 +1,74 @@
+package analytics
+
+import (
+	"math"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+)
+
+const (
+	// jetlagHours is the smallest time zone shift whose following days are
+	// left out of baselines.
+	jetlagHours = 2
+
+	// jetlagDays is how many days, from the day of a shift, WithoutJetlag
+	// leaves out.
+	jetlagDays = 3
+)
+
+// TimezoneShift returns the time zone change from prev's cycle to d's. ok
+// is false unless d is the day after prev, both cycles have an offset, and
+// the offsets differ.
+func TimezoneShift(prev, d fetch.DayData) (s fetch.Shift, ok bool) {
+	if prev.Cycle == nil || d.Cycle == nil || !d.Date.Equal(prev.Date.AddDate(0, 0, 1)) {
+		return s, false
+	}
+	from, ok1 := offsetHours(prev.Cycle.TimezoneOffset)
+	to, ok2 := offsetHours(d.Cycle.TimezoneOffset)
+	if !ok1 || !ok2 || from == to {
+		return s, false
+	}
+	return fetch.Shift{Date: d.Date, From: prev.Cycle.TimezoneOffset, To: d.Cycle.TimezoneOffset, Hours: to - from}, true
+}
+
+// TimezoneShifts returns every time zone change between consecutive days.
+func TimezoneShifts(days []fetch.DayData) []fetch.Shift {
+	var out []fetch.Shift
+	for i := 1; i < len(days); i++ {
+		if s, ok := TimezoneShift(days[i-1], days[i]); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// WithoutJetlag returns days without those distorted by jetlag: the day of
+// a shift of two hours or more and the two days after it. The last day is
+// always kept, since it is the one compared with a baseline.
+func WithoutJetlag(days []fetch.DayData) []fetch.DayData {
+	var until time.Time
+	out := make([]fetch.DayData, 0, len(days))
+	for i, d := range days {
+		if i > 0 {
+			if s, ok := TimezoneShift(days[i-1], d); ok && math.Abs(s.Hours) >= jetlagHours {
+				until = d.Date.AddDate(0, 0, jetlagDays)
+			}
+		}
+		if d.Date.Before(until) && i < len(days)-1 {
+			continue
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// offsetHours parses a UTC offset such as "-05:00" or "Z" into hours.
+func offsetHours(offset string) (float64, bool) {
+	t, err := time.Parse("Z07:00", offset)
+	if err != nil {
+		return 0, false
+	}
+	_, secs := t.Zone()
+	return float64(secs) / 3600, true
+}
@@ -0,0 +1,52 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/models"
+)
+
+func inZone(i int, offset string) fetch.DayData {
+	return fetch.DayData{Date: day(i), Cycle: &models.Cycle{TimezoneOffset: offset}}
+}
+
+func TestTimezoneShifts(t *testing.T) {
+	days := []fetch.DayData{
+		inZone(0, "-05:00"),
+		inZone(1, "+01:00"),
+		inZone(2, "+01:00"),
+		inZone(4, "+05:30"), // after a gap: not a consecutive shift
+		inZone(5, "Z"),
+		{Date: day(6)},
+	}
+	got := TimezoneShifts(days)
+	if len(got) != 2 {
+		t.Fatalf("got %d shifts, want 2: %+v", len(got), got)
+	}
+	if s := got[0]; !s.Date.Equal(day(1)) || s.From != "-05:00" || s.To != "+01:00" || s.Hours != 6 {
+		t.Errorf("first shift = %+v", s)
+	}
+	if s := got[1]; !s.Date.Equal(day(5)) || s.Hours != -5.5 {
+		t.Errorf("second shift = %+v", s)
+	}
+}
+
+func TestWithoutJetlag(t *testing.T) {
+	var days []fetch.DayData
+	for i := 0; i < 8; i++ {
+		zone := "-05:00"
+		if i >= 3 {
+			zone = "+01:00"
+		}
+		days = append(days, inZone(i, zone))
+	}
+	got := WithoutJetlag(days)
+	if len(got) != 5 || !got[2].Date.Equal(day(2)) || !got[3].Date.Equal(day(6)) {
+		t.Errorf("kept %d days, want days 0–2 and 6–7", len(got))
+	}
+	// The last day is kept even right after a shift.
+	if got := WithoutJetlag(days[:4]); len(got) != 4 {
+		t.Errorf("kept %d days, want all 4", len(got))
+	}
+}
@@ -0,0 +1,354 @@
+// Package archive parses the account data export WHOOP offers for download
+// (Settings → Data Export) into DayData, so history can be rendered without
+// API calls.
+//
+// The export is a ZIP of CSVs. physiological_cycles.csv holds one row per
+// cycle with its strain and recovery; sleeps.csv and workouts.csv hold one
+// row per record, each naming the cycle it belongs to by "Cycle start time".
+// Times are local wall-clock times with the offset in "Cycle timezone".
+package archive
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/models"
+)
+
+// kjPerKcal converts the export's calories (kcal) to the API's kilojoules.
+const kjPerKcal = 4.184
+
+// ReadFile parses the export ZIP at name.
+func ReadFile(name string) ([]fetch.DayData, error) {
+	zr, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return Read(&zr.Reader)
+}
+
+// Read parses an export and returns one DayData per cycle, sorted by date.
+// Like fetch.GetDayData, a cycle belongs to the UTC calendar day its start
+// falls on; when two cycles start on the same day the later one wins.
+func Read(zr *zip.Reader) ([]fetch.DayData, error) {
+	cycles, err := readCSV(zr, "physiological_cycles.csv")
+	if err != nil {
+		return nil, err
+	}
+	if cycles == nil {
+		return nil, errors.New("physiological_cycles.csv not found; is this a WHOOP data export?")
+	}
+	sleeps, err := readCSV(zr, "sleeps.csv")
+	if err != nil {
+		return nil, err
+	}
+	workouts, err := readCSV(zr, "workouts.csv")
+	if err != nil {
+		return nil, err
+	}
+
+	days := make(map[string]*fetch.DayData)
+	byCycle := make(map[string]*fetch.DayData)
+	for _, r := range cycles {
+		d, err := parseCycle(r)
+		if err != nil {
+			return nil, fmt.Errorf("physiological_cycles.csv line %d: %w", r.line, err)
+		}
+		key := d.Date.Format("2006-01-02")
+		if prev, ok := days[key]; ok && prev.Cycle.Start > d.Cycle.Start {
+			continue
+		}
+		days[key] = d
+		byCycle[r.get("Cycle start time")] = d
+	}
+	for _, r := range sleeps {
+		d := byCycle[r.get("Cycle start time")]
+		if d == nil {
+			continue
+		}
+		s, err := parseSleep(r)
+		if err != nil {
+			return nil, fmt.Errorf("sleeps.csv line %d: %w", r.line, err)
+		}
+		d.Sleeps = append(d.Sleeps, s)
+	}
+	for _, r := range workouts {
+		d := byCycle[r.get("Cycle start time")]
+		if d == nil {
+			continue
+		}
+		w, err := parseWorkout(r)
+		if err != nil {
+			return nil, fmt.Errorf("workouts.csv line %d: %w", r.line, err)
+		}
+		d.Workouts = append(d.Workouts, w)
+	}
+
+	out := make([]fetch.DayData, 0, len(days))
+	for _, d := range days {
+		d.Workouts, d.DuplicateWorkouts = fetch.SplitDuplicateWorkouts(d.Workouts)
+		out = append(out, *d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Date.Before(out[j].Date) })
+	return out, nil
+}
+
+// row is one CSV record with columns looked up by header name.
+type row struct {
+	line   int
+	header map[string]int
+	fields []string
+}
+
+func (r row) get(col string) string {
+	i, ok := r.header[col]
+	if !ok || i >= len(r.fields) {
+		return ""
+	}
+	return strings.TrimSpace(r.fields[i])
+}
+
+// float returns a numeric column; ok is false when it is blank.
+func (r row) float(col string) (v float64, ok bool, err error) {
+	s := r.get(col)
+	if s == "" {
+		return 0, false, nil
+	}
+	v, err = strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("%s: %w", col, err)
+	}
+	return v, true, nil
+}
+
+// readCSV reads the CSV called name from anywhere in the archive. It
+// returns nil rows, not an error, when the file is absent.
+func readCSV(zr *zip.Reader, name string) ([]row, error) {
+	for _, f := range zr.File {
+		if path.Base(f.Name) != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		cr := csv.NewReader(rc)
+		cr.FieldsPerRecord = -1
+		head, err := cr.Read()
+		if err == io.EOF {
+			return []row{}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		header := make(map[string]int, len(head))
+		for i, h := range head {
+			header[strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))] = i
+		}
+		rows := []row{}
+		for line := 2; ; line++ {
+			fields, err := cr.Read()
+			if err == io.EOF {
+				return rows, nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			rows = append(rows, row{line: line, header: header, fields: fields})
+		}
+	}
+	return nil, nil
+}
+
+// parseTime parses a local "2006-01-02 15:04:05" time with a "UTC-05:00"
+// offset and returns it in RFC 3339, as the API reports times.
+func parseTime(value, tz string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	offset := strings.TrimPrefix(tz, "UTC")
+	if offset == "" {
+		offset = "+00:00"
+	}
+	t, err := time.Parse("2006-01-02 15:04:05-07:00", value+offset)
+	if err != nil {
+		return "", fmt.Errorf("time %q %q: %w", value, tz, err)
+	}
+	return t.UTC().Format(time.RFC3339), nil
+}
+
+// minutes converts a duration column in minutes to milliseconds.
+func minutes(r row, col string) (int64, error) {
+	v, _, err := r.float(col)
+	return int64(v * 60_000), err
+}
+
+// floats reads numeric columns into the pointed-to fields, stopping at the
+// first parse error.
+func floats(r row, cols map[string]*float64) error {
+	for col, dst := range cols {
+		v, _, err := r.float(col)
+		if err != nil {
+			return err
+		}
+		*dst = v
+	}
+	return nil
+}
+
+func parseCycle(r row) (*fetch.DayData, error) {
+	tz := r.get("Cycle timezone")
+	start, err := parseTime(r.get("Cycle start time"), tz)
+	if err != nil {
+		return nil, err
+	}
+	if start == "" {
+		return nil, errors.New("missing Cycle start time")
+	}
+	end, err := parseTime(r.get("Cycle end time"), tz)
+	if err != nil {
+		return nil, err
+	}
+	st, _ := time.Parse(time.RFC3339, start)
+	id := int(st.Unix())
+
+	cycle := &models.Cycle{ID: id, Start: start, End: end, TimezoneOffset: strings.TrimPrefix(tz, "UTC")}
+	var kcal, maxHR, avgHR float64
+	if err := floats(r, map[string]*float64{
+		"Day Strain":          &cycle.Score.Strain,
+		"Energy burned (cal)": &kcal,
+		"Max HR (bpm)":        &maxHR,
+		"Average HR (bpm)":    &avgHR,
+	}); err != nil {
+		return nil, err
+	}
+	cycle.Score.Kilojoule = kcal * kjPerKcal
+	cycle.Score.MaxHeartRate = int(maxHR)
+	cycle.Score.AverageHeartRate = int(avgHR)
+	cycle.ScoreState = "SCORED"
+	if _, ok, _ := r.float("Day Strain"); !ok {
+		cycle.ScoreState = "UNSCORABLE"
+	}
+
+	d := &fetch.DayData{
+		Date:  time.Date(st.Year(), st.Month(), st.Day(), 0, 0, 0, 0, time.UTC),
+		Cycle: cycle,
+	}
+	score, ok, err := r.float("Recovery score %")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		rec := &models.Recovery{CycleID: id, ScoreState: "SCORED"}
+		rec.Score.RecoveryScore = score
+		if err := floats(r, map[string]*float64{
+			"Resting heart rate (bpm)":    &rec.Score.RestingHeartRate,
+			"Heart rate variability (ms)": &rec.Score.HrvRmssdMilli,
+			"Skin temp (celsius)":         &rec.Score.SkinTempCelsius,
+			"Blood oxygen %":              &rec.Score.Spo2Percentage,
+		}); err != nil {
+			return nil, err
+		}
+		d.Recovery = rec
+	}
+	return d, nil
+}
+
+func parseSleep(r row) (models.Sleep, error) {
+	tz := r.get("Cycle timezone")
+	var s models.Sleep
+	var err error
+	if s.Start, err = parseTime(r.get("Sleep onset"), tz); err != nil {
+		return s, err
+	}
+	if s.End, err = parseTime(r.get("Wake onset"), tz); err != nil {
+		return s, err
+	}
+	s.ID = "export-sleep-" + s.Start
+	s.TimezoneOffset = strings.TrimPrefix(tz, "UTC")
+	s.Nap = strings.EqualFold(r.get("Nap"), "true")
+	s.ScoreState = "SCORED"
+	if _, ok, _ := r.float("Sleep performance %"); !ok {
+		s.ScoreState = "UNSCORABLE"
+	}
+
+	sc := &s.Score
+	if err := floats(r, map[string]*float64{
+		"Sleep performance %":    &sc.SleepPerformance,
+		"Sleep consistency %":    &sc.SleepConsistency,
+		"Sleep efficiency %":     &sc.SleepEfficiency,
+		"Respiratory rate (rpm)": &sc.RespiratoryRate,
+	}); err != nil {
+		return s, err
+	}
+	ss := &sc.StageSummary
+	for col, dst := range map[string]*int64{
+		"In bed duration (min)":      &ss.TotalInBedTimeMilli,
+		"Awake duration (min)":       &ss.TotalAwakeTimeMilli,
+		"Light sleep duration (min)": &ss.TotalLightSleepTimeMilli,
+		"Deep (SWS) duration (min)":  &ss.TotalSlowWaveSleepTimeMilli,
+		"REM duration (min)":         &ss.TotalRemSleepTimeMilli,
+	} {
+		if *dst, err = minutes(r, col); err != nil {
+			return s, err
+		}
+	}
+	// The export gives total need and debt; attribute the rest to baseline.
+	need, err := minutes(r, "Sleep need (min)")
+	if err != nil {
+		return s, err
+	}
+	debt, err := minutes(r, "Sleep debt (min)")
+	if err != nil {
+		return s, err
+	}
+	sc.SleepNeeded.NeedFromSleepDebtMillis = debt
+	sc.SleepNeeded.BaselineMillis = need - debt
+	return s, nil
+}
+
+func parseWorkout(r row) (models.Workout, error) {
+	tz := r.get("Cycle timezone")
+	var w models.Workout
+	var err error
+	if w.Start, err = parseTime(r.get("Workout start time"), tz); err != nil {
+		return w, err
+	}
+	if w.End, err = parseTime(r.get("Workout end time"), tz); err != nil {
+		return w, err
+	}
+	w.ID = "export-workout-" + w.Start
+	w.TimezoneOffset = strings.TrimPrefix(tz, "UTC")
+	w.SportName = r.get("Activity name")
+	w.SportID = -1
+	w.ScoreState = "SCORED"
+
+	sc := &w.Score
+	var kcal, maxHR, avgHR float64
+	if err := floats(r, map[string]*float64{
+		"Activity Strain":          &sc.Strain,
+		"Energy burned (cal)":      &kcal,
+		"Max HR (bpm)":             &maxHR,
+		"Average HR (bpm)":         &avgHR,
+		"Distance (meters)":        &sc.DistanceMeter,
+		"Altitude gain (meters)":   &sc.AltitudeGainMeter,
+		"Altitude change (meters)": &sc.AltitudeChangeMeter,
+	}); err != nil {
+		return w, err
+	}
+	sc.Kilojoule = kcal * kjPerKcal
+	sc.MaxHeartRate = int(maxHR)
+	sc.AverageHeartRate = int(avgHR)
+	return w, nil
+}
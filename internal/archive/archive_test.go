@@ -0,0 +1,114 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+)
+
+const cyclesCSV = `Cycle start time,Cycle end time,Cycle timezone,Recovery score %,Resting heart rate (bpm),Heart rate variability (ms),Skin temp (celsius),Blood oxygen %,Day Strain,Energy burned (cal),Max HR (bpm),Average HR (bpm)
+2021-03-02 23:10:00,2021-03-03 22:40:00,UTC-05:00,64,52,71,33.4,95.5,12.3,2400,171,68
+2021-03-01 22:55:00,2021-03-02 23:10:00,UTC-05:00,,,,,,8.1,2000,150,64
+`
+
+const sleepsCSV = `Cycle start time,Cycle end time,Cycle timezone,Sleep onset,Wake onset,Sleep performance %,Respiratory rate (rpm),Asleep duration (min),In bed duration (min),Light sleep duration (min),Deep (SWS) duration (min),REM duration (min),Awake duration (min),Sleep need (min),Sleep debt (min),Sleep efficiency %,Sleep consistency %,Nap
+2021-03-02 23:10:00,2021-03-03 22:40:00,UTC-05:00,2021-03-02 23:10:00,2021-03-03 06:40:00,88,15.2,420,450,200,100,120,30,480,20,93,80,false
+2021-03-02 23:10:00,2021-03-03 22:40:00,UTC-05:00,2021-03-03 14:00:00,2021-03-03 14:30:00,,,25,30,15,5,5,5,,,,,true
+`
+
+const workoutsCSV = `Cycle start time,Cycle end time,Cycle timezone,Workout start time,Workout end time,Duration (min),Activity name,Activity Strain,Energy burned (cal),Max HR (bpm),Average HR (bpm),Distance (meters)
+2021-03-02 23:10:00,2021-03-03 22:40:00,UTC-05:00,2021-03-03 07:30:00,2021-03-03 08:15:00,45,Running,10.5,500,180,150,8000
+2021-03-02 23:10:00,2021-03-03 22:40:00,UTC-05:00,2021-03-03 07:31:00,2021-03-03 08:10:00,39,Activity,9.1,450,178,148,
+`
+
+func zipOf(t *testing.T, files map[string]string) *zip.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(body))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return zr
+}
+
+func TestRead(t *testing.T) {
+	days, err := Read(zipOf(t, map[string]string{
+		"my_whoop_data_2024_01_01/physiological_cycles.csv": cyclesCSV,
+		"my_whoop_data_2024_01_01/sleeps.csv":               sleepsCSV,
+		"my_whoop_data_2024_01_01/workouts.csv":             workoutsCSV,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 2 {
+		t.Fatalf("got %d days, want 2", len(days))
+	}
+
+	// Cycles are assigned by their UTC start date, like the API path.
+	first, d := days[0], days[1]
+	if first.Date != time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC) || first.Recovery != nil {
+		t.Errorf("first day = %s, recovery %v; want 2021-03-02 without recovery", first.Date, first.Recovery)
+	}
+	if d.Date != time.Date(2021, 3, 3, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("second day = %s, want 2021-03-03", d.Date)
+	}
+
+	if d.Cycle.Start != "2021-03-03T04:10:00Z" || d.Cycle.Score.Strain != 12.3 || d.Cycle.Score.MaxHeartRate != 171 {
+		t.Errorf("cycle = %+v", d.Cycle)
+	}
+	if got := d.Cycle.Score.Kilojoule; got < 10041 || got > 10042 {
+		t.Errorf("kilojoule = %v, want 2400 kcal in kJ", got)
+	}
+	r := d.Recovery
+	if r == nil || r.ScoreState != "SCORED" || r.CycleID != d.Cycle.ID || r.Score.RecoveryScore != 64 || r.Score.HrvRmssdMilli != 71 || r.Score.RestingHeartRate != 52 {
+		t.Errorf("recovery = %+v", r)
+	}
+
+	if len(d.Sleeps) != 2 {
+		t.Fatalf("got %d sleeps, want 2", len(d.Sleeps))
+	}
+	s := d.Sleeps[0]
+	if s.Nap || s.Score.SleepPerformance != 88 || s.Score.StageSummary.TotalRemSleepTimeMilli != 120*60_000 {
+		t.Errorf("sleep = %+v", s)
+	}
+	if s.Score.SleepNeeded.BaselineMillis != 460*60_000 || s.Score.SleepNeeded.NeedFromSleepDebtMillis != 20*60_000 {
+		t.Errorf("sleep need = %+v", s.Score.SleepNeeded)
+	}
+	if nap := d.Sleeps[1]; !nap.Nap || nap.ScoreState != "UNSCORABLE" {
+		t.Errorf("nap = %+v", nap)
+	}
+
+	// The overlapping auto-detected workout is split off as a duplicate.
+	if len(d.Workouts) != 1 || len(d.DuplicateWorkouts) != 1 {
+		t.Fatalf("workouts = %d, duplicates = %d; want 1, 1", len(d.Workouts), len(d.DuplicateWorkouts))
+	}
+	w := d.Workouts[0]
+	if w.SportName != "Running" || w.Score.Strain != 10.5 || w.Score.DistanceMeter != 8000 || w.Start != "2021-03-03T12:30:00Z" {
+		t.Errorf("workout = %+v", w)
+	}
+}
+
+func TestRead_NotAnExport(t *testing.T) {
+	if _, err := Read(zipOf(t, map[string]string{"notes.txt": "hi"})); err == nil {
+		t.Error("expected error for a ZIP without physiological_cycles.csv")
+	}
+}
+
+func TestRead_BadNumber(t *testing.T) {
+	bad := "Cycle start time,Cycle timezone,Day Strain\n2021-03-02 23:10:00,UTC+01:00,lots\n"
+	if _, err := Read(zipOf(t, map[string]string{"physiological_cycles.csv": bad})); err == nil {
+		t.Error("expected error for a non-numeric strain")
+	}
+}
@@ -5,21 +5,29 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
+	"strings"
 	"time"
 )
 
 const (
-	tokenURL    = "https://api.prod.whoop.com/oauth/oauth2/token"
-	authURL     = "https://api.prod.whoop.com/oauth/oauth2/auth"
-	tokenFile   = "tokens.json"
+	tokenURL     = "https://api.prod.whoop.com/oauth/oauth2/token"
+	authURL      = "https://api.prod.whoop.com/oauth/oauth2/auth"
+	revokeURL    = "https://api.prod.whoop.com/developer/v2/user/access"
+	tokenFile    = "tokens.json"
 	callbackPort = ":3000"
 )
 
+// Scopes are the OAuth scopes requested by StartAuthFlow.
+var Scopes = []string{"offline", "read:profile", "read:body_measurement", "read:cycles", "read:recovery", "read:sleep", "read:workout"}
+
 // TokenResponse holds OAuth token data returned by WHOOP.
 type TokenResponse struct {
 	AccessToken  string    `json:"access_token"`
@@ -64,7 +72,7 @@ You can obtain free credentials by creating an app at:
 		return fmt.Errorf("failed to generate state: %w", err)
 	}
 
-	scopes := "offline read:profile read:body_measurement read:cycles read:recovery read:sleep read:workout"
+	scopes := strings.Join(Scopes, " ")
 
 	params := url.Values{}
 	params.Set("response_type", "code")
@@ -182,6 +190,9 @@ func SaveTokens(tokens TokenResponse) error {
 	return os.WriteFile(tokenFile, data, 0600)
 }
 
+// TokenPath returns the file tokens are saved to.
+func TokenPath() string { return tokenFile }
+
 // LoadTokens reads tokens from tokens.json.
 func LoadTokens() (TokenResponse, error) {
 	data, err := os.ReadFile(tokenFile)
@@ -208,7 +219,7 @@ func RefreshIfNeeded() (string, error) {
 		return tokens.AccessToken, nil
 	}
 
-	fmt.Println("Access token expiring soon, refreshing...")
+	slog.Info("Access token expiring soon, refreshing...")
 	refreshed, err := refreshTokens(tokens.RefreshToken)
 	if err != nil {
 		return "", fmt.Errorf("token refresh failed: %w", err)
@@ -234,3 +245,49 @@ func refreshTokens(refreshToken string) (TokenResponse, error) {
 
 	return postTokenRequest(data)
 }
+
+// Revoke withdraws the app's access to the account accessToken belongs to.
+// WHOOP invalidates the access and refresh tokens along with the grant.
+func Revoke(accessToken string) error {
+	req, err := http.NewRequest(http.MethodDelete, revokeURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return fmt.Errorf("revoke request failed: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("revoke endpoint returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// DeleteTokens overwrites tokens.json with zeros before removing it, so the
+// tokens are not left readable in the file's old blocks. A missing file is
+// not an error.
+func DeleteTokens() error {
+	f, err := os.OpenFile(tokenFile, os.O_WRONLY, 0)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err == nil {
+		_, err = f.Write(make([]byte, info.Size()))
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("overwrite %s: %w", tokenFile, err)
+	}
+	return os.Remove(tokenFile)
+}
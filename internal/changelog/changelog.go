@@ -0,0 +1,96 @@
+// Package changelog records changes that affect how notes should be read
+// later: template edits, alert threshold changes, and WHOOP-side revisions
+// of data that was already written.
+package changelog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// Entry kinds.
+const (
+	KindTemplate = "template"
+	KindConfig   = "config"
+	KindData     = "data"
+)
+
+// Entry is one recorded change.
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+	Detail string    `json:"detail"`
+}
+
+// Log is the change journal, persisted as a single JSON file.
+type Log struct {
+	path string
+
+	// Since is when tracking began; changes before it are unknown.
+	Since        time.Time         `json:"since"`
+	Fingerprints map[string]string `json:"fingerprints"`
+	Entries      []Entry           `json:"entries"`
+}
+
+// Open reads the log at path. A missing file yields an empty log that
+// starts tracking now.
+func Open(path string) (*Log, error) {
+	l := &Log{path: path, Since: time.Now().UTC(), Fingerprints: map[string]string{}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return l, nil
+		}
+		return nil, fmt.Errorf("read changelog: %w", err)
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("parse changelog: %w", err)
+	}
+	if l.Fingerprints == nil {
+		l.Fingerprints = map[string]string{}
+	}
+	return l, nil
+}
+
+// Record appends an entry stamped with the current time.
+func (l *Log) Record(kind, detail string) {
+	l.Entries = append(l.Entries, Entry{Time: time.Now().UTC(), Kind: kind, Detail: detail})
+}
+
+// Track compares fingerprint with the one last seen for key and records
+// detail when it differs. The first fingerprint seen for a key is only
+// remembered, since there is nothing to compare it with. It reports whether
+// an entry was recorded.
+func (l *Log) Track(kind, key, fingerprint, detail string) bool {
+	prev, seen := l.Fingerprints[key]
+	l.Fingerprints[key] = fingerprint
+	if !seen || prev == fingerprint {
+		return false
+	}
+	l.Record(kind, detail)
+	return true
+}
+
+// Between returns the entries recorded in [start, end), oldest first.
+func (l *Log) Between(start, end time.Time) []Entry {
+	var out []Entry
+	for _, e := range l.Entries {
+		if !e.Time.Before(start) && e.Time.Before(end) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Save writes the log back to its file.
+func (l *Log) Save() error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(l.path, data, 0600)
+}
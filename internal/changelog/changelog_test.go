@@ -0,0 +1,56 @@
+package changelog
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTrack(t *testing.T) {
+	l, err := Open(filepath.Join(t.TempDir(), "changelog.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Track(KindTemplate, "daily", "aaa", "daily changed") {
+		t.Error("first fingerprint should not be recorded as a change")
+	}
+	if l.Track(KindTemplate, "daily", "aaa", "daily changed") {
+		t.Error("unchanged fingerprint should not be recorded")
+	}
+	if !l.Track(KindTemplate, "daily", "bbb", "daily changed") {
+		t.Error("new fingerprint should be recorded")
+	}
+	if len(l.Entries) != 1 || l.Entries[0].Kind != KindTemplate || l.Entries[0].Detail != "daily changed" {
+		t.Errorf("unexpected entries: %+v", l.Entries)
+	}
+}
+
+func TestSaveOpen_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changelog.json")
+	l, _ := Open(path)
+	l.Track(KindConfig, "alerts", "x", "")
+	l.Record(KindData, "WHOOP revised 2026-02-10")
+	if err := l.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Since.Equal(l.Since) || got.Fingerprints["alerts"] != "x" || len(got.Entries) != 1 {
+		t.Errorf("round trip mismatch: %+v", got)
+	}
+	if got.Track(KindConfig, "alerts", "x", "") {
+		t.Error("fingerprint should survive a round trip")
+	}
+}
+
+func TestBetween(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 2, d, 12, 0, 0, 0, time.UTC) }
+	l := &Log{Entries: []Entry{{Time: day(1), Detail: "a"}, {Time: day(10), Detail: "b"}, {Time: day(20), Detail: "c"}}}
+	got := l.Between(day(1), day(20))
+	if len(got) != 2 || got[0].Detail != "a" || got[1].Detail != "b" {
+		t.Errorf("Between = %+v, want a and b", got)
+	}
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// finalAfter is how long after a request's end time its response is
+// treated as final and kept until the cache is cleared, matching the local
+// store's settle time.
+const finalAfter = 48 * time.Hour
+
+// Final reports whether responses for a window ending at end are final,
+// and kept by a Cache until it is cleared.
+func Final(end time.Time) bool { return time.Since(end) > finalAfter }
+
+// cached is one response in a Cache.
+type cached struct {
+	recording
+	Fetched time.Time `json:"fetched"`
+	// Final is set for responses whose window ended long enough ago that
+	// WHOOP has finished scoring it.
+	Final bool `json:"final,omitempty"`
+}
+
+// ErrOffline is returned for requests an offline Cache has no response to.
+var ErrOffline = errors.New("not available offline")
+
+// Cache is an http.RoundTripper that keeps successful GET responses in
+// Dir, keyed by endpoint and query like Recorder's files. A response is
+// served from Dir while younger than TTL, or for good once final (see
+// finalAfter). Requests sent with "Cache-Control: no-cache" always go to
+// Next, and refresh the stored copy. A nil Next means http.DefaultTransport.
+//
+// An Offline cache never calls Next: it serves whatever copy it has, however
+// old, and fails with ErrOffline otherwise.
+type Cache struct {
+	Dir     string
+	TTL     time.Duration
+	Next    http.RoundTripper
+	Offline bool
+}
+
+// RoundTrip answers req from the cache or sends it through Next, storing
+// 200 and 404 responses.
+func (c Cache) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := c.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if req.Method != http.MethodGet {
+		return next.RoundTrip(req)
+	}
+	uri := req.URL.RequestURI()
+	path := recordingPath(c.Dir, uri)
+	if c.Offline || req.Header.Get("Cache-Control") != "no-cache" {
+		var e cached
+		if b, err := os.ReadFile(path); err == nil && json.Unmarshal(b, &e) == nil && (c.Offline || e.Final || time.Since(e.Fetched) < c.TTL) {
+			return &http.Response{
+				StatusCode: e.Status,
+				Status:     fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+				Header:     http.Header{"Content-Type": {"application/json"}, "X-Cache": {"hit"}},
+				Body:       io.NopCloser(bytes.NewReader(e.Body)),
+				Request:    req,
+			}, nil
+		}
+	}
+
+	if c.Offline {
+		return nil, fmt.Errorf("no cached response for %s: %w", uri, ErrOffline)
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil || (resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound) {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if !json.Valid(body) && len(body) > 0 {
+		return resp, nil
+	}
+
+	e := cached{recording: recording{URL: uri, Status: resp.StatusCode}, Fetched: time.Now().UTC()}
+	if len(body) > 0 {
+		e.Body = body
+	}
+	if end, err := time.Parse(time.RFC3339, req.URL.Query().Get("end")); err == nil {
+		e.Final = Final(end)
+	}
+	// A response that cannot be cached is still a good response.
+	if b, err := json.Marshal(e); err == nil && os.MkdirAll(c.Dir, 0700) == nil {
+		_ = os.WriteFile(path, b, 0600)
+	}
+	return resp, nil
+}
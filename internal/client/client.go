@@ -1,11 +1,15 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -13,13 +17,41 @@ import (
 // Collection endpoints use this to signal an empty result set.
 var ErrNotFound = errors.New("not found")
 
-const defaultBaseURL = "https://api.prod.whoop.com/developer/v2"
+// ErrUnauthorized is returned when the API responds with 401: the access
+// token is invalid, expired, or revoked.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// ErrBudgetExhausted is returned once the client has made as many requests
+// as its budget allows.
+var ErrBudgetExhausted = errors.New("API call budget exhausted")
+
+// APIVersion is the WHOOP developer API version this client targets.
+const APIVersion = "v2"
+
+const defaultBaseURL = "https://api.prod.whoop.com/developer/" + APIVersion
 
 // Client is an authenticated WHOOP API client.
 type Client struct {
 	accessToken string
 	baseURL     string
 	httpClient  *http.Client
+	ctx         context.Context
+	noCache     bool
+	trace       bool
+	usage       *usage
+}
+
+// usage counts requests. It is shared by a Client and its WithContext
+// copies, so the budget covers them all.
+type usage struct {
+	mu     sync.Mutex
+	budget int
+	calls  int
+	pauses int
+	// pause is the current spacing between requests and next the earliest
+	// time the next one may start; see observe.
+	pause time.Duration
+	next  time.Time
 }
 
 // NewClient creates a new Client with the given access token.
@@ -28,6 +60,7 @@ func NewClient(token string) *Client {
 		accessToken: token,
 		baseURL:     defaultBaseURL,
 		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		usage:       &usage{},
 	}
 }
 
@@ -37,26 +70,115 @@ func NewClientWithBaseURL(token, baseURL string) *Client {
 		accessToken: token,
 		baseURL:     baseURL,
 		httpClient:  &http.Client{Timeout: 5 * time.Second},
+		usage:       &usage{},
+	}
+}
+
+// SetTransport sends c's requests through rt instead of the default
+// transport, as --demo, --record, and --replay do.
+func (c *Client) SetTransport(rt http.RoundTripper) {
+	c.httpClient.Transport = rt
+}
+
+// WithContext returns a copy of c whose requests, including waits between
+// retries, are cancelled with ctx. The copy shares c's budget and counts.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	c2 := *c
+	c2.ctx = ctx
+	return &c2
+}
+
+// SetTrace logs every request attempt at info level: method, path, query,
+// status, latency, retry number, and WHOOP's rate-limit headers.
+func (c *Client) SetTrace(on bool) {
+	c.trace = on
+}
+
+// NoCache returns a copy of c whose requests skip any response cache in
+// its transport (see Cache). The copy shares c's budget and counts.
+func (c *Client) NoCache() *Client {
+	c2 := *c
+	c2.noCache = true
+	return &c2
+}
+
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
 	}
+	return c.ctx
+}
+
+// SetBudget limits the client to n HTTP requests, after which Get returns
+// ErrBudgetExhausted. Zero means unlimited.
+func (c *Client) SetBudget(n int) {
+	c.usage.mu.Lock()
+	defer c.usage.mu.Unlock()
+	c.usage.budget = n
+}
+
+// Calls returns the number of HTTP requests made so far.
+func (c *Client) Calls() int {
+	c.usage.mu.Lock()
+	defer c.usage.mu.Unlock()
+	return c.usage.calls
+}
+
+// RateLimitPauses returns how many times a request was retried after a 429.
+func (c *Client) RateLimitPauses() int {
+	c.usage.mu.Lock()
+	defer c.usage.mu.Unlock()
+	return c.usage.pauses
+}
+
+// take reserves one request from the budget.
+func (c *Client) take() bool {
+	u := c.usage
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.budget > 0 && u.calls >= u.budget {
+		return false
+	}
+	u.calls++
+	return true
 }
 
 // Get performs a GET request to the WHOOP API.
-// It retries on HTTP 429 with exponential backoff (1s, 2s, 4s).
+// It retries on HTTP 429 with exponential backoff (1s, 2s, 4s), and paces
+// requests from the responses' rate-limit headers and 429s. Every attempt,
+// including retries, counts against the budget.
 func (c *Client) Get(path string, params url.Values) ([]byte, error) {
 	backoff := time.Second
 	for attempt := 0; attempt <= 3; attempt++ {
-		body, statusCode, err := c.doGet(path, params)
+		if err := c.wait(); err != nil {
+			return nil, err
+		}
+		if !c.take() {
+			return nil, ErrBudgetExhausted
+		}
+		body, statusCode, err := c.doGet(path, params, attempt)
 		if err != nil {
 			return nil, err
 		}
 		if statusCode == http.StatusTooManyRequests {
-			time.Sleep(backoff)
+			slog.Debug("rate limited, retrying", "path", path, "attempt", attempt+1, "backoff", backoff)
+			c.usage.mu.Lock()
+			c.usage.pauses++
+			c.usage.mu.Unlock()
+			select {
+			case <-time.After(backoff):
+			case <-c.context().Done():
+				return nil, c.context().Err()
+			}
 			backoff *= 2
 			continue
 		}
 		if statusCode == http.StatusNotFound {
 			return nil, ErrNotFound
 		}
+		if statusCode == http.StatusUnauthorized {
+			return nil, fmt.Errorf("WHOOP API returned 401 for %s: %w", path, ErrUnauthorized)
+		}
 		if statusCode < 200 || statusCode >= 300 {
 			return nil, fmt.Errorf("WHOOP API returned %d for %s", statusCode, path)
 		}
@@ -65,31 +187,76 @@ func (c *Client) Get(path string, params url.Values) ([]byte, error) {
 	return nil, fmt.Errorf("WHOOP API rate limit exceeded for %s after retries", path)
 }
 
-// doGet executes a single GET request and returns body, status code, and error.
-func (c *Client) doGet(path string, params url.Values) ([]byte, int, error) {
+// doGet executes a single GET request and returns body, status code, and
+// error. attempt is the number of retries before it, for tracing.
+func (c *Client) doGet(path string, params url.Values, attempt int) ([]byte, int, error) {
 	reqURL := c.baseURL + path
 	if len(params) > 0 {
 		reqURL += "?" + params.Encode()
 	}
 
-	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	req, err := http.NewRequestWithContext(c.context(), http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to build request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+c.accessToken)
 	req.Header.Set("Accept", "application/json")
+	if c.noCache {
+		req.Header.Set("Cache-Control", "no-cache")
+	}
 
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		if c.trace {
+			slog.Info("HTTP GET", "path", path, "query", redactQuery(params), "retry", attempt, "took", time.Since(start).Round(time.Millisecond), "err", err)
+		}
+		slog.Debug("GET failed", "path", path, "query", params.Encode(), "err", err)
 		return nil, 0, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	if c.trace {
+		args := []any{"path", path, "query", redactQuery(params), "status", resp.StatusCode,
+			"took", time.Since(start).Round(time.Millisecond), "retry", attempt}
+		if resp.Header.Get("X-Cache") == "hit" {
+			args = append(args, "cached", true)
+		}
+		for _, h := range []string{"Limit", "Remaining", "Reset"} {
+			if v := resp.Header.Get("X-RateLimit-" + h); v != "" {
+				args = append(args, "ratelimit_"+strings.ToLower(h), v)
+			}
+		}
+		slog.Info("HTTP GET", args...)
+	}
 
+	if resp.Header.Get("X-Cache") == "hit" {
+		// Answered by Cache without a request to WHOOP.
+		c.usage.mu.Lock()
+		c.usage.calls--
+		c.usage.mu.Unlock()
+	} else {
+		c.observe(resp.StatusCode, resp.Header)
+	}
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, resp.StatusCode, fmt.Errorf("failed to read response body: %w", err)
 	}
+	slog.Debug("GET", "path", path, "query", params.Encode(), "status", resp.StatusCode, "bytes", len(body), "took", time.Since(start).Round(time.Millisecond), "cached", resp.Header.Get("X-Cache") == "hit")
 
 	return body, resp.StatusCode, nil
 }
+
+// redactQuery encodes params for logs with any credentials masked.
+func redactQuery(params url.Values) string {
+	q := url.Values{}
+	for k, v := range params {
+		switch strings.ToLower(k) {
+		case "access_token", "refresh_token", "token", "client_secret":
+			q[k] = []string{"REDACTED"}
+		default:
+			q[k] = v
+		}
+	}
+	return q.Encode()
+}
@@ -1,9 +1,14 @@
 package client
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 )
@@ -14,6 +19,7 @@ func newTestClient(srv *httptest.Server) *Client {
 		accessToken: "test-token",
 		baseURL:     srv.URL,
 		httpClient:  &http.Client{Timeout: 5 * time.Second},
+		usage:       &usage{},
 	}
 }
 
@@ -51,6 +57,18 @@ func TestGet_NotFound(t *testing.T) {
 	}
 }
 
+func TestGet_Unauthorized(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv)
+	if _, err := c.Get("/user/profile/basic", nil); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
 func TestGet_ServerError(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -117,6 +135,9 @@ func TestGet_RateLimitRetry(t *testing.T) {
 	if attempts != 3 {
 		t.Errorf("server received %d attempts, want 3", attempts)
 	}
+	if got := c.RateLimitPauses(); got != 2 {
+		t.Errorf("RateLimitPauses() = %d, want 2", got)
+	}
 }
 
 // TestGet_RateLimitExhausted verifies the error message when all retries are consumed.
@@ -152,3 +173,193 @@ func TestGet_PathAppended(t *testing.T) {
 		t.Errorf("server received path %q, want /activity/sleep", receivedPath)
 	}
 }
+
+func TestGet_Budget(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv)
+	c.SetBudget(2)
+	for i := 0; i < 2; i++ {
+		if _, err := c.Get("/path", nil); err != nil {
+			t.Fatalf("call %d: %v", i+1, err)
+		}
+	}
+	if _, err := c.Get("/path", nil); err != ErrBudgetExhausted {
+		t.Errorf("third call: expected ErrBudgetExhausted, got %v", err)
+	}
+	if c.Calls() != 2 {
+		t.Errorf("Calls() = %d, want 2", c.Calls())
+	}
+}
+
+func TestGet_ContextCancelsBackoff(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	base := newTestClient(srv)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := base.WithContext(ctx).Get("/test", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Get waited %s; the 1s backoff should have been cut short", time.Since(start))
+	}
+	if base.Calls() != 1 || base.RateLimitPauses() != 1 {
+		t.Errorf("base client counted %d calls, %d pauses; want 1, 1", base.Calls(), base.RateLimitPauses())
+	}
+}
+
+func TestObserve_Pacing(t *testing.T) {
+	c := NewClient("t")
+	h := http.Header{}
+
+	c.observe(http.StatusTooManyRequests, h)
+	if got := c.Pause(); got != minPause {
+		t.Errorf("after first 429: pause %s, want %s", got, minPause)
+	}
+	c.observe(http.StatusTooManyRequests, h)
+	if got := c.Pause(); got != 2*minPause {
+		t.Errorf("after second 429: pause %s, want %s", got, 2*minPause)
+	}
+	c.observe(http.StatusOK, h)
+	if got := c.Pause(); got != 900*time.Millisecond {
+		t.Errorf("after success: pause %s, want 900ms", got)
+	}
+	for i := 0; i < 30; i++ {
+		c.observe(http.StatusOK, h)
+	}
+	if got := c.Pause(); got != 0 {
+		t.Errorf("after many successes: pause %s, want 0", got)
+	}
+
+	h.Set("X-RateLimit-Remaining", "3")
+	h.Set("X-RateLimit-Reset", "20")
+	c.observe(http.StatusOK, h)
+	if got := c.Pause(); got != 5*time.Second {
+		t.Errorf("3 left with 20s to reset: pause %s, want 5s", got)
+	}
+
+	h = http.Header{"Retry-After": {"30"}}
+	c.observe(http.StatusTooManyRequests, h)
+	if wait := time.Until(c.usage.next); wait < 29*time.Second {
+		t.Errorf("Retry-After 30: next request in %s, want ~30s", wait)
+	}
+}
+
+func TestHeaderInt(t *testing.T) {
+	h := http.Header{"X-Ratelimit-Limit": {"100, 100;window=60, 10000;window=86400"}}
+	if n, ok := headerInt(h, "X-RateLimit-Limit"); !ok || n != 100 {
+		t.Errorf("headerInt = %d, %v; want 100, true", n, ok)
+	}
+	if _, ok := headerInt(h, "X-RateLimit-Reset"); ok {
+		t.Error("headerInt of a missing header: ok = true")
+	}
+}
+
+func TestCache(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte(`{"n":1}`))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv)
+	c.SetTransport(Cache{Dir: t.TempDir(), TTL: time.Hour})
+	for i := 0; i < 2; i++ {
+		if body, err := c.Get("/cycle", nil); err != nil || string(body) != `{"n":1}` {
+			t.Fatalf("Get %d = %q, %v", i+1, body, err)
+		}
+	}
+	if hits != 1 || c.Calls() != 1 {
+		t.Errorf("fresh response: server hit %d times, Calls() = %d; want 1, 1", hits, c.Calls())
+	}
+	if _, err := c.NoCache().Get("/cycle", nil); err != nil {
+		t.Fatal(err)
+	}
+	if hits != 2 {
+		t.Errorf("NoCache: server hit %d times, want 2", hits)
+	}
+
+	// With no TTL only final responses, for windows long past, are reused.
+	c = newTestClient(srv)
+	c.SetTransport(Cache{Dir: t.TempDir()})
+	old := url.Values{"end": {time.Now().AddDate(0, 0, -5).UTC().Format(time.RFC3339)}}
+	recent := url.Values{"end": {time.Now().UTC().Format(time.RFC3339)}}
+	hits = 0
+	for i := 0; i < 2; i++ {
+		c.Get("/cycle", old)
+		c.Get("/cycle", recent)
+	}
+	if hits != 3 {
+		t.Errorf("server hit %d times, want 3 (once for the final window, twice for the recent one)", hits)
+	}
+}
+
+func TestFinal(t *testing.T) {
+	if Final(time.Now().Add(-24 * time.Hour)) {
+		t.Error("a window that ended yesterday is final")
+	}
+	if !Final(time.Now().Add(-72 * time.Hour)) {
+		t.Error("a window that ended three days ago is not final")
+	}
+}
+
+func TestCache_Offline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"n":1}`))
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+
+	online := newTestClient(srv)
+	online.SetTransport(Cache{Dir: dir, TTL: time.Nanosecond})
+	if _, err := online.Get("/cycle", nil); err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+
+	c := newTestClient(srv)
+	c.SetTransport(Cache{Dir: dir, Offline: true})
+	if body, err := c.NoCache().Get("/cycle", nil); err != nil || string(body) != `{"n":1}` {
+		t.Errorf("expired cached response offline = %q, %v; want it served", body, err)
+	}
+	if _, err := c.Get("/sleep", nil); !errors.Is(err, ErrOffline) {
+		t.Errorf("uncached request offline: err = %v, want ErrOffline", err)
+	}
+}
+
+func TestGet_Trace(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "97")
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	c := newTestClient(srv)
+	c.SetTrace(true)
+	if _, err := c.Get("/cycle", url.Values{"start": {"2026-02-10"}, "access_token": {"secret"}}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"path=/cycle", "status=200", "retry=0", "ratelimit_remaining=97", "access_token=REDACTED", "start=2026-02-10"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("trace %q lacks %q", out, want)
+		}
+	}
+	if strings.Contains(out, "secret") {
+		t.Errorf("trace %q leaks the token", out)
+	}
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pacing bounds. After a 429 requests are spaced at least minPause apart,
+// doubling on each further 429 up to maxPause. Every success without one
+// shortens the pause by a quarter, down to none.
+const (
+	minPause = 600 * time.Millisecond // WHOOP allows 100 requests a minute
+	maxPause = 10 * time.Second
+	// lowWater is the X-RateLimit-Remaining below which the remaining
+	// requests are spread over what is left of the window.
+	lowWater = 10
+)
+
+// wait blocks until the pacer allows the next request, or the client's
+// context is done.
+func (c *Client) wait() error {
+	c.usage.mu.Lock()
+	d := time.Until(c.usage.next)
+	c.usage.mu.Unlock()
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(d):
+		return nil
+	case <-c.context().Done():
+		return c.context().Err()
+	}
+}
+
+// observe adjusts the pause between requests to a response: longer after a
+// 429 or when the rate-limit headers show the window running out, shorter
+// while there is headroom.
+func (c *Client) observe(status int, h http.Header) {
+	u := c.usage
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	now := time.Now()
+	remaining, okRemaining := headerInt(h, "X-RateLimit-Remaining")
+	reset, okReset := headerInt(h, "X-RateLimit-Reset")
+	switch {
+	case status == http.StatusTooManyRequests:
+		u.pause = min(max(2*u.pause, minPause), maxPause)
+		if after, ok := headerInt(h, "Retry-After"); ok {
+			u.next = now.Add(max(u.pause, time.Duration(after)*time.Second))
+			return
+		}
+	case okRemaining && okReset && remaining < lowWater:
+		u.pause = time.Duration(reset) * time.Second / time.Duration(remaining+1)
+	default:
+		u.pause -= u.pause / 4
+		if u.pause < 10*time.Millisecond {
+			u.pause = 0
+		}
+	}
+	u.next = now.Add(u.pause)
+}
+
+// Pause returns the current pause between requests.
+func (c *Client) Pause() time.Duration {
+	c.usage.mu.Lock()
+	defer c.usage.mu.Unlock()
+	return c.usage.pause
+}
+
+// headerInt parses the first number in a header such as "42" or
+// "100, 100;window=60".
+func headerInt(h http.Header, key string) (int, bool) {
+	v := h.Get(key)
+	if i := strings.IndexAny(v, ",;"); i >= 0 {
+		v = v[:i]
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	return n, err == nil
+}
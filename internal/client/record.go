@@ -0,0 +1,154 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// recording is one saved response. Neither request nor response headers
+// are saved, so the access token never is; personal fields in the body are
+// replaced by scrub.
+type recording struct {
+	URL    string          `json:"url"` // path and query
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// recordingPath returns the file a response to uri is saved in: the
+// endpoint, readable, plus a hash of the full path and query.
+func recordingPath(dir, uri string) string {
+	path, _, _ := strings.Cut(uri, "?")
+	path = strings.TrimPrefix(path, "/developer/"+APIVersion)
+	name := strings.ReplaceAll(strings.Trim(path, "/"), "/", "_")
+	sum := sha256.Sum256([]byte(uri))
+	return filepath.Join(dir, fmt.Sprintf("%s-%s.json", name, hex.EncodeToString(sum[:6])))
+}
+
+// scrubbed holds the stand-in for each personal field of a response, so a
+// recording can be shared.
+var scrubbed = map[string]any{
+	"user_id":    0,
+	"email":      "athlete@example.com",
+	"first_name": "Demo",
+	"last_name":  "Athlete",
+}
+
+// scrub returns body with every field in scrubbed replaced, at any depth.
+// A body without them is returned as it is; otherwise numbers are kept
+// exactly as the API sent them.
+func scrub(body []byte) []byte {
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return body
+	}
+	changed := false
+	var walk func(any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for k, x := range v {
+				if s, ok := scrubbed[k]; ok {
+					v[k], changed = s, true
+				} else {
+					walk(x)
+				}
+			}
+		case []any:
+			for _, x := range v {
+				walk(x)
+			}
+		}
+	}
+	walk(v)
+	if !changed {
+		return body
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return b
+}
+
+// Recorder is an http.RoundTripper that saves every response it passes on
+// to a file in Dir, for Replayer to serve later. A nil Next means
+// http.DefaultTransport.
+type Recorder struct {
+	Dir  string
+	Next http.RoundTripper
+}
+
+// RoundTrip sends req through Next and saves the response.
+func (r Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := r.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	uri := req.URL.RequestURI()
+	rec := recording{URL: uri, Status: resp.StatusCode}
+	if json.Valid(body) {
+		rec.Body = scrub(body)
+	}
+	b, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(r.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("record: %w", err)
+	}
+	if err := os.WriteFile(recordingPath(r.Dir, uri), append(b, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("record: %w", err)
+	}
+	return resp, nil
+}
+
+// Replayer is an http.RoundTripper that answers requests with responses a
+// Recorder saved in Dir, without a network. A request that was never
+// recorded fails.
+type Replayer struct {
+	Dir string
+}
+
+// RoundTrip returns the saved response to req.
+func (r Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	uri := req.URL.RequestURI()
+	b, err := os.ReadFile(recordingPath(r.Dir, uri))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no recorded response for %s in %s", uri, r.Dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rec recording
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return nil, fmt.Errorf("replay %s: %w", recordingPath(r.Dir, uri), err)
+	}
+	return &http.Response{
+		StatusCode: rec.Status,
+		Status:     fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(rec.Body)),
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"records":[{"id":` + r.URL.Query().Get("n") + `}],"next_token":""}`))
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+
+	rec := NewClientWithBaseURL("secret-token", srv.URL)
+	rec.SetTransport(Recorder{Dir: dir})
+	want := map[string]string{}
+	for _, n := range []string{"1", "2"} {
+		body, err := rec.Get("/cycle", url.Values{"n": {n}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want[n] = string(body)
+	}
+	if _, err := rec.Get("/missing", nil); err != ErrNotFound {
+		t.Fatalf("recording a 404: err = %v, want ErrNotFound", err)
+	}
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 3 {
+		t.Fatalf("got %d recordings, want 3", len(files))
+	}
+	for _, f := range files {
+		b, _ := os.ReadFile(dir + "/" + f.Name())
+		if strings.Contains(string(b), "secret-token") {
+			t.Errorf("%s contains the access token", f.Name())
+		}
+	}
+
+	play := NewClientWithBaseURL("other-token", srv.URL)
+	play.SetTransport(Replayer{Dir: dir})
+	for n, w := range want {
+		body, err := play.Get("/cycle", url.Values{"n": {n}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got bytes.Buffer
+		json.Compact(&got, body)
+		if got.String() != w {
+			t.Errorf("replayed n=%s: got %s, want %s", n, body, w)
+		}
+	}
+	if _, err := play.Get("/missing", nil); err != ErrNotFound {
+		t.Errorf("replaying a 404: err = %v, want ErrNotFound", err)
+	}
+	if _, err := play.Get("/cycle", url.Values{"n": {"3"}}); err == nil || !strings.Contains(err.Error(), "no recorded response") {
+		t.Errorf("unrecorded request: err = %v", err)
+	}
+}
+
+func TestRecorder_Scrub(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"user_id":10129,"email":"jane@example.org","first_name":"Jane","last_name":"Doe","records":[{"id":7,"user_id":10129,"strain":5.2812}]}`))
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+
+	c := NewClientWithBaseURL("tok", srv.URL)
+	c.SetTransport(Recorder{Dir: dir})
+	if _, err := c.Get("/user/profile/basic", nil); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 {
+		t.Fatalf("got %d recordings, want 1", len(files))
+	}
+	b, _ := os.ReadFile(dir + "/" + files[0].Name())
+	for _, s := range []string{"10129", "jane@example.org", "Jane", "Doe"} {
+		if strings.Contains(string(b), s) {
+			t.Errorf("recording contains %q:\n%s", s, b)
+		}
+	}
+	if !strings.Contains(string(b), "5.2812") {
+		t.Errorf("recording lost other fields:\n%s", b)
+	}
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// TransportConfig sets how requests reach the network. The zero value
+// behaves like http.DefaultTransport, which honours $HTTPS_PROXY,
+// $HTTP_PROXY, and $NO_PROXY.
+type TransportConfig struct {
+	// Proxy is the URL of an http, https, or socks5 proxy used for every
+	// request, overriding the environment.
+	Proxy string
+	// CAFile is a PEM bundle of certificate authorities trusted in
+	// addition to the system's.
+	CAFile string
+	// MinTLSVersion is "1.2" (the default) or "1.3".
+	MinTLSVersion string
+	// InsecureSkipVerify accepts any server certificate.
+	InsecureSkipVerify bool
+}
+
+// NewTransport returns a copy of http.DefaultTransport configured by tc.
+func NewTransport(tc TransportConfig) (*http.Transport, error) {
+	base, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("default transport is a %T, not *http.Transport", http.DefaultTransport)
+	}
+	t := base.Clone()
+	if tc.Proxy != "" {
+		u, err := url.Parse(tc.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("proxy: %w", err)
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: tc.InsecureSkipVerify}
+	switch tc.MinTLSVersion {
+	case "", "1.2":
+	case "1.3":
+		cfg.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported minimum TLS version %q (want 1.2 or 1.3)", tc.MinTLSVersion)
+	}
+	if tc.CAFile != "" {
+		pem, err := os.ReadFile(tc.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA bundle %s: no PEM certificates found", tc.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	t.TLSClientConfig = cfg
+	return t, nil
+}
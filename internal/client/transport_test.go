@@ -0,0 +1,68 @@
+package client
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewTransport_CAFile(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	// Without the test server's certificate the request is refused.
+	plain, err := NewTransport(TransportConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newTestClient(srv)
+	c.SetTransport(plain)
+	if _, err := c.Get("/cycle", nil); err == nil {
+		t.Fatal("expected a certificate error without the CA bundle")
+	}
+
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(ca, cert, 0600); err != nil {
+		t.Fatal(err)
+	}
+	trusted, err := NewTransport(TransportConfig{CAFile: ca})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetTransport(trusted)
+	if _, err := c.Get("/cycle", nil); err != nil {
+		t.Errorf("with the CA bundle: %v", err)
+	}
+}
+
+func TestNewTransport_Proxy(t *testing.T) {
+	tr, err := NewTransport(TransportConfig{Proxy: "http://proxy.example:3128"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://api.prod.whoop.com/", nil)
+	u, err := tr.Proxy(req)
+	if err != nil || u == nil || u.Host != "proxy.example:3128" {
+		t.Errorf("Proxy = %v, %v; want proxy.example:3128", u, err)
+	}
+}
+
+func TestNewTransport_Invalid(t *testing.T) {
+	bad := filepath.Join(t.TempDir(), "bad.pem")
+	os.WriteFile(bad, []byte("not a certificate"), 0600)
+	for name, tc := range map[string]TransportConfig{
+		"tls version": {MinTLSVersion: "1.1"},
+		"missing CA":  {CAFile: filepath.Join(t.TempDir(), "none.pem")},
+		"empty CA":    {CAFile: bad},
+	} {
+		if _, err := NewTransport(tc); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,514 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/alert"
+	"github.com/benstraw/whoop-garden/internal/links"
+	"github.com/benstraw/whoop-garden/internal/locale"
+	"github.com/benstraw/whoop-garden/internal/storage"
+)
+
+// defaultPath is the config file looked up in the current directory when
+// $WHOOP_CONFIG is not set.
+const defaultPath = "config.json"
+
+// Config holds optional user settings. Every field is optional; the zero
+// value reproduces the built-in defaults.
+type Config struct {
+	// OutputDir overrides the note destination directory. It takes
+	// precedence over $OBSIDIAN_VAULT_PATH but not over --output.
+	OutputDir string `json:"output_dir"`
+
+	// Layout arranges notes in the output directory: "year" (default) puts
+	// them in a folder per year, "flat" all in the output directory. Run
+	// migrate after changing it.
+	Layout string `json:"layout"`
+
+	// LinkStyle is how wikilinks between notes name their target: "vault"
+	// (default) by the path from the vault root, "relative" by the path
+	// from the linking note, "name" by the note name alone.
+	LinkStyle string `json:"link_style"`
+
+	// Properties maps the metrics daily notes carry as typed frontmatter
+	// properties, from PropertyMetrics, to the property names. Unset, all
+	// are written under their own names; {} writes none.
+	Properties map[string]string `json:"properties"`
+
+	Frontmatter Frontmatter `json:"frontmatter"`
+
+	// Vault is the Obsidian vault name used in obsidian:// links. It
+	// defaults to the last element of $OBSIDIAN_VAULT_PATH.
+	Vault string `json:"vault"`
+
+	// PersonaPath is where persona --write puts the persona, relative to
+	// $OBSIDIAN_VAULT_PATH unless absolute. Default Health/WHOOP/Persona.md.
+	PersonaPath string `json:"persona_path"`
+
+	// IncludeProfile adds the user's name and body measurements to the
+	// persona, and height, weight, and max heart rate to daily note
+	// frontmatter. It is off by default for privacy.
+	IncludeProfile bool `json:"include_profile"`
+
+	// RollingAverages shows seven-day averages next to each daily note's
+	// numbers. The six days before each note are read through the local
+	// store, fetching any that are not cached.
+	RollingAverages bool `json:"rolling_averages"`
+
+	// DayOverDay shows each daily note's key metrics against the day
+	// before, read through the local store like RollingAverages.
+	DayOverDay bool `json:"day_over_day"`
+
+	// HRVBaselineDays is the window, typically 30 or 60 days, that each
+	// daily note's HRV is compared with as a z-score and percentile. Zero
+	// disables it in daily notes; the persona uses 30 days then.
+	HRVBaselineDays int `json:"hrv_baseline_days"`
+
+	// RespiratoryThreshold flags nights whose respiratory rate is more than
+	// this many breaths per minute from the 30-night baseline, often an
+	// early sign of illness. Zero disables it; 1 is a sensible start.
+	RespiratoryThreshold float64 `json:"respiratory_threshold"`
+
+	// StrainBands replace the strain labels of notes and summaries:
+	// each band names the strains from its min up to the next band's.
+	StrainBands []StrainBand `json:"strain_bands"`
+
+	// StrainTargets replace the strain suggested for a day's recovery:
+	// each target covers the recoveries from its min_recovery up to the
+	// next target's.
+	StrainTargets []StrainTarget `json:"strain_targets"`
+
+	// SportNames names WHOOP sport IDs that this version does not know yet,
+	// or renames known ones, for workouts the API sends without a
+	// sport_name: {"233": "Padel"}.
+	SportNames map[int]string `json:"sport_names"`
+
+	// Sports sets the emoji, Obsidian tag, and category of sports by name,
+	// over the built-in emoji and categories: {"Running": {"tag": "run"}}.
+	// Daily notes are tagged with the tags of the day's sports.
+	Sports map[string]Sport `json:"sports"`
+
+	// Vitals compares each daily note's skin temperature and SpO2 with the
+	// 30 days before it, flagging skin temperature more than 1 °C off and
+	// SpO2 more than 3 points below.
+	Vitals bool `json:"vitals"`
+
+	// Travel notes a time zone change since the day before in daily notes
+	// and leaves the days around it out of the HRV, respiratory rate, skin
+	// temperature, and SpO2 baselines, since jetlag distorts them.
+	Travel bool `json:"travel"`
+
+	// Streaks shows the current and best streaks of green recovery, 7h+
+	// sleep, and workout days in daily and weekly notes, computed over
+	// the local store's history without API calls.
+	Streaks bool `json:"streaks"`
+
+	// SleepDebt adds a Sleep Debt section to daily notes covering the seven
+	// nights ending on the note's day, read through the local store.
+	SleepDebt bool `json:"sleep_debt"`
+
+	// Bedtime adds a recommended bedtime for the coming night to today's
+	// daily note and summaries, early enough to meet the last night's sleep
+	// need before WakeTime given how efficiently recent nights were slept.
+	Bedtime bool `json:"bedtime"`
+
+	// WakeTime is the target wake time bedtimes are recommended for, as
+	// "HH:MM". Empty uses the average wake time of the last seven nights.
+	WakeTime string `json:"wake_time"`
+
+	// TrainingLoad adds the acute:chronic workload ratio to weekly notes,
+	// which needs the 21 days before the week from the local store.
+	TrainingLoad bool `json:"training_load"`
+
+	// Records keeps Records.md, the all-time bests, up to date whenever
+	// daily notes are written.
+	Records bool `json:"records"`
+
+	// Index keeps WHOOP.md and the year index notes up to date whenever
+	// notes are written.
+	Index bool `json:"index"`
+
+	// CacheDir overrides where fetched day data is stored between runs.
+	// $WHOOP_CACHE_DIR and the user cache directory are used otherwise.
+	CacheDir string `json:"cache_dir"`
+
+	// HTTPCacheTTL is how long WHOOP API responses are reused from the
+	// response cache in the cache directory, as a Go duration such as
+	// "30m". Responses for windows that ended more than two days ago are
+	// kept until "cache clear". Empty means "1h"; "0" turns the cache off.
+	HTTPCacheTTL string `json:"http_cache_ttl"`
+
+	// TemplateSet selects a subdirectory of the templates directory, e.g.
+	// "de" for templates/de/. Templates missing from the set fall back to
+	// the default English templates.
+	TemplateSet string `json:"template_set"`
+
+	// Locale is the language of generated text outside the templates,
+	// such as the persona and helper labels, and of number and date
+	// formatting: "en", "de", or "es". It defaults to template_set when
+	// that names a supported locale, and selects the template set of the
+	// same name when template_set is not set.
+	Locale string `json:"locale"`
+
+	// Units is the unit system of distances and elevations in notes:
+	// "metric" (the default) or "imperial".
+	Units string `json:"units"`
+
+	// SleepTime is what sleep averages measure: "in_bed" (the default),
+	// or "asleep", which leaves out time awake and without data.
+	SleepTime string `json:"sleep_time"`
+
+	// MaxAPICalls caps the WHOOP API requests made by a single run. Zero
+	// means unlimited; --max-calls overrides it.
+	MaxAPICalls int `json:"max_api_calls"`
+
+	Alerts Alerts `json:"alerts"`
+
+	Notify Notify `json:"notify"`
+
+	MQTT MQTT `json:"mqtt"`
+
+	Strava Strava `json:"strava"`
+
+	// Storage selects where notes are written. The zero value writes to
+	// the local output directory.
+	Storage storage.Config `json:"storage"`
+
+	Daemon Daemon `json:"daemon"`
+
+	Git Git `json:"git"`
+
+	Journal Journal `json:"journal"`
+
+	HTTP HTTP `json:"http"`
+}
+
+// UnitSystems are the values Units can take.
+var UnitSystems = []string{"metric", "imperial"}
+
+// SleepTimes are the values SleepTime can take.
+var SleepTimes = []string{"in_bed", "asleep"}
+
+// PropertyMetrics are the metrics daily notes can carry as properties, in
+// the order they are written.
+var PropertyMetrics = []string{"recovery", "hrv", "rhr", "strain", "sleep_hours", "workouts", "sports", "sport_categories"}
+
+// Frontmatter is added to the YAML frontmatter of every generated note.
+type Frontmatter struct {
+	// Fields are static fields, written as given: strings, numbers,
+	// booleans, or lists. Fields a note's template already sets are kept
+	// as the template writes them.
+	Fields map[string]any `json:"fields"`
+
+	// Tags are added to each note's tags.
+	Tags []string `json:"tags"`
+
+	// CSSClasses are set as cssclasses on every generated note, for
+	// styling them with CSS snippets.
+	CSSClasses []string `json:"cssclasses"`
+
+	Aliases Aliases `json:"aliases"`
+}
+
+// Aliases are patterns for the aliases of daily and weekly notes. YYYY,
+// MMM, MM, DD, and WW stand for the note's year, month name, month, day,
+// and ISO week, so "WHOOP MMM DD" gives "WHOOP Feb 10" and "YYYY-WWW"
+// gives "2026-W07". Weekly notes take the date of their Monday.
+type Aliases struct {
+	Daily  []string `json:"daily"`
+	Weekly []string `json:"weekly"`
+}
+
+// StrainBand is one strain label and the lowest strain it applies to.
+type StrainBand struct {
+	Name string  `json:"name"`
+	Min  float64 `json:"min"`
+}
+
+// StrainTarget suggests strain from Low to High for recoveries from
+// MinRecovery up.
+type StrainTarget struct {
+	MinRecovery float64 `json:"min_recovery"`
+	Low         float64 `json:"low"`
+	High        float64 `json:"high"`
+}
+
+// Sport is how notes present one sport. Empty fields keep the built-in
+// value.
+type Sport struct {
+	Emoji    string `json:"emoji"`
+	Tag      string `json:"tag"`
+	Category string `json:"category"`
+}
+
+// SportCategories are the categories a sport can be grouped under.
+var SportCategories = []string{"cardio", "strength", "recovery", "sport"}
+
+// Journal links each daily note from the note of the same date in a journal
+// kept elsewhere in the vault. It is off while Path is empty.
+type Journal struct {
+	// Path is the journal's note for a date, relative to
+	// $OBSIDIAN_VAULT_PATH unless absolute, with YYYY, MM, and DD standing
+	// for the year, month, and day, e.g. "Journal/YYYY/YYYY-MM-DD.md".
+	Path string `json:"path"`
+
+	// Create writes the journal note when it does not exist yet. Off by
+	// default, so that the journal's own template can create it.
+	Create bool `json:"create"`
+}
+
+// HTTP configures how whoop-garden reaches the network, for corporate
+// proxies or inspecting traffic. It applies to every request: the WHOOP API,
+// token refresh, Strava, notifications, and remote storage.
+type HTTP struct {
+	// Proxy is the URL of an http, https, or socks5 proxy. Empty uses
+	// $HTTPS_PROXY, $HTTP_PROXY, and $NO_PROXY.
+	Proxy string `json:"proxy"`
+
+	// CAFile is a PEM bundle of certificate authorities to trust besides
+	// the system's, such as a proxy's or mitmproxy's.
+	CAFile string `json:"ca_file"`
+
+	// MinTLSVersion is the lowest TLS version accepted: "1.2" (the
+	// default) or "1.3".
+	MinTLSVersion string `json:"min_tls_version"`
+
+	// InsecureSkipVerify accepts any certificate. For debugging only.
+	InsecureSkipVerify bool `json:"insecure_skip_verify"`
+}
+
+// Git configures committing written notes to the vault's git repository.
+type Git struct {
+	// AutoCommit commits the notes written by each run, leaving other
+	// changes in the repository alone.
+	AutoCommit bool `json:"auto_commit"`
+}
+
+// Daemon configures the daemon command's JSON-RPC endpoint.
+type Daemon struct {
+	// Token, if set, must be sent by clients as a bearer token.
+	Token string `json:"token"`
+}
+
+// Alerts configures metric alert rules and where they are delivered.
+type Alerts struct {
+	// WebhookURL receives a JSON POST per alert. Alerts are printed to
+	// stdout when it is empty.
+	WebhookURL string       `json:"webhook_url"`
+	Rules      []alert.Rule `json:"rules"`
+}
+
+// Notify configures the low-recovery notification and the daily summary.
+// Every configured ntfy, desktop, or webhook channel receives the
+// low-recovery and respiratory rate notifications; with none, they are
+// printed.
+type Notify struct {
+	// LowRecovery is the recovery percentage below which a newly scored
+	// day triggers a notification. Zero disables it.
+	LowRecovery float64 `json:"low_recovery"`
+
+	// Respiratory notifies when today's respiratory rate is flagged
+	// against its baseline; see respiratory_threshold.
+	Respiratory bool `json:"respiratory"`
+
+	// Ntfy is an ntfy topic URL, e.g. https://ntfy.sh/my-topic, and
+	// NtfyToken an optional access token for it.
+	Ntfy      string `json:"ntfy"`
+	NtfyToken string `json:"ntfy_token"`
+
+	// Desktop shows a local desktop notification.
+	Desktop bool `json:"desktop"`
+
+	// WebhookURL receives a JSON POST, as for alerts.
+	WebhookURL string `json:"webhook_url"`
+
+	// Chat channels for the daily summary posted by the notify command.
+	Telegram Telegram `json:"telegram"`
+	Slack    string   `json:"slack_webhook_url"`
+	Discord  string   `json:"discord_webhook_url"`
+
+	// SummaryChannels lists the chat channels ("telegram", "slack",
+	// "discord") that receive the daily summary automatically once the
+	// day's recovery is scored.
+	SummaryChannels []string `json:"summary_channels"`
+}
+
+// MQTT configures publishing each day's headline metrics to an MQTT broker,
+// with Home Assistant discovery topics.
+type MQTT struct {
+	// Broker is tcp://host:1883, or ssl://host:8883 for TLS. Publishing is
+	// off when it is empty.
+	Broker   string `json:"broker"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// TopicPrefix is prepended to state topics; default "whoop-garden".
+	TopicPrefix string `json:"topic_prefix"`
+
+	// DiscoveryPrefix is Home Assistant's discovery prefix; default
+	// "homeassistant".
+	DiscoveryPrefix string `json:"discovery_prefix"`
+}
+
+// Strava configures matching workouts to Strava activities. Set either a
+// long-lived AccessToken or the app's ClientID and ClientSecret with a
+// RefreshToken; matching is off when neither is set.
+type Strava struct {
+	AccessToken  string `json:"access_token"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Telegram identifies the bot and chat that receive Telegram messages.
+type Telegram struct {
+	BotToken string `json:"bot_token"`
+	ChatID   string `json:"chat_id"`
+}
+
+// Path returns the config file location: $WHOOP_CONFIG or ./config.json.
+func Path() string {
+	if p := os.Getenv("WHOOP_CONFIG"); p != "" {
+		return p
+	}
+	return defaultPath
+}
+
+// Load reads the config file from Path(). A missing file is not an error
+// and yields the zero Config.
+func Load() (Config, error) {
+	return LoadFile(Path())
+}
+
+// LoadFile reads and parses the config file at path.
+func LoadFile(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("read config %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	if err := alert.Validate(cfg.Alerts.Rules); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+	if n := cfg.Notify.LowRecovery; n < 0 || n > 100 {
+		return cfg, fmt.Errorf("config %s: notify.low_recovery must be between 0 and 100, got %g", path, n)
+	}
+	for _, ch := range cfg.Notify.SummaryChannels {
+		if ch != "telegram" && ch != "slack" && ch != "discord" {
+			return cfg, fmt.Errorf("config %s: unknown summary channel %q (want telegram, slack, or discord)", path, ch)
+		}
+	}
+	if s := cfg.Strava; s.RefreshToken != "" && (s.ClientID == "" || s.ClientSecret == "") {
+		return cfg, fmt.Errorf("config %s: strava.refresh_token needs strava.client_id and strava.client_secret", path)
+	}
+	if n := cfg.HRVBaselineDays; n != 0 && (n < 14 || n > 365) {
+		return cfg, fmt.Errorf("config %s: hrv_baseline_days must be between 14 and 365, got %d", path, n)
+	}
+	if cfg.Layout != "" && cfg.Layout != links.LayoutYear && cfg.Layout != links.LayoutFlat {
+		return cfg, fmt.Errorf("config %s: unknown layout %q (want year or flat)", path, cfg.Layout)
+	}
+	if s := cfg.LinkStyle; s != "" && s != links.LinkVault && s != links.LinkRelative && s != links.LinkName {
+		return cfg, fmt.Errorf("config %s: unknown link_style %q (want vault, relative, or name)", path, s)
+	}
+	if cfg.Locale != "" && !locale.Valid(cfg.Locale) {
+		return cfg, fmt.Errorf("config %s: unknown locale %q (want %s)", path, cfg.Locale, strings.Join(locale.Supported, ", "))
+	}
+	if cfg.Units != "" && !slices.Contains(UnitSystems, cfg.Units) {
+		return cfg, fmt.Errorf("config %s: unknown units %q (want %s)", path, cfg.Units, strings.Join(UnitSystems, ", "))
+	}
+	if cfg.SleepTime != "" && !slices.Contains(SleepTimes, cfg.SleepTime) {
+		return cfg, fmt.Errorf("config %s: unknown sleep_time %q (want %s)", path, cfg.SleepTime, strings.Join(SleepTimes, ", "))
+	}
+	if p := cfg.HTTP.Proxy; p != "" {
+		u, err := url.Parse(p)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") {
+			return cfg, fmt.Errorf("config %s: http.proxy must be an http, https, or socks5 URL, got %q", path, p)
+		}
+	}
+	switch cfg.HTTP.MinTLSVersion {
+	case "", "1.2", "1.3":
+	default:
+		return cfg, fmt.Errorf("config %s: http.min_tls_version must be \"1.2\" or \"1.3\", got %q", path, cfg.HTTP.MinTLSVersion)
+	}
+	if cfg.HTTPCacheTTL != "" {
+		if d, err := time.ParseDuration(cfg.HTTPCacheTTL); err != nil || d < 0 {
+			return cfg, fmt.Errorf("config %s: http_cache_ttl must be a duration such as \"1h\", got %q", path, cfg.HTTPCacheTTL)
+		}
+	}
+	if cfg.WakeTime != "" {
+		if _, err := time.Parse("15:04", cfg.WakeTime); err != nil {
+			return cfg, fmt.Errorf("config %s: wake_time must be HH:MM, got %q", path, cfg.WakeTime)
+		}
+	}
+	for metric, name := range cfg.Properties {
+		if !slices.Contains(PropertyMetrics, metric) {
+			return cfg, fmt.Errorf("config %s: properties: unknown metric %q", path, metric)
+		}
+		if name == "" {
+			return cfg, fmt.Errorf("config %s: properties: %s needs a property name", path, metric)
+		}
+	}
+	for key := range cfg.Frontmatter.Fields {
+		if key == "" || strings.ContainsAny(key, ": ") {
+			return cfg, fmt.Errorf("config %s: frontmatter: invalid field name %q", path, key)
+		}
+	}
+	for _, tag := range cfg.Frontmatter.Tags {
+		if tag == "" || strings.ContainsAny(tag, " #,") {
+			return cfg, fmt.Errorf("config %s: frontmatter: invalid tag %q (tags are written without # and cannot contain spaces)", path, tag)
+		}
+	}
+	for i, b := range cfg.StrainBands {
+		if b.Name == "" {
+			return cfg, fmt.Errorf("config %s: strain_bands: band %d needs a name", path, i+1)
+		}
+		if i > 0 && b.Min <= cfg.StrainBands[i-1].Min {
+			return cfg, fmt.Errorf("config %s: strain_bands must be listed from the lowest min up, got %g after %g", path, b.Min, cfg.StrainBands[i-1].Min)
+		}
+	}
+	for i, t := range cfg.StrainTargets {
+		if t.MinRecovery < 0 || t.MinRecovery > 100 {
+			return cfg, fmt.Errorf("config %s: strain_targets: min_recovery must be between 0 and 100, got %g", path, t.MinRecovery)
+		}
+		if t.Low < 0 || t.High > 21 || t.Low > t.High {
+			return cfg, fmt.Errorf("config %s: strain_targets: target %d needs 0 <= low <= high <= 21, got %g–%g", path, i+1, t.Low, t.High)
+		}
+		if i > 0 && t.MinRecovery <= cfg.StrainTargets[i-1].MinRecovery {
+			return cfg, fmt.Errorf("config %s: strain_targets must be listed from the lowest min_recovery up, got %g after %g", path, t.MinRecovery, cfg.StrainTargets[i-1].MinRecovery)
+		}
+	}
+	for id, name := range cfg.SportNames {
+		if name == "" {
+			return cfg, fmt.Errorf("config %s: sport_names: sport %d needs a name", path, id)
+		}
+	}
+	for name, sp := range cfg.Sports {
+		if sp.Tag != "" && strings.ContainsAny(sp.Tag, " #,") {
+			return cfg, fmt.Errorf("config %s: sports: %s: invalid tag %q (tags are written without # and cannot contain spaces)", path, name, sp.Tag)
+		}
+		if sp.Category != "" && !slices.Contains(SportCategories, sp.Category) {
+			return cfg, fmt.Errorf("config %s: sports: %s: unknown category %q (want one of %s)", path, name, sp.Category, strings.Join(SportCategories, ", "))
+		}
+	}
+	if cfg.RespiratoryThreshold < 0 {
+		return cfg, fmt.Errorf("config %s: respiratory_threshold must not be negative, got %g", path, cfg.RespiratoryThreshold)
+	}
+	if err := cfg.Storage.Validate(); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+	return cfg, nil
+}
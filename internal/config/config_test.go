@@ -0,0 +1,284 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFile_Missing(t *testing.T) {
+	cfg, err := LoadFile(filepath.Join(t.TempDir(), "nope.json"))
+	if err != nil {
+		t.Fatalf("missing config should not be an error, got %v", err)
+	}
+	if cfg.OutputDir != "" {
+		t.Errorf("expected zero config, got %+v", cfg)
+	}
+}
+
+func TestLoadFile_Parses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"output_dir": "/tmp/vault", "template_set": "de"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.OutputDir != "/tmp/vault" {
+		t.Errorf("OutputDir = %q, want /tmp/vault", cfg.OutputDir)
+	}
+	if cfg.TemplateSet != "de" {
+		t.Errorf("TemplateSet = %q, want de", cfg.TemplateSet)
+	}
+}
+
+func TestLoadFile_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{not json`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestPath_EnvOverride(t *testing.T) {
+	t.Setenv("WHOOP_CONFIG", "/etc/whoop.json")
+	if got := Path(); got != "/etc/whoop.json" {
+		t.Errorf("Path() = %q, want /etc/whoop.json", got)
+	}
+}
+
+func TestLoadFile_InvalidAlertRule(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	body := `{"alerts": {"rules": [{"name": "x", "metric": "nope", "threshold": 1}]}}`
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for unknown alert metric")
+	}
+}
+
+func TestLoadFile_InvalidLowRecovery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"notify": {"low_recovery": 120}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for low_recovery above 100")
+	}
+}
+
+func TestLoadFile_StravaRefreshWithoutClient(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"strava": {"refresh_token": "r"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for strava.refresh_token without client credentials")
+	}
+}
+
+func TestLoadFile_InvalidHRVBaselineDays(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"hrv_baseline_days": 7}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for hrv_baseline_days below 14")
+	}
+}
+
+func TestLoadFile_UnknownLayout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"layout": "monthly"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for an unknown layout")
+	}
+}
+
+func TestLoadFile_UnknownLinkStyle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"link_style": "absolute"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for an unknown link_style")
+	}
+}
+
+func TestLoadFile_UnknownLocale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"locale": "fr"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for an unknown locale")
+	}
+}
+
+func TestLoadFile_UnknownProperty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"properties": {"steps": "steps"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for an unknown property metric")
+	}
+}
+
+func TestLoadFile_InvalidFrontmatterTag(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"frontmatter": {"tags": ["#daily health"]}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for a tag with a space")
+	}
+}
+
+func TestLoadFile_UnorderedStrainBands(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"strain_bands": [{"name": "High", "min": 14}, {"name": "Low", "min": 0}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for strain_bands out of order")
+	}
+}
+
+func TestLoadFile_InvalidStrainTargets(t *testing.T) {
+	for _, targets := range []string{
+		`[{"min_recovery": 67, "low": 14, "high": 18}, {"min_recovery": 0, "low": 0, "high": 10}]`,
+		`[{"min_recovery": 0, "low": 12, "high": 8}]`,
+		`[{"min_recovery": 0, "low": 0, "high": 25}]`,
+	} {
+		path := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(path, []byte(`{"strain_targets": `+targets+`}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil {
+			t.Errorf("expected error for strain_targets %s", targets)
+		}
+	}
+}
+
+func TestLoadFile_SportNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"sport_names": {"233": "Padel"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.SportNames[233] != "Padel" {
+		t.Errorf("SportNames = %v", cfg.SportNames)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"sport_names": {"padel": "Padel"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for a sport_names key that is not an ID")
+	}
+}
+
+func TestLoadFile_Sports(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"sports": {"Running": {"emoji": "👟", "tag": "training/run", "category": "cardio"}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Sports["Running"].Tag != "training/run" {
+		t.Errorf("Sports = %v", cfg.Sports)
+	}
+
+	for _, bad := range []string{
+		`{"sports": {"Running": {"category": "endurance"}}}`,
+		`{"sports": {"Running": {"tag": "#run"}}}`,
+		`{"sports": {"Running": {"tag": "long run"}}}`,
+	} {
+		if err := os.WriteFile(path, []byte(bad), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil {
+			t.Errorf("expected error for %s", bad)
+		}
+	}
+}
+
+func TestLoadFile_InvalidUnits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"units": "furlongs"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for unknown units")
+	}
+}
+
+func TestLoadFile_InvalidSleepTime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"sleep_time": "awake"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for unknown sleep_time")
+	}
+}
+
+func TestLoadFile_InvalidWakeTime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"wake_time": "6.30"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for malformed wake_time")
+	}
+}
+
+func TestLoadFile_InvalidHTTP(t *testing.T) {
+	for _, body := range []string{
+		`{"http": {"proxy": "proxy.example:3128"}}`,
+		`{"http": {"proxy": "ftp://proxy.example"}}`,
+		`{"http": {"min_tls_version": "1.0"}}`,
+	} {
+		path := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil {
+			t.Errorf("expected error for %s", body)
+		}
+	}
+}
+
+func TestLoadFile_InvalidHTTPCacheTTL(t *testing.T) {
+	for _, ttl := range []string{"1 hour", "-5m"} {
+		path := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(path, []byte(`{"http_cache_ttl": "`+ttl+`"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil {
+			t.Errorf("expected error for http_cache_ttl %q", ttl)
+		}
+	}
+}
+
+func TestLoadFile_NegativeRespiratoryThreshold(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"respiratory_threshold": -1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for a negative respiratory_threshold")
+	}
+}
@@ -0,0 +1,315 @@
+// Package demo serves made-up WHOOP API responses, so the whole pipeline
+// can be tried without a WHOOP developer app.
+//
+// Transport answers the endpoints whoop-garden calls with plausible cycles,
+// recoveries, sleeps, and workouts. Each day's records are generated from
+// the date alone, so the same day always gets the same numbers.
+package demo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/models"
+)
+
+// Offset is the timezone_offset of every demo record.
+const Offset = "-05:00"
+
+// userID is the user_id of every demo record.
+const userID = 10001
+
+// Transport is an http.RoundTripper that answers WHOOP API requests with
+// demo data instead of sending them. Records that would start after Now are
+// left out; a nil Now means time.Now.
+type Transport struct {
+	Now func() time.Time
+}
+
+// RoundTrip answers req. Unknown paths get a 404, as they would from WHOOP.
+func (t Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	now := time.Now()
+	if t.Now != nil {
+		now = t.Now()
+	}
+	q := req.URL.Query()
+	start, _ := time.Parse(time.RFC3339, q.Get("start"))
+	end, _ := time.Parse(time.RFC3339, q.Get("end"))
+	if end.IsZero() || end.After(now) {
+		end = now
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+
+	var body any
+	switch path := req.URL.Path; {
+	case strings.HasSuffix(path, "/user/profile/basic"):
+		body = models.UserProfile{UserID: userID, Email: "demo@example.com", FirstName: "Demo", LastName: "User"}
+	case strings.HasSuffix(path, "/user/measurement/body"):
+		body = models.BodyMeasurements{HeightMeter: 1.78, WeightKilogram: 75.4, MaxHeartRate: 189}
+	case strings.HasSuffix(path, "/cycle"):
+		body = page(start, end, limit, func(d day) []models.Cycle { return []models.Cycle{d.cycle(now)} }, func(c models.Cycle) string { return c.Start })
+	case strings.HasSuffix(path, "/recovery"):
+		body = page(start, end, limit, func(d day) []models.Recovery { return []models.Recovery{d.recovery()} }, func(r models.Recovery) string { return r.CreatedAt })
+	case strings.HasSuffix(path, "/activity/sleep"):
+		body = page(start, end, limit, day.sleeps, func(s models.Sleep) string { return s.Start })
+	case strings.HasSuffix(path, "/activity/workout"):
+		body = page(start, end, limit, day.workouts, func(w models.Workout) string { return w.Start })
+	default:
+		return respond(req, http.StatusNotFound, nil), nil
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return respond(req, http.StatusOK, b), nil
+}
+
+// respond builds a response to req.
+func respond(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}
+}
+
+// page returns the records of the days around [start, end) whose key time
+// falls in the range, newest first like the WHOOP API.
+func page[T any](start, end time.Time, limit int, records func(day) []T, key func(T) string) models.PaginatedResponse[T] {
+	var out []T
+	first := start.UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	for d := first; d.Before(end.AddDate(0, 0, 1)); d = d.AddDate(0, 0, 1) {
+		for _, r := range records(newDay(d)) {
+			t, err := time.Parse(time.RFC3339, key(r))
+			if err == nil && !t.Before(start) && t.Before(end) {
+				out = append(out, r)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return key(out[i]) > key(out[j]) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return models.PaginatedResponse[T]{Records: out}
+}
+
+// day generates the records of the cycle starting on one calendar day.
+type day struct {
+	date  time.Time // midnight UTC
+	n     int64     // days since the Unix epoch
+	start time.Time // cycle start, on waking
+	bed   time.Duration
+	load  float64 // 0 (easy day) to 1 (hard day)
+	form  float64 // 0 (run down) to 1 (fresh)
+}
+
+// newDay seeds the generator for date from the date alone.
+func newDay(date time.Time) day {
+	n := date.Unix() / 86400
+	rng := rand.New(rand.NewSource(n))
+	d := day{date: date, n: n}
+	// Waking between 06:00 and 07:30 local time.
+	d.start = date.Add(11*time.Hour + time.Duration(rng.Intn(90))*time.Minute)
+	d.bed = 6*time.Hour + time.Duration(rng.Intn(150))*time.Minute
+	// A slow two-week wave plus day-to-day noise, so weeks differ.
+	wave := math.Sin(float64(n) * 2 * math.Pi / 13)
+	d.form = clamp(0.55+0.25*wave+0.25*(rng.Float64()-0.5)+0.2*(d.bed.Hours()-7.2)/1.2, 0.15, 0.98)
+	d.load = rng.Float64()
+	return d
+}
+
+// cycle returns the day's cycle. It ends at the next night's bedtime, so
+// the sleep query for the cycle finds only the night before it, and is
+// still open when that is after now.
+func (d day) cycle(now time.Time) models.Cycle {
+	rng := d.rand(1)
+	next := newDay(d.date.AddDate(0, 0, 1))
+	end := next.start.Add(-next.bed)
+	strain := 6 + 13*d.load + rng.Float64()
+	c := models.Cycle{
+		ID: int(d.n), UserID: userID,
+		CreatedAt: stamp(d.start), UpdatedAt: stamp(end),
+		Start: stamp(d.start), End: stamp(end), TimezoneOffset: Offset, ScoreState: "SCORED",
+		Score: models.CycleScore{
+			Strain:           round(strain, 1),
+			Kilojoule:        round(7500+450*strain+rng.Float64()*600, 1),
+			AverageHeartRate: 60 + int(strain),
+			MaxHeartRate:     130 + int(strain*3),
+		},
+	}
+	if end.After(now) {
+		c.End, c.UpdatedAt = "", stamp(now)
+	}
+	return c
+}
+
+// recovery returns the recovery scored on waking.
+func (d day) recovery() models.Recovery {
+	rng := d.rand(2)
+	score := math.Round(100 * d.form)
+	return models.Recovery{
+		CycleID: int(d.n), SleepID: d.sleepID(), UserID: userID,
+		CreatedAt: stamp(d.start), UpdatedAt: stamp(d.start.Add(5 * time.Minute)), ScoreState: "SCORED",
+		Score: models.RecoveryScore{
+			RecoveryScore:    score,
+			RestingHeartRate: math.Round(62 - 12*d.form + 2*rng.Float64()),
+			HrvRmssdMilli:    round(32+34*d.form+4*rng.Float64(), 3),
+			Spo2Percentage:   round(95.5+2*rng.Float64(), 2),
+			SkinTempCelsius:  round(33.3+0.6*rng.Float64(), 2),
+		},
+	}
+}
+
+// sleeps returns the night before the cycle and, on some days, a nap.
+func (d day) sleeps() []models.Sleep {
+	rng := d.rand(3)
+	awake := time.Duration(20+rng.Intn(40)) * time.Minute
+	asleep := d.bed - awake
+	deep := time.Duration(float64(asleep) * (0.18 + 0.06*rng.Float64()))
+	rem := time.Duration(float64(asleep) * (0.2 + 0.06*rng.Float64()))
+	need := 7*time.Hour + 30*time.Minute + time.Duration(rng.Intn(40))*time.Minute
+	night := models.Sleep{
+		ID: d.sleepID(), UserID: userID,
+		CreatedAt: stamp(d.start), UpdatedAt: stamp(d.start.Add(5 * time.Minute)),
+		Start: stamp(d.start.Add(-d.bed)), End: stamp(d.start), TimezoneOffset: Offset, ScoreState: "SCORED",
+		Score: models.SleepScore{
+			StageSummary: models.SleepStageSummary{
+				TotalInBedTimeMilli:         d.bed.Milliseconds(),
+				TotalAwakeTimeMilli:         awake.Milliseconds(),
+				TotalLightSleepTimeMilli:    (asleep - deep - rem).Milliseconds(),
+				TotalSlowWaveSleepTimeMilli: deep.Milliseconds(),
+				TotalRemSleepTimeMilli:      rem.Milliseconds(),
+				SleepCycleCount:             int(asleep.Hours() / 1.5),
+				DisturbanceCount:            5 + rng.Intn(12),
+			},
+			SleepNeeded: models.SleepNeeded{
+				BaselineMillis:             (7*time.Hour + 30*time.Minute).Milliseconds(),
+				NeedFromRecentStrainMillis: (need - 7*time.Hour - 30*time.Minute).Milliseconds(),
+			},
+			RespiratoryRate:  round(14.6+0.8*rng.Float64(), 2),
+			SleepPerformance: math.Min(100, math.Round(100*float64(asleep)/float64(need))),
+			SleepConsistency: math.Round(65 + 30*rng.Float64()),
+			SleepEfficiency:  round(100*float64(asleep)/float64(d.bed), 1),
+		},
+	}
+	sleeps := []models.Sleep{night}
+	if rng.Intn(10) == 0 {
+		start := d.start.Add(7*time.Hour + 30*time.Minute)
+		nap := 20 + rng.Intn(40)
+		sleeps = append(sleeps, models.Sleep{
+			ID: fmt.Sprintf("00000000-0000-4000-8001-%012d", d.n), UserID: userID,
+			CreatedAt: stamp(start), UpdatedAt: stamp(start),
+			Start: stamp(start), End: stamp(start.Add(time.Duration(nap) * time.Minute)), TimezoneOffset: Offset,
+			Nap: true, ScoreState: "SCORED",
+			Score: models.SleepScore{StageSummary: models.SleepStageSummary{
+				TotalInBedTimeMilli:      int64(nap) * 60_000,
+				TotalLightSleepTimeMilli: int64(nap-5) * 60_000,
+				TotalAwakeTimeMilli:      5 * 60_000,
+			}},
+		})
+	}
+	return sleeps
+}
+
+// sport is a workout the demo user does, with meters covered per minute
+// (zero when it has no distance).
+type sport struct {
+	id       int
+	name     string
+	perMin   float64
+	duration int // typical length in minutes
+}
+
+var sports = []sport{
+	{0, "Running", 180, 45},
+	{1, "Cycling", 420, 75},
+	{45, "Weightlifting", 0, 55},
+	{44, "Yoga", 0, 40},
+	{63, "Walking", 90, 35},
+}
+
+// workouts returns the day's workouts: most days one, hard days two, and
+// easy days sometimes none.
+func (d day) workouts() []models.Workout {
+	rng := d.rand(4)
+	count := 1
+	switch {
+	case d.load > 0.8:
+		count = 2
+	case d.load < 0.25:
+		count = 0
+	}
+	var ws []models.Workout
+	for i := 0; i < count; i++ {
+		s := sports[rng.Intn(len(sports))]
+		minutes := s.duration - 10 + rng.Intn(25)
+		// Morning before work, or evening after it.
+		start := d.start.Add(time.Duration(1+10*i)*time.Hour + time.Duration(rng.Intn(60))*time.Minute)
+		length := time.Duration(minutes) * time.Minute
+		strain := 5 + 10*d.load*rng.Float64() + 2*rng.Float64()
+		zones := [6]int64{}
+		left := length.Milliseconds()
+		for z := 1; z < 5 && left > 0; z++ {
+			zones[z] = left * int64(10+rng.Intn(30)) / 100
+			left -= zones[z]
+		}
+		zones[0] = left
+		dist := round(s.perMin*float64(minutes)*(0.9+0.2*rng.Float64()), 1)
+		ws = append(ws, models.Workout{
+			ID: fmt.Sprintf("00000000-0000-4000-8002-%010d%02d", d.n, i), UserID: userID,
+			CreatedAt: stamp(start.Add(length)), UpdatedAt: stamp(start.Add(length + 10*time.Minute)),
+			Start: stamp(start), End: stamp(start.Add(length)), TimezoneOffset: Offset,
+			SportID: s.id, SportName: s.name, ScoreState: "SCORED",
+			Score: models.WorkoutScore{
+				Strain:            round(strain, 1),
+				AverageHeartRate:  105 + int(strain*3),
+				MaxHeartRate:      140 + int(strain*3),
+				Kilojoule:         round(float64(minutes)*(25+4*strain), 1),
+				PercentRecorded:   100,
+				DistanceMeter:     dist,
+				AltitudeGainMeter: round(dist*(0.005+0.01*rng.Float64()), 1),
+				ZoneDuration: models.ZoneDuration{
+					ZoneZeroMillis: zones[0], ZoneOneMillis: zones[1], ZoneTwoMillis: zones[2],
+					ZoneThreeMillis: zones[3], ZoneFourMillis: zones[4],
+				},
+			},
+		})
+	}
+	return ws
+}
+
+// rand returns a generator for one kind of record of the day, so each
+// kind's numbers do not depend on which others were generated first.
+func (d day) rand(kind int64) *rand.Rand {
+	return rand.New(rand.NewSource(d.n<<3 | kind))
+}
+
+// sleepID is the ID of the night's sleep, shaped like a WHOOP v2 UUID.
+func (d day) sleepID() string {
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", d.n)
+}
+
+// stamp formats t like WHOOP does.
+func stamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
@@ -0,0 +1,84 @@
+package demo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/client"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/render"
+)
+
+func demoClient(now time.Time) *client.Client {
+	c := client.NewClient("demo")
+	c.SetTransport(Transport{Now: func() time.Time { return now }})
+	return c
+}
+
+func TestTransport_GetDayData(t *testing.T) {
+	c := demoClient(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	date := time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
+
+	d, err := fetch.GetDayData(c, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Cycle == nil || d.Cycle.End == "" {
+		t.Fatalf("want a closed cycle, got %+v", d.Cycle)
+	}
+	if d.Recovery == nil || d.Recovery.CycleID != d.Cycle.ID {
+		t.Fatalf("recovery not matched to cycle: %+v", d.Recovery)
+	}
+	if got := len(render.NonNapSleeps(d.Sleeps)); got != 1 {
+		t.Errorf("got %d main sleeps, want 1", got)
+	}
+	if s := render.PrimarySleep(d.Sleeps); s == nil || s.ID != d.Recovery.SleepID {
+		t.Errorf("recovery sleep_id does not match the night's sleep")
+	}
+	if r := d.Recovery.Score.RecoveryScore; r < 0 || r > 100 {
+		t.Errorf("recovery %v out of range", r)
+	}
+	if s := d.Cycle.Score.Strain; s < 0 || s > 21 {
+		t.Errorf("strain %v out of range", s)
+	}
+
+	again, err := fetch.GetDayData(c, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(d, again) {
+		t.Error("the same day gave different data")
+	}
+}
+
+func TestTransport_Now(t *testing.T) {
+	now := time.Date(2026, 2, 10, 20, 0, 0, 0, time.UTC)
+	c := demoClient(now)
+
+	today, err := fetch.GetDayData(c, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if today.Cycle == nil || today.Cycle.End != "" {
+		t.Errorf("want today's cycle open, got %+v", today.Cycle)
+	}
+
+	tomorrow, err := fetch.GetDayData(c, now.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tomorrow.Cycle != nil {
+		t.Errorf("want no cycle after now, got %+v", tomorrow.Cycle)
+	}
+}
+
+func TestTransport_Profile(t *testing.T) {
+	p, err := fetch.GetUserProfile(demoClient(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.FirstName != "Demo" {
+		t.Errorf("FirstName = %q", p.FirstName)
+	}
+}
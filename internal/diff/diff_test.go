@@ -0,0 +1,60 @@
+package diff
+
+import "testing"
+
+func TestUnified_Identical(t *testing.T) {
+	if got := Unified("a", "b", "x\ny\n", "x\ny\n"); got != "" {
+		t.Errorf("expected empty diff, got %q", got)
+	}
+}
+
+func TestUnified_SingleChange(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+	b := "1\n2\n3\n4\nFIVE\n6\n7\n8\n9\n"
+	want := `--- old
++++ new
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-5
++FIVE
+ 6
+ 7
+ 8
+`
+	if got := Unified("old", "new", a, b); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnified_NewFile(t *testing.T) {
+	want := "--- /dev/null\n+++ note.md\n@@ -0,0 +1,2 @@\n+a\n+b\n"
+	if got := Unified("/dev/null", "note.md", "", "a\nb\n"); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnified_SeparateHunks(t *testing.T) {
+	a := "a\n1\n2\n3\n4\n5\n6\n7\n8\nb\n"
+	b := "A\n1\n2\n3\n4\n5\n6\n7\n8\nB\n"
+	got := Unified("x", "y", a, b)
+	want := `--- x
++++ y
+@@ -1,4 +1,4 @@
+-a
++A
+ 1
+ 2
+ 3
+@@ -7,4 +7,4 @@
+ 6
+ 7
+ 8
+-b
++B
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,247 @@
+// Package export writes fetched DayData in machine-readable formats. Tabular
+// formats share a stable, flattened column schema at two levels: one row per
+// day (DayColumns) and one row per workout (WorkoutColumns).
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/analytics"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/models"
+	"github.com/benstraw/whoop-garden/internal/render"
+)
+
+// Type is the value type of a column.
+type Type int
+
+const (
+	String Type = iota
+	Float
+	Int
+	Date
+)
+
+// Column is one flattened field of a row of type T. Value returns nil when
+// the row has no value for the column (missing or unscored records);
+// otherwise a string, float64, int64, or time.Time matching Type.
+type Column[T any] struct {
+	Name  string
+	Type  Type
+	Value func(row T) any
+}
+
+// DayColumns is the day-level column set in output order. Durations are in
+// minutes. New columns are appended so positional consumers keep working.
+var DayColumns = []Column[fetch.DayData]{
+	{"date", Date, func(d fetch.DayData) any { return d.Date }},
+	{"recovery", Float, recovery(func(s models.RecoveryScore) float64 { return s.RecoveryScore })},
+	{"hrv", Float, recovery(func(s models.RecoveryScore) float64 { return s.HrvRmssdMilli })},
+	{"rhr", Float, recovery(func(s models.RecoveryScore) float64 { return s.RestingHeartRate })},
+	{"spo2", Float, recovery(func(s models.RecoveryScore) float64 { return s.Spo2Percentage })},
+	{"skin_temp", Float, recovery(func(s models.RecoveryScore) float64 { return s.SkinTempCelsius })},
+	{"strain", Float, cycle(func(s models.CycleScore) any { return s.Strain })},
+	{"kilojoule", Float, cycle(func(s models.CycleScore) any { return s.Kilojoule })},
+	{"avg_hr", Int, cycle(func(s models.CycleScore) any { return int64(s.AverageHeartRate) })},
+	{"max_hr", Int, cycle(func(s models.CycleScore) any { return int64(s.MaxHeartRate) })},
+	{"sleep_in_bed_min", Int, sleep(func(s models.Sleep) any { return minutes(s.Score.StageSummary.TotalInBedTimeMilli) })},
+	{"sleep_asleep_min", Int, sleep(func(s models.Sleep) any { return minutes(analytics.AsleepMillis(s)) })},
+	{"sleep_light_min", Int, sleep(func(s models.Sleep) any { return minutes(s.Score.StageSummary.TotalLightSleepTimeMilli) })},
+	{"sleep_sws_min", Int, sleep(func(s models.Sleep) any { return minutes(s.Score.StageSummary.TotalSlowWaveSleepTimeMilli) })},
+	{"sleep_rem_min", Int, sleep(func(s models.Sleep) any { return minutes(s.Score.StageSummary.TotalRemSleepTimeMilli) })},
+	{"sleep_performance", Float, sleep(func(s models.Sleep) any { return s.Score.SleepPerformance })},
+	{"sleep_efficiency", Float, sleep(func(s models.Sleep) any { return s.Score.SleepEfficiency })},
+	{"sleep_consistency", Float, sleep(func(s models.Sleep) any { return s.Score.SleepConsistency })},
+	{"respiratory_rate", Float, sleep(func(s models.Sleep) any { return s.Score.RespiratoryRate })},
+	{"disturbances", Int, sleep(func(s models.Sleep) any { return int64(s.Score.StageSummary.DisturbanceCount) })},
+	{"nap_min", Int, func(d fetch.DayData) any {
+		var total int64
+		for _, s := range d.Sleeps {
+			if s.Nap && s.ScoreState == "SCORED" {
+				total += s.Score.StageSummary.TotalInBedTimeMilli
+			}
+		}
+		return minutes(total)
+	}},
+	{"workouts", Int, func(d fetch.DayData) any { return int64(len(d.Workouts)) }},
+	{"workout_strain", Float, func(d fetch.DayData) any {
+		var total float64
+		for _, w := range d.Workouts {
+			if w.ScoreState == "SCORED" {
+				total += w.Score.Strain
+			}
+		}
+		return total
+	}},
+}
+
+// WorkoutRow is one workout together with the day it was attributed to.
+type WorkoutRow struct {
+	Date    time.Time
+	Workout models.Workout
+}
+
+// WorkoutRows flattens the workouts of days into rows, in day order.
+func WorkoutRows(days []fetch.DayData) []WorkoutRow {
+	var rows []WorkoutRow
+	for _, d := range days {
+		for _, w := range d.Workouts {
+			rows = append(rows, WorkoutRow{Date: d.Date, Workout: w})
+		}
+	}
+	return rows
+}
+
+// WorkoutColumns is the workout-level column set in output order.
+var WorkoutColumns = []Column[WorkoutRow]{
+	{"date", Date, func(r WorkoutRow) any { return r.Date }},
+	{"id", String, func(r WorkoutRow) any { return r.Workout.ID }},
+	{"sport_id", Int, func(r WorkoutRow) any { return int64(r.Workout.SportID) }},
+	{"sport", String, func(r WorkoutRow) any { return render.WorkoutSport(r.Workout) }},
+	{"start", String, func(r WorkoutRow) any { return r.Workout.Start }},
+	{"end", String, func(r WorkoutRow) any { return r.Workout.End }},
+	{"duration_min", Int, func(r WorkoutRow) any {
+		start, err1 := fetch.ParseWhoopTime(r.Workout.Start)
+		end, err2 := fetch.ParseWhoopTime(r.Workout.End)
+		if err1 != nil || err2 != nil {
+			return nil
+		}
+		return int64(end.Sub(start) / time.Minute)
+	}},
+	{"strain", Float, workout(func(s models.WorkoutScore) any { return s.Strain })},
+	{"avg_hr", Int, workout(func(s models.WorkoutScore) any { return int64(s.AverageHeartRate) })},
+	{"max_hr", Int, workout(func(s models.WorkoutScore) any { return int64(s.MaxHeartRate) })},
+	{"kilojoule", Float, workout(func(s models.WorkoutScore) any { return s.Kilojoule })},
+	{"distance_m", Float, workout(func(s models.WorkoutScore) any { return s.DistanceMeter })},
+	{"altitude_gain_m", Float, workout(func(s models.WorkoutScore) any { return s.AltitudeGainMeter })},
+	{"percent_recorded", Float, workout(func(s models.WorkoutScore) any { return s.PercentRecorded })},
+	{"zone0_min", Int, workout(func(s models.WorkoutScore) any { return minutes(s.ZoneDuration.ZoneZeroMillis) })},
+	{"zone1_min", Int, workout(func(s models.WorkoutScore) any { return minutes(s.ZoneDuration.ZoneOneMillis) })},
+	{"zone2_min", Int, workout(func(s models.WorkoutScore) any { return minutes(s.ZoneDuration.ZoneTwoMillis) })},
+	{"zone3_min", Int, workout(func(s models.WorkoutScore) any { return minutes(s.ZoneDuration.ZoneThreeMillis) })},
+	{"zone4_min", Int, workout(func(s models.WorkoutScore) any { return minutes(s.ZoneDuration.ZoneFourMillis) })},
+	{"zone5_min", Int, workout(func(s models.WorkoutScore) any { return minutes(s.ZoneDuration.ZoneFiveMillis) })},
+}
+
+func recovery(f func(models.RecoveryScore) float64) func(fetch.DayData) any {
+	return func(d fetch.DayData) any {
+		if d.Recovery == nil || d.Recovery.ScoreState != "SCORED" {
+			return nil
+		}
+		return f(d.Recovery.Score)
+	}
+}
+
+func cycle(f func(models.CycleScore) any) func(fetch.DayData) any {
+	return func(d fetch.DayData) any {
+		if d.Cycle == nil || d.Cycle.ScoreState != "SCORED" {
+			return nil
+		}
+		return f(d.Cycle.Score)
+	}
+}
+
+func sleep(f func(models.Sleep) any) func(fetch.DayData) any {
+	return func(d fetch.DayData) any {
+		s := analytics.PrimarySleep(d.Sleeps)
+		if s == nil || s.ScoreState != "SCORED" {
+			return nil
+		}
+		return f(*s)
+	}
+}
+
+func workout(f func(models.WorkoutScore) any) func(WorkoutRow) any {
+	return func(r WorkoutRow) any {
+		if r.Workout.ScoreState != "SCORED" {
+			return nil
+		}
+		return f(r.Workout.Score)
+	}
+}
+
+func minutes(ms int64) int64 { return ms / 60000 }
+
+// Select returns the named columns from all in the order given. An empty
+// list selects every column.
+func Select[T any](all []Column[T], names []string) ([]Column[T], error) {
+	if len(names) == 0 {
+		return all, nil
+	}
+	byName := make(map[string]Column[T], len(all))
+	for _, c := range all {
+		byName[c.Name] = c
+	}
+	cols := make([]Column[T], 0, len(names))
+	for _, n := range names {
+		c, ok := byName[strings.TrimSpace(n)]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q (available: %s)", n, strings.Join(ColumnNames(all), ", "))
+		}
+		cols = append(cols, c)
+	}
+	return cols, nil
+}
+
+// ColumnNames returns the names of cols.
+func ColumnNames[T any](cols []Column[T]) []string {
+	names := make([]string, len(cols))
+	for i, c := range cols {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// WriteJSON writes one DayData JSON object per line (NDJSON).
+func WriteJSON(w io.Writer, days []fetch.DayData) error {
+	enc := json.NewEncoder(w)
+	for _, d := range days {
+		if err := enc.Encode(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes a header row followed by one row per element of rows.
+// Missing values are empty cells.
+func WriteCSV[T any](w io.Writer, rows []T, cols []Column[T]) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(ColumnNames(cols)); err != nil {
+		return err
+	}
+	record := make([]string, len(cols))
+	for _, r := range rows {
+		for i, c := range cols {
+			record[i] = formatValue(c.Value(r))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case time.Time:
+		return v.Format("2006-01-02")
+	default:
+		return fmt.Sprint(v)
+	}
+}
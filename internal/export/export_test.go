@@ -0,0 +1,134 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/models"
+)
+
+func testDays() []fetch.DayData {
+	scored := fetch.DayData{
+		Date:     time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC),
+		Cycle:    &models.Cycle{ScoreState: "SCORED", Score: models.CycleScore{Strain: 12.5, AverageHeartRate: 70}},
+		Recovery: &models.Recovery{ScoreState: "SCORED", Score: models.RecoveryScore{RecoveryScore: 80, HrvRmssdMilli: 55.5}},
+		Sleeps: []models.Sleep{{ScoreState: "SCORED", Score: models.SleepScore{
+			StageSummary: models.SleepStageSummary{TotalInBedTimeMilli: 8 * 3600000, TotalAwakeTimeMilli: 30 * 60000},
+		}}},
+		Workouts: []models.Workout{
+			{ID: "w1", SportID: 0, ScoreState: "SCORED", Start: "2026-02-10T17:00:00.000Z", End: "2026-02-10T17:45:00.000Z", Score: models.WorkoutScore{Strain: 8.5}},
+			{ID: "w2", SportName: "yoga", ScoreState: "PENDING_SCORE"},
+		},
+	}
+	empty := fetch.DayData{Date: time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)}
+	return []fetch.DayData{scored, empty}
+}
+
+func TestWriteCSV(t *testing.T) {
+	cols, err := Select(DayColumns, []string{"date", "recovery", "hrv", "strain", "avg_hr", "sleep_asleep_min", "workouts"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, testDays(), cols); err != nil {
+		t.Fatal(err)
+	}
+	want := "date,recovery,hrv,strain,avg_hr,sleep_asleep_min,workouts\n" +
+		"2026-02-10,80,55.5,12.5,70,450,2\n" +
+		"2026-02-11,,,,,,0\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	cols, err := Select(DayColumns, nil)
+	if err != nil || len(cols) != len(DayColumns) {
+		t.Errorf("empty selection should return all %d columns, got %d (err %v)", len(DayColumns), len(cols), err)
+	}
+	if _, err := Select(DayColumns, []string{"date", "bogus"}); err == nil {
+		t.Error("expected error for unknown column")
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, testDays()); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	var d fetch.DayData
+	if err := json.Unmarshal([]byte(lines[0]), &d); err != nil {
+		t.Fatal(err)
+	}
+	if d.Recovery == nil || d.Recovery.Score.RecoveryScore != 80 {
+		t.Errorf("round-tripped recovery = %+v", d.Recovery)
+	}
+}
+
+func TestWriteCSV_Workouts(t *testing.T) {
+	cols, err := Select(WorkoutColumns, []string{"date", "id", "sport", "duration_min", "strain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, WorkoutRows(testDays()), cols); err != nil {
+		t.Fatal(err)
+	}
+	want := "date,id,sport,duration_min,strain\n" +
+		"2026-02-10,w1,Running,45,8.5\n" +
+		"2026-02-10,w2,yoga,,\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteParquet_Framing(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteParquet(&buf, testDays(), DayColumns); err != nil {
+		t.Fatal(err)
+	}
+	b := buf.Bytes()
+	if string(b[:4]) != "PAR1" || string(b[len(b)-4:]) != "PAR1" {
+		t.Fatalf("missing PAR1 magic: % x ... % x", b[:4], b[len(b)-4:])
+	}
+	metaLen := int(binary.LittleEndian.Uint32(b[len(b)-8:]))
+	if metaLen <= 0 || metaLen > len(b)-12 {
+		t.Fatalf("footer length %d out of range for %d-byte file", metaLen, len(b))
+	}
+	meta := b[len(b)-8-metaLen : len(b)-8]
+	if meta[len(meta)-1] != 0 {
+		t.Error("file metadata should end with a struct stop byte")
+	}
+	if !bytes.Contains(meta, []byte("sleep_asleep_min")) {
+		t.Error("file metadata is missing column names")
+	}
+}
+
+func TestEncodeLevels(t *testing.T) {
+	got := encodeLevels([]byte{1, 1, 1, 0, 1})
+	want := []byte{3 << 1, 1, 1 << 1, 0, 1 << 1, 1}
+	if !bytes.Equal(got, want) {
+		t.Errorf("encodeLevels = % x, want % x", got, want)
+	}
+}
+
+func TestThriftWriter(t *testing.T) {
+	var tw thriftWriter
+	tw.i32(1, 1)       // short form: delta 1, i32, zigzag(1)=2
+	tw.i64(3, -1)      // short form: delta 2, i64, zigzag(-1)=1
+	tw.binary(20, "a") // long form: type byte, zigzag field id 40
+	tw.stop()
+	want := []byte{0x15, 0x02, 0x26, 0x01, 0x08, 0x28, 0x01, 'a', 0x00}
+	if !bytes.Equal(tw.buf.Bytes(), want) {
+		t.Errorf("got % x, want % x", tw.buf.Bytes(), want)
+	}
+}
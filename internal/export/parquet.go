@@ -0,0 +1,273 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+)
+
+// Parquet physical and converted types, encodings, and other enum values
+// from the format's parquet.thrift.
+const (
+	pqInt32     = 1
+	pqInt64     = 2
+	pqDouble    = 5
+	pqByteArray = 6
+
+	pqOptional = 1
+
+	pqConvertedUTF8 = 0
+	pqConvertedDate = 6
+
+	pqEncodingPlain = 0
+	pqEncodingRLE   = 3
+
+	pqDataPage      = 0
+	pqUncompressed  = 0
+	pqFormatVersion = 1
+)
+
+var parquetMagic = []byte("PAR1")
+
+// WriteParquet writes rows as a single-row-group, uncompressed Parquet file.
+// Every column is OPTIONAL so missing values round-trip as nulls. Date
+// columns use the DATE logical type (days since the Unix epoch).
+//
+// The writer is deliberately minimal: one PLAIN-encoded data page per
+// column. That keeps it dependency-free while remaining readable by DuckDB,
+// pandas/pyarrow, and Spark.
+func WriteParquet[T any](w io.Writer, rows []T, cols []Column[T]) error {
+	var buf bytes.Buffer
+	buf.Write(parquetMagic)
+
+	type chunk struct {
+		offset int64
+		size   int64
+	}
+	chunks := make([]chunk, len(cols))
+	for i, c := range cols {
+		page := encodePage(rows, c)
+
+		var header thriftWriter
+		header.i32(1, pqDataPage)
+		header.i32(2, int32(len(page)))
+		header.i32(3, int32(len(page)))
+		header.structBegin(5)
+		header.i32(1, int32(len(rows)))
+		header.i32(2, pqEncodingPlain)
+		header.i32(3, pqEncodingRLE)
+		header.i32(4, pqEncodingRLE)
+		header.structEnd()
+		header.stop()
+
+		chunks[i] = chunk{offset: int64(buf.Len()), size: int64(header.buf.Len() + len(page))}
+		buf.Write(header.buf.Bytes())
+		buf.Write(page)
+	}
+
+	var meta thriftWriter
+	meta.i32(1, pqFormatVersion)
+	meta.listBegin(2, thriftStruct, len(cols)+1)
+	meta.elemBegin()
+	meta.binary(4, "schema")
+	meta.i32(5, int32(len(cols)))
+	meta.elemEnd()
+	for _, c := range cols {
+		meta.elemBegin()
+		meta.i32(1, physicalType(c.Type))
+		meta.i32(3, pqOptional)
+		meta.binary(4, c.Name)
+		switch c.Type {
+		case String:
+			meta.i32(6, pqConvertedUTF8)
+		case Date:
+			meta.i32(6, pqConvertedDate)
+		}
+		meta.elemEnd()
+	}
+	meta.i64(3, int64(len(rows)))
+	meta.listBegin(4, thriftStruct, 1)
+	meta.elemBegin()
+	meta.listBegin(1, thriftStruct, len(cols))
+	var total int64
+	for i, c := range cols {
+		meta.elemBegin()
+		meta.i64(2, chunks[i].offset)
+		meta.structBegin(3)
+		meta.i32(1, physicalType(c.Type))
+		meta.listBegin(2, thriftI32, 2)
+		meta.elemI32(pqEncodingPlain)
+		meta.elemI32(pqEncodingRLE)
+		meta.listBegin(3, thriftBinary, 1)
+		meta.elemBinary(c.Name)
+		meta.i32(4, pqUncompressed)
+		meta.i64(5, int64(len(rows)))
+		meta.i64(6, chunks[i].size)
+		meta.i64(7, chunks[i].size)
+		meta.i64(9, chunks[i].offset)
+		meta.structEnd()
+		meta.elemEnd()
+		total += chunks[i].size
+	}
+	meta.i64(2, total)
+	meta.i64(3, int64(len(rows)))
+	meta.elemEnd()
+	meta.binary(6, "whoop-garden")
+	meta.stop()
+
+	buf.Write(meta.buf.Bytes())
+	binary.Write(&buf, binary.LittleEndian, uint32(meta.buf.Len()))
+	buf.Write(parquetMagic)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func physicalType(t Type) int32 {
+	switch t {
+	case Float:
+		return pqDouble
+	case Int:
+		return pqInt64
+	case Date:
+		return pqInt32
+	default:
+		return pqByteArray
+	}
+}
+
+// encodePage returns the body of a data page for column c: RLE definition
+// levels (1 = present, 0 = null) followed by PLAIN-encoded non-null values.
+func encodePage[T any](rows []T, c Column[T]) []byte {
+	defs := make([]byte, len(rows))
+	var values bytes.Buffer
+	for i, r := range rows {
+		v := c.Value(r)
+		if v == nil {
+			continue
+		}
+		defs[i] = 1
+		switch v := v.(type) {
+		case float64:
+			binary.Write(&values, binary.LittleEndian, math.Float64bits(v))
+		case int64:
+			binary.Write(&values, binary.LittleEndian, v)
+		case time.Time:
+			days := time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400
+			binary.Write(&values, binary.LittleEndian, int32(days))
+		case string:
+			binary.Write(&values, binary.LittleEndian, uint32(len(v)))
+			values.WriteString(v)
+		}
+	}
+
+	levels := encodeLevels(defs)
+	var page bytes.Buffer
+	binary.Write(&page, binary.LittleEndian, uint32(len(levels)))
+	page.Write(levels)
+	page.Write(values.Bytes())
+	return page.Bytes()
+}
+
+// encodeLevels encodes bit-width-1 levels with the RLE/bit-packing hybrid,
+// using only RLE runs.
+func encodeLevels(levels []byte) []byte {
+	var out []byte
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		out = binary.AppendUvarint(out, uint64(j-i)<<1)
+		out = append(out, levels[i])
+		i = j
+	}
+	return out
+}
+
+// Thrift compact protocol type codes.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes the subset of the Thrift compact protocol needed for
+// Parquet metadata: i32, i64, binary, lists, and nested structs.
+type thriftWriter struct {
+	buf    bytes.Buffer
+	last   int16
+	nested []int16
+}
+
+func (t *thriftWriter) field(id int16, typ byte) {
+	if delta := id - t.last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.varint(int64(id))
+	}
+	t.last = id
+}
+
+func (t *thriftWriter) varint(v int64) {
+	t.buf.Write(binary.AppendUvarint(nil, uint64((v<<1)^(v>>63))))
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.field(id, thriftI32)
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.field(id, thriftI64)
+	t.varint(v)
+}
+
+func (t *thriftWriter) binary(id int16, s string) {
+	t.field(id, thriftBinary)
+	t.elemBinary(s)
+}
+
+func (t *thriftWriter) structBegin(id int16) {
+	t.field(id, thriftStruct)
+	t.elemBegin()
+}
+
+func (t *thriftWriter) structEnd() { t.elemEnd() }
+
+func (t *thriftWriter) listBegin(id int16, elemType byte, n int) {
+	t.field(id, thriftList)
+	if n < 15 {
+		t.buf.WriteByte(byte(n)<<4 | elemType)
+	} else {
+		t.buf.WriteByte(0xf0 | elemType)
+		t.buf.Write(binary.AppendUvarint(nil, uint64(n)))
+	}
+}
+
+// elemBegin starts a struct that is a list element or field value.
+func (t *thriftWriter) elemBegin() {
+	t.nested = append(t.nested, t.last)
+	t.last = 0
+}
+
+// elemEnd writes the struct's stop byte and restores the enclosing field id.
+func (t *thriftWriter) elemEnd() {
+	t.stop()
+	t.last = t.nested[len(t.nested)-1]
+	t.nested = t.nested[:len(t.nested)-1]
+}
+
+func (t *thriftWriter) elemI32(v int32) { t.varint(int64(v)) }
+
+func (t *thriftWriter) elemBinary(s string) {
+	t.buf.Write(binary.AppendUvarint(nil, uint64(len(s))))
+	t.buf.WriteString(s)
+}
+
+func (t *thriftWriter) stop() { t.buf.WriteByte(0) }
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/url"
+	"slices"
 	"time"
 
 	"github.com/benstraw/whoop-garden/internal/client"
@@ -15,11 +17,118 @@ const whoopTimeLayout = "2006-01-02T15:04:05.999Z"
 
 // DayData aggregates all WHOOP data for a single calendar day.
 type DayData struct {
-	Date     time.Time
-	Cycle    *models.Cycle
-	Recovery *models.Recovery
-	Sleeps   []models.Sleep
-	Workouts []models.Workout
+	Date     time.Time        `json:"date"`
+	Cycle    *models.Cycle    `json:"cycle"`
+	Recovery *models.Recovery `json:"recovery"`
+	Sleeps   []models.Sleep   `json:"sleeps"`
+	Workouts []models.Workout `json:"workouts"`
+
+	// DuplicateWorkouts holds workouts that overlapped one in Workouts
+	// (typically a manual entry and WHOOP's auto-detected copy) and were
+	// left out of it so they are not counted twice.
+	DuplicateWorkouts []models.Workout `json:"duplicate_workouts,omitempty"`
+}
+
+// Rolling holds averages over a window of days. Each average covers only
+// the days that have the metric; Days is how many days had any data.
+type Rolling struct {
+	Days        int
+	AvgRecovery float64
+	AvgHRV      float64
+	AvgRHR      float64
+	AvgStrain   float64
+	AvgSleep    int64 // primary sleep, milliseconds: AvgInBed or AvgAsleep
+	AvgInBed    int64
+	AvgAsleep   int64
+}
+
+// Baseline is a metric's mean and spread over a window of days, and where
+// one day's value falls against it.
+type Baseline struct {
+	Days       int // scored days in the window
+	Mean       float64
+	Std        float64
+	Value      float64
+	Z          float64 // (Value - Mean) / Std
+	Percentile float64 // share of the window below Value, 0–100
+	Flagged    bool    // Value strays from Mean by more than a set threshold
+}
+
+// Shift is a change of time zone between consecutive days' cycles.
+type Shift struct {
+	Date     time.Time // the first day in the new zone
+	From, To string    // UTC offsets, e.g. "-05:00"
+	Hours    float64   // To minus From
+}
+
+// Streak is a run of consecutive days meeting a condition: the one ending
+// on the latest day, zero if that day breaks it, and the longest seen.
+type Streak struct {
+	Current int
+	Best    int
+}
+
+// Streaks tracks green recovery days, nights of at least seven hours'
+// sleep, and days with a workout.
+type Streaks struct {
+	Green   Streak
+	Sleep   Streak
+	Workout Streak
+}
+
+// TrainingLoad compares the mean day strain of the last 7 days (acute)
+// with that of the last 28 (chronic). Spike is set when Ratio, the
+// acute:chronic workload ratio, exceeds 1.5.
+type TrainingLoad struct {
+	Acute   float64
+	Chronic float64
+	Ratio   float64
+	Spike   bool
+}
+
+// SleepNight is one scored night's sleep need and time asleep, with the
+// debt carried after it.
+type SleepNight struct {
+	Date   time.Time
+	Need   int64 // milliseconds, excluding need from existing debt
+	Asleep int64 // milliseconds
+	Debt   int64 // cumulative debt after this night, milliseconds
+}
+
+// SleepNeed is WHOOP's sleep need for a night, by component, against the
+// time slept. Averaged over several nights, each field is the mean and
+// Nights counts the nights.
+type SleepNeed struct {
+	Nights      int
+	Baseline    int64   // milliseconds
+	Debt        int64   // from existing sleep debt, milliseconds
+	Strain      int64   // from recent strain, milliseconds
+	Nap         int64   // from recent naps (negative: naps lower the need), milliseconds
+	Need        int64   // the sum of the components, milliseconds
+	Asleep      int64   // milliseconds
+	Fulfillment float64 // Asleep as a percentage of Need
+}
+
+// Bedtime is a recommended bedtime: early enough to sleep Need before
+// Wake, spending InBed in bed at the recent sleep efficiency. Bedtime and
+// Wake are local hours from midnight, Bedtime negative before midnight.
+type Bedtime struct {
+	Bedtime    float64
+	Wake       float64
+	Need       int64   // milliseconds
+	InBed      int64   // milliseconds
+	Efficiency float64 // percentage of time in bed asleep
+}
+
+// SleepDebt is the sleep debt built up over a window of nights and a
+// suggested way to pay it off: PayoffMillis of extra sleep on each of the
+// next PayoffNights nights. PayoffNights is zero when no payoff is needed.
+type SleepDebt struct {
+	Nights       []SleepNight
+	Latest       *SleepNight // the window's last day; nil if it has no scored night
+	Debt         int64
+	PayoffNights int
+	PayoffMillis int64
 }
 
 // GetUserProfile fetches the authenticated user's profile.
@@ -76,6 +185,7 @@ func fetchPaginated[T any](c *client.Client, path string, start, end time.Time)
 		if page.NextToken == "" {
 			break
 		}
+		slog.Debug("next page", "path", path, "records", len(all), "next_token", page.NextToken)
 		nextToken = page.NextToken
 	}
 	return all, nil
@@ -127,15 +237,9 @@ func GetDayData(c *client.Client, date time.Time) (DayData, error) {
 	cycle := cycles[0]
 	data.Cycle = &cycle
 
-	cycleStart, err := ParseWhoopTime(cycle.Start)
+	cycleStart, cycleEnd, err := cycleBounds(cycle, nextDay)
 	if err != nil {
-		return data, fmt.Errorf("parse cycle start: %w", err)
-	}
-	cycleEnd := nextDay // default if cycle hasn't ended yet
-	if cycle.End != "" {
-		if t, err := ParseWhoopTime(cycle.End); err == nil {
-			cycleEnd = t
-		}
+		return data, err
 	}
 
 	// Phase 2: fetch recovery, sleeps, and workouts concurrently.
@@ -162,10 +266,7 @@ func GetDayData(c *client.Client, date time.Time) (DayData, error) {
 	}()
 
 	go func() {
-		// Sleep window: 24h before cycle start (captures preceding night's sleep)
-		// through cycle end (captures naps during the day).
-		sleepStart := cycleStart.Add(-24 * time.Hour)
-		v, err := GetSleeps(c, sleepStart, cycleEnd)
+		v, err := GetSleeps(c, sleepWindowStart(cycleStart), cycleEnd)
 		sleepCh <- sleepResult{v, err}
 	}()
 
@@ -195,11 +296,254 @@ func GetDayData(c *client.Client, date time.Time) (DayData, error) {
 		}
 	}
 	data.Sleeps = sr.v
-	data.Workouts = wr.v
+	data.Workouts, data.DuplicateWorkouts = SplitDuplicateWorkouts(wr.v)
 
 	return data, nil
 }
 
+// duplicateOverlap is the share of the shorter workout's duration two
+// workouts must overlap by to be treated as the same session.
+const duplicateOverlap = 0.5
+
+// SplitDuplicateWorkouts separates workouts that record the same session
+// twice. Two workouts are duplicates when they overlap by at least half of
+// the shorter one's duration; of each group, the scored workout with the
+// highest strain is kept. kept preserves the input order. Workouts with
+// unparseable times are always kept.
+func SplitDuplicateWorkouts(ws []models.Workout) (kept, dupes []models.Workout) {
+	type span struct{ start, end time.Time }
+	spans := make([]span, len(ws))
+	valid := make([]bool, len(ws))
+	for i, w := range ws {
+		start, err1 := ParseWhoopTime(w.Start)
+		end, err2 := ParseWhoopTime(w.End)
+		spans[i] = span{start, end}
+		valid[i] = err1 == nil && err2 == nil && end.After(start)
+	}
+	overlaps := func(i, j int) bool {
+		a, b := spans[i], spans[j]
+		start, end := a.start, a.end
+		if b.start.After(start) {
+			start = b.start
+		}
+		if b.end.Before(end) {
+			end = b.end
+		}
+		shorter := a.end.Sub(a.start)
+		if d := b.end.Sub(b.start); d < shorter {
+			shorter = d
+		}
+		return end.Sub(start) >= time.Duration(float64(shorter)*duplicateOverlap)
+	}
+	better := func(i, j int) bool {
+		si, sj := ws[i].ScoreState == "SCORED", ws[j].ScoreState == "SCORED"
+		if si != sj {
+			return si
+		}
+		return ws[i].Score.Strain > ws[j].Score.Strain
+	}
+
+	dropped := make([]bool, len(ws))
+	for i := range ws {
+		if !valid[i] || dropped[i] {
+			continue
+		}
+		for j := i + 1; j < len(ws); j++ {
+			if !valid[j] || dropped[j] || !overlaps(i, j) {
+				continue
+			}
+			if better(j, i) {
+				dropped[i] = true
+				break
+			}
+			dropped[j] = true
+		}
+	}
+	if !slices.Contains(dropped, true) {
+		return ws, nil
+	}
+	for i, w := range ws {
+		if dropped[i] {
+			dupes = append(dupes, w)
+		} else {
+			kept = append(kept, w)
+		}
+	}
+	return kept, dupes
+}
+
+// GetRecoveryData fetches only the recovery and sleeps for date, using two
+// API calls instead of GetDayData's four. Recoveries are queried by the
+// calendar day (a recovery is created when the cycle starts on waking) and
+// matched to a sleep via sleep_id. Cycle and Workouts are left empty.
+func GetRecoveryData(c *client.Client, date time.Time) (DayData, error) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	nextDay := day.AddDate(0, 0, 1)
+
+	data := DayData{Date: day}
+
+	type sleepResult struct {
+		v   []models.Sleep
+		err error
+	}
+	sleepCh := make(chan sleepResult, 1)
+	go func() {
+		v, err := GetSleeps(c, sleepWindowStart(day), nextDay)
+		sleepCh <- sleepResult{v, err}
+	}()
+
+	recoveries, err := GetRecoveries(c, day, nextDay)
+	sr := <-sleepCh
+	if err != nil {
+		return data, err
+	}
+	if sr.err != nil {
+		return data, sr.err
+	}
+	data.Sleeps = sr.v
+
+	for i := range recoveries {
+		for _, s := range sr.v {
+			if recoveries[i].SleepID == s.ID {
+				data.Recovery = &recoveries[i]
+				return data, nil
+			}
+		}
+	}
+	if len(recoveries) > 0 {
+		data.Recovery = &recoveries[0]
+	}
+	return data, nil
+}
+
+// cycleBounds returns the time range of cycle. An open cycle (no end yet)
+// is treated as ending at fallbackEnd.
+func cycleBounds(cycle models.Cycle, fallbackEnd time.Time) (time.Time, time.Time, error) {
+	start, err := ParseWhoopTime(cycle.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("parse cycle start: %w", err)
+	}
+	end := fallbackEnd
+	if cycle.End != "" {
+		if t, err := ParseWhoopTime(cycle.End); err == nil {
+			end = t
+		}
+	}
+	return start, end, nil
+}
+
+// sleepWindowStart returns where the sleep query for a cycle begins: 24h
+// before cycle start captures the preceding night's sleep, and the window
+// runs through cycle end to capture naps during the day.
+func sleepWindowStart(cycleStart time.Time) time.Time {
+	return cycleStart.Add(-24 * time.Hour)
+}
+
+// latestUpdate probes path with limit=1 and returns the updated_at of the
+// first record in [start, end), or "" when there are none.
+func latestUpdate(c *client.Client, path string, start, end time.Time) (string, error) {
+	params := url.Values{}
+	params.Set("start", start.UTC().Format(time.RFC3339))
+	params.Set("end", end.UTC().Format(time.RFC3339))
+	params.Set("limit", "1")
+	body, err := c.Get(path, params)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("probe %s: %w", path, err)
+	}
+	var page models.PaginatedResponse[struct {
+		UpdatedAt string `json:"updated_at"`
+	}]
+	if err := json.Unmarshal(body, &page); err != nil {
+		return "", fmt.Errorf("parse probe %s: %w", path, err)
+	}
+	if len(page.Records) == 0 {
+		return "", nil
+	}
+	return page.Records[0].UpdatedAt, nil
+}
+
+// newer reports whether WHOOP timestamp probe is later than every timestamp
+// in stored. A probe that finds a record when none were stored counts as
+// newer; an empty probe never does.
+func newer(probe string, stored ...string) bool {
+	if probe == "" {
+		return false
+	}
+	p, err := ParseWhoopTime(probe)
+	if err != nil {
+		return true // unparseable: refetch to be safe
+	}
+	for _, s := range stored {
+		if t, err := ParseWhoopTime(s); err == nil && !p.After(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// DayChanged reports whether WHOOP holds newer data for d than d itself. It
+// issues one limit=1 request per collection over the same windows as
+// GetDayData and compares the newest record's updated_at with the stored
+// records. This is much cheaper than a full refetch but only sees the first
+// record WHOOP returns for each collection (the most recent by start time).
+func DayChanged(c *client.Client, d DayData) (bool, error) {
+	day := time.Date(d.Date.Year(), d.Date.Month(), d.Date.Day(), 0, 0, 0, 0, time.UTC)
+	nextDay := day.AddDate(0, 0, 1)
+
+	probe, err := latestUpdate(c, "/cycle", day, nextDay)
+	if err != nil {
+		return false, err
+	}
+	if d.Cycle == nil {
+		return probe != "", nil
+	}
+	if newer(probe, d.Cycle.UpdatedAt) {
+		return true, nil
+	}
+
+	cycleStart, cycleEnd, err := cycleBounds(*d.Cycle, nextDay)
+	if err != nil {
+		return false, err
+	}
+
+	var recoveryUpdated []string
+	if d.Recovery != nil {
+		recoveryUpdated = append(recoveryUpdated, d.Recovery.UpdatedAt)
+	}
+	var sleepUpdated, workoutUpdated []string
+	for _, s := range d.Sleeps {
+		sleepUpdated = append(sleepUpdated, s.UpdatedAt)
+	}
+	for _, ws := range [][]models.Workout{d.Workouts, d.DuplicateWorkouts} {
+		for _, w := range ws {
+			workoutUpdated = append(workoutUpdated, w.UpdatedAt)
+		}
+	}
+
+	probes := []struct {
+		path       string
+		start, end time.Time
+		stored     []string
+	}{
+		{"/recovery", cycleStart, cycleEnd, recoveryUpdated},
+		{"/activity/sleep", sleepWindowStart(cycleStart), cycleEnd, sleepUpdated},
+		{"/activity/workout", cycleStart, cycleEnd, workoutUpdated},
+	}
+	for _, p := range probes {
+		got, err := latestUpdate(c, p.path, p.start, p.end)
+		if err != nil {
+			return false, err
+		}
+		if newer(got, p.stored...) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // ParseWhoopTime parses a WHOOP timestamp string into time.Time.
 func ParseWhoopTime(s string) (time.Time, error) {
 	t, err := time.Parse(whoopTimeLayout, s)
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
@@ -198,3 +200,121 @@ func TestGetRecoveries_NotFound(t *testing.T) {
 		t.Errorf("expected 0 recoveries, got %d", len(recoveries))
 	}
 }
+
+// --- DayChanged ---
+
+func TestDayChanged(t *testing.T) {
+	updated := "2026-02-11T09:00:00.000Z"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("limit") != "1" {
+			t.Errorf("probe should use limit=1, got %q", r.URL.RawQuery)
+		}
+		if r.URL.Path != "/cycle" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(models.PaginatedResponse[models.Cycle]{
+			Records: []models.Cycle{{ID: 1, UpdatedAt: updated}},
+		})
+	}))
+	defer srv.Close()
+	c := client.NewClientWithBaseURL("tok", srv.URL)
+
+	stored := DayData{
+		Date:  time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC),
+		Cycle: &models.Cycle{ID: 1, Start: "2026-02-10T07:00:00.000Z", End: "2026-02-11T07:00:00.000Z", UpdatedAt: updated},
+	}
+	changed, err := DayChanged(c, stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed {
+		t.Error("same updated_at should not count as changed")
+	}
+
+	updated = "2026-02-12T10:00:00.000Z"
+	if changed, _ := DayChanged(c, stored); !changed {
+		t.Error("newer cycle updated_at should count as changed")
+	}
+
+	if changed, _ := DayChanged(c, DayData{Date: stored.Date}); !changed {
+		t.Error("a cycle appearing where none was stored should count as changed")
+	}
+}
+
+// --- GetRecoveryData ---
+
+func TestGetRecoveryData(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls[r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/recovery":
+			json.NewEncoder(w).Encode(models.PaginatedResponse[models.Recovery]{
+				Records: []models.Recovery{{CycleID: 1, SleepID: "other"}, {CycleID: 2, SleepID: "main"}},
+			})
+		case "/activity/sleep":
+			json.NewEncoder(w).Encode(models.PaginatedResponse[models.Sleep]{
+				Records: []models.Sleep{{ID: "main"}},
+			})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	c := client.NewClientWithBaseURL("tok", srv.URL)
+
+	d, err := GetRecoveryData(c, time.Date(2026, 2, 10, 15, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 2 || calls["/recovery"] != 1 || calls["/activity/sleep"] != 1 {
+		t.Errorf("calls = %v, want one recovery and one sleep request", calls)
+	}
+	if d.Recovery == nil || d.Recovery.CycleID != 2 {
+		t.Errorf("Recovery = %+v, want the one matching the fetched sleep", d.Recovery)
+	}
+	if d.Cycle != nil || len(d.Sleeps) != 1 {
+		t.Errorf("unexpected DayData: cycle=%v sleeps=%d", d.Cycle, len(d.Sleeps))
+	}
+}
+
+// --- SplitDuplicateWorkouts ---
+
+func TestSplitDuplicateWorkouts(t *testing.T) {
+	w := func(id, start, end, state string, strain float64) models.Workout {
+		return models.Workout{ID: id, Start: start, End: end, ScoreState: state, Score: models.WorkoutScore{Strain: strain}}
+	}
+	ws := []models.Workout{
+		// Manual entry and auto-detected copy of the same run.
+		w("manual", "2026-02-10T07:00:00.000Z", "2026-02-10T08:00:00.000Z", "SCORED", 9.5),
+		w("auto", "2026-02-10T07:05:00.000Z", "2026-02-10T07:55:00.000Z", "SCORED", 10.2),
+		// Back-to-back session that only touches the run's end.
+		w("lift", "2026-02-10T07:50:00.000Z", "2026-02-10T08:40:00.000Z", "SCORED", 6.0),
+		// Unscored copy of the lift loses even with higher strain.
+		w("lift-copy", "2026-02-10T08:00:00.000Z", "2026-02-10T08:40:00.000Z", "PENDING_SCORE", 12),
+		w("bad", "nope", "2026-02-10T09:00:00.000Z", "SCORED", 1),
+	}
+	kept, dupes := SplitDuplicateWorkouts(ws)
+
+	ids := func(ws []models.Workout) (out []string) {
+		for _, w := range ws {
+			out = append(out, w.ID)
+		}
+		return out
+	}
+	if got, want := ids(kept), []string{"auto", "lift", "bad"}; !slices.Equal(got, want) {
+		t.Errorf("kept = %v, want %v", got, want)
+	}
+	if got, want := ids(dupes), []string{"manual", "lift-copy"}; !slices.Equal(got, want) {
+		t.Errorf("dupes = %v, want %v", got, want)
+	}
+
+	if kept, dupes := SplitDuplicateWorkouts(ws[2:3]); len(kept) != 1 || dupes != nil {
+		t.Errorf("single workout: kept %d, dupes %v", len(kept), dupes)
+	}
+}
@@ -0,0 +1,275 @@
+// Package links checks and repairs the wikilinks between generated notes.
+package links
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// wikilinkRe matches [[target]], [[target|alias]] and [[target#heading|alias]].
+var wikilinkRe = regexp.MustCompile(`\[\[([^\]|#]+)(#[^\]|]*)?(?:\|([^\]]*))?\]\]`)
+
+// noteNameRe matches generated note basenames and captures kind and key.
+var noteNameRe = regexp.MustCompile(`^(daily|weekly|monthly|quarterly|index)-(\d{4}-\d{2}-\d{2}|\d{4}-W\d{2}|\d{4}-\d{2}|\d{4}-Q[1-4]|\d{4})$`)
+
+// Layouts arrange notes in the output directory.
+const (
+	LayoutYear = "year" // a folder per year, e.g. 2026/daily-2026-02-10.md (default)
+	LayoutFlat = "flat" // every note directly in the output directory
+)
+
+// Rel returns where layout puts the note of kind and key, as a
+// slash-separated path relative to the output directory without the .md
+// extension. The year folder is the year the key starts with, which for
+// weekly notes is the ISO year.
+func Rel(layout, kind, key string) string {
+	name := kind + "-" + key
+	if layout == LayoutFlat {
+		return name
+	}
+	return key[:4] + "/" + name
+}
+
+// Link styles are the ways a Linker writes wikilink targets.
+const (
+	LinkVault    = "vault"    // the path from the vault root (default)
+	LinkRelative = "relative" // the path from the linking note's folder
+	LinkName     = "name"     // the note name alone, which Obsidian resolves
+)
+
+// Linker writes the wikilink targets between notes.
+type Linker struct {
+	Layout string // see Rel
+	Folder string // the output directory's path in the vault, e.g. "Health/WHOOP"
+	Style  string // LinkVault, LinkRelative, or LinkName
+}
+
+// Path returns the vault path of the note at rel, a slash-separated path
+// relative to the output directory without the .md extension.
+func (l Linker) Path(rel string) string { return path.Join(l.Folder, rel) }
+
+// Link returns the target of a link from the note at vault path from to the
+// note at vault path to.
+func (l Linker) Link(from, to string) string {
+	switch l.Style {
+	case LinkName:
+		return path.Base(to)
+	case LinkRelative:
+		rel, err := filepath.Rel(filepath.FromSlash(path.Dir(from)), filepath.FromSlash(to))
+		if err == nil {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return to
+}
+
+// Note returns the target of a link from the note at vault path from to the
+// generated note of kind and key.
+func (l Linker) Note(from, kind, key string) string {
+	return l.Link(from, l.Path(Rel(l.Layout, kind, key)))
+}
+
+// Retarget points every link to a generated note in content, the note at
+// vault path from, at where l puts that note. Headings and aliases are kept.
+func Retarget(content, from string, l Linker) string {
+	links := Parse(content)
+	// Apply from the end so earlier offsets stay valid.
+	for i := len(links) - 1; i >= 0; i-- {
+		wl := links[i]
+		kind, key, ok := NoteOf(wl.Target)
+		if !ok {
+			continue
+		}
+		target := l.Note(from, kind, key)
+		if target == wl.Target {
+			continue
+		}
+		rest := content[wl.start+len("[[")+len(wl.Target) : wl.end]
+		content = content[:wl.start] + "[[" + target + rest + content[wl.end:]
+	}
+	return content
+}
+
+// Link is a wikilink found in a note.
+type Link struct {
+	Target string
+	Alias  string
+	Line   int
+	start  int // byte offsets of the whole [[...]] in the note
+	end    int
+}
+
+// Parse returns every wikilink in content.
+func Parse(content string) []Link {
+	var out []Link
+	for _, m := range wikilinkRe.FindAllStringSubmatchIndex(content, -1) {
+		l := Link{
+			Target: content[m[2]:m[3]],
+			Line:   strings.Count(content[:m[0]], "\n") + 1,
+			start:  m[0],
+			end:    m[1],
+		}
+		if m[6] >= 0 {
+			l.Alias = content[m[6]:m[7]]
+		}
+		out = append(out, l)
+	}
+	return out
+}
+
+// Note is a generated daily, weekly, monthly, or year index note on disk.
+type Note struct {
+	Kind string // "daily", "weekly", "monthly", "quarterly", or "index"
+	Key  string // "2026-02-10", "2026-W07", "2026-02", or "2026"
+	Path string
+}
+
+// NoteOf reports the kind and key of a link target or note path.
+func NoteOf(target string) (kind, key string, ok bool) {
+	base := strings.TrimSuffix(path.Base(filepath.ToSlash(target)), ".md")
+	m := noteNameRe.FindStringSubmatch(base)
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// Scan finds all generated notes under dir.
+func Scan(dir string) ([]Note, error) {
+	var notes []Note
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(p) != ".md" {
+			return nil
+		}
+		if kind, key, ok := NoteOf(p); ok {
+			notes = append(notes, Note{Kind: kind, Key: key, Path: p})
+		}
+		return nil
+	})
+	return notes, err
+}
+
+// Broken is a link to a generated note that does not exist.
+type Broken struct {
+	Note Note
+	Link Link
+	Kind string
+	Key  string
+	// Suggest is the key of the nearest existing note in the link's
+	// direction, or "" when there is none. It is only set for navigation
+	// links between notes of the same kind (prev/next day, prev/next week).
+	Suggest string
+}
+
+// index holds the sorted keys of existing notes per kind.
+type index map[string][]string
+
+func newIndex(notes []Note) index {
+	idx := index{}
+	for _, n := range notes {
+		idx[n.Kind] = append(idx[n.Kind], n.Key)
+	}
+	for k := range idx {
+		sort.Strings(idx[k])
+	}
+	return idx
+}
+
+func (idx index) has(kind, key string) bool {
+	keys := idx[kind]
+	i := sort.SearchStrings(keys, key)
+	return i < len(keys) && keys[i] == key
+}
+
+// Check reports broken links between generated notes under dir. Links that
+// point past the newest (or before the oldest) note of their kind are not
+// reported: the next-day link of today's note is expected to dangle until
+// tomorrow's note is written.
+func Check(dir string) ([]Broken, error) {
+	notes, err := Scan(dir)
+	if err != nil {
+		return nil, err
+	}
+	idx := newIndex(notes)
+
+	var broken []Broken
+	for _, n := range notes {
+		data, err := os.ReadFile(n.Path)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range Parse(string(data)) {
+			kind, key, ok := NoteOf(l.Target)
+			if !ok || idx.has(kind, key) {
+				continue
+			}
+			keys := idx[kind]
+			if len(keys) == 0 || key < keys[0] || key > keys[len(keys)-1] {
+				continue
+			}
+			b := Broken{Note: n, Link: l, Kind: kind, Key: key}
+			if kind == n.Kind {
+				b.Suggest = nearest(keys, key, key < n.Key)
+			}
+			broken = append(broken, b)
+		}
+	}
+	return broken, nil
+}
+
+// nearest returns the closest existing key before (or after) key.
+func nearest(keys []string, key string, before bool) string {
+	i := sort.SearchStrings(keys, key)
+	if before {
+		if i == 0 {
+			return ""
+		}
+		return keys[i-1]
+	}
+	if i >= len(keys) {
+		return ""
+	}
+	return keys[i]
+}
+
+// Fix rewrites every broken link that has a suggestion, replacing the old key
+// in both target and alias. It returns the number of links rewritten.
+func Fix(broken []Broken) (int, error) {
+	byPath := map[string][]Broken{}
+	for _, b := range broken {
+		if b.Suggest != "" {
+			byPath[b.Note.Path] = append(byPath[b.Note.Path], b)
+		}
+	}
+	fixed := 0
+	for p, bs := range byPath {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return fixed, err
+		}
+		content := string(data)
+		// Apply from the end so earlier offsets stay valid.
+		sort.Slice(bs, func(i, j int) bool { return bs[i].Link.start > bs[j].Link.start })
+		for _, b := range bs {
+			old := content[b.Link.start:b.Link.end]
+			repl := strings.ReplaceAll(old, b.Key, b.Suggest)
+			// Year folders follow the key's year.
+			repl = strings.Replace(repl, "/"+b.Key[:4]+"/", "/"+b.Suggest[:4]+"/", 1)
+			content = content[:b.Link.start] + repl + content[b.Link.end:]
+			fixed++
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			return fixed, fmt.Errorf("write %s: %w", p, err)
+		}
+	}
+	return fixed, nil
+}
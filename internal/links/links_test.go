@@ -0,0 +1,141 @@
+package links
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	got := Parse("a [[Health/WHOOP/2026/daily-2026-02-09|← 2026-02-09]]\nb [[plain]] [[x#h|y]]")
+	if len(got) != 3 {
+		t.Fatalf("got %d links, want 3", len(got))
+	}
+	if got[0].Target != "Health/WHOOP/2026/daily-2026-02-09" || got[0].Alias != "← 2026-02-09" || got[0].Line != 1 {
+		t.Errorf("got[0] = %+v", got[0])
+	}
+	if got[1].Target != "plain" || got[1].Alias != "" || got[1].Line != 2 {
+		t.Errorf("got[1] = %+v", got[1])
+	}
+	if got[2].Target != "x" || got[2].Alias != "y" {
+		t.Errorf("got[2] = %+v", got[2])
+	}
+}
+
+func writeNote(t *testing.T, dir, year, name, body string) string {
+	t.Helper()
+	d := filepath.Join(dir, year)
+	if err := os.MkdirAll(d, 0755); err != nil {
+		t.Fatal(err)
+	}
+	p := filepath.Join(d, name+".md")
+	if err := os.WriteFile(p, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func nav(prev, next string) string {
+	return "[[Health/WHOOP/2026/daily-" + prev + "|← " + prev + "]] | [[Health/WHOOP/2026/daily-" + next + "|" + next + " →]]\n"
+}
+
+func TestCheckAndFix_Gap(t *testing.T) {
+	dir := t.TempDir()
+	// 2026-02-11 is missing (no data that day).
+	writeNote(t, dir, "2026", "daily-2026-02-10", nav("2026-02-09", "2026-02-11"))
+	p := writeNote(t, dir, "2026", "daily-2026-02-12", nav("2026-02-11", "2026-02-13"))
+
+	broken, err := Check(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 02-09 and 02-13 are outside the existing range and are not reported.
+	if len(broken) != 2 {
+		t.Fatalf("got %d broken links, want 2: %+v", len(broken), broken)
+	}
+	for _, b := range broken {
+		if b.Key != "2026-02-11" {
+			t.Errorf("unexpected broken key %s", b.Key)
+		}
+	}
+
+	n, err := Fix(broken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("fixed %d, want 2", n)
+	}
+	data, _ := os.ReadFile(p)
+	if !strings.Contains(string(data), "[[Health/WHOOP/2026/daily-2026-02-10|← 2026-02-10]]") {
+		t.Errorf("prev link not rewritten to nearest existing day:\n%s", data)
+	}
+	if broken, _ := Check(dir); len(broken) != 0 {
+		t.Errorf("expected no broken links after fix, got %+v", broken)
+	}
+}
+
+func TestCheck_CrossKindNotFixed(t *testing.T) {
+	dir := t.TempDir()
+	writeNote(t, dir, "2026", "daily-2026-02-09", "")
+	writeNote(t, dir, "2026", "daily-2026-02-11", "")
+	writeNote(t, dir, "2026", "weekly-2026-W07",
+		"[[Health/WHOOP/2026/daily-2026-02-10|Tue Feb 10]]\n")
+
+	broken, err := Check(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(broken) != 1 {
+		t.Fatalf("got %d broken, want 1", len(broken))
+	}
+	if broken[0].Suggest != "" {
+		t.Errorf("weekly → daily link should be reported only, got suggestion %q", broken[0].Suggest)
+	}
+}
+
+func TestRel(t *testing.T) {
+	if got := Rel(LayoutYear, "weekly", "2026-W01"); got != "2026/weekly-2026-W01" {
+		t.Errorf("year layout = %q", got)
+	}
+	if got := Rel("", "monthly", "2026-02"); got != "2026/monthly-2026-02" {
+		t.Errorf("default layout = %q", got)
+	}
+	if got := Rel(LayoutFlat, "daily", "2026-02-10"); got != "daily-2026-02-10" {
+		t.Errorf("flat layout = %q", got)
+	}
+}
+
+func TestRetarget(t *testing.T) {
+	in := "[[Health/WHOOP/2026/daily-2026-02-09|← 2026-02-09]] | [[Health/WHOOP/2026/weekly-2026-W07#Days]] [[Other/note|x]]\n"
+	want := "[[Fitness/daily-2026-02-09|← 2026-02-09]] | [[Fitness/weekly-2026-W07#Days]] [[Other/note|x]]\n"
+	l := Linker{Layout: LayoutFlat, Folder: "Fitness"}
+	if got := Retarget(in, "Fitness/daily-2026-02-10", l); got != want {
+		t.Errorf("Retarget = %q\nwant %q", got, want)
+	}
+	if got := Retarget(want, "Fitness/daily-2026-02-10", l); got != want {
+		t.Errorf("Retarget changed already retargeted links: %q", got)
+	}
+}
+
+func TestLinkerNote(t *testing.T) {
+	from := "Health/WHOOP/2026/daily-2026-01-01"
+	for _, tc := range []struct {
+		style, want string
+	}{
+		{"", "Health/WHOOP/2025/daily-2025-12-31"},
+		{LinkVault, "Health/WHOOP/2025/daily-2025-12-31"},
+		{LinkRelative, "../2025/daily-2025-12-31"},
+		{LinkName, "daily-2025-12-31"},
+	} {
+		l := Linker{Folder: "Health/WHOOP", Style: tc.style}
+		if got := l.Note(from, "daily", "2025-12-31"); got != tc.want {
+			t.Errorf("%q style: Note = %q, want %q", tc.style, got, tc.want)
+		}
+	}
+	l := Linker{Folder: "Health/WHOOP", Style: LinkRelative}
+	if got := l.Link("Health/WHOOP/WHOOP", l.Path("2026/index-2026")); got != "2026/index-2026" {
+		t.Errorf("relative link from the top = %q", got)
+	}
+}
@@ -0,0 +1,205 @@
+package locale
+
+var deMonths = [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"}
+
+var esMonths = [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"}
+
+// de is the German catalog.
+var de = map[string]string{
+	// Labels returned by template helpers.
+	"green":     "grün",
+	"yellow":    "gelb",
+	"red":       "rot",
+	"Minimal":   "Minimal",
+	"Light":     "Leicht",
+	"Moderate":  "Moderat",
+	"Strenuous": "Anstrengend",
+	"All Out":   "Maximal",
+
+	"%sσ above baseline": "%sσ über dem Ausgangswert",
+	"%sσ below baseline": "%sσ unter dem Ausgangswert",
+	"at baseline":        "im Bereich des Ausgangswerts",
+
+	// HRV trend.
+	"Insufficient data":     "Zu wenige Daten",
+	"Stable":                "Stabil",
+	"Improving (+%s%%/day)": "Steigend (+%s %%/Tag)",
+	"Declining (%s%%/day)":  "Fallend (%s %%/Tag)",
+
+	// Persona.
+	"%d days":                                 "%d Tage",
+	"WHOOP Health Persona":                    "WHOOP-Gesundheitsprofil",
+	"Auto-generated":                          "Automatisch erstellt",
+	"Regenerate with %s. Covers %s → %s.":     "Neu erstellen mit %s. Umfasst %s → %s.",
+	"Health Persona (%d-Day Rolling Summary)": "Gesundheitsprofil (gleitende %d-Tage-Übersicht)",
+	"Period":                 "Zeitraum",
+	"Compared with":          "Verglichen mit",
+	"Profile":                "Profil",
+	"Name":                   "Name",
+	"Height":                 "Größe",
+	"Weight":                 "Gewicht",
+	"Max Heart Rate":         "Maximale Herzfrequenz",
+	"Recovery":               "Erholung",
+	"Average Recovery Score": "Durchschnittliche Erholung",
+	"vs prior %dd":           "ggü. den %d Tagen davor",
+	"Average HRV":            "Durchschnittliche HRV",
+	"HRV Trend":              "HRV-Trend",
+	"Latest HRV vs Baseline": "Letzte HRV ggü. Ausgangswert",
+	"%s ms vs %s ± %s ms over %d days, %s percentile": "%s ms ggü. %s ± %s ms über %d Tage, %s Perzentil",
+	"Average RHR":      "Durchschnittlicher Ruhepuls",
+	"Calibrating Days": "Kalibrierungstage",
+	"left out of the recovery figures; WHOOP was still calibrating": "nicht in den Erholungswerten enthalten; WHOOP kalibrierte noch",
+	"Sleep":                         "Schlaf",
+	"Average Sleep Duration":        "Durchschnittliche Schlafdauer",
+	"Average Sleep Performance":     "Durchschnittliche Schlafleistung",
+	"Naps":                          "Nickerchen",
+	"Average Time Asleep":           "Durchschnittliche Schlafzeit",
+	"of %s in bed":                  "von %s im Bett",
+	"%s in total":                   "%s insgesamt",
+	"Sleep Midpoint":                "Schlafmitte",
+	"bed %s, wake %s":               "ins Bett %s, auf %s",
+	"drifting %s/week":              "verschiebt sich um %s/Woche",
+	"Social Jetlag":                 "Sozialer Jetlag",
+	"free-day midpoint vs workdays": "Schlafmitte an freien Tagen ggü. Arbeitstagen",
+	"Strain":                        "Belastung",
+	"Average Day Strain":            "Durchschnittliche Tagesbelastung",
+	"Total Workouts":                "Trainings gesamt",
+	"Acute:Chronic Workload Ratio":  "Verhältnis akute zu chronischer Belastung",
+	"7-day strain %s vs 28-day %s":  "7-Tage-Belastung %s ggü. 28 Tagen %s",
+	"load spike":                    "Belastungsspitze",
+	"Sports":                        "Sportarten",
+	"Sport":                         "Sportart",
+	"Sessions":                      "Einheiten",
+	"Time":                          "Zeit",
+	"Recovery Distribution":         "Verteilung der Erholung",
+	"Green":                         "Grün",
+	"Yellow":                        "Gelb",
+	"Red":                           "Rot",
+	// Narrative.
+	"Recovery dropped %s points to %s%% after a strain of %s yesterday.":                       "Die Erholung fiel nach einer Belastung von %[3]s gestern um %[1]s Punkte auf %[2]s %%.",
+	"Recovery dropped %s points to %s%%.":                                                      "Die Erholung fiel um %s Punkte auf %s %%.",
+	"Recovery rose %s points to %s%%.":                                                         "Die Erholung stieg um %s Punkte auf %s %%.",
+	"Recovery of %s%% is well above the 7-day average of %s%%.":                                "Die Erholung liegt mit %s %% deutlich über dem 7-Tage-Schnitt von %s %%.",
+	"Recovery of %s%% is well below the 7-day average of %s%%.":                                "Die Erholung liegt mit %s %% deutlich unter dem 7-Tage-Schnitt von %s %%.",
+	"Recovery is %s at %s%%.":                                                                  "Die Erholung ist %s bei %s %%.",
+	"HRV is well above baseline at %s ms.":                                                     "Die HRV liegt mit %s ms deutlich über dem Ausgangswert.",
+	"HRV is well below baseline at %s ms.":                                                     "Die HRV liegt mit %s ms deutlich unter dem Ausgangswert.",
+	"You slept %s of the %s you needed.":                                                       "Du hast %s der benötigten %s geschlafen.",
+	"You slept the full %s you needed.":                                                        "Du hast die vollen benötigten %s geschlafen.",
+	"Sleep debt is %s.":                                                                        "Die Schlafschuld beträgt %s.",
+	"Recovery suggests a strain of %s–%s today.":                                               "Die Erholung spricht heute für eine Belastung von %s–%s.",
+	"Strain of %s overshot the %s–%s target.":                                                  "Die Belastung von %s lag über dem Ziel von %s–%s.",
+	"Strain of %s stayed under the %s–%s target.":                                              "Die Belastung von %s blieb unter dem Ziel von %s–%s.",
+	"Strain of %s landed in the %s–%s target.":                                                 "Die Belastung von %s lag im Ziel von %s–%s.",
+	"Respiratory rate of %s is off its baseline of %s, which can be an early sign of illness.": "Die Atemfrequenz von %s weicht vom Ausgangswert %s ab, oft ein frühes Anzeichen einer Erkrankung.",
+	"Average recovery rose %s points to %s%%.":                                                 "Die durchschnittliche Erholung stieg um %s Punkte auf %s %%.",
+	"Average recovery fell %s points to %s%%.":                                                 "Die durchschnittliche Erholung fiel um %s Punkte auf %s %%.",
+	"Average recovery was %s%%, with %d green and %d red days.":                                "Die durchschnittliche Erholung lag bei %s %%, mit %d grünen und %d roten Tagen.",
+	"Recovery dropped %d days running through %s while strain stayed high.":                    "Die Erholung fiel %d Tage in Folge bis zum %s, während die Belastung hoch blieb.",
+	"Recovery dropped %d days running through %s.":                                             "Die Erholung fiel %d Tage in Folge bis zum %s.",
+	"Strain overshot its target on %d of %d days.":                                             "Die Belastung lag an %d von %d Tagen über dem Ziel.",
+	"Strain fell short of its target on %d of %d days.":                                        "Die Belastung blieb an %d von %d Tagen unter dem Ziel.",
+	"Strain matched its target every day.":                                                     "Die Belastung lag jeden Tag im Ziel.",
+	"Sleep met %s%% of need on average.":                                                       "Der Schlaf deckte im Schnitt %s %% des Bedarfs.",
+	"Sleep debt stands at %s.":                                                                 "Die Schlafschuld beträgt %s.",
+}
+
+// es is the Spanish catalog.
+var es = map[string]string{
+	// Labels returned by template helpers.
+	"green":     "verde",
+	"yellow":    "amarillo",
+	"red":       "rojo",
+	"Minimal":   "Mínimo",
+	"Light":     "Ligero",
+	"Moderate":  "Moderado",
+	"Strenuous": "Intenso",
+	"All Out":   "Máximo",
+
+	"%sσ above baseline": "%sσ por encima de la referencia",
+	"%sσ below baseline": "%sσ por debajo de la referencia",
+	"at baseline":        "en la referencia",
+
+	// HRV trend.
+	"Insufficient data":     "Datos insuficientes",
+	"Stable":                "Estable",
+	"Improving (+%s%%/day)": "Mejorando (+%s %%/día)",
+	"Declining (%s%%/day)":  "Empeorando (%s %%/día)",
+
+	// Persona.
+	"%d days":                                 "%d días",
+	"WHOOP Health Persona":                    "Perfil de salud WHOOP",
+	"Auto-generated":                          "Generado automáticamente",
+	"Regenerate with %s. Covers %s → %s.":     "Vuelve a generarlo con %s. Cubre %s → %s.",
+	"Health Persona (%d-Day Rolling Summary)": "Perfil de salud (resumen móvil de %d días)",
+	"Period":                 "Periodo",
+	"Compared with":          "Comparado con",
+	"Profile":                "Perfil",
+	"Name":                   "Nombre",
+	"Height":                 "Altura",
+	"Weight":                 "Peso",
+	"Max Heart Rate":         "Frecuencia cardíaca máxima",
+	"Recovery":               "Recuperación",
+	"Average Recovery Score": "Recuperación media",
+	"vs prior %dd":           "frente a los %d días anteriores",
+	"Average HRV":            "VFC media",
+	"HRV Trend":              "Tendencia de la VFC",
+	"Latest HRV vs Baseline": "Última VFC frente a la referencia",
+	"%s ms vs %s ± %s ms over %d days, %s percentile": "%s ms frente a %s ± %s ms en %d días, percentil %s",
+	"Average RHR":      "FC en reposo media",
+	"Calibrating Days": "Días de calibración",
+	"left out of the recovery figures; WHOOP was still calibrating": "excluidos de las cifras de recuperación; WHOOP aún estaba calibrando",
+	"Sleep":                         "Sueño",
+	"Average Sleep Duration":        "Duración media del sueño",
+	"Average Sleep Performance":     "Rendimiento medio del sueño",
+	"Naps":                          "Siestas",
+	"Average Time Asleep":           "Tiempo medio dormido",
+	"of %s in bed":                  "de %s en la cama",
+	"%s in total":                   "%s en total",
+	"Sleep Midpoint":                "Punto medio del sueño",
+	"bed %s, wake %s":               "acostarse %s, despertar %s",
+	"drifting %s/week":              "desplazándose %s/semana",
+	"Social Jetlag":                 "Jet lag social",
+	"free-day midpoint vs workdays": "punto medio en días libres frente a laborables",
+	"Strain":                        "Esfuerzo",
+	"Average Day Strain":            "Esfuerzo diario medio",
+	"Total Workouts":                "Entrenamientos totales",
+	"Acute:Chronic Workload Ratio":  "Relación carga aguda:crónica",
+	"7-day strain %s vs 28-day %s":  "esfuerzo de 7 días %s frente a 28 días %s",
+	"load spike":                    "pico de carga",
+	"Sports":                        "Deportes",
+	"Sport":                         "Deporte",
+	"Sessions":                      "Sesiones",
+	"Time":                          "Tiempo",
+	"Recovery Distribution":         "Distribución de la recuperación",
+	"Green":                         "Verde",
+	"Yellow":                        "Amarillo",
+	"Red":                           "Rojo",
+	// Narrative.
+	"Recovery dropped %s points to %s%% after a strain of %s yesterday.":                       "La recuperación bajó %s puntos hasta el %s %% tras un esfuerzo de %s ayer.",
+	"Recovery dropped %s points to %s%%.":                                                      "La recuperación bajó %s puntos hasta el %s %%.",
+	"Recovery rose %s points to %s%%.":                                                         "La recuperación subió %s puntos hasta el %s %%.",
+	"Recovery of %s%% is well above the 7-day average of %s%%.":                                "La recuperación del %s %% está muy por encima de la media de 7 días del %s %%.",
+	"Recovery of %s%% is well below the 7-day average of %s%%.":                                "La recuperación del %s %% está muy por debajo de la media de 7 días del %s %%.",
+	"Recovery is %s at %s%%.":                                                                  "La recuperación es %s, del %s %%.",
+	"HRV is well above baseline at %s ms.":                                                     "La VFC está muy por encima de la referencia, con %s ms.",
+	"HRV is well below baseline at %s ms.":                                                     "La VFC está muy por debajo de la referencia, con %s ms.",
+	"You slept %s of the %s you needed.":                                                       "Dormiste %s de las %s que necesitabas.",
+	"You slept the full %s you needed.":                                                        "Dormiste las %s completas que necesitabas.",
+	"Sleep debt is %s.":                                                                        "La deuda de sueño es de %s.",
+	"Recovery suggests a strain of %s–%s today.":                                               "La recuperación sugiere un esfuerzo de %s–%s hoy.",
+	"Strain of %s overshot the %s–%s target.":                                                  "El esfuerzo de %s superó el objetivo de %s–%s.",
+	"Strain of %s stayed under the %s–%s target.":                                              "El esfuerzo de %s se quedó por debajo del objetivo de %s–%s.",
+	"Strain of %s landed in the %s–%s target.":                                                 "El esfuerzo de %s quedó dentro del objetivo de %s–%s.",
+	"Respiratory rate of %s is off its baseline of %s, which can be an early sign of illness.": "La frecuencia respiratoria de %s se aleja de su referencia de %s, lo que puede ser un signo temprano de enfermedad.",
+	"Average recovery rose %s points to %s%%.":                                                 "La recuperación media subió %s puntos hasta el %s %%.",
+	"Average recovery fell %s points to %s%%.":                                                 "La recuperación media bajó %s puntos hasta el %s %%.",
+	"Average recovery was %s%%, with %d green and %d red days.":                                "La recuperación media fue del %s %%, con %d días verdes y %d rojos.",
+	"Recovery dropped %d days running through %s while strain stayed high.":                    "La recuperación bajó %d días seguidos hasta el %s mientras el esfuerzo seguía alto.",
+	"Recovery dropped %d days running through %s.":                                             "La recuperación bajó %d días seguidos hasta el %s.",
+	"Strain overshot its target on %d of %d days.":                                             "El esfuerzo superó su objetivo %d de %d días.",
+	"Strain fell short of its target on %d of %d days.":                                        "El esfuerzo no alcanzó su objetivo %d de %d días.",
+	"Strain matched its target every day.":                                                     "El esfuerzo se ajustó a su objetivo todos los días.",
+	"Sleep met %s%% of need on average.":                                                       "El sueño cubrió de media el %s %% de la necesidad.",
+	"Sleep debt stands at %s.":                                                                 "La deuda de sueño es de %s.",
+}
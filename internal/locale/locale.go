@@ -0,0 +1,132 @@
+// Package locale translates the text whoop-garden generates outside of
+// templates and formats numbers and dates for a language.
+//
+// Messages are looked up by their English text, so English needs no
+// catalog and a missing translation falls back to English.
+package locale
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported lists the locales with a catalog, English first.
+var Supported = []string{"en", "de", "es"}
+
+// Valid reports whether name is a supported locale.
+func Valid(name string) bool {
+	for _, s := range Supported {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Locale translates messages and formats values for one language. The
+// zero value is English.
+type Locale struct {
+	Name     string
+	messages map[string]string
+	decimal  string
+	months   [12]string
+	date     string // layout of Date, with "MONTH" for the month name
+}
+
+// english is the Locale every other one falls back to.
+var english = Locale{
+	Name:    "en",
+	decimal: ".",
+	months:  [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	date:    "MONTH 2, 2006",
+}
+
+// Get returns the Locale for name, or English when name is not supported.
+func Get(name string) Locale {
+	switch name {
+	case "de":
+		return Locale{Name: "de", messages: de, decimal: ",", months: deMonths, date: "2. MONTH 2006"}
+	case "es":
+		return Locale{Name: "es", messages: es, decimal: ",", months: esMonths, date: "2 de MONTH de 2006"}
+	}
+	return english
+}
+
+// T translates msg, the English text, and formats it with args like
+// fmt.Sprintf when any are given.
+func (l Locale) T(msg string, args ...any) string {
+	if s, ok := l.messages[msg]; ok {
+		msg = s
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Number formats v with decimals digits after the locale's decimal
+// separator: 48.2 is "48,2" in German.
+func (l Locale) Number(v float64, decimals int) string {
+	return l.Decimal(strconv.FormatFloat(v, 'f', decimals, 64))
+}
+
+// Decimal replaces the decimal point of s, a number formatted with fmt,
+// with the locale's separator.
+func (l Locale) Decimal(s string) string {
+	if sep := l.separator(); sep != "." {
+		s = strings.Replace(s, ".", sep, 1)
+	}
+	return s
+}
+
+// Date formats t as a long date: "February 10, 2026", "10. Februar 2026".
+func (l Locale) Date(t time.Time) string {
+	layout := l.date
+	if layout == "" {
+		layout = english.date
+	}
+	before, after, _ := strings.Cut(layout, "MONTH")
+	return t.Format(before) + l.Month(t.Month()) + t.Format(after)
+}
+
+// Month returns the name of m.
+func (l Locale) Month(m time.Month) string {
+	if l.months[0] == "" {
+		return english.months[m-1]
+	}
+	return l.months[m-1]
+}
+
+// Ordinal rounds n and writes it as an ordinal: "22nd", "22.", "22.º".
+func (l Locale) Ordinal(n float64) string {
+	i := int(math.Round(n))
+	switch l.Name {
+	case "de":
+		return fmt.Sprintf("%d.", i)
+	case "es":
+		return fmt.Sprintf("%d.º", i)
+	}
+	suffix := "th"
+	if i%100 < 11 || i%100 > 13 {
+		switch i % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", i, suffix)
+}
+
+// separator returns the decimal separator.
+func (l Locale) separator() string {
+	if l.decimal == "" {
+		return english.decimal
+	}
+	return l.decimal
+}
@@ -0,0 +1,43 @@
+package locale
+
+import (
+	"testing"
+	"time"
+)
+
+func TestT(t *testing.T) {
+	if got := Get("de").T("Recovery"); got != "Erholung" {
+		t.Errorf("de Recovery = %q", got)
+	}
+	if got := Get("es").T("%d days", 3); got != "3 días" {
+		t.Errorf("es %%d days = %q", got)
+	}
+	if got := Get("de").T("Not in the catalog"); got != "Not in the catalog" {
+		t.Errorf("missing message = %q, want the English text", got)
+	}
+	if got := Get("fr").T("%d days", 2); got != "2 days" {
+		t.Errorf("unsupported locale = %q, want English", got)
+	}
+}
+
+func TestNumberAndDate(t *testing.T) {
+	date := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		locale, num, date, ordinal string
+	}{
+		{"en", "48.25", "March 9, 2026", "22nd"},
+		{"de", "48,25", "9. März 2026", "22."},
+		{"es", "48,25", "9 de marzo de 2026", "22.º"},
+	} {
+		l := Get(tc.locale)
+		if got := l.Number(48.254, 2); got != tc.num {
+			t.Errorf("%s Number = %q, want %q", tc.locale, got, tc.num)
+		}
+		if got := l.Date(date); got != tc.date {
+			t.Errorf("%s Date = %q, want %q", tc.locale, got, tc.date)
+		}
+		if got := l.Ordinal(22); got != tc.ordinal {
+			t.Errorf("%s Ordinal = %q, want %q", tc.locale, got, tc.ordinal)
+		}
+	}
+}
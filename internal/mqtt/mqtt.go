@@ -0,0 +1,187 @@
+// Package mqtt is a minimal MQTT 3.1.1 publisher. It supports QoS 0
+// publishes with the retain flag, which is all Home Assistant needs for
+// sensor discovery and state; there are no subscriptions.
+package mqtt
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"time"
+)
+
+// Packet types, already shifted into the high nibble of the fixed header.
+const (
+	packetConnect    = 0x10
+	packetConnack    = 0x20
+	packetPublish    = 0x30
+	packetDisconnect = 0xE0
+)
+
+// keepAlive is advertised to the broker. Connections are short-lived, so
+// no pings are ever sent.
+const keepAlive = 60
+
+// Options configures a connection.
+type Options struct {
+	// Broker is tcp://host:port, or ssl://, tls://, or mqtts:// for TLS.
+	// The port defaults to 1883, or 8883 with TLS.
+	Broker   string
+	ClientID string
+	Username string
+	Password string
+	Timeout  time.Duration
+}
+
+// Client is a connection to a broker.
+type Client struct {
+	conn net.Conn
+	w    *bufio.Writer
+}
+
+// connackErrors describes the CONNACK return codes that refuse a connection.
+var connackErrors = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "client identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+// Dial connects to the broker and completes the MQTT handshake.
+func Dial(o Options) (*Client, error) {
+	u, err := url.Parse(o.Broker)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid broker URL %q (expected tcp://host:port)", o.Broker)
+	}
+	if o.Timeout == 0 {
+		o.Timeout = 10 * time.Second
+	}
+	secure := false
+	switch u.Scheme {
+	case "tcp", "mqtt":
+	case "ssl", "tls", "mqtts":
+		secure = true
+	default:
+		return nil, fmt.Errorf("unsupported broker scheme %q", u.Scheme)
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		port := "1883"
+		if secure {
+			port = "8883"
+		}
+		addr = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	dialer := &net.Dialer{Timeout: o.Timeout}
+	var conn net.Conn
+	if secure {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: u.Hostname()})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("connect to %s: %w", addr, err)
+	}
+	conn.SetDeadline(time.Now().Add(o.Timeout))
+
+	c := &Client{conn: conn, w: bufio.NewWriter(conn)}
+	if err := c.connect(o); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Client) connect(o Options) error {
+	var body []byte
+	body = appendString(body, "MQTT")
+	body = append(body, 4) // protocol level 3.1.1
+	flags := byte(0x02)    // clean session
+	if o.Username != "" {
+		flags |= 0x80
+		if o.Password != "" {
+			flags |= 0x40
+		}
+	}
+	body = append(body, flags, keepAlive>>8, keepAlive&0xff)
+	body = appendString(body, o.ClientID)
+	if o.Username != "" {
+		body = appendString(body, o.Username)
+		if o.Password != "" {
+			body = appendString(body, o.Password)
+		}
+	}
+	if err := c.write(packetConnect, body); err != nil {
+		return err
+	}
+
+	var ack [4]byte
+	if _, err := io.ReadFull(c.conn, ack[:]); err != nil {
+		return fmt.Errorf("read CONNACK: %w", err)
+	}
+	if ack[0] != packetConnack || ack[1] != 2 {
+		return errors.New("broker did not answer with CONNACK")
+	}
+	if rc := ack[3]; rc != 0 {
+		if msg, ok := connackErrors[rc]; ok {
+			return fmt.Errorf("broker refused connection: %s", msg)
+		}
+		return fmt.Errorf("broker refused connection: code %d", rc)
+	}
+	return nil
+}
+
+// Publish sends payload to topic at QoS 0.
+func (c *Client) Publish(topic string, payload []byte, retain bool) error {
+	header := byte(packetPublish)
+	if retain {
+		header |= 0x01
+	}
+	body := appendString(nil, topic)
+	body = append(body, payload...)
+	return c.write(header, body)
+}
+
+// Close sends DISCONNECT and closes the connection.
+func (c *Client) Close() error {
+	err := c.write(packetDisconnect, nil)
+	if cerr := c.conn.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// write sends one packet and flushes it.
+func (c *Client) write(header byte, body []byte) error {
+	c.w.WriteByte(header)
+	c.w.Write(appendLength(nil, len(body)))
+	c.w.Write(body)
+	return c.w.Flush()
+}
+
+// appendLength encodes n as an MQTT variable-length integer.
+func appendLength(b []byte, n int) []byte {
+	for {
+		d := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			d |= 0x80
+		}
+		b = append(b, d)
+		if n == 0 {
+			return b
+		}
+	}
+}
+
+// appendString encodes s with its two-byte length prefix.
+func appendString(b []byte, s string) []byte {
+	b = append(b, byte(len(s)>>8), byte(len(s)))
+	return append(b, s...)
+}
@@ -0,0 +1,114 @@
+package mqtt
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"testing"
+)
+
+// packet is one packet read by the fake broker.
+type packet struct {
+	header byte
+	body   []byte
+}
+
+func readPacket(r *bufio.Reader) (packet, error) {
+	h, err := r.ReadByte()
+	if err != nil {
+		return packet{}, err
+	}
+	n, mult := 0, 1
+	for {
+		d, err := r.ReadByte()
+		if err != nil {
+			return packet{}, err
+		}
+		n += int(d&0x7f) * mult
+		if d&0x80 == 0 {
+			break
+		}
+		mult *= 128
+	}
+	body := make([]byte, n)
+	_, err = io.ReadFull(r, body)
+	return packet{h, body}, err
+}
+
+// fakeBroker accepts one connection, answers CONNACK with rc, and sends
+// every packet it reads on the returned channel.
+func fakeBroker(t *testing.T, rc byte) (string, <-chan packet) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	ch := make(chan packet, 16)
+	go func() {
+		defer close(ch)
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			p, err := readPacket(r)
+			if err != nil {
+				return
+			}
+			ch <- p
+			if p.header == packetConnect {
+				conn.Write([]byte{packetConnack, 2, 0, rc})
+			}
+		}
+	}()
+	return "tcp://" + ln.Addr().String(), ch
+}
+
+func TestPublish(t *testing.T) {
+	broker, ch := fakeBroker(t, 0)
+	c, err := Dial(Options{Broker: broker, ClientID: "wg", Username: "u", Password: "p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Publish("whoop-garden/recovery", []byte("28"), true); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	connect := <-ch
+	if connect.header != packetConnect || string(connect.body[2:6]) != "MQTT" || connect.body[7] != 0xC2 {
+		t.Errorf("unexpected CONNECT: %x %q", connect.header, connect.body)
+	}
+	pub := <-ch
+	if pub.header != packetPublish|0x01 {
+		t.Errorf("PUBLISH header = %x, want retained QoS 0", pub.header)
+	}
+	want := append(appendString(nil, "whoop-garden/recovery"), "28"...)
+	if string(pub.body) != string(want) {
+		t.Errorf("PUBLISH body = %q, want %q", pub.body, want)
+	}
+	if disc := <-ch; disc.header != packetDisconnect {
+		t.Errorf("last packet = %x, want DISCONNECT", disc.header)
+	}
+}
+
+func TestDial_Refused(t *testing.T) {
+	broker, _ := fakeBroker(t, 4)
+	if _, err := Dial(Options{Broker: broker}); err == nil || err.Error() != "broker refused connection: bad user name or password" {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestAppendLength(t *testing.T) {
+	tests := map[int]string{0: "\x00", 127: "\x7f", 128: "\x80\x01", 321: "\xc1\x02", 16384: "\x80\x80\x01"}
+	for n, want := range tests {
+		if got := string(appendLength(nil, n)); got != want {
+			t.Errorf("appendLength(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
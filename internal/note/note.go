@@ -0,0 +1,201 @@
+// Package note recognizes generated markdown notes and edits their sections in place.
+//
+// A section starts at a "## " heading line and runs up to the next "## "
+// heading or "---" rule, whichever comes first.
+package note
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// FirstHeading returns the first "## " heading line in doc, or "" if none.
+func FirstHeading(doc string) string {
+	for _, line := range strings.Split(doc, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			return line
+		}
+	}
+	return ""
+}
+
+// Section returns the section of doc that starts with heading, including
+// the heading line itself.
+func Section(doc, heading string) (string, bool) {
+	start, end, ok := bounds(doc, heading)
+	if !ok {
+		return "", false
+	}
+	return doc[start:end], true
+}
+
+// ReplaceSection replaces the section of doc that starts with heading by
+// section, which should begin with the same heading.
+func ReplaceSection(doc, heading, section string) (string, bool) {
+	start, end, ok := bounds(doc, heading)
+	if !ok {
+		return doc, false
+	}
+	return doc[:start] + section + doc[end:], true
+}
+
+// bounds returns the byte range of heading's section in doc.
+func bounds(doc, heading string) (int, int, bool) {
+	start := -1
+	pos := 0
+	for pos <= len(doc) {
+		next := strings.IndexByte(doc[pos:], '\n')
+		lineEnd := len(doc)
+		if next >= 0 {
+			lineEnd = pos + next
+		}
+		line := strings.TrimRight(doc[pos:lineEnd], "\r")
+		if start < 0 {
+			if line == heading {
+				start = pos
+			}
+		} else if strings.HasPrefix(line, "## ") || line == "---" {
+			return start, pos, true
+		}
+		if next < 0 {
+			break
+		}
+		pos = lineEnd + 1
+	}
+	if start < 0 {
+		return 0, 0, false
+	}
+	return start, len(doc), true
+}
+
+// Generated reports whether doc was written by whoop-garden: its YAML
+// frontmatter has a "generator: whoop-garden" line.
+func Generated(doc string) bool {
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 0 || strings.TrimRight(lines[0], "\r") != "---" {
+		return false
+	}
+	for _, line := range lines[1:] {
+		line = strings.TrimRight(line, "\r")
+		if line == "---" {
+			return false
+		}
+		if line == "generator: whoop-garden" || strings.HasPrefix(line, "generator: whoop-garden ") {
+			return true
+		}
+	}
+	return false
+}
+
+// SetBlock replaces the lines between the "<!-- name -->" and
+// "<!-- /name -->" markers in doc with body, or appends the markers and body
+// at the end of doc when it has none.
+func SetBlock(doc, name, body string) string {
+	begin, end := "<!-- "+name+" -->", "<!-- /"+name+" -->"
+	block := begin + "\n" + strings.TrimSuffix(body, "\n") + "\n" + end
+	if i := strings.Index(doc, begin); i >= 0 {
+		if j := strings.Index(doc[i:], end); j >= 0 {
+			return doc[:i] + block + doc[i+j+len(end):]
+		}
+	}
+	switch {
+	case doc == "":
+	case strings.HasSuffix(doc, "\n\n"):
+	case strings.HasSuffix(doc, "\n"):
+		doc += "\n"
+	default:
+		doc += "\n\n"
+	}
+	return doc + block + "\n"
+}
+
+// AddFrontmatter adds fields and tags to the YAML frontmatter of doc.
+// fields maps keys to YAML values; keys the frontmatter already has are left
+// as they are. tags are appended to the tags list, block or inline, unless
+// already in it. doc is returned unchanged when it has no frontmatter.
+func AddFrontmatter(doc string, fields map[string]string, tags []string) string {
+	if !strings.HasPrefix(doc, "---\n") {
+		return doc
+	}
+	end := strings.Index(doc[4:], "\n---\n")
+	if end < 0 {
+		return doc
+	}
+	lines := strings.Split(doc[4:4+end], "\n")
+
+	tagsAt := -1
+	has := map[string]bool{}
+	for i, line := range lines {
+		key, _, ok := strings.Cut(line, ":")
+		if !ok || line == "" || line[0] == ' ' || line[0] == '-' {
+			continue
+		}
+		has[key] = true
+		if key == "tags" {
+			tagsAt = i
+		}
+	}
+
+	if len(tags) > 0 {
+		if tagsAt < 0 {
+			lines = append(lines, "tags:")
+			tagsAt = len(lines) - 1
+		}
+		_, value, _ := strings.Cut(lines[tagsAt], ":")
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+			inner := strings.TrimSpace(value[1 : len(value)-1])
+			items := splitTags(strings.Split(inner, ","))
+			for _, t := range tags {
+				if !slices.Contains(items, t) {
+					items = append(items, t)
+					if inner != "" {
+						inner += ", "
+					}
+					inner += t
+				}
+			}
+			lines[tagsAt] = "tags: [" + inner + "]"
+		} else {
+			last := tagsAt
+			var items []string
+			for last+1 < len(lines) && strings.HasPrefix(strings.TrimLeft(lines[last+1], " "), "- ") {
+				last++
+				items = append(items, strings.TrimPrefix(strings.TrimLeft(lines[last], " "), "- "))
+			}
+			items = splitTags(items)
+			var added []string
+			for _, t := range tags {
+				if !slices.Contains(items, t) {
+					items = append(items, t)
+					added = append(added, "  - "+t)
+				}
+			}
+			lines = append(lines[:last+1], append(added, lines[last+1:]...)...)
+		}
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		if !has[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		lines = append(lines, k+": "+fields[k])
+	}
+	return "---\n" + strings.Join(lines, "\n") + doc[4+end:]
+}
+
+// splitTags trims the spaces and quotes around tags.
+func splitTags(items []string) []string {
+	var out []string
+	for _, t := range items {
+		if t = strings.Trim(strings.TrimSpace(t), `"'`); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
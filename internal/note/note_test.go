@@ -0,0 +1,118 @@
+package note
+
+import (
+	"strings"
+	"testing"
+)
+
+const doc = `# Title
+
+## Recovery
+
+old recovery
+
+---
+
+## Sleep
+
+sleep body
+`
+
+func TestFirstHeading(t *testing.T) {
+	if got := FirstHeading(doc); got != "## Recovery" {
+		t.Errorf("FirstHeading = %q, want ## Recovery", got)
+	}
+	if got := FirstHeading("no headings"); got != "" {
+		t.Errorf("FirstHeading = %q, want empty", got)
+	}
+}
+
+func TestSection(t *testing.T) {
+	got, ok := Section(doc, "## Recovery")
+	if !ok || got != "## Recovery\n\nold recovery\n\n" {
+		t.Errorf("Section = %q, %v", got, ok)
+	}
+	got, ok = Section(doc, "## Sleep")
+	if !ok || got != "## Sleep\n\nsleep body\n" {
+		t.Errorf("last Section = %q, %v", got, ok)
+	}
+	if _, ok := Section(doc, "## Strain"); ok {
+		t.Error("missing heading should not be found")
+	}
+}
+
+func TestReplaceSection(t *testing.T) {
+	got, ok := ReplaceSection(doc, "## Recovery", "## Recovery\n\nnew recovery\n\n")
+	if !ok {
+		t.Fatal("heading not found")
+	}
+	want := "# Title\n\n## Recovery\n\nnew recovery\n\n---\n\n## Sleep\n\nsleep body\n"
+	if got != want {
+		t.Errorf("got:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestGenerated(t *testing.T) {
+	for _, tc := range []struct {
+		doc  string
+		want bool
+	}{
+		{"---\ndate: 2026-02-10\ngenerator: whoop-garden v1.4.0\n---\n\n# Day\n", true},
+		{"---\r\ngenerator: whoop-garden dev\r\n---\r\n", true},
+		{"---\ndate: 2026-02-10\n---\n\ngenerator: whoop-garden v1.4.0\n", false},
+		{"---\ngenerator: whoop-gardener\n---\n", false},
+		{"# My own note\n", false},
+	} {
+		if got := Generated(tc.doc); got != tc.want {
+			t.Errorf("Generated(%q) = %v, want %v", tc.doc, got, tc.want)
+		}
+	}
+}
+
+func TestSetBlock(t *testing.T) {
+	const line = "WHOOP: [[daily-2026-02-10]]"
+	got := SetBlock("# Tuesday\n\nWent for a run.", "whoop-garden", line)
+	want := "# Tuesday\n\nWent for a run.\n\n<!-- whoop-garden -->\n" + line + "\n<!-- /whoop-garden -->\n"
+	if got != want {
+		t.Fatalf("append = %q, want %q", got, want)
+	}
+
+	edited := strings.Replace(got, "# Tuesday", "# Tuesday\n\nMore notes.", 1) + "\nLater.\n"
+	updated := SetBlock(edited, "whoop-garden", "WHOOP: [[daily-2026-02-10|61%]]")
+	if !strings.Contains(updated, "<!-- whoop-garden -->\nWHOOP: [[daily-2026-02-10|61%]]\n<!-- /whoop-garden -->\n\nLater.\n") {
+		t.Errorf("update = %q", updated)
+	}
+	if strings.Count(updated, "<!-- whoop-garden -->") != 1 || !strings.Contains(updated, "More notes.") {
+		t.Errorf("update should replace the block in place: %q", updated)
+	}
+
+	if got := SetBlock("", "whoop-garden", line); got != "<!-- whoop-garden -->\n"+line+"\n<!-- /whoop-garden -->\n" {
+		t.Errorf("empty doc = %q", got)
+	}
+}
+
+func TestAddFrontmatter(t *testing.T) {
+	doc := "---\ntype: note\ntags:\n  - fitness/whoop\n  - \"2026\"\ncreated: 2026-02-10\n---\n\n# Day\n"
+	got := AddFrontmatter(doc, map[string]string{"source": `"WHOOP"`, "type": `"log"`}, []string{"daily/health", "2026"})
+	want := "---\ntype: note\ntags:\n  - fitness/whoop\n  - \"2026\"\n  - daily/health\ncreated: 2026-02-10\nsource: \"WHOOP\"\n---\n\n# Day\n"
+	if got != want {
+		t.Errorf("block tags = %q, want %q", got, want)
+	}
+	if again := AddFrontmatter(got, map[string]string{"source": `"WHOOP"`}, []string{"daily/health"}); again != got {
+		t.Errorf("adding twice changed the note: %q", again)
+	}
+
+	got = AddFrontmatter("---\ntags: [ai-brain/context, fitness/whoop]\n---\n", nil, []string{"fitness/whoop", "health"})
+	if want := "---\ntags: [ai-brain/context, fitness/whoop, health]\n---\n"; got != want {
+		t.Errorf("inline tags = %q, want %q", got, want)
+	}
+
+	got = AddFrontmatter("---\ntype: note\n---\n", nil, []string{"health"})
+	if want := "---\ntype: note\ntags:\n  - health\n---\n"; got != want {
+		t.Errorf("no tags = %q, want %q", got, want)
+	}
+
+	if got := AddFrontmatter("# No frontmatter\n", nil, []string{"health"}); got != "# No frontmatter\n" {
+		t.Errorf("no frontmatter = %q", got)
+	}
+}
@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// telegramAPI is the Telegram Bot API base URL.
+const telegramAPI = "https://api.telegram.org"
+
+// Telegram sends notifications as messages from a bot to a chat.
+type Telegram struct {
+	BotToken   string
+	ChatID     string
+	BaseURL    string // defaults to the public Bot API; set in tests
+	HTTPClient *http.Client
+}
+
+// NewTelegram returns a Telegram notifier with a 10s timeout.
+func NewTelegram(botToken, chatID string) *Telegram {
+	return &Telegram{BotToken: botToken, ChatID: chatID, BaseURL: telegramAPI, HTTPClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Notify implements Notifier.
+func (t *Telegram) Notify(title, message string) error {
+	return postJSON(t.HTTPClient, t.BaseURL+"/bot"+t.BotToken+"/sendMessage", "telegram", map[string]any{
+		"chat_id":                  t.ChatID,
+		"text":                     title + "\n" + message,
+		"disable_web_page_preview": true,
+	})
+}
+
+// Discord posts notifications to a Discord channel webhook.
+type Discord struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+// NewDiscord returns a Discord notifier with a 10s timeout.
+func NewDiscord(url string) *Discord {
+	return &Discord{URL: url, HTTPClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Notify implements Notifier. The title is sent in bold.
+func (d *Discord) Notify(title, message string) error {
+	return postJSON(d.HTTPClient, d.URL, "discord", map[string]any{
+		"content": "**" + title + "**\n" + message,
+	})
+}
+
+// postJSON POSTs v as JSON to endpoint and fails on a non-2xx response.
+// name identifies the service in errors, which never include endpoint: it
+// can hold a bot token or webhook secret.
+func postJSON(hc *http.Client, endpoint, name string, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	resp, err := hc.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			err = ue.Err
+		}
+		return fmt.Errorf("%s request failed: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %d", name, resp.StatusCode)
+	}
+	return nil
+}
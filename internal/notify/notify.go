@@ -0,0 +1,33 @@
+package notify
+
+import (
+	"net/http"
+	"time"
+)
+
+// Notifier delivers a short message to the user.
+type Notifier interface {
+	Notify(title, message string) error
+}
+
+// Webhook posts notifications as JSON to a URL. The body carries both a
+// "text" field (understood by Slack-style incoming webhooks) and separate
+// "title"/"message" fields for custom receivers.
+type Webhook struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+// NewWebhook returns a Webhook notifier with a 10s timeout.
+func NewWebhook(url string) *Webhook {
+	return &Webhook{URL: url, HTTPClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Notify implements Notifier.
+func (w *Webhook) Notify(title, message string) error {
+	return postJSON(w.HTTPClient, w.URL, "webhook", map[string]string{
+		"text":    title + "\n" + message,
+		"title":   title,
+		"message": message,
+	})
+}
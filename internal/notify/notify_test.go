@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhook_Notify(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	if err := NewWebhook(srv.URL).Notify("Red recovery", "28%"); err != nil {
+		t.Fatal(err)
+	}
+	if got["title"] != "Red recovery" || got["message"] != "28%" || got["text"] != "Red recovery\n28%" {
+		t.Errorf("unexpected payload: %v", got)
+	}
+}
+
+func TestWebhook_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	if err := NewWebhook(srv.URL).Notify("t", "m"); err == nil {
+		t.Error("expected error on 400")
+	}
+}
+
+func TestNtfy_Notify(t *testing.T) {
+	var title, auth, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		title, auth = r.Header.Get("Title"), r.Header.Get("Authorization")
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer srv.Close()
+
+	if err := NewNtfy(srv.URL+"/whoop", "tk").Notify("Red recovery: 28%", "HRV 41 ms"); err != nil {
+		t.Fatal(err)
+	}
+	if title != "Red recovery: 28%" || body != "HRV 41 ms" || auth != "Bearer tk" {
+		t.Errorf("got title %q, body %q, auth %q", title, body, auth)
+	}
+}
+
+func TestDesktopCommand(t *testing.T) {
+	name, args, err := desktopCommand("darwin", `Say "hi"`, "28%")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `display notification "28%" with title "Say \"hi\""`
+	if name != "osascript" || len(args) != 2 || args[1] != want {
+		t.Errorf("darwin: got %s %q", name, args)
+	}
+	if name, _, _ := desktopCommand("linux", "t", "m"); name != "notify-send" {
+		t.Errorf("linux: got %s", name)
+	}
+	if _, _, err := desktopCommand("plan9", "t", "m"); err == nil {
+		t.Error("expected error for unsupported OS")
+	}
+}
+
+func TestTelegram_Notify(t *testing.T) {
+	var path string
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	tg := NewTelegram("123:abc", "42")
+	tg.BaseURL = srv.URL
+	if err := tg.Notify("WHOOP · Tue 10 Feb", "Recovery 28%"); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/bot123:abc/sendMessage" {
+		t.Errorf("path = %q", path)
+	}
+	if got["chat_id"] != "42" || got["text"] != "WHOOP · Tue 10 Feb\nRecovery 28%" {
+		t.Errorf("unexpected payload: %v", got)
+	}
+}
+
+func TestTelegram_ErrorHidesToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Close()
+
+	tg := NewTelegram("123:secret", "42")
+	tg.BaseURL = srv.URL
+	err := tg.Notify("t", "m")
+	if err == nil {
+		t.Fatal("expected an error from a closed server")
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("error leaks the bot token: %v", err)
+	}
+}
+
+func TestDiscord_Notify(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	if err := NewDiscord(srv.URL).Notify("t", "m"); err != nil {
+		t.Fatal(err)
+	}
+	if got["content"] != "**t**\nm" {
+		t.Errorf("content = %q", got["content"])
+	}
+}
@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Ntfy publishes notifications to an ntfy topic (https://ntfy.sh or a
+// self-hosted server).
+type Ntfy struct {
+	// URL is the topic URL, e.g. https://ntfy.sh/my-whoop-topic.
+	URL string
+	// Token, if set, is sent as a bearer token for protected topics.
+	Token      string
+	HTTPClient *http.Client
+}
+
+// NewNtfy returns an Ntfy notifier with a 10s timeout.
+func NewNtfy(url, token string) *Ntfy {
+	return &Ntfy{URL: url, Token: token, HTTPClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Notify implements Notifier.
+func (n *Ntfy) Notify(title, message string) error {
+	req, err := http.NewRequest(http.MethodPost, n.URL, strings.NewReader(message))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Title", title)
+	req.Header.Set("Tags", "heart")
+	if n.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.Token)
+	}
+	resp, err := n.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("ntfy request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("ntfy returned %d", resp.StatusCode)
+	}
+	return nil
+}
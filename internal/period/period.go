@@ -0,0 +1,149 @@
+package period
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Period is a half-open range of calendar days [Start, End) in UTC.
+type Period struct {
+	Label string
+	Start time.Time
+	End   time.Time
+}
+
+// Days returns the number of calendar days covered by p.
+func (p Period) Days() int {
+	return int(p.End.Sub(p.Start).Hours() / 24)
+}
+
+// Last returns the final day included in p.
+func (p Period) Last() time.Time { return p.End.AddDate(0, 0, -1) }
+
+// Parse interprets spec as one of:
+//
+//	2026-02-10              a single day
+//	2026-W07                an ISO week (Mon–Sun)
+//	2026-02                 a calendar month
+//	2026-Q1                 a calendar quarter
+//	2026                    a calendar year
+//	2025-11-01:2025-11-30   an inclusive date range
+func Parse(spec string) (Period, error) {
+	spec = strings.TrimSpace(spec)
+	if from, to, ok := strings.Cut(spec, ":"); ok {
+		start, err := time.Parse(dateLayout, from)
+		if err != nil {
+			return Period{}, fmt.Errorf("invalid range start %q (expected YYYY-MM-DD)", from)
+		}
+		last, err := time.Parse(dateLayout, to)
+		if err != nil {
+			return Period{}, fmt.Errorf("invalid range end %q (expected YYYY-MM-DD)", to)
+		}
+		if last.Before(start) {
+			return Period{}, fmt.Errorf("range %q ends before it starts", spec)
+		}
+		return Range(start, last), nil
+	}
+	if t, err := time.Parse(dateLayout, spec); err == nil {
+		return Period{Label: spec, Start: t, End: t.AddDate(0, 0, 1)}, nil
+	}
+	var year, week int
+	if n, _ := fmt.Sscanf(spec, "%4d-W%2d", &year, &week); n == 2 && len(spec) == 8 {
+		if week < 1 || week > 53 {
+			return Period{}, fmt.Errorf("invalid ISO week %q", spec)
+		}
+		p := Week(isoWeekStart(year, week))
+		if y, _ := p.Start.ISOWeek(); y != year {
+			return Period{}, fmt.Errorf("invalid ISO week %q", spec)
+		}
+		return p, nil
+	}
+	var quarter int
+	if n, _ := fmt.Sscanf(spec, "%4d-Q%1d", &year, &quarter); n == 2 && len(spec) == 7 {
+		if quarter < 1 || quarter > 4 {
+			return Period{}, fmt.Errorf("invalid quarter %q", spec)
+		}
+		return Quarter(time.Date(year, time.Month(quarter*3), 1, 0, 0, 0, 0, time.UTC)), nil
+	}
+	if t, err := time.Parse("2006-01", spec); err == nil {
+		return Month(t), nil
+	}
+	if t, err := time.Parse("2006", spec); err == nil {
+		return Year(t.Year()), nil
+	}
+	return Period{}, fmt.Errorf("invalid period %q (expected YYYY-MM-DD, YYYY-Www, YYYY-MM, YYYY-Qn, YYYY, or FROM:TO)", spec)
+}
+
+// Range returns the inclusive period from first through last.
+func Range(first, last time.Time) Period {
+	start := day(first)
+	end := day(last).AddDate(0, 0, 1)
+	return Period{
+		Label: start.Format(dateLayout) + " → " + end.AddDate(0, 0, -1).Format(dateLayout),
+		Start: start,
+		End:   end,
+	}
+}
+
+// Week returns the ISO week (Monday through Sunday) containing t.
+func Week(t time.Time) Period {
+	d := day(t)
+	weekday := int(d.Weekday())
+	if weekday == 0 {
+		weekday = 7 // treat Sunday as day 7
+	}
+	start := d.AddDate(0, 0, -(weekday - 1))
+	year, week := start.ISOWeek()
+	return Period{Label: fmt.Sprintf("%d-W%02d", year, week), Start: start, End: start.AddDate(0, 0, 7)}
+}
+
+// Month returns the calendar month containing t.
+func Month(t time.Time) Period {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return Period{Label: start.Format("2006-01"), Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+// Quarter returns the calendar quarter containing t, labelled "2026-Q1".
+func Quarter(t time.Time) Period {
+	q := (int(t.Month()) - 1) / 3
+	start := time.Date(t.Year(), time.Month(q*3+1), 1, 0, 0, 0, 0, time.UTC)
+	return Period{Label: fmt.Sprintf("%d-Q%d", t.Year(), q+1), Start: start, End: start.AddDate(0, 3, 0)}
+}
+
+// Months splits p into the calendar months it overlaps, the first and
+// last cut to p.
+func (p Period) Months() []Period {
+	var months []Period
+	for start := p.Start; start.Before(p.End); start = Month(start).End {
+		m := Month(start)
+		if m.Start.Before(p.Start) {
+			m.Start = p.Start
+		}
+		if m.End.After(p.End) {
+			m.End = p.End
+		}
+		months = append(months, m)
+	}
+	return months
+}
+
+// Year returns the calendar year.
+func Year(year int) Period {
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	return Period{Label: fmt.Sprintf("%d", year), Start: start, End: start.AddDate(1, 0, 0)}
+}
+
+// day truncates t to midnight UTC on its calendar date.
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// isoWeekStart returns the Monday of the given ISO week.
+func isoWeekStart(year, week int) time.Time {
+	// January 4th is always in ISO week 1.
+	jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, time.UTC)
+	return Week(jan4).Start.AddDate(0, 0, (week-1)*7)
+}
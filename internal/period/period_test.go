@@ -0,0 +1,83 @@
+package period
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec      string
+		wantStart string
+		wantEnd   string // exclusive
+		wantLabel string
+	}{
+		{"2026-02-10", "2026-02-10", "2026-02-11", "2026-02-10"},
+		{"2026-W07", "2026-02-09", "2026-02-16", "2026-W07"},
+		{"2019-W01", "2018-12-31", "2019-01-07", "2019-W01"},
+		{"2025-06", "2025-06-01", "2025-07-01", "2025-06"},
+		{"2025", "2025-01-01", "2026-01-01", "2025"},
+		{"2026-Q1", "2026-01-01", "2026-04-01", "2026-Q1"},
+		{"2025-Q4", "2025-10-01", "2026-01-01", "2025-Q4"},
+		{"2025-11-01:2025-11-30", "2025-11-01", "2025-12-01", "2025-11-01 → 2025-11-30"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.spec, func(t *testing.T) {
+			p, err := Parse(tc.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.Start.Format(dateLayout); got != tc.wantStart {
+				t.Errorf("Start = %s, want %s", got, tc.wantStart)
+			}
+			if got := p.End.Format(dateLayout); got != tc.wantEnd {
+				t.Errorf("End = %s, want %s", got, tc.wantEnd)
+			}
+			if p.Label != tc.wantLabel {
+				t.Errorf("Label = %q, want %q", p.Label, tc.wantLabel)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "last-week", "2026-W60", "2026-13", "2026-02-10:2026-02-01", "2026-02-10:nope", "2026-Q5", "2026-Q0"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) expected error", spec)
+		}
+	}
+}
+
+func TestWeek_Sunday(t *testing.T) {
+	// 2026-02-15 is a Sunday; its week starts Monday 2026-02-09.
+	p := Week(time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC))
+	if got := p.Start.Format(dateLayout); got != "2026-02-09" {
+		t.Errorf("Start = %s, want 2026-02-09", got)
+	}
+	if p.Days() != 7 {
+		t.Errorf("Days = %d, want 7", p.Days())
+	}
+}
+
+func TestQuarter(t *testing.T) {
+	p := Quarter(time.Date(2026, 5, 20, 0, 0, 0, 0, time.UTC))
+	if p.Label != "2026-Q2" || p.Start.Format(dateLayout) != "2026-04-01" || p.Days() != 91 {
+		t.Errorf("Quarter = %s from %s for %d days, want 2026-Q2 from 2026-04-01 for 91", p.Label, p.Start.Format(dateLayout), p.Days())
+	}
+	var labels []string
+	for _, m := range p.Months() {
+		labels = append(labels, m.Label)
+	}
+	if got := strings.Join(labels, " "); got != "2026-04 2026-05 2026-06" {
+		t.Errorf("Months = %s, want 2026-04 2026-05 2026-06", got)
+	}
+}
+
+func TestMonths_Clipped(t *testing.T) {
+	p := Range(time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC))
+	ms := p.Months()
+	if len(ms) != 2 || ms[0].Days() != 12 || ms[1].Days() != 10 {
+		t.Errorf("Months = %+v, want January's last 12 days and February's first 10", ms)
+	}
+}
@@ -0,0 +1,143 @@
+// Package progress reports how far a multi-day backfill has got.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mode selects how a Bar reports.
+type Mode int
+
+const (
+	// ModeBar redraws a single line in place. Use it on a terminal.
+	ModeBar Mode = iota
+	// ModeLines prints a plain line at every 10% of progress, for logs
+	// and other non-terminal output.
+	ModeLines
+	// ModeQuiet reports nothing.
+	ModeQuiet
+)
+
+// barWidth is the number of cells inside the brackets.
+const barWidth = 24
+
+// Bar tracks completed steps out of a known total and reports progress with
+// an ETA.
+type Bar struct {
+	// Status, if set, returns extra text for the progress line, such as
+	// API call counts.
+	Status func() string
+
+	mu     sync.Mutex
+	w      io.Writer
+	mode   Mode
+	unit   string
+	total  int
+	done   int
+	start  time.Time
+	now    func() time.Time
+	drawn  bool
+	decile int
+}
+
+// New returns a Bar for total steps of unit (e.g. "days") that writes to w.
+func New(w io.Writer, total int, unit string, mode Mode) *Bar {
+	b := &Bar{w: w, mode: mode, unit: unit, total: total, now: time.Now}
+	b.start = b.now()
+	return b
+}
+
+// Step marks one more step complete and updates the report.
+func (b *Bar) Step() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.done++
+	switch b.mode {
+	case ModeBar:
+		b.draw()
+	case ModeLines:
+		if d := b.done * 10 / max(b.total, 1); d > b.decile || b.done == b.total {
+			b.decile = d
+			fmt.Fprintln(b.w, b.line(false))
+		}
+	}
+}
+
+// Above runs fn with the bar cleared, so anything fn prints appears above
+// the bar instead of being drawn over by it.
+func (b *Bar) Above(fn func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.drawn {
+		b.clear()
+		defer b.draw()
+	}
+	fn()
+}
+
+// Done removes the bar. Further steps are not reported.
+func (b *Bar) Done() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.drawn {
+		b.clear()
+	}
+	b.mode = ModeQuiet
+}
+
+// Elapsed returns the time since the bar was created.
+func (b *Bar) Elapsed() time.Duration { return b.now().Sub(b.start) }
+
+func (b *Bar) draw() {
+	if b.mode != ModeBar {
+		return
+	}
+	fmt.Fprint(b.w, "\r\033[K"+b.line(true))
+	b.drawn = true
+}
+
+func (b *Bar) clear() {
+	fmt.Fprint(b.w, "\r\033[K")
+	b.drawn = false
+}
+
+// line formats the current progress, with a bar graphic when graphic is set.
+func (b *Bar) line(graphic bool) string {
+	var sb strings.Builder
+	if graphic {
+		filled := barWidth
+		if b.total > 0 {
+			filled = b.done * barWidth / b.total
+		}
+		sb.WriteString("[")
+		sb.WriteString(strings.Repeat("=", filled))
+		if filled < barWidth {
+			sb.WriteString(">")
+			sb.WriteString(strings.Repeat(" ", barWidth-filled-1))
+		}
+		sb.WriteString("] ")
+	}
+	fmt.Fprintf(&sb, "%d/%d %s", b.done, b.total, b.unit)
+	if b.Status != nil {
+		if s := b.Status(); s != "" {
+			sb.WriteString(" · " + s)
+		}
+	}
+	if eta, ok := b.eta(); ok {
+		sb.WriteString(" · ETA " + eta.String())
+	}
+	return sb.String()
+}
+
+// eta estimates the time left from the average time per completed step.
+func (b *Bar) eta() (time.Duration, bool) {
+	if b.done == 0 || b.done >= b.total {
+		return 0, false
+	}
+	per := b.Elapsed() / time.Duration(b.done)
+	return (per * time.Duration(b.total-b.done)).Round(time.Second), true
+}
@@ -0,0 +1,87 @@
+package progress
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock advances one second every time it is read after the start.
+func fakeClock() func() time.Time {
+	t := time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		t = t.Add(time.Second)
+		return t
+	}
+}
+
+func TestLines(t *testing.T) {
+	var buf bytes.Buffer
+	b := New(&buf, 20, "days", ModeLines)
+	calls := 0
+	b.Status = func() string { return fmt.Sprintf("%d calls", calls) }
+	for i := 0; i < 20; i++ {
+		calls += 4
+		b.Step()
+	}
+	b.Done()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 10 {
+		t.Fatalf("got %d lines, want one per 10%%:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "2/20 days · 8 calls · ETA ") {
+		t.Errorf("first line = %q", lines[0])
+	}
+	if lines[9] != "20/20 days · 80 calls" {
+		t.Errorf("last line = %q", lines[9])
+	}
+}
+
+func TestBar(t *testing.T) {
+	var buf bytes.Buffer
+	b := New(&buf, 4, "days", ModeBar)
+	b.now = fakeClock()
+	b.start = b.now()
+	b.Step()
+	b.Above(func() { buf.WriteString("warning\n") })
+	b.Step()
+	b.Done()
+
+	out := buf.String()
+	if !strings.Contains(out, "[======>                 ] 1/4 days · ETA ") {
+		t.Errorf("missing first bar in %q", out)
+	}
+	if !strings.Contains(out, "\r\033[Kwarning\n") {
+		t.Errorf("bar not cleared before Above output: %q", out)
+	}
+	if !strings.HasSuffix(out, "\r\033[K") {
+		t.Errorf("Done did not clear the bar: %q", out)
+	}
+}
+
+func TestETA(t *testing.T) {
+	b := New(&bytes.Buffer{}, 10, "days", ModeQuiet)
+	start := b.start
+	b.now = func() time.Time { return start.Add(20 * time.Second) }
+	for i := 0; i < 4; i++ {
+		b.Step()
+	}
+	eta, ok := b.eta()
+	if !ok || eta != 30*time.Second {
+		t.Errorf("eta() = %s, %v; want 30s, true", eta, ok)
+	}
+}
+
+func TestQuiet(t *testing.T) {
+	var buf bytes.Buffer
+	b := New(&buf, 3, "days", ModeQuiet)
+	b.Step()
+	b.Step()
+	b.Done()
+	if buf.Len() != 0 {
+		t.Errorf("quiet bar wrote %q", buf.String())
+	}
+}
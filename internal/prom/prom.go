@@ -0,0 +1,50 @@
+// Package prom writes metrics in the Prometheus text exposition format.
+package prom
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Metric types.
+const (
+	Gauge   = "gauge"
+	Counter = "counter"
+)
+
+// Metric is a single unlabelled sample with its metadata.
+type Metric struct {
+	Name  string
+	Help  string
+	Type  string
+	Value float64
+}
+
+// Write writes ms to w in the text exposition format (version 0.0.4).
+func Write(w io.Writer, ms []Metric) error {
+	bw := bufio.NewWriter(w)
+	for _, m := range ms {
+		fmt.Fprintf(bw, "# HELP %s %s\n", m.Name, m.Help)
+		fmt.Fprintf(bw, "# TYPE %s %s\n", m.Name, m.Type)
+		fmt.Fprintf(bw, "%s %s\n", m.Name, formatValue(m.Value))
+	}
+	return bw.Flush()
+}
+
+// ContentType is the Content-Type of the output of Write.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
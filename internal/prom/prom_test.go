@@ -0,0 +1,32 @@
+package prom
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	var sb strings.Builder
+	err := Write(&sb, []Metric{
+		{Name: "whoop_recovery_percent", Help: "Latest recovery score.", Type: Gauge, Value: 28},
+		{Name: "whoop_garden_api_calls_total", Help: "WHOOP API requests.", Type: Counter, Value: 1234567},
+		{Name: "x", Help: "h", Type: Gauge, Value: math.Inf(1)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP whoop_recovery_percent Latest recovery score.
+# TYPE whoop_recovery_percent gauge
+whoop_recovery_percent 28
+# HELP whoop_garden_api_calls_total WHOOP API requests.
+# TYPE whoop_garden_api_calls_total counter
+whoop_garden_api_calls_total 1234567
+# HELP x h
+# TYPE x gauge
+x +Inf
+`
+	if sb.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", sb.String(), want)
+	}
+}
@@ -0,0 +1,169 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/analytics"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+)
+
+// The helpers below take numbers as any so that templates can pass int
+// and float64 fields alike.
+
+// Round rounds v to places decimal places.
+func Round(v any, places int) (float64, error) {
+	f, err := toFloat(v)
+	if err != nil {
+		return 0, err
+	}
+	p := math.Pow(10, float64(places))
+	return math.Round(f*p) / p, nil
+}
+
+// Pct returns part as a percentage of total, or 0 when total is zero.
+func Pct(part, total any) (float64, error) {
+	q, err := Divide(part, total)
+	return q * 100, err
+}
+
+// Divide returns a/b, or 0 when b is zero.
+func Divide(a, b any) (float64, error) {
+	x, err := toFloat(a)
+	if err != nil {
+		return 0, err
+	}
+	y, err := toFloat(b)
+	if err != nil || y == 0 {
+		return 0, err
+	}
+	return x / y, nil
+}
+
+// KJToKcal converts kilojoules to kilocalories.
+func KJToKcal(kj any) (float64, error) {
+	f, err := toFloat(kj)
+	return f / 4.184, err
+}
+
+// MetersToMiles converts meters to miles.
+func MetersToMiles(m any) (float64, error) {
+	f, err := toFloat(m)
+	return f / metersPerMile, err
+}
+
+// FormatTime formats t, a time.Time or a WHOOP timestamp, with a Go time
+// layout such as "15:04". Timestamps that do not parse give "".
+func FormatTime(layout string, t any) string {
+	switch v := t.(type) {
+	case time.Time:
+		return v.Format(layout)
+	case string:
+		if parsed, err := fetch.ParseWhoopTime(v); err == nil {
+			return parsed.Format(layout)
+		}
+	}
+	return ""
+}
+
+// LocalTime moves the WHOOP timestamp ts to its record's timezone_offset,
+// so times read as they did on the wearer's clock.
+func LocalTime(ts, offset string) time.Time {
+	t, _ := analytics.LocalTime(ts, offset)
+	return t
+}
+
+// DurationBetween formats the time from start to end, two WHOOP
+// timestamps, like millisToMinutes: "1h 5m". It is "" when either does not
+// parse.
+func DurationBetween(start, end string) string {
+	s, err1 := fetch.ParseWhoopTime(start)
+	e, err2 := fetch.ParseWhoopTime(end)
+	if err1 != nil || err2 != nil {
+		return ""
+	}
+	return MillisToMinutes(e.Sub(s).Milliseconds())
+}
+
+// Default returns v, or def when v is empty: nil, zero, "", or an empty
+// slice or map. It reads well in pipelines: {{.Name | default "—"}}.
+func Default(def, v any) any {
+	if v == nil {
+		return def
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		if rv.Len() == 0 {
+			return def
+		}
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return def
+		}
+	default:
+		if rv.IsZero() {
+			return def
+		}
+	}
+	return v
+}
+
+// Sum adds the numbers in list, a slice of ints or floats.
+func Sum(list any) (float64, error) {
+	var total float64
+	err := eachNumber(list, func(f float64) { total += f })
+	return total, err
+}
+
+// Avg averages the numbers in list, or returns 0 when it is empty.
+func Avg(list any) (float64, error) {
+	var total float64
+	n := 0
+	err := eachNumber(list, func(f float64) { total += f; n++ })
+	return avg(total, n), err
+}
+
+// Last returns the last element of list, or nil when it is empty.
+func Last(list any) (any, error) {
+	rv := reflect.ValueOf(list)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("last: %T is not a list", list)
+	}
+	if rv.Len() == 0 {
+		return nil, nil
+	}
+	return rv.Index(rv.Len() - 1).Interface(), nil
+}
+
+// eachNumber calls fn with every element of list as a float64.
+func eachNumber(list any, fn func(float64)) error {
+	rv := reflect.ValueOf(list)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return fmt.Errorf("%T is not a list of numbers", list)
+	}
+	for i := 0; i < rv.Len(); i++ {
+		f, err := toFloat(rv.Index(i).Interface())
+		if err != nil {
+			return err
+		}
+		fn(f)
+	}
+	return nil
+}
+
+// toFloat converts an int, uint, or float of any size to float64.
+func toFloat(v any) (float64, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	}
+	return 0, fmt.Errorf("%v (%T) is not a number", v, v)
+}
@@ -0,0 +1,257 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benstraw/whoop-garden/internal/analytics"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+)
+
+// dayRules each say at most one thing about a day, in the order the
+// narrative tells them.
+var dayRules = []func(Day) string{
+	dayRecoveryRule,
+	dayHRVRule,
+	daySleepRule,
+	daySleepDebtRule,
+	dayStrainRule,
+	dayRespiratoryRule,
+}
+
+// weekRules each say at most one thing about a week.
+var weekRules = []func(WeekStats) string{
+	weekRecoveryRule,
+	weekDropRule,
+	weekStrainRule,
+	weekSleepRule,
+	weekSleepDebtRule,
+}
+
+// Narrative describes a day (Day or fetch.DayData) or a week (WeekStats)
+// in a few sentences, one from each rule that applies. The same data
+// always gives the same text; it is empty when no rule applies.
+func Narrative(v any) (string, error) {
+	var out []string
+	add := func(s string) {
+		if s != "" {
+			out = append(out, s)
+		}
+	}
+	switch x := v.(type) {
+	case Day:
+		for _, rule := range dayRules {
+			add(rule(x))
+		}
+	case *Day:
+		if x != nil {
+			return Narrative(*x)
+		}
+	case fetch.DayData:
+		return Narrative(Day{DayData: x})
+	case *fetch.DayData:
+		if x != nil {
+			return Narrative(*x)
+		}
+	case WeekStats:
+		for _, rule := range weekRules {
+			add(rule(x))
+		}
+	case *WeekStats:
+		if x != nil {
+			return Narrative(*x)
+		}
+	default:
+		return "", fmt.Errorf("narrative: want a day or week stats, got %T", v)
+	}
+	return strings.Join(out, " "), nil
+}
+
+// narrativeSwing is the change in recovery points the narrative calls a
+// drop or a rise.
+const narrativeSwing = 10
+
+// highStrain is the day strain the narrative calls high.
+const highStrain = 14
+
+func dayRecoveryRule(d Day) string {
+	r := analytics.ScoredRecovery(d.DayData)
+	if r == nil {
+		return ""
+	}
+	score := r.Score.RecoveryScore
+	if d.Previous != nil {
+		if prev := analytics.ScoredRecovery(*d.Previous); prev != nil {
+			delta := score - prev.Score.RecoveryScore
+			strain := 0.0
+			if c := d.Previous.Cycle; c != nil && c.ScoreState == "SCORED" {
+				strain = c.Score.Strain
+			}
+			switch {
+			case delta <= -narrativeSwing && strain >= highStrain:
+				return Locale.T("Recovery dropped %s points to %s%% after a strain of %s yesterday.", Locale.Number(-delta, 0), Locale.Number(score, 0), Locale.Number(strain, 1))
+			case delta <= -narrativeSwing:
+				return Locale.T("Recovery dropped %s points to %s%%.", Locale.Number(-delta, 0), Locale.Number(score, 0))
+			case delta >= narrativeSwing:
+				return Locale.T("Recovery rose %s points to %s%%.", Locale.Number(delta, 0), Locale.Number(score, 0))
+			}
+		}
+	}
+	if ro := d.Rolling7; ro != nil && ro.Days >= 3 {
+		switch diff := score - ro.AvgRecovery; {
+		case diff >= narrativeSwing:
+			return Locale.T("Recovery of %s%% is well above the 7-day average of %s%%.", Locale.Number(score, 0), Locale.Number(ro.AvgRecovery, 0))
+		case diff <= -narrativeSwing:
+			return Locale.T("Recovery of %s%% is well below the 7-day average of %s%%.", Locale.Number(score, 0), Locale.Number(ro.AvgRecovery, 0))
+		}
+	}
+	return Locale.T("Recovery is %s at %s%%.", Locale.T(RecoveryColor(score)), Locale.Number(score, 0))
+}
+
+func dayHRVRule(d Day) string {
+	b := d.HRVBaseline
+	switch {
+	case b == nil:
+		return ""
+	case b.Z >= 1:
+		return Locale.T("HRV is well above baseline at %s ms.", Locale.Number(b.Value, 0))
+	case b.Z <= -1:
+		return Locale.T("HRV is well below baseline at %s ms.", Locale.Number(b.Value, 0))
+	}
+	return ""
+}
+
+func daySleepRule(d Day) string {
+	s := analytics.PrimarySleep(d.Sleeps)
+	if s == nil {
+		return ""
+	}
+	n, ok := analytics.NightSleepNeed(*s)
+	switch {
+	case !ok:
+		return ""
+	case n.Fulfillment < 85:
+		return Locale.T("You slept %s of the %s you needed.", MillisToMinutes(n.Asleep), MillisToMinutes(n.Need))
+	case n.Fulfillment >= 100:
+		return Locale.T("You slept the full %s you needed.", MillisToMinutes(n.Need))
+	}
+	return ""
+}
+
+func daySleepDebtRule(d Day) string {
+	if d.SleepDebt == nil || d.SleepDebt.Debt < 3_600_000 {
+		return ""
+	}
+	return Locale.T("Sleep debt is %s.", MillisToMinutes(d.SleepDebt.Debt))
+}
+
+func dayStrainRule(d Day) string {
+	r := analytics.ScoredRecovery(d.DayData)
+	if r == nil {
+		return ""
+	}
+	t := TargetStrain(r.Score.RecoveryScore)
+	if t == nil {
+		return ""
+	}
+	low, high := Locale.Number(t.Low, 0), Locale.Number(t.High, 0)
+	c := d.Cycle
+	if c == nil || c.ScoreState != "SCORED" || c.End == "" {
+		return Locale.T("Recovery suggests a strain of %s–%s today.", low, high)
+	}
+	strain := Locale.Number(c.Score.Strain, 1)
+	switch t.Compare(c.Score.Strain) {
+	case 1:
+		return Locale.T("Strain of %s overshot the %s–%s target.", strain, low, high)
+	case -1:
+		return Locale.T("Strain of %s stayed under the %s–%s target.", strain, low, high)
+	}
+	return Locale.T("Strain of %s landed in the %s–%s target.", strain, low, high)
+}
+
+func dayRespiratoryRule(d Day) string {
+	b := d.Respiratory
+	if b == nil || !b.Flagged {
+		return ""
+	}
+	return Locale.T("Respiratory rate of %s is off its baseline of %s, which can be an early sign of illness.", Locale.Number(b.Value, 1), Locale.Number(b.Mean, 1))
+}
+
+func weekRecoveryRule(ws WeekStats) string {
+	if ws.RecoveryDays == 0 {
+		return ""
+	}
+	avg := Locale.Number(ws.AvgRecovery, 0)
+	if p := ws.Previous; p != nil && p.RecoveryDays > 0 {
+		switch delta := ws.AvgRecovery - p.AvgRecovery; {
+		case delta >= 5:
+			return Locale.T("Average recovery rose %s points to %s%%.", Locale.Number(delta, 0), avg)
+		case delta <= -5:
+			return Locale.T("Average recovery fell %s points to %s%%.", Locale.Number(-delta, 0), avg)
+		}
+	}
+	return Locale.T("Average recovery was %s%%, with %d green and %d red days.", avg, ws.GreenDays, ws.RedDays)
+}
+
+// weekDropRule reports the longest run of at least three consecutive
+// days on which recovery fell, and whether strain stayed high during it.
+func weekDropRule(ws WeekStats) string {
+	best, bestEnd, run := 0, 0, 0
+	for i := 1; i < len(ws.Days); i++ {
+		prev, cur := analytics.ScoredRecovery(ws.Days[i-1]), analytics.ScoredRecovery(ws.Days[i])
+		if prev != nil && cur != nil && cur.Score.RecoveryScore < prev.Score.RecoveryScore {
+			run++
+		} else {
+			run = 0
+		}
+		if run > best {
+			best, bestEnd = run, i
+		}
+	}
+	if best < 3 {
+		return ""
+	}
+	var strain float64
+	n := 0
+	for _, d := range ws.Days[bestEnd-best : bestEnd] {
+		if d.Cycle != nil && d.Cycle.ScoreState == "SCORED" {
+			strain += d.Cycle.Score.Strain
+			n++
+		}
+	}
+	end := Locale.Date(ws.Days[bestEnd].Date)
+	if n > 0 && strain/float64(n) >= highStrain {
+		return Locale.T("Recovery dropped %d days running through %s while strain stayed high.", best, end)
+	}
+	return Locale.T("Recovery dropped %d days running through %s.", best, end)
+}
+
+func weekStrainRule(ws WeekStats) string {
+	n := len(ws.StrainTargets)
+	switch {
+	case n < 3:
+		return ""
+	case ws.AboveTargetDays*2 > n:
+		return Locale.T("Strain overshot its target on %d of %d days.", ws.AboveTargetDays, n)
+	case ws.BelowTargetDays*2 > n:
+		return Locale.T("Strain fell short of its target on %d of %d days.", ws.BelowTargetDays, n)
+	case ws.OnTargetDays == n:
+		return Locale.T("Strain matched its target every day.")
+	}
+	return ""
+}
+
+func weekSleepRule(ws WeekStats) string {
+	n := ws.SleepNeed
+	if n == nil || n.Fulfillment >= 90 {
+		return ""
+	}
+	return Locale.T("Sleep met %s%% of need on average.", Locale.Number(n.Fulfillment, 0))
+}
+
+func weekSleepDebtRule(ws WeekStats) string {
+	if ws.SleepDebt == nil || ws.SleepDebt.Debt < 3_600_000 {
+		return ""
+	}
+	return Locale.T("Sleep debt stands at %s.", MillisToMinutes(ws.SleepDebt.Debt))
+}
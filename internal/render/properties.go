@@ -0,0 +1,100 @@
+package render
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/analytics"
+	"github.com/benstraw/whoop-garden/internal/config"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+)
+
+// Properties maps the metrics daily notes carry as frontmatter properties
+// to the property names they are written under. nil writes every metric
+// under its own name. main sets it from config.
+var Properties map[string]string
+
+// propertyValues gives each of config.PropertyMetrics the YAML for its
+// value on a day, starting with a space for scalars and a newline for
+// lists, or false when the day has none.
+var propertyValues = map[string]func(fetch.DayData) (string, bool){
+	"recovery": func(d fetch.DayData) (string, bool) {
+		if r := analytics.ScoredRecovery(d); r != nil {
+			return " " + strconv.FormatFloat(r.Score.RecoveryScore, 'f', 0, 64), true
+		}
+		return "", false
+	},
+	"hrv": func(d fetch.DayData) (string, bool) {
+		if r := d.Recovery; r != nil && r.ScoreState == "SCORED" {
+			return " " + strconv.FormatFloat(r.Score.HrvRmssdMilli, 'f', 1, 64), true
+		}
+		return "", false
+	},
+	"rhr": func(d fetch.DayData) (string, bool) {
+		if r := d.Recovery; r != nil && r.ScoreState == "SCORED" {
+			return " " + strconv.FormatFloat(r.Score.RestingHeartRate, 'f', 0, 64), true
+		}
+		return "", false
+	},
+	"strain": func(d fetch.DayData) (string, bool) {
+		if c := d.Cycle; c != nil && c.ScoreState == "SCORED" {
+			return " " + strconv.FormatFloat(c.Score.Strain, 'f', 1, 64), true
+		}
+		return "", false
+	},
+	"sleep_hours": func(d fetch.DayData) (string, bool) {
+		if s := analytics.PrimarySleep(d.Sleeps); s != nil && s.ScoreState == "SCORED" {
+			h := float64(analytics.AsleepMillis(*s)) / float64(time.Hour/time.Millisecond)
+			return " " + strconv.FormatFloat(h, 'f', 2, 64), true
+		}
+		return "", false
+	},
+	"workouts": func(d fetch.DayData) (string, bool) {
+		return " " + strconv.Itoa(len(d.Workouts)), true
+	},
+	"sports": func(d fetch.DayData) (string, bool) {
+		var out string
+		seen := map[string]bool{}
+		for _, w := range d.Workouts {
+			name := WorkoutSport(w)
+			if !seen[name] {
+				seen[name] = true
+				out += "\n  - " + strconv.Quote(name)
+			}
+		}
+		return out, out != ""
+	},
+	"sport_categories": func(d fetch.DayData) (string, bool) {
+		var out string
+		seen := map[string]bool{}
+		for _, w := range d.Workouts {
+			m, _ := SportInfo(w)
+			if m.Category != "" && !seen[m.Category] {
+				seen[m.Category] = true
+				out += "\n  - " + m.Category
+			}
+		}
+		return out, out != ""
+	},
+}
+
+// DayProperties returns the frontmatter lines for d's properties: numbers
+// unquoted and sports as a YAML list, so Obsidian types them. Metrics the
+// day has no value for are left out.
+func DayProperties(d Day) []string {
+	var lines []string
+	for _, metric := range config.PropertyMetrics {
+		name := metric
+		if Properties != nil {
+			name = Properties[metric]
+		}
+		if name == "" {
+			continue
+		}
+		if v, ok := propertyValues[metric](d.DayData); ok {
+			lines = append(lines, fmt.Sprintf("%s:%s", name, v))
+		}
+	}
+	return lines
+}
@@ -4,47 +4,114 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
 
+	"github.com/benstraw/whoop-garden/internal/analytics"
+	"github.com/benstraw/whoop-garden/internal/changelog"
+	"github.com/benstraw/whoop-garden/internal/config"
 	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/links"
+	"github.com/benstraw/whoop-garden/internal/locale"
 	"github.com/benstraw/whoop-garden/internal/models"
+	"github.com/benstraw/whoop-garden/internal/strava"
 )
 
+// Version identifies the whoop-garden build in note frontmatter. main sets
+// it at startup.
+var Version = "dev"
+
+// Locale translates generated text and formats numbers and dates. main
+// sets it from config.
+var Locale = locale.Get("en")
+
+// Now returns the current time for generated dates. templates test pins it
+// so output does not change from day to day.
+var Now = time.Now
+
+// Links writes the wikilink targets between notes. main sets it at startup.
+var Links = func() links.Linker { return links.Linker{Folder: "Health/WHOOP"} }
+
 const personaTemplate = `---
 type: context
 tags: [ai-brain/context, fitness/whoop]
 updated: {{.GeneratedDate}}
+generator: whoop-garden {{version}}
 ---
 
-# WHOOP Health Persona
+# {{t "WHOOP Health Persona"}}
+
+> [!info] {{t "Auto-generated"}}
+> {{t "Regenerate with %s. Covers %s → %s." "` + "`whoop-garden persona`" + `" .PeriodStart .PeriodEnd}}
 
-> [!info] Auto-generated
-> Regenerate with ` + "`" + `whoop-garden persona` + "`" + `. Covers {{.PeriodStart}} → {{.PeriodEnd}}.
+## {{t "Health Persona (%d-Day Rolling Summary)" .Days}}
 
-## Health Persona (30-Day Rolling Summary)
+**{{t "Period"}}:** {{.PeriodStart}} → {{.PeriodEnd}}
+{{- with .Prior}}
+**{{t "Compared with"}}:** {{.PeriodStart}} → {{.PeriodEnd}}
+{{- end}}
+{{- if or .Profile .Body}}
 
-**Period:** {{.PeriodStart}} → {{.PeriodEnd}}
+### {{t "Profile"}}
+{{- with .Profile}}
+- {{t "Name"}}: **{{.FirstName}} {{.LastName}}**
+{{- end}}
+{{- with .Body}}
+- {{t "Height"}}: **{{num .HeightMeter 2}} m**
+- {{t "Weight"}}: **{{num .WeightKilogram 1}} kg**
+- {{t "Max Heart Rate"}}: **{{.MaxHeartRate}} bpm**
+{{- end}}
+{{- end}}
 
-### Recovery
-- Average Recovery Score: **{{printf "%.0f" .AvgRecovery}}%**
-- Average HRV: **{{printf "%.1f" .AvgHRV}} ms**
-- HRV Trend: **{{.HRVTrend}}**
-- Average RHR: **{{printf "%.0f" .AvgRHR}} bpm**
+### {{t "Recovery"}}
+- {{t "Average Recovery Score"}}: **{{num .AvgRecovery 0}}%**{{with .Prior}} ({{delta .AvgRecovery $.AvgRecovery "%.0f"}} {{t "vs prior %dd" .Days}}){{end}}
+- {{t "Average HRV"}}: **{{num .AvgHRV 1}} ms**{{with .Prior}} ({{delta .AvgHRV $.AvgHRV "%.1f"}} ms){{end}}
+- {{t "HRV Trend"}}: **{{.HRVTrend}}**
+{{- with .HRVBaseline}}
+- {{t "Latest HRV vs Baseline"}}: **{{sigma .Z}}** ({{t "%s ms vs %s ± %s ms over %d days, %s percentile" (num .Value 1) (num .Mean 1) (num .Std 1) .Days (ordinal .Percentile)}})
+{{- end}}
+- {{t "Average RHR"}}: **{{num .AvgRHR 0}} bpm**{{with .Prior}} ({{delta .AvgRHR $.AvgRHR "%.0f"}} bpm){{end}}
+{{- if .CalibratingDays}}
+- {{t "Calibrating Days"}}: **{{.CalibratingDays}}** ({{t "left out of the recovery figures; WHOOP was still calibrating"}})
+{{- end}}
 
-### Sleep
-- Average Sleep Duration: **{{millisToMinutes .AvgSleepMillis}}**
-- Average Sleep Performance: **{{printf "%.0f" .AvgSleepPerf}}%**
+### {{t "Sleep"}}
+- {{t "Average Sleep Duration"}}: **{{millisToMinutes .AvgSleepMillis}}**{{with .Prior}} ({{deltaMillis .AvgSleepMillis $.AvgSleepMillis}}){{end}}
+- {{t "Average Time Asleep"}}: **{{millisToMinutes .AvgAsleepMillis}}** ({{t "of %s in bed" (millisToMinutes .AvgInBedMillis)}})
+- {{t "Average Sleep Performance"}}: **{{num .AvgSleepPerf 0}}%**{{with .Prior}} ({{delta .AvgSleepPerf $.AvgSleepPerf "%.0f"}}){{end}}
+{{- if .Naps}}
+- {{t "Naps"}}: **{{.Naps}}** ({{t "%s in total" (millisToMinutes .NapMillis)}}){{with .Prior}} ({{deltaInt .Naps $.Naps}}){{end}}
+{{- end}}
+{{- with .Schedule}}
+- {{t "Sleep Midpoint"}}: **{{clock .AvgMidpoint}}** ({{t "bed %s, wake %s" (clock .AvgBedtime) (clock .AvgWake)}}){{if .MidpointDriftMillis}}, {{t "drifting %s/week" (deltaMillis 0 .MidpointDriftMillis)}}{{end}}
+{{- if .SocialJetlagMillis}}
+- {{t "Social Jetlag"}}: **{{deltaMillis 0 .SocialJetlagMillis}}** ({{t "free-day midpoint vs workdays"}})
+{{- end}}
+{{- end}}
 
-### Strain
-- Average Day Strain: **{{printf "%.1f" .AvgStrain}}**
-- Total Workouts: **{{.TotalWorkouts}}**
+### {{t "Strain"}}
+- {{t "Average Day Strain"}}: **{{num .AvgStrain 1}}**{{with .Prior}} ({{delta .AvgStrain $.AvgStrain "%.1f"}}){{end}}
+- {{t "Total Workouts"}}: **{{.TotalWorkouts}}**{{with .Prior}} ({{deltaInt .TotalWorkouts $.TotalWorkouts}}){{end}}
+{{- with .TrainingLoad}}
+- {{t "Acute:Chronic Workload Ratio"}}: **{{num .Ratio 2}}** ({{t "7-day strain %s vs 28-day %s" (num .Acute 1) (num .Chronic 1)}}){{if .Spike}} ⚠️ {{t "load spike"}}{{end}}
+{{- end}}
+{{- if .Sports}}
 
-### Recovery Distribution
-- Green (67–100): {{.GreenDays}} days
-- Yellow (34–66): {{.YellowDays}} days
-- Red (0–33): {{.RedDays}} days
+### {{t "Sports"}}
+| {{t "Sport"}} | {{t "Sessions"}} | {{t "Time"}} | {{t "Strain"}} |
+|-------|----------|------|--------|
+{{- range .Sports}}
+| {{.Name}} | {{.Sessions}} | {{millisToMinutes .Millis}} | {{num .Strain 1}} |
+{{- end}}
+{{- end}}
+
+### {{t "Recovery Distribution"}}
+- {{t "Green"}} (67–100): {{t "%d days" .GreenDays}}{{with .Prior}} ({{deltaInt .GreenDays $.GreenDays}}){{end}}
+- {{t "Yellow"}} (34–66): {{t "%d days" .YellowDays}}{{with .Prior}} ({{deltaInt .YellowDays $.YellowDays}}){{end}}
+- {{t "Red"}} (0–33): {{t "%d days" .RedDays}}{{with .Prior}} ({{deltaInt .RedDays $.RedDays}}){{end}}
 `
 
 // avg returns total/count, or 0 when count is zero.
@@ -58,22 +125,131 @@ func avg(total float64, count int) float64 {
 // FuncMap returns the template helper functions.
 func FuncMap() template.FuncMap {
 	return template.FuncMap{
+		"version":         func() string { return Version },
+		"properties":      DayProperties,
 		"millisToMinutes": MillisToMinutes,
+		"asleepMillis":    analytics.AsleepMillis,
+		"balance":         analytics.Balance,
+		"strainTarget":    TargetStrain,
+		"narrative":       Narrative,
 		"recoveryColor":   RecoveryColor,
-		"strainCategory":  StrainCategory,
+		"strainCategory":  func(strain float64) string { return Locale.T(StrainCategory(strain)) },
 		"sportName":       SportName,
+		"sport":           WorkoutSport,
+		"sportInfo":       SportInfo,
+		"sportTags":       SportTags,
 		"primarySleep":    PrimarySleep,
 		"nonNapSleeps":    NonNapSleeps,
+		"naps":            Naps,
+		"napMillis":       NapMillis,
+		"stages":          SleepStages,
+		"stageBar":        StageBar,
+		"sleepNeed":       SleepNeed,
 		"prevDay":         PrevDay,
 		"nextDay":         NextDay,
 		"isoWeek":         ISOWeekStr,
 		"prevWeek":        PrevWeekStr,
 		"nextWeek":        NextWeekStr,
+		"noteLink":        func(kind, key string) string { return Links().Note("", kind, key) },
 		"prevDayYear":     PrevDayYear,
 		"nextDayYear":     NextDayYear,
 		"isoWeekYear":     ISOWeekYear,
 		"prevWeekYear":    PrevWeekYear,
 		"nextWeekYear":    NextWeekYear,
+		"delta":           Delta,
+		"deltaMillis":     DeltaMillis,
+		"deltaInt":        DeltaInt,
+		"trend":           Trend,
+		"sigma":           Sigma,
+		"ordinal":         Ordinal,
+		"clock":           Clock,
+		"t":               Locale.T,
+		"num":             Locale.Number,
+		"elevation":       Elevation,
+		"distance":        Distance,
+		"date":            Locale.Date,
+		"round":           Round,
+		"pct":             Pct,
+		"divide":          Divide,
+		"kjToKcal":        KJToKcal,
+		"metersToMiles":   MetersToMiles,
+		"formatTime":      FormatTime,
+		"localTime":       LocalTime,
+		"durationBetween": DurationBetween,
+		"default":         Default,
+		"sum":             Sum,
+		"avg":             Avg,
+		"last":            Last,
+	}
+}
+
+// Delta formats the change from a to b using format (e.g. "%.1f") with an
+// explicit sign: "+3.2", "-1.0", or "±0" when the formatted change is zero.
+func Delta(a, b float64, format string) string {
+	d := Locale.Decimal(fmt.Sprintf(format, math.Abs(b-a)))
+	if d == Locale.Decimal(fmt.Sprintf(format, 0.0)) {
+		return "±" + d
+	}
+	if b < a {
+		return "-" + d
+	}
+	return "+" + d
+}
+
+// Trend formats the change from a to b like Delta, but with an arrow
+// instead of a sign: "↑7", "↓1.2", or "→" when the formatted change is zero.
+func Trend(a, b float64, format string) string {
+	d := Delta(a, b, format)
+	switch d[0] {
+	case '+':
+		return "↑" + d[1:]
+	case '-':
+		return "↓" + d[1:]
+	}
+	return "→"
+}
+
+// Sigma describes a z-score against a baseline, e.g. "-1.8σ below
+// baseline", or "at baseline" when it rounds to zero.
+func Sigma(z float64) string {
+	d := Delta(0, z, "%.1f")
+	switch d[0] {
+	case '+':
+		return Locale.T("%sσ above baseline", d)
+	case '-':
+		return Locale.T("%sσ below baseline", d)
+	}
+	return Locale.T("at baseline")
+}
+
+// Ordinal rounds n and writes it as an ordinal in the locale: "1st",
+// "22nd", "13th" in English.
+func Ordinal(n float64) string { return Locale.Ordinal(n) }
+
+// Clock formats hours from midnight as a 24-hour time: -0.5 is "23:30"
+// and 6.25 is "06:15".
+func Clock(hours float64) string {
+	m := int(math.Round(hours*60)) % (24 * 60)
+	if m < 0 {
+		m += 24 * 60
+	}
+	return fmt.Sprintf("%02d:%02d", m/60, m%60)
+}
+
+// DeltaInt formats the change from a to b as a signed integer.
+func DeltaInt(a, b int) string { return Delta(float64(a), float64(b), "%.0f") }
+
+// DeltaMillis formats the change from a to b milliseconds as a signed
+// duration, e.g. "+25m" or "-1h 5m".
+func DeltaMillis(a, b int64) string {
+	d := b - a
+	switch {
+	case d/60_000 == 0:
+		return "±0m"
+	case d < 0:
+		return "-" + MillisToMinutes(-d)
+	default:
+		return "+" + MillisToMinutes(d)
 	}
 }
 
@@ -93,19 +269,7 @@ func PrevWeekYear(t time.Time) int { year, _ := t.AddDate(0, 0, -7).ISOWeek(); r
 func NextWeekYear(t time.Time) int { year, _ := t.AddDate(0, 0, 7).ISOWeek(); return year }
 
 // PrimarySleep returns the longest non-nap sleep from a slice, or nil if none.
-func PrimarySleep(sleeps []models.Sleep) *models.Sleep {
-	var best *models.Sleep
-	for i := range sleeps {
-		s := &sleeps[i]
-		if s.Nap {
-			continue
-		}
-		if best == nil || s.Score.StageSummary.TotalInBedTimeMilli > best.Score.StageSummary.TotalInBedTimeMilli {
-			best = s
-		}
-	}
-	return best
-}
+func PrimarySleep(sleeps []models.Sleep) *models.Sleep { return analytics.PrimarySleep(sleeps) }
 
 // IndexedSleep wraps a Sleep with its ordinal position among non-nap sleeps.
 type IndexedSleep struct {
@@ -124,6 +288,118 @@ func NonNapSleeps(sleeps []models.Sleep) []IndexedSleep {
 	return result
 }
 
+// Naps filters to nap entries and attaches ordinal index.
+func Naps(sleeps []models.Sleep) []IndexedSleep {
+	var result []IndexedSleep
+	for _, s := range sleeps {
+		if s.Nap {
+			result = append(result, IndexedSleep{Index: len(result), Sleep: s})
+		}
+	}
+	return result
+}
+
+// NapMillis returns the total in-bed time of the naps among sleeps.
+func NapMillis(sleeps []models.Sleep) int64 {
+	var total int64
+	for _, s := range sleeps {
+		if s.Nap {
+			total += s.Score.StageSummary.TotalInBedTimeMilli
+		}
+	}
+	return total
+}
+
+// SleepTime is what sleep averages measure: "in_bed", the time from lying
+// down to getting up, or "asleep", that time less time awake and without
+// data. main sets it from config.
+var SleepTime = "in_bed"
+
+// chooseSleep returns inBed or asleep, as SleepTime says.
+func chooseSleep(inBed, asleep int64) int64 {
+	if SleepTime == "asleep" {
+		return asleep
+	}
+	return inBed
+}
+
+// SleepStages returns the stage percentages of s, or nil when it has none.
+func SleepStages(s models.Sleep) *analytics.Stages {
+	if st, ok := analytics.SleepStages(s); ok {
+		return &st
+	}
+	return nil
+}
+
+// SleepNeed returns the need WHOOP computed for s against the time asleep,
+// or nil when it has none.
+func SleepNeed(s models.Sleep) *fetch.SleepNeed {
+	if n, ok := analytics.NightSleepNeed(s); ok {
+		return &n
+	}
+	return nil
+}
+
+// stageBarWidth is the number of cells in a StageBar.
+const stageBarWidth = 20
+
+// stageBlocks draw the stages in a StageBar, deepest first.
+var stageBlocks = []string{"█", "▓", "▒", "░"}
+
+// StageBar draws st as a bar of stageBarWidth cells: deep sleep, REM, light
+// sleep, then awake. Cells are shared out by largest remainder, so each
+// stage gets its fair share and the bar always has the full width.
+func StageBar(st analytics.Stages) string {
+	pcts := []float64{st.Deep, st.REM, st.Light, st.Awake}
+	var total float64
+	for _, p := range pcts {
+		total += p
+	}
+	if total <= 0 {
+		return ""
+	}
+	cells := make([]int, len(pcts))
+	rem := make([]float64, len(pcts))
+	used := 0
+	for i, p := range pcts {
+		exact := p / total * stageBarWidth
+		cells[i] = int(exact)
+		rem[i] = exact - float64(cells[i])
+		used += cells[i]
+	}
+	for ; used < stageBarWidth; used++ {
+		best := 0
+		for i := range rem {
+			if rem[i] > rem[best] {
+				best = i
+			}
+		}
+		cells[best]++
+		rem[best] = -1
+	}
+	var b strings.Builder
+	for i, n := range cells {
+		b.WriteString(strings.Repeat(stageBlocks[i], n))
+	}
+	return b.String()
+}
+
+// NoteRel returns where the configured layout puts the note of kind and
+// key; see links.Rel.
+func NoteRel(kind, key string) string { return links.Rel(Links().Layout, kind, key) }
+
+// funcsFrom returns FuncMap with noteLink writing the links of the note at
+// rel, its path relative to the output directory without .md. noteLink
+// takes a note's kind ("daily", "weekly", "monthly", "quarterly", or "index")
+// and key.
+func funcsFrom(rel string) template.FuncMap {
+	funcs := FuncMap()
+	l := Links()
+	from := l.Path(rel)
+	funcs["noteLink"] = func(kind, key string) string { return l.Note(from, kind, key) }
+	return funcs
+}
+
 // PrevDay returns "YYYY-MM-DD" for the day before t.
 func PrevDay(t time.Time) string { return t.AddDate(0, 0, -1).Format("2006-01-02") }
 
@@ -165,33 +441,106 @@ func RecoveryColor(score float64) string {
 	}
 }
 
-// StrainCategory returns a label for a strain value.
+// StrainBands are the bands strainCategory labels strain with, lowest
+// first; each labels the strains from its Min up to the next band's Min.
+// main replaces them when config sets strain_bands.
+var StrainBands = []config.StrainBand{
+	{Name: "Minimal", Min: 0},
+	{Name: "Light", Min: 7},
+	{Name: "Moderate", Min: 10},
+	{Name: "Strenuous", Min: 14},
+	{Name: "All Out", Min: 18},
+}
+
+// StrainCategory returns the label of the band strain falls in. Strain
+// below the lowest band gets the lowest band's label.
 func StrainCategory(strain float64) string {
-	switch {
-	case strain >= 18:
-		return "All Out"
-	case strain >= 14:
-		return "Strenuous"
-	case strain >= 10:
-		return "Moderate"
-	case strain >= 7:
-		return "Light"
-	default:
-		return "Minimal"
+	if len(StrainBands) == 0 {
+		return ""
 	}
+	label := StrainBands[0].Name
+	for _, b := range StrainBands[1:] {
+		if strain >= b.Min {
+			label = b.Name
+		}
+	}
+	return label
 }
 
+// SportNames names sport IDs ahead of models.SPORT_NAMES. main sets it from
+// config.
+var SportNames map[int]string
+
 // SportName returns the human-readable name for a WHOOP sport ID.
 func SportName(id int) string {
+	if name, ok := SportNames[id]; ok {
+		return name
+	}
 	if name, ok := models.SPORT_NAMES[id]; ok {
 		return name
 	}
 	return fmt.Sprintf("Sport(%d)", id)
 }
 
+// WorkoutSport returns the name of w's sport: the sport_name WHOOP sent,
+// or else the name of its sport ID.
+func WorkoutSport(w models.Workout) string {
+	if w.SportName != "" {
+		return w.SportName
+	}
+	return SportName(w.SportID)
+}
+
+// Day is what a daily note is rendered from: the day's WHOOP data and the
+// details main adds before rendering, none of which are fetched from WHOOP
+// or stored. Each is nil unless configured and available.
+type Day struct {
+	fetch.DayData
+
+	// Strava maps workout IDs to the Strava activity recorded for the
+	// same session.
+	Strava map[string]*strava.Activity
+
+	// Body is the user's current body measurements, on today's note only;
+	// WHOOP keeps no history of them.
+	Body *models.BodyMeasurements
+
+	// Rolling7 averages the seven days ending on Date.
+	Rolling7 *fetch.Rolling
+
+	// Previous is the day before Date.
+	Previous *fetch.DayData
+
+	// HRVBaseline compares the day's HRV with the days before it.
+	HRVBaseline *fetch.Baseline
+
+	// Respiratory compares the night's respiratory rate with the 30 nights
+	// before it.
+	Respiratory *fetch.Baseline
+
+	// SkinTemp and SpO2 compare the day's skin temperature and blood
+	// oxygen with the 30 days before it.
+	SkinTemp *fetch.Baseline
+	SpO2     *fetch.Baseline
+
+	// Travel is the time zone change since the day before, if the zone
+	// changed.
+	Travel *fetch.Shift
+
+	// Streaks are the streaks running on Date.
+	Streaks *fetch.Streaks
+
+	// SleepDebt covers the seven nights ending on Date.
+	SleepDebt *fetch.SleepDebt
+
+	// Bedtime recommends when to go to bed the night after Date, on
+	// today's note only.
+	Bedtime *fetch.Bedtime
+}
+
 // RenderDaily renders a daily markdown note from a file template.
-func RenderDaily(data fetch.DayData, tmplPath string) (string, error) {
-	tmpl, err := template.New("daily").Funcs(FuncMap()).ParseFiles(tmplPath)
+func RenderDaily(data Day, tmplPath string) (string, error) {
+	tmpl, err := template.New("daily").Funcs(funcsFrom(NoteRel("daily", data.Date.Format("2006-01-02")))).ParseFiles(tmplPath)
 	if err != nil {
 		return "", fmt.Errorf("parse daily template: %w", err)
 	}
@@ -202,35 +551,104 @@ func RenderDaily(data fetch.DayData, tmplPath string) (string, error) {
 	return buf.String(), nil
 }
 
-// personaData holds aggregated stats for the persona template.
-type personaData struct {
+// RenderSummary renders the compact plain-text summary of a day posted to
+// chat channels.
+func RenderSummary(data Day, tmplPath string) (string, error) {
+	tmpl, err := template.New("summary").Funcs(FuncMap()).ParseFiles(tmplPath)
+	if err != nil {
+		return "", fmt.Errorf("parse summary template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "summary.txt.tmpl", data); err != nil {
+		return "", fmt.Errorf("render summary template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// PersonaStats holds aggregated stats for the persona template and the
+// stats command.
+type PersonaStats struct {
 	GeneratedDate  string
 	PeriodStart    string
 	PeriodEnd      string
+	Days           int
 	AvgRecovery    float64
 	AvgHRV         float64
 	HRVTrend       string
 	AvgRHR         float64
-	AvgSleepMillis int64
+	AvgSleepMillis int64 // in bed or asleep, as SleepTime says
 	AvgSleepPerf   float64
 	AvgStrain      float64
 	TotalWorkouts  int
+	Naps           int
+	NapMillis      int64 // total in-bed time of Naps
+	Sports         []SportStat
 	GreenDays      int
 	YellowDays     int
 	RedDays        int
+
+	// CalibratingDays counts days scored while WHOOP was still calibrating,
+	// which the recovery figures leave out.
+	CalibratingDays int
+
+	// AvgInBedMillis and AvgAsleepMillis average the main sleeps' time in
+	// bed and time asleep, whichever AvgSleepMillis shows.
+	AvgInBedMillis  int64
+	AvgAsleepMillis int64
+
+	// Prior is the preceding window of the same length, which the persona
+	// shows deltas against. It is nil when there is nothing to compare.
+	Prior *PersonaStats
+
+	// HRVBaseline compares the last day's HRV with the days before it. It
+	// is nil when there is too little history.
+	HRVBaseline *fetch.Baseline
+
+	// TrainingLoad is the acute:chronic workload ratio at the last day. It
+	// is nil when there is too little history.
+	TrainingLoad *fetch.TrainingLoad
+
+	// Schedule holds the window's bedtimes, wake times, and sleep
+	// midpoints. It is nil without sleep times.
+	Schedule *analytics.Schedule
+
+	// Profile and Body are shown when set; they are left nil unless the
+	// user opts in with include_profile.
+	Profile *models.UserProfile
+	Body    *models.BodyMeasurements
 }
 
 // RenderPersonaSection generates a markdown persona section using 30d rolling data.
 func RenderPersonaSection(data []fetch.DayData) (string, error) {
+	return RenderPersona(data, nil)
+}
+
+// RenderPersona renders the persona for data with deltas against prior,
+// the window before it. Deltas are left out when prior has no cycles.
+func RenderPersona(data, prior []fetch.DayData) (string, error) {
 	if len(data) == 0 {
 		return "", fmt.Errorf("no data provided for persona")
 	}
+	return RenderPersonaFromStats(BuildPersonaComparison(data, prior))
+}
 
-	pd := aggregatePersonaData(data)
+// BuildPersonaComparison aggregates data and, when prior has any cycles,
+// the prior window into pd.Prior. data must not be empty.
+func BuildPersonaComparison(data, prior []fetch.DayData) PersonaStats {
+	pd := BuildPersonaStats(data)
+	for _, d := range prior {
+		if d.Cycle != nil {
+			ps := BuildPersonaStats(prior)
+			pd.Prior = &ps
+			break
+		}
+	}
+	return pd
+}
 
-	funcMap := FuncMap()
-	// millisToMinutes is used in template directly via funcMap
-	tmpl, err := template.New("persona").Funcs(funcMap).Parse(personaTemplate)
+// RenderPersonaFromStats renders the persona from pre-built stats.
+func RenderPersonaFromStats(pd PersonaStats) (string, error) {
+	tmpl, err := template.New("persona").Funcs(FuncMap()).Parse(personaTemplate)
 	if err != nil {
 		return "", fmt.Errorf("parse persona template: %w", err)
 	}
@@ -242,13 +660,17 @@ func RenderPersonaSection(data []fetch.DayData) (string, error) {
 	return buf.String(), nil
 }
 
-func aggregatePersonaData(data []fetch.DayData) personaData {
+// BuildPersonaStats aggregates data into PersonaStats. data must not be empty.
+func BuildPersonaStats(data []fetch.DayData) PersonaStats {
 	var (
 		totalRecovery    float64
 		totalHRV         float64
 		totalRHR         float64
 		totalSleepMillis int64
+		totalAsleep      int64
 		totalSleepPerf   float64
+		naps             int
+		napMillis        int64
 		totalStrain      float64
 		totalWorkouts    int
 		greenDays        int
@@ -257,18 +679,22 @@ func aggregatePersonaData(data []fetch.DayData) personaData {
 		recoveryCount    int
 		sleepCount       int
 		cycleCount       int
+		calibratingDays  int
 		hrvValues        []float64
 	)
 
 	for _, d := range data {
-		if d.Recovery != nil && d.Recovery.ScoreState == "SCORED" {
-			totalRecovery += d.Recovery.Score.RecoveryScore
-			totalHRV += d.Recovery.Score.HrvRmssdMilli
-			totalRHR += d.Recovery.Score.RestingHeartRate
-			hrvValues = append(hrvValues, d.Recovery.Score.HrvRmssdMilli)
+		if analytics.Calibrating(d) {
+			calibratingDays++
+		}
+		if r := analytics.ScoredRecovery(d); r != nil {
+			totalRecovery += r.Score.RecoveryScore
+			totalHRV += r.Score.HrvRmssdMilli
+			totalRHR += r.Score.RestingHeartRate
+			hrvValues = append(hrvValues, r.Score.HrvRmssdMilli)
 			recoveryCount++
 
-			switch RecoveryColor(d.Recovery.Score.RecoveryScore) {
+			switch RecoveryColor(r.Score.RecoveryScore) {
 			case "green":
 				greenDays++
 			case "yellow":
@@ -281,6 +707,7 @@ func aggregatePersonaData(data []fetch.DayData) personaData {
 		for _, s := range d.Sleeps {
 			if !s.Nap && s.ScoreState == "SCORED" {
 				totalSleepMillis += s.Score.StageSummary.TotalInBedTimeMilli
+				totalAsleep += analytics.AsleepMillis(s)
 				totalSleepPerf += s.Score.SleepPerformance
 				sleepCount++
 			}
@@ -292,39 +719,53 @@ func aggregatePersonaData(data []fetch.DayData) personaData {
 		}
 
 		totalWorkouts += len(d.Workouts)
+		naps += len(Naps(d.Sleeps))
+		napMillis += NapMillis(d.Sleeps)
 	}
 
-	var avgSleepMs int64
+	var avgInBed, avgAsleep int64
 	if sleepCount > 0 {
-		avgSleepMs = totalSleepMillis / int64(sleepCount)
+		avgInBed = totalSleepMillis / int64(sleepCount)
+		avgAsleep = totalAsleep / int64(sleepCount)
 	}
 
 	first := data[0].Date.Format("2006-01-02")
 	last := data[len(data)-1].Date.Format("2006-01-02")
 
-	return personaData{
-		GeneratedDate:  time.Now().Format("2006-01-02"),
-		PeriodStart:    first,
-		PeriodEnd:      last,
-		AvgRecovery:    avg(totalRecovery, recoveryCount),
-		AvgHRV:         avg(totalHRV, recoveryCount),
-		HRVTrend:       hrvTrendLabel(hrvValues),
-		AvgRHR:         avg(totalRHR, recoveryCount),
-		AvgSleepMillis: avgSleepMs,
-		AvgSleepPerf:   avg(totalSleepPerf, sleepCount),
-		AvgStrain:      avg(totalStrain, cycleCount),
-		TotalWorkouts:  totalWorkouts,
-		GreenDays:      greenDays,
-		YellowDays:     yellowDays,
-		RedDays:        redDays,
+	ps := PersonaStats{
+		GeneratedDate:   Now().Format("2006-01-02"),
+		PeriodStart:     first,
+		PeriodEnd:       last,
+		Days:            len(data),
+		AvgRecovery:     avg(totalRecovery, recoveryCount),
+		AvgHRV:          avg(totalHRV, recoveryCount),
+		HRVTrend:        hrvTrendLabel(hrvValues),
+		AvgRHR:          avg(totalRHR, recoveryCount),
+		AvgSleepMillis:  chooseSleep(avgInBed, avgAsleep),
+		AvgInBedMillis:  avgInBed,
+		AvgAsleepMillis: avgAsleep,
+		AvgSleepPerf:    avg(totalSleepPerf, sleepCount),
+		AvgStrain:       avg(totalStrain, cycleCount),
+		TotalWorkouts:   totalWorkouts,
+		Naps:            naps,
+		NapMillis:       napMillis,
+		Sports:          SportBreakdown(data),
+		GreenDays:       greenDays,
+		YellowDays:      yellowDays,
+		RedDays:         redDays,
+		CalibratingDays: calibratingDays,
+	}
+	if sc, ok := analytics.SleepSchedule(data); ok {
+		ps.Schedule = &sc
 	}
+	return ps
 }
 
 // hrvTrendLabel computes a linear regression slope over HRV values and returns a label.
 func hrvTrendLabel(vals []float64) string {
 	n := len(vals)
 	if n < 3 {
-		return "Insufficient data"
+		return Locale.T("Insufficient data")
 	}
 
 	// Least-squares slope: slope = (n*Σ(xy) - Σx*Σy) / (n*Σx² - (Σx)²)
@@ -339,43 +780,194 @@ func hrvTrendLabel(vals []float64) string {
 	fn := float64(n)
 	denom := fn*sumX2 - sumX*sumX
 	if denom == 0 {
-		return "Stable"
+		return Locale.T("Stable")
 	}
 	slope := (fn*sumXY - sumX*sumY) / denom
 
 	// Normalize by mean HRV to get percentage change per day.
 	meanHRV := sumY / fn
 	if meanHRV == 0 {
-		return "Stable"
+		return Locale.T("Stable")
 	}
 	normalizedSlope := slope / meanHRV * 100
 
 	switch {
 	case normalizedSlope > 0.5:
-		return fmt.Sprintf("Improving (+%.1f%%/day)", math.Abs(normalizedSlope))
+		return Locale.T("Improving (+%s%%/day)", Locale.Number(math.Abs(normalizedSlope), 1))
 	case normalizedSlope < -0.5:
-		return fmt.Sprintf("Declining (%.1f%%/day)", normalizedSlope)
+		return Locale.T("Declining (%s%%/day)", Locale.Number(normalizedSlope, 1))
 	default:
-		return "Stable"
+		return Locale.T("Stable")
+	}
+}
+
+// SportStat totals the workouts of one sport over a period.
+type SportStat struct {
+	Name     string
+	Sessions int
+	Millis   int64
+	Strain   float64
+	Climb    float64 // altitude gained, in meters
+	Meters   float64 // distance covered
+}
+
+// SportBreakdown totals the workouts in days by sport, most sessions first,
+// then by total time.
+func SportBreakdown(days []fetch.DayData) []SportStat {
+	byName := map[string]*SportStat{}
+	var out []SportStat
+	for _, d := range days {
+		for _, w := range d.Workouts {
+			name := WorkoutSport(w)
+			st := byName[name]
+			if st == nil {
+				st = &SportStat{Name: name}
+				byName[name] = st
+			}
+			st.Sessions++
+			st.Strain += w.Score.Strain
+			st.Climb += w.Score.AltitudeGainMeter
+			st.Meters += w.Score.DistanceMeter
+			start, err1 := time.Parse(time.RFC3339, w.Start)
+			end, err2 := time.Parse(time.RFC3339, w.End)
+			if err1 == nil && err2 == nil && end.After(start) {
+				st.Millis += end.Sub(start).Milliseconds()
+			}
+		}
 	}
+	for _, st := range byName {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Sessions != out[j].Sessions {
+			return out[i].Sessions > out[j].Sessions
+		}
+		if out[i].Millis != out[j].Millis {
+			return out[i].Millis > out[j].Millis
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// Mileage is the distance covered in one sport in each of four weeks.
+type Mileage struct {
+	Name  string
+	Weeks [4]float64 // meters, oldest first; the last is the current week
+	Avg   float64    // mean of Weeks
+}
+
+// BuildMileage returns the distance per sport over the week of days and
+// the three weeks before it, the 21 days of history. Sports without
+// distance in any of the weeks are left out; the rest are ordered by the
+// current week's distance, then by the four-week average.
+func BuildMileage(days, history []fetch.DayData) []Mileage {
+	weeks := [4][]fetch.DayData{3: days}
+	for i := 2; i >= 0 && len(history) > 0; i-- {
+		n := min(7, len(history))
+		weeks[i] = history[len(history)-n:]
+		history = history[:len(history)-n]
+	}
+	byName := map[string]*Mileage{}
+	var out []Mileage
+	for i, week := range weeks {
+		for _, st := range SportBreakdown(week) {
+			if st.Meters <= 0 {
+				continue
+			}
+			m := byName[st.Name]
+			if m == nil {
+				m = &Mileage{Name: st.Name}
+				byName[st.Name] = m
+			}
+			m.Weeks[i] = st.Meters
+		}
+	}
+	for _, m := range byName {
+		m.Avg = (m.Weeks[0] + m.Weeks[1] + m.Weeks[2] + m.Weeks[3]) / 4
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Weeks[3] != out[j].Weeks[3] {
+			return out[i].Weeks[3] > out[j].Weeks[3]
+		}
+		if out[i].Avg != out[j].Avg {
+			return out[i].Avg > out[j].Avg
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
 }
 
 // WeekStats aggregates weekly data for the weekly template.
 type WeekStats struct {
-	Days          []fetch.DayData
-	WeekStart     string
-	WeekEnd       string
-	AvgRecovery   float64
-	AvgHRV        float64
-	AvgRHR        float64
-	AvgStrain     float64
-	AvgSleepMillis int64
-	GreenDays     int
-	YellowDays    int
-	RedDays       int
-	TotalWorkouts int
-	BestDay       *fetch.DayData
-	WorstDay      *fetch.DayData
+	Days           []fetch.DayData
+	WeekStart      string
+	WeekEnd        string
+	AvgRecovery    float64
+	AvgHRV         float64
+	AvgRHR         float64
+	AvgStrain      float64
+	AvgSleepMillis int64 // in bed or asleep, as SleepTime says
+	GreenDays      int
+	YellowDays     int
+	RedDays        int
+	TotalWorkouts  int
+	Naps           int
+	NapMillis      int64             // total in-bed time of Naps
+	Stages         *analytics.Stages // mean stage percentages of the main sleeps; nil without any
+	SleepNeed      *fetch.SleepNeed  // mean need and fulfillment of the main sleeps; nil without any
+
+	// OverreachedDays, BalancedDays and UndertrainedDays count the days by
+	// strain against recovery; see analytics.Balance.
+	OverreachedDays  int
+	BalancedDays     int
+	UndertrainedDays int
+
+	// StrainTargets compare each scored day's strain with the target its
+	// recovery suggested; BelowTargetDays, OnTargetDays and AboveTargetDays
+	// count them by result.
+	StrainTargets   []TargetDay
+	BelowTargetDays int
+	OnTargetDays    int
+	AboveTargetDays int
+
+	Sports       []SportStat
+	Climb        float64             // altitude gained across all workouts, in meters
+	Mileage      []Mileage           // distance per sport over four weeks; nil unless built
+	Streaks      *fetch.Streaks      // as of the week's last day; nil unless enabled
+	SleepDebt    *fetch.SleepDebt    // nil when no night is scored
+	Schedule     *analytics.Schedule // bedtimes and wake times; nil without sleeps
+	TrainingLoad *fetch.TrainingLoad // as of the week's last day; nil unless enabled
+	Shifts       []fetch.Shift       // time zone changes during the week
+	BestDay      *fetch.DayData
+	WorstDay     *fetch.DayData
+
+	// RecoveryDays, StrainDays and SleepDays count the days that contributed
+	// to each average, out of len(Days).
+	RecoveryDays int
+	StrainDays   int
+	SleepDays    int
+
+	// CalibratingDays counts days scored while WHOOP was still calibrating.
+	// They are left out of the recovery figures and RecoveryDays.
+	CalibratingDays int
+
+	// AvgInBedMillis and AvgAsleepMillis average the main sleeps' time in
+	// bed and time asleep, whichever AvgSleepMillis shows.
+	AvgInBedMillis  int64
+	AvgAsleepMillis int64
+
+	// Missing lists the dates (YYYY-MM-DD) that could not be fetched and
+	// MissingZeroed reports whether they were counted as zero in averages.
+	Missing       []string
+	MissingZeroed bool
+
+	// Previous is the week before, which the weekly note compares each
+	// aggregate with. It is nil when there is nothing to compare.
+	Previous *WeekStats
+
+	sleepCount int
 }
 
 // BuildWeekStats aggregates a slice of DayData into WeekStats for templates.
@@ -388,19 +980,23 @@ func BuildWeekStats(days []fetch.DayData) WeekStats {
 	ws.WeekEnd = days[len(days)-1].Date.Format("2006-01-02")
 
 	var totalRec, totalHRV, totalRHR, totalStrain float64
-	var totalSleepMs int64
+	var totalSleepMs, totalAsleepMs int64
 	var recCount, sleepCount, strainCount int
 	var bestScore, worstScore float64
 	bestScore = -1
 	worstScore = 101
 
 	for i, d := range days {
+		sleptBefore := sleepCount
 		ws.TotalWorkouts += len(d.Workouts)
-		if d.Recovery != nil && d.Recovery.ScoreState == "SCORED" {
-			s := d.Recovery.Score.RecoveryScore
+		if analytics.Calibrating(d) {
+			ws.CalibratingDays++
+		}
+		if r := analytics.ScoredRecovery(d); r != nil {
+			s := r.Score.RecoveryScore
 			totalRec += s
-			totalHRV += d.Recovery.Score.HrvRmssdMilli
-			totalRHR += d.Recovery.Score.RestingHeartRate
+			totalHRV += r.Score.HrvRmssdMilli
+			totalRHR += r.Score.RestingHeartRate
 			recCount++
 
 			switch RecoveryColor(s) {
@@ -428,13 +1024,27 @@ func BuildWeekStats(days []fetch.DayData) WeekStats {
 			totalStrain += d.Cycle.Score.Strain
 			strainCount++
 		}
+		switch analytics.Balance(d) {
+		case analytics.Overreached:
+			ws.OverreachedDays++
+		case analytics.Balanced:
+			ws.BalancedDays++
+		case analytics.Undertrained:
+			ws.UndertrainedDays++
+		}
 
 		for _, sl := range d.Sleeps {
 			if !sl.Nap && sl.ScoreState == "SCORED" {
 				totalSleepMs += sl.Score.StageSummary.TotalInBedTimeMilli
+				totalAsleepMs += analytics.AsleepMillis(sl)
 				sleepCount++
 			}
 		}
+		ws.Naps += len(Naps(d.Sleeps))
+		ws.NapMillis += NapMillis(d.Sleeps)
+		if sleepCount > sleptBefore {
+			ws.SleepDays++
+		}
 	}
 
 	ws.AvgRecovery = avg(totalRec, recCount)
@@ -442,12 +1052,83 @@ func BuildWeekStats(days []fetch.DayData) WeekStats {
 	ws.AvgRHR = avg(totalRHR, recCount)
 	ws.AvgStrain = avg(totalStrain, strainCount)
 	if sleepCount > 0 {
-		ws.AvgSleepMillis = totalSleepMs / int64(sleepCount)
+		ws.AvgInBedMillis = totalSleepMs / int64(sleepCount)
+		ws.AvgAsleepMillis = totalAsleepMs / int64(sleepCount)
+	}
+	ws.AvgSleepMillis = chooseSleep(ws.AvgInBedMillis, ws.AvgAsleepMillis)
+	ws.Sports = SportBreakdown(days)
+	for _, st := range ws.Sports {
+		ws.Climb += st.Climb
+	}
+	if sd, ok := analytics.SleepDebt(days); ok {
+		ws.SleepDebt = &sd
+	}
+	if sc, ok := analytics.SleepSchedule(days); ok {
+		ws.Schedule = &sc
+	}
+	if st, ok := analytics.AvgStages(days); ok {
+		ws.Stages = &st
 	}
+	if n, ok := analytics.AvgSleepNeed(days); ok {
+		ws.SleepNeed = &n
+	}
+	ws.StrainTargets = StrainTargetDays(days)
+	for _, td := range ws.StrainTargets {
+		switch td.Result {
+		case -1:
+			ws.BelowTargetDays++
+		case 0:
+			ws.OnTargetDays++
+		case 1:
+			ws.AboveTargetDays++
+		}
+	}
+	ws.Shifts = analytics.TimezoneShifts(days)
+	ws.RecoveryDays = recCount
+	ws.StrainDays = strainCount
+	ws.sleepCount = sleepCount
 
 	return ws
 }
 
+// BuildWeekComparison aggregates days and, when prev has any recovery or
+// cycle, the week before into ws.Previous. A time zone change from prev's
+// last day to the first of days counts among ws.Shifts.
+func BuildWeekComparison(days, prev []fetch.DayData) WeekStats {
+	ws := BuildWeekStats(days)
+	if n := len(prev); n > 0 && len(days) > 0 {
+		if s, ok := analytics.TimezoneShift(prev[n-1], days[0]); ok {
+			ws.Shifts = append([]fetch.Shift{s}, ws.Shifts...)
+		}
+	}
+	for _, d := range prev {
+		if d.Recovery != nil || d.Cycle != nil {
+			ps := BuildWeekStats(prev)
+			ws.Previous = &ps
+			break
+		}
+	}
+	return ws
+}
+
+// ZeroMissing recomputes the averages as if every day in ws.Missing had
+// scored zero, instead of leaving those days out.
+func (ws *WeekStats) ZeroMissing() {
+	m := len(ws.Missing)
+	if m == 0 {
+		return
+	}
+	scale := func(n int) float64 { return float64(n) / float64(n+m) }
+	ws.AvgRecovery *= scale(ws.RecoveryDays)
+	ws.AvgHRV *= scale(ws.RecoveryDays)
+	ws.AvgRHR *= scale(ws.RecoveryDays)
+	ws.AvgStrain *= scale(ws.StrainDays)
+	ws.AvgSleepMillis = int64(float64(ws.AvgSleepMillis) * scale(ws.sleepCount))
+	ws.AvgInBedMillis = int64(float64(ws.AvgInBedMillis) * scale(ws.sleepCount))
+	ws.AvgAsleepMillis = int64(float64(ws.AvgAsleepMillis) * scale(ws.sleepCount))
+	ws.MissingZeroed = true
+}
+
 // weeklyTemplateData is passed to the weekly template.
 type weeklyTemplateData struct {
 	Stats WeekStats
@@ -456,6 +1137,9 @@ type weeklyTemplateData struct {
 // RenderWeeklyFromStats renders a weekly note from pre-aggregated WeekStats.
 func RenderWeeklyFromStats(stats WeekStats, tmplPath string) (string, error) {
 	funcMap := FuncMap()
+	if len(stats.Days) > 0 {
+		funcMap = funcsFrom(NoteRel("weekly", ISOWeekStr(stats.Days[0].Date)))
+	}
 	funcMap["join"] = strings.Join
 	tmpl, err := template.New("weekly.md.tmpl").Funcs(funcMap).ParseFiles(tmplPath)
 	if err != nil {
@@ -467,3 +1151,240 @@ func RenderWeeklyFromStats(stats WeekStats, tmplPath string) (string, error) {
 	}
 	return buf.String(), nil
 }
+
+// MonthlyData is passed to the monthly template.
+type MonthlyData struct {
+	Month string // "YYYY-MM"
+	Start time.Time
+	Stats WeekStats
+	// Changes are the template, config, and data changes recorded during
+	// the month; TrackingSince is when recording began.
+	Changes       []changelog.Entry
+	TrackingSince time.Time
+}
+
+// RenderMonthly renders a monthly note.
+func RenderMonthly(data MonthlyData, tmplPath string) (string, error) {
+	funcMap := funcsFrom(NoteRel("monthly", data.Month))
+	funcMap["join"] = strings.Join
+	tmpl, err := template.New("monthly.md.tmpl").Funcs(funcMap).ParseFiles(tmplPath)
+	if err != nil {
+		return "", fmt.Errorf("parse monthly template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "monthly.md.tmpl", data); err != nil {
+		return "", fmt.Errorf("render monthly template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// QuarterlyData is passed to the quarterly template.
+type QuarterlyData struct {
+	Quarter string // "YYYY-Qn"
+	Prev    string // the quarters before and after, "YYYY-Qn"
+	Next    string
+	Start   time.Time
+	Stats   WeekStats
+	Months  []MonthStats
+	// Changes and TrackingSince are as in MonthlyData, for the quarter.
+	Changes       []changelog.Entry
+	TrackingSince time.Time
+}
+
+// MonthStats aggregates one month of a longer period.
+type MonthStats struct {
+	Month string // "YYYY-MM"
+	Start time.Time
+	Stats WeekStats
+}
+
+// RenderQuarterly renders a quarterly note.
+func RenderQuarterly(data QuarterlyData, tmplPath string) (string, error) {
+	funcMap := funcsFrom(NoteRel("quarterly", data.Quarter))
+	funcMap["join"] = strings.Join
+	tmpl, err := template.New("quarterly.md.tmpl").Funcs(funcMap).ParseFiles(tmplPath)
+	if err != nil {
+		return "", fmt.Errorf("parse quarterly template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "quarterly.md.tmpl", data); err != nil {
+		return "", fmt.Errorf("render quarterly template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// CompareSide is one aggregated period in a comparison.
+type CompareSide struct {
+	Label string
+	Start string
+	End   string
+	Stats WeekStats
+}
+
+// CompareSport is one sport's totals on each side of a comparison; a
+// side without the sport has a zero SportStat.
+type CompareSport struct {
+	Name string
+	A    SportStat
+	B    SportStat
+}
+
+// CompareSports pairs up the sports of two periods, most sessions across
+// both first, then by name.
+func CompareSports(a, b []SportStat) []CompareSport {
+	byName := map[string]*CompareSport{}
+	var out []*CompareSport
+	get := func(name string) *CompareSport {
+		if cs, ok := byName[name]; ok {
+			return cs
+		}
+		cs := &CompareSport{Name: name}
+		byName[name] = cs
+		out = append(out, cs)
+		return cs
+	}
+	for _, s := range a {
+		get(s.Name).A = s
+	}
+	for _, s := range b {
+		get(s.Name).B = s
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		ni, nj := out[i].A.Sessions+out[i].B.Sessions, out[j].A.Sessions+out[j].B.Sessions
+		if ni != nj {
+			return ni > nj
+		}
+		return out[i].Name < out[j].Name
+	})
+	sports := make([]CompareSport, len(out))
+	for i, cs := range out {
+		sports[i] = *cs
+	}
+	return sports
+}
+
+// compareTemplateData is passed to the compare template.
+type compareTemplateData struct {
+	GeneratedDate string
+	A             CompareSide
+	B             CompareSide
+	Sports        []CompareSport
+}
+
+// RenderCompare renders a side-by-side comparison note of two periods.
+// Deltas in the template are expressed as B relative to A.
+func RenderCompare(a, b CompareSide, tmplPath string) (string, error) {
+	tmpl, err := template.New("compare.md.tmpl").Funcs(FuncMap()).ParseFiles(tmplPath)
+	if err != nil {
+		return "", fmt.Errorf("parse compare template: %w", err)
+	}
+	data := compareTemplateData{
+		GeneratedDate: Now().Format("2006-01-02"),
+		A:             a,
+		B:             b,
+		Sports:        CompareSports(a.Stats.Sports, b.Stats.Sports),
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "compare.md.tmpl", data); err != nil {
+		return "", fmt.Errorf("render compare template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// CorrelationReport is passed to the correlate template.
+type CorrelationReport struct {
+	GeneratedDate string
+	Start         string
+	End           string
+	Days          int
+	Correlations  []analytics.Correlation // strongest first
+}
+
+// RenderCorrelations renders the correlation report note.
+func RenderCorrelations(rep CorrelationReport, tmplPath string) (string, error) {
+	tmpl, err := template.New("correlate.md.tmpl").Funcs(FuncMap()).ParseFiles(tmplPath)
+	if err != nil {
+		return "", fmt.Errorf("parse correlate template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "correlate.md.tmpl", rep); err != nil {
+		return "", fmt.Errorf("render correlate template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// RecordsData is passed to the records template.
+type RecordsData struct {
+	GeneratedDate string
+	Since         string // first day of the history
+	Through       string // last day of the history
+	Records       analytics.Records
+}
+
+// RenderRecords renders the personal records note.
+func RenderRecords(data RecordsData, tmplPath string) (string, error) {
+	tmpl, err := template.New("records.md.tmpl").Funcs(funcsFrom("Records")).ParseFiles(tmplPath)
+	if err != nil {
+		return "", fmt.Errorf("parse records template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "records.md.tmpl", data); err != nil {
+		return "", fmt.Errorf("render records template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// IndexWeek is a weekly note listed in a year index.
+type IndexWeek struct {
+	Key         string    // "2026-W07"
+	Start, Last time.Time // Monday and Sunday
+}
+
+// YearIndexData is passed to the year index template.
+type YearIndexData struct {
+	GeneratedDate string
+	Year          string
+	Home          string      // wikilink target of WHOOP.md
+	Prev, Next    string      // neighbouring years with notes, or ""
+	Months        []time.Time // first days of months with a monthly note
+	Weeks         []IndexWeek // oldest first
+}
+
+// IndexYear is a year listed in WHOOP.md.
+type IndexYear struct {
+	Year        string
+	Days, Weeks int // daily and weekly notes
+}
+
+// HomeData is passed to the WHOOP.md template.
+type HomeData struct {
+	GeneratedDate string
+	Persona       string      // wikilink target of the persona note, or ""
+	Records       string      // wikilink target of Records.md, or ""
+	Years         []IndexYear // newest first
+}
+
+// RenderYearIndex renders the index note of one year.
+func RenderYearIndex(data YearIndexData, tmplPath string) (string, error) {
+	return renderFile(tmplPath, "index", NoteRel("index", data.Year), data)
+}
+
+// RenderHome renders WHOOP.md, the notes' map of content.
+func RenderHome(data HomeData, tmplPath string) (string, error) {
+	return renderFile(tmplPath, "home", "WHOOP", data)
+}
+
+// renderFile executes the template file at tmplPath for the note at rel;
+// what names it in errors.
+func renderFile(tmplPath, what, rel string, data any) (string, error) {
+	name := filepath.Base(tmplPath)
+	tmpl, err := template.New(name).Funcs(funcsFrom(rel)).ParseFiles(tmplPath)
+	if err != nil {
+		return "", fmt.Errorf("parse %s template: %w", what, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("render %s template: %w", what, err)
+	}
+	return buf.String(), nil
+}
@@ -3,12 +3,19 @@ package render
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"text/template"
 	"time"
 
+	"github.com/benstraw/whoop-garden/internal/analytics"
+	"github.com/benstraw/whoop-garden/internal/config"
 	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/links"
+	"github.com/benstraw/whoop-garden/internal/locale"
 	"github.com/benstraw/whoop-garden/internal/models"
+	"github.com/benstraw/whoop-garden/internal/strava"
 )
 
 // --- MillisToMinutes ---
@@ -82,6 +89,118 @@ func TestStrainCategory(t *testing.T) {
 	}
 }
 
+func TestStrainCategory_CustomBands(t *testing.T) {
+	defer func(b []config.StrainBand) { StrainBands = b }(StrainBands)
+	StrainBands = []config.StrainBand{{Name: "Light", Min: 0}, {Name: "Moderate", Min: 10}, {Name: "High", Min: 14}, {Name: "Overreaching", Min: 18}}
+	for strain, want := range map[float64]string{-1: "Light", 9.9: "Light", 10: "Moderate", 15: "High", 20: "Overreaching"} {
+		if got := StrainCategory(strain); got != want {
+			t.Errorf("StrainCategory(%.1f) = %q, want %q", strain, got, want)
+		}
+	}
+}
+
+// --- StrainTarget ---
+
+func TestTargetStrain(t *testing.T) {
+	for rec, want := range map[float64]StrainTarget{0: {0, 0, 10}, 33.9: {0, 0, 10}, 34: {34, 10, 14}, 90: {67, 14, 18}} {
+		if got := TargetStrain(rec); got == nil || *got != want {
+			t.Errorf("TargetStrain(%v) = %v, want %v", rec, got, want)
+		}
+	}
+	target := StrainTarget{Low: 8, High: 12}
+	for strain, want := range map[float64]int{7.9: -1, 8: 0, 12: 0, 14.3: 1} {
+		if got := target.Compare(strain); got != want {
+			t.Errorf("Compare(%v) = %d, want %d", strain, got, want)
+		}
+	}
+	defer func(ts []StrainTarget) { StrainTargets = ts }(StrainTargets)
+	StrainTargets = nil
+	if got := TargetStrain(50); got != nil {
+		t.Errorf("TargetStrain without targets = %v, want nil", got)
+	}
+}
+
+func TestBuildWeekStats_StrainTargets(t *testing.T) {
+	day := func(i int, recovery, strain float64) fetch.DayData {
+		d := fetch.DayData{
+			Date:     time.Date(2026, 2, 9+i, 0, 0, 0, 0, time.UTC),
+			Recovery: &models.Recovery{ScoreState: "SCORED"},
+			Cycle:    &models.Cycle{ScoreState: "SCORED"},
+		}
+		d.Recovery.Score.RecoveryScore = recovery
+		d.Cycle.Score.Strain = strain
+		return d
+	}
+	days := []fetch.DayData{day(0, 80, 15), day(1, 20, 14.3), day(2, 50, 8), {Date: time.Date(2026, 2, 12, 0, 0, 0, 0, time.UTC)}}
+	ws := BuildWeekStats(days)
+	if len(ws.StrainTargets) != 3 {
+		t.Fatalf("got %d target days, want 3", len(ws.StrainTargets))
+	}
+	if got := ws.StrainTargets[1]; got.Target.High != 10 || got.Result != 1 {
+		t.Errorf("day 2 = %+v, want above a 0–10 target", got)
+	}
+	if ws.OnTargetDays != 1 || ws.AboveTargetDays != 1 || ws.BelowTargetDays != 1 {
+		t.Errorf("on/above/below = %d/%d/%d, want 1/1/1", ws.OnTargetDays, ws.AboveTargetDays, ws.BelowTargetDays)
+	}
+}
+
+// --- Narrative ---
+
+func TestNarrative(t *testing.T) {
+	day := func(i int, recovery, strain float64) fetch.DayData {
+		d := fetch.DayData{
+			Date:     time.Date(2026, 2, 9+i, 0, 0, 0, 0, time.UTC),
+			Recovery: &models.Recovery{ScoreState: "SCORED"},
+			Cycle:    &models.Cycle{ScoreState: "SCORED", End: "2026-02-10T11:00:00.000Z"},
+		}
+		d.Recovery.Score.RecoveryScore = recovery
+		d.Cycle.Score.Strain = strain
+		return d
+	}
+
+	prev := day(0, 70, 16.2)
+	d := Day{DayData: day(1, 45, 15), Previous: &prev}
+	d.SleepDebt = &fetch.SleepDebt{Debt: 7_800_000}
+	got, err := Narrative(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Recovery dropped 25 points to 45% after a strain of 16.2 yesterday. Sleep debt is 2h 10m. Strain of 15.0 overshot the 10–14 target."
+	if got != want {
+		t.Errorf("day:\n got %q\nwant %q", got, want)
+	}
+
+	ws := BuildWeekStats([]fetch.DayData{day(0, 80, 15), day(1, 60, 16), day(2, 40, 15), day(3, 30, 14)})
+	got, _ = Narrative(&ws)
+	want = "Average recovery was 52%, with 1 green and 1 red days. Recovery dropped 3 days running through February 12, 2026 while strain stayed high. Strain overshot its target on 3 of 4 days."
+	if got != want {
+		t.Errorf("week:\n got %q\nwant %q", got, want)
+	}
+
+	defer func(l locale.Locale) { Locale = l }(Locale)
+	Locale = locale.Get("de")
+	if got, _ := Narrative(d); !strings.HasPrefix(got, "Die Erholung fiel nach einer Belastung von 16,2 gestern um 25 Punkte auf 45 %.") {
+		t.Errorf("German day narrative = %q", got)
+	}
+	if _, err := Narrative(42); err == nil {
+		t.Error("expected an error for an unsupported value")
+	}
+}
+
+func TestCompareSports(t *testing.T) {
+	a := []SportStat{{Name: "Running", Sessions: 3}, {Name: "Yoga", Sessions: 1}}
+	b := []SportStat{{Name: "Cycling", Sessions: 4}, {Name: "Running", Sessions: 2}}
+	got := CompareSports(a, b)
+	want := []CompareSport{
+		{Name: "Running", A: a[0], B: b[1]},
+		{Name: "Cycling", B: b[0]},
+		{Name: "Yoga", A: a[1]},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CompareSports = %+v, want %+v", got, want)
+	}
+}
+
 // --- SportName ---
 
 func TestSportName(t *testing.T) {
@@ -96,6 +215,250 @@ func TestSportName(t *testing.T) {
 	}
 }
 
+func TestWorkoutSport(t *testing.T) {
+	defer func(m map[int]string) { SportNames = m }(SportNames)
+	SportNames = map[int]string{233: "Padel", 44: "Hot Yoga"}
+	tests := []struct {
+		w    models.Workout
+		want string
+	}{
+		{models.Workout{SportID: 0, SportName: "Trail Running"}, "Trail Running"},
+		{models.Workout{SportID: 233}, "Padel"},
+		{models.Workout{SportID: 44}, "Hot Yoga"},
+		{models.Workout{SportID: 1}, "Cycling"},
+		{models.Workout{SportID: 9999}, "Sport(9999)"},
+	}
+	for _, tt := range tests {
+		if got := WorkoutSport(tt.w); got != tt.want {
+			t.Errorf("WorkoutSport(%d, %q) = %q, want %q", tt.w.SportID, tt.w.SportName, got, tt.want)
+		}
+	}
+}
+
+func TestSportInfo(t *testing.T) {
+	defer func(m map[string]SportMeta) { Sports = m }(Sports)
+	Sports = map[string]SportMeta{"running": {Tag: "training/run"}, "Padel": {Emoji: "🎾", Category: "sport"}}
+
+	m, err := SportInfo(models.Workout{SportID: 0, SportName: "Running"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m != (SportMeta{Name: "Running", Emoji: "🏃", Tag: "training/run", Category: "cardio"}) {
+		t.Errorf("Running: got %+v", m)
+	}
+	if m, _ := SportInfo("Padel"); m.Emoji != "🎾" || m.Category != "sport" || m.Tag != "" {
+		t.Errorf("Padel: got %+v", m)
+	}
+	if m, _ := SportInfo("Underwater Hockey"); m != (SportMeta{Name: "Underwater Hockey"}) {
+		t.Errorf("unknown sport: got %+v", m)
+	}
+	if _, err := SportInfo(42); err == nil {
+		t.Error("expected error for an int")
+	}
+
+	ws := []models.Workout{{SportName: "Running"}, {SportName: "Cycling"}, {SportName: "Running"}}
+	if got := SportTags(ws); !reflect.DeepEqual(got, []string{"training/run"}) {
+		t.Errorf("SportTags = %v", got)
+	}
+}
+
+func TestSportBreakdown_Climb(t *testing.T) {
+	days := []fetch.DayData{
+		{Workouts: []models.Workout{{SportName: "Hiking/Rucking", Score: models.WorkoutScore{AltitudeGainMeter: 412}}}},
+		{Workouts: []models.Workout{
+			{SportName: "Hiking/Rucking", Score: models.WorkoutScore{AltitudeGainMeter: 300}},
+			{SportName: "Cycling", Score: models.WorkoutScore{AltitudeGainMeter: 850.5}},
+		}},
+	}
+	got := map[string]float64{}
+	for _, st := range SportBreakdown(days) {
+		got[st.Name] = st.Climb
+	}
+	if got["Hiking/Rucking"] != 712 || got["Cycling"] != 850.5 {
+		t.Errorf("climb by sport = %v", got)
+	}
+	if ws := BuildWeekStats(days); ws.Climb != 1562.5 {
+		t.Errorf("week climb = %v, want 1562.5", ws.Climb)
+	}
+}
+
+func TestBuildMileage(t *testing.T) {
+	run := func(m float64) fetch.DayData {
+		return fetch.DayData{Workouts: []models.Workout{{SportName: "Running", Score: models.WorkoutScore{DistanceMeter: m}}}}
+	}
+	swim := fetch.DayData{Workouts: []models.Workout{{SportName: "Swimming", Score: models.WorkoutScore{DistanceMeter: 1500}}}}
+	lift := fetch.DayData{Workouts: []models.Workout{{SportName: "Weightlifting"}}}
+
+	var history []fetch.DayData
+	for i := 0; i < 21; i++ {
+		history = append(history, fetch.DayData{})
+	}
+	history[0] = run(10000)  // three weeks back
+	history[7] = swim        // two weeks back
+	history[20] = run(12000) // last week
+	days := []fetch.DayData{run(5000), run(8000), lift}
+
+	got := BuildMileage(days, history)
+	want := []Mileage{
+		{Name: "Running", Weeks: [4]float64{10000, 0, 12000, 13000}, Avg: 8750},
+		{Name: "Swimming", Weeks: [4]float64{0, 1500, 0, 0}, Avg: 375},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildMileage = %+v, want %+v", got, want)
+	}
+
+	if got := BuildMileage(days, nil); len(got) != 1 || got[0].Weeks != [4]float64{3: 13000} {
+		t.Errorf("without history: got %+v", got)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	defer func(u string) { Units = u }(Units)
+	tests := []struct {
+		units, sport string
+		meters       float64
+		want         string
+	}{
+		{"metric", "Running", 8046.7, "8.05 km"},
+		{"metric", "Swimming", 1500, "1500 m"},
+		{"imperial", "Running", 8046.7, "5.00 mi"},
+		{"imperial", "swimming", 1500, "1640 yd"},
+	}
+	for _, tt := range tests {
+		Units = tt.units
+		if got := Distance(tt.meters, tt.sport); got != tt.want {
+			t.Errorf("Distance(%v, %q) in %s = %q, want %q", tt.meters, tt.sport, tt.units, got, tt.want)
+		}
+	}
+}
+
+func TestElevation(t *testing.T) {
+	defer func(u string) { Units = u }(Units)
+	Units = "metric"
+	if got := Elevation(412.4); got != "412 m" {
+		t.Errorf("metric: got %q", got)
+	}
+	Units = "imperial"
+	if got := Elevation(412.4); got != "1353 ft" {
+		t.Errorf("imperial: got %q", got)
+	}
+}
+
+// --- Delta ---
+
+func TestDelta(t *testing.T) {
+	tests := []struct {
+		a, b   float64
+		format string
+		want   string
+	}{
+		{60, 64, "%.0f", "+4"},
+		{64, 60, "%.0f", "-4"},
+		{50, 50, "%.1f", "±0.0"},
+		{50, 50.01, "%.1f", "±0.0"},
+		{10.4, 12.1, "%.1f", "+1.7"},
+	}
+	for _, tc := range tests {
+		if got := Delta(tc.a, tc.b, tc.format); got != tc.want {
+			t.Errorf("Delta(%v, %v, %q) = %q, want %q", tc.a, tc.b, tc.format, got, tc.want)
+		}
+	}
+}
+
+func TestTrend(t *testing.T) {
+	cases := []struct {
+		a, b   float64
+		format string
+		want   string
+	}{
+		{65, 72, "%.0f", "↑7"},
+		{55, 54, "%.0f", "↓1"},
+		{42.3, 40.1, "%.1f", "↓2.2"},
+		{60.2, 60.4, "%.0f", "→"},
+	}
+	for _, tc := range cases {
+		if got := Trend(tc.a, tc.b, tc.format); got != tc.want {
+			t.Errorf("Trend(%v, %v, %q) = %q, want %q", tc.a, tc.b, tc.format, got, tc.want)
+		}
+	}
+}
+
+func TestSigma(t *testing.T) {
+	for z, want := range map[float64]string{
+		-1.8: "-1.8σ below baseline",
+		0.42: "+0.4σ above baseline",
+		0.02: "at baseline",
+	} {
+		if got := Sigma(z); got != want {
+			t.Errorf("Sigma(%v) = %q, want %q", z, got, want)
+		}
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	for n, want := range map[float64]string{1: "1st", 2.4: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 100: "100th"} {
+		if got := Ordinal(n); got != want {
+			t.Errorf("Ordinal(%v) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestClock(t *testing.T) {
+	for h, want := range map[float64]string{-0.5: "23:30", 6.25: "06:15", 0: "00:00", -1.0 / 120: "23:59", 23.999: "00:00"} {
+		if got := Clock(h); got != want {
+			t.Errorf("Clock(%v) = %q, want %q", h, got, want)
+		}
+	}
+}
+
+func TestTemplateHelpers(t *testing.T) {
+	w := models.Workout{
+		Start:          "2026-02-10T17:00:00.000Z",
+		End:            "2026-02-10T18:05:30.000Z",
+		TimezoneOffset: "-05:00",
+		Score:          models.WorkoutScore{Kilojoule: 2092, DistanceMeter: 8046.72, AverageHeartRate: 141},
+	}
+	for tmpl, want := range map[string]string{
+		`{{round 3.14159 2}}`:                                                                   "3.14",
+		`{{pct 3 4}} {{pct 1 0}}`:                                                               "75 0",
+		`{{divide .Score.AverageHeartRate 3}}`:                                                  "47",
+		`{{printf "%.0f" (kjToKcal .Score.Kilojoule)}}`:                                         "500",
+		`{{printf "%.1f" (metersToMiles .Score.DistanceMeter)}}`:                                "5.0",
+		`{{formatTime "15:04" .Start}}`:                                                         "17:00",
+		`{{formatTime "15:04" (localTime .Start .TimezoneOffset)}}`:                             "12:00",
+		`{{durationBetween .Start .End}}`:                                                       "1h 5m",
+		`{{.SportName | default "—"}} {{.Score.Strain | default 0.5}} {{.ID | default "none"}}`: "— 0.5 none",
+		`{{sum .Nums}} {{avg .Nums}} {{last .Nums}} {{avg .None}} {{last .None}}`:               "9 3 5 0 <no value>",
+	} {
+		data := struct {
+			models.Workout
+			Nums []int
+			None []float64
+		}{w, []int{1, 3, 5}, nil}
+		tt := template.Must(template.New("t").Funcs(FuncMap()).Parse(tmpl))
+		var b strings.Builder
+		if err := tt.Execute(&b, data); err != nil {
+			t.Errorf("%s: %v", tmpl, err)
+			continue
+		}
+		if b.String() != want {
+			t.Errorf("%s = %q, want %q", tmpl, b.String(), want)
+		}
+	}
+}
+
+func TestDeltaMillis(t *testing.T) {
+	if got := DeltaMillis(25_200_000, 26_700_000); got != "+25m" {
+		t.Errorf("got %q, want +25m", got)
+	}
+	if got := DeltaMillis(28_800_000, 24_900_000); got != "-1h 5m" {
+		t.Errorf("got %q, want -1h 5m", got)
+	}
+	if got := DeltaMillis(1000, 2000); got != "±0m" {
+		t.Errorf("got %q, want ±0m", got)
+	}
+}
+
 // --- Date navigation helpers ---
 
 func TestPrevNextDay(t *testing.T) {
@@ -145,6 +508,89 @@ func TestYearHelpers(t *testing.T) {
 	}
 }
 
+func TestRenderDaily_LinkStyle(t *testing.T) {
+	tmplPath := filepath.Join("..", "..", "templates", "daily.md.tmpl")
+	day := Day{DayData: fetch.DayData{Date: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}}
+
+	got, err := RenderDaily(day, tmplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[[Health/WHOOP/2025/daily-2025-12-31|← 2025-12-31]]"; !strings.Contains(got, want) {
+		t.Errorf("default links: missing %q", want)
+	}
+
+	defer func(l func() links.Linker) { Links = l }(Links)
+	Links = func() links.Linker { return links.Linker{Folder: "Health/WHOOP", Style: links.LinkRelative} }
+	if got, err = RenderDaily(day, tmplPath); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"[[../2025/daily-2025-12-31|← 2025-12-31]]", "[[weekly-2026-W01|Week 2026-W01]]"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("relative links: missing %q", want)
+		}
+	}
+
+	Links = func() links.Linker {
+		return links.Linker{Layout: links.LayoutFlat, Folder: "Fitness", Style: links.LinkName}
+	}
+	if got, err = RenderDaily(day, tmplPath); err != nil {
+		t.Fatal(err)
+	}
+	if want := "[[daily-2026-01-02|2026-01-02 →]]"; !strings.Contains(got, want) {
+		t.Errorf("name links: missing %q", want)
+	}
+}
+
+func TestRenderDaily_Properties(t *testing.T) {
+	tmplPath := filepath.Join("..", "..", "templates", "daily.md.tmpl")
+	day := Day{DayData: fetch.DayData{
+		Date:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Recovery: makeRecovery(61),
+		Cycle:    makeCycle(12.34),
+		Sleeps:   []models.Sleep{makeSleep(27_000_000)},
+		Workouts: []models.Workout{{SportName: "Running"}, {SportName: "Cycling"}, {SportName: "Running"}},
+	}}
+
+	got, err := RenderDaily(day, tmplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "recovery: 61\nhrv: 50.0\nrhr: 55\nstrain: 12.3\nsleep_hours: 7.50\nworkouts: 3\nsports:\n  - \"Running\"\n  - \"Cycling\"\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("default properties: missing\n%s\nin\n%s", want, got)
+	}
+
+	defer func(p map[string]string) { Properties = p }(Properties)
+	Properties = map[string]string{"recovery": "whoop_recovery"}
+	if got, err = RenderDaily(day, tmplPath); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "whoop_recovery: 61\n") || strings.Contains(got, "strain: ") {
+		t.Errorf("configured properties: got\n%s", got)
+	}
+
+	// A day not yet scored has only its workout count.
+	Properties = nil
+	if got, err = RenderDaily(Day{DayData: fetch.DayData{Date: day.Date}}, tmplPath); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "recovery: ") || !strings.Contains(got, "workouts: 0\n") {
+		t.Errorf("unscored properties: got\n%s", got)
+	}
+}
+
+func TestPropertyValues(t *testing.T) {
+	for _, metric := range config.PropertyMetrics {
+		if propertyValues[metric] == nil {
+			t.Errorf("no value for property metric %q", metric)
+		}
+	}
+	if len(propertyValues) != len(config.PropertyMetrics) {
+		t.Errorf("%d property values for %d metrics", len(propertyValues), len(config.PropertyMetrics))
+	}
+}
+
 // --- PrimarySleep ---
 
 func TestPrimarySleep(t *testing.T) {
@@ -203,6 +649,73 @@ func TestNonNapSleeps_Empty(t *testing.T) {
 	}
 }
 
+// --- Naps ---
+
+func TestNaps(t *testing.T) {
+	nap := func(id string, ms int64) models.Sleep {
+		return models.Sleep{Nap: true, ID: id, Score: models.SleepScore{StageSummary: models.SleepStageSummary{TotalInBedTimeMilli: ms}}}
+	}
+	sleeps := []models.Sleep{
+		{Nap: false, ID: "a", Score: models.SleepScore{StageSummary: models.SleepStageSummary{TotalInBedTimeMilli: 25_200_000}}},
+		nap("nap1", 1_800_000),
+		nap("nap2", 2_400_000),
+	}
+	got := Naps(sleeps)
+	if len(got) != 2 || got[0].Sleep.ID != "nap1" || got[1].Index != 1 || got[1].Sleep.ID != "nap2" {
+		t.Errorf("Naps = %+v", got)
+	}
+	if got := NapMillis(sleeps); got != 4_200_000 {
+		t.Errorf("NapMillis = %d, want 4200000", got)
+	}
+
+	days := []fetch.DayData{{Sleeps: sleeps}, {Sleeps: []models.Sleep{nap("nap3", 600_000)}}}
+	if ws := BuildWeekStats(days); ws.Naps != 3 || ws.NapMillis != 4_800_000 {
+		t.Errorf("week: %d naps, %d ms", ws.Naps, ws.NapMillis)
+	}
+}
+
+func TestStageBar(t *testing.T) {
+	tests := []struct {
+		st   analytics.Stages
+		want string
+	}{
+		{analytics.Stages{Deep: 25, REM: 20, Light: 45, Awake: 10}, "█████▓▓▓▓▒▒▒▒▒▒▒▒▒░░"},
+		{analytics.Stages{Deep: 23, REM: 24, Light: 45, Awake: 8}, "█████▓▓▓▓▓▒▒▒▒▒▒▒▒▒░"},
+		{analytics.Stages{Light: 100}, "▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒"},
+		{analytics.Stages{}, ""},
+	}
+	for _, tt := range tests {
+		if got := StageBar(tt.st); got != tt.want {
+			t.Errorf("StageBar(%+v) = %q, want %q", tt.st, got, tt.want)
+		}
+	}
+}
+
+func TestSleepTime(t *testing.T) {
+	defer func(v string) { SleepTime = v }(SleepTime)
+	night := func(date time.Time, inBed, awake int64) fetch.DayData {
+		return fetch.DayData{Date: date, Sleeps: []models.Sleep{{ScoreState: "SCORED", Score: models.SleepScore{
+			StageSummary: models.SleepStageSummary{TotalInBedTimeMilli: inBed, TotalAwakeTimeMilli: awake},
+		}}}}
+	}
+	date := time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC)
+	days := []fetch.DayData{night(date, 8*3_600_000, 3_600_000), night(date.AddDate(0, 0, 1), 7*3_600_000, 0)}
+
+	for _, tt := range []struct {
+		sleepTime string
+		want      int64
+	}{{"in_bed", 7.5 * 3_600_000}, {"asleep", 7 * 3_600_000}} {
+		SleepTime = tt.sleepTime
+		ws := BuildWeekStats(days)
+		if ws.AvgSleepMillis != tt.want || ws.AvgInBedMillis != 7.5*3_600_000 || ws.AvgAsleepMillis != 7*3_600_000 {
+			t.Errorf("%s: week avg %d, in bed %d, asleep %d", tt.sleepTime, ws.AvgSleepMillis, ws.AvgInBedMillis, ws.AvgAsleepMillis)
+		}
+		if ps := BuildPersonaStats(days); ps.AvgSleepMillis != tt.want {
+			t.Errorf("%s: persona avg %d, want %d", tt.sleepTime, ps.AvgSleepMillis, tt.want)
+		}
+	}
+}
+
 // --- hrvTrendLabel ---
 
 func TestHRVTrendLabel(t *testing.T) {
@@ -326,6 +839,152 @@ func TestBuildWeekStats_BasicAggregation(t *testing.T) {
 	}
 }
 
+func TestSportBreakdown(t *testing.T) {
+	workout := func(name string, sportID int, start, end string, strain float64) models.Workout {
+		w := models.Workout{SportName: name, SportID: sportID, Start: start, End: end}
+		w.Score.Strain = strain
+		return w
+	}
+	days := []fetch.DayData{
+		{Workouts: []models.Workout{
+			workout("running", 0, "2026-02-09T07:00:00Z", "2026-02-09T07:45:00Z", 9.5),
+			workout("", 1, "2026-02-09T18:00:00Z", "2026-02-09T19:00:00Z", 8),
+		}},
+		{Workouts: []models.Workout{
+			workout("running", 0, "2026-02-10T07:00:00Z", "2026-02-10T07:30:00Z", 7.25),
+		}},
+	}
+	got := SportBreakdown(days)
+	want := []SportStat{
+		{Name: "running", Sessions: 2, Millis: 75 * 60_000, Strain: 16.75},
+		{Name: SportName(1), Sessions: 1, Millis: 60 * 60_000, Strain: 8},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SportBreakdown = %+v, want %+v", got, want)
+	}
+	if ws := BuildWeekStats(days); !reflect.DeepEqual(ws.Sports, want) {
+		t.Errorf("WeekStats.Sports = %+v, want %+v", ws.Sports, want)
+	}
+}
+
+func TestBuildWeekStats_SleepDebt(t *testing.T) {
+	hour := int64(3_600_000)
+	s := makeSleep(6 * hour)
+	s.Score.SleepNeeded.BaselineMillis = 8 * hour
+	days := []fetch.DayData{
+		{Date: time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC), Sleeps: []models.Sleep{s}},
+		{Date: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)},
+	}
+	ws := BuildWeekStats(days)
+	if ws.SleepDebt == nil {
+		t.Fatal("SleepDebt is nil")
+	}
+	if ws.SleepDebt.Debt != 2*hour || len(ws.SleepDebt.Nights) != 1 {
+		t.Errorf("SleepDebt = %+v", ws.SleepDebt)
+	}
+	if BuildWeekStats(days[1:]).SleepDebt != nil {
+		t.Error("SleepDebt should be nil without scored nights")
+	}
+}
+
+func TestBuildWeekStats_Schedule(t *testing.T) {
+	days := []fetch.DayData{{
+		Date:   time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC),
+		Sleeps: []models.Sleep{{Start: "2026-02-09T04:10:00Z", End: "2026-02-09T12:05:00Z", TimezoneOffset: "-05:00"}},
+	}}
+	ws := BuildWeekStats(days)
+	if ws.Schedule == nil || Clock(ws.Schedule.AvgBedtime) != "23:10" || Clock(ws.Schedule.AvgWake) != "07:05" {
+		t.Errorf("Schedule = %+v", ws.Schedule)
+	}
+}
+
+func TestRenderWeekly_Travel(t *testing.T) {
+	tmplPath := filepath.Join("..", "..", "templates", "weekly.md.tmpl")
+	zone := func(d int, offset string) fetch.DayData {
+		return fetch.DayData{Date: time.Date(2026, 2, d, 0, 0, 0, 0, time.UTC), Cycle: &models.Cycle{TimezoneOffset: offset}}
+	}
+	prev := []fetch.DayData{zone(8, "-08:00")}
+	days := []fetch.DayData{zone(9, "-05:00"), zone(10, "+01:00")}
+	got, err := RenderWeeklyFromStats(BuildWeekComparison(days, prev), tmplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "## Travel\n\n- Shifted **+3h** on Mon Feb 09 (-08:00 → -05:00)\n- Shifted **+6h** on Tue Feb 10 (-05:00 → +01:00)\n\nJetlag"
+	if !strings.Contains(got, want) {
+		t.Errorf("output missing %q:\n%s", want, got)
+	}
+}
+
+func TestBuildWeekStats_Calibrating(t *testing.T) {
+	calibrating := makeRecovery(20)
+	calibrating.Score.UserCalibrating = true
+	days := []fetch.DayData{{Recovery: calibrating}, {Recovery: makeRecovery(80)}}
+	ws := BuildWeekStats(days)
+	if ws.CalibratingDays != 1 || ws.RecoveryDays != 1 || ws.AvgRecovery != 80 || ws.RedDays != 0 {
+		t.Errorf("got %d calibrating, %d recovery days, avg %v, %d red; want 1, 1, 80, 0",
+			ws.CalibratingDays, ws.RecoveryDays, ws.AvgRecovery, ws.RedDays)
+	}
+	if ws.WorstDay == nil || ws.WorstDay.Recovery.Score.RecoveryScore != 80 {
+		t.Error("a calibrating day should not be the worst day")
+	}
+	if ps := BuildPersonaStats(days); ps.CalibratingDays != 1 || ps.AvgRecovery != 80 {
+		t.Errorf("persona: %d calibrating days, avg %v; want 1, 80", ps.CalibratingDays, ps.AvgRecovery)
+	}
+}
+
+func TestRenderDaily_Calibrating(t *testing.T) {
+	tmplPath := filepath.Join("..", "..", "templates", "daily.md.tmpl")
+	rec := makeRecovery(60)
+	rec.Score.UserCalibrating = true
+	got, err := RenderDaily(Day{DayData: fetch.DayData{Date: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), Recovery: rec}}, tmplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "|\n\n> [!note] Calibrating\n") {
+		t.Errorf("output missing the calibrating note:\n%s", got)
+	}
+}
+
+func TestBuildWeekStats_Balance(t *testing.T) {
+	days := []fetch.DayData{
+		{Recovery: makeRecovery(20), Cycle: makeCycle(15)},
+		{Recovery: makeRecovery(50), Cycle: makeCycle(15)},
+		{Recovery: makeRecovery(80), Cycle: makeCycle(6)},
+		{Recovery: makeRecovery(80), Cycle: makeCycle(4)},
+		{Recovery: makeRecovery(80)},
+	}
+	ws := BuildWeekStats(days)
+	if ws.OverreachedDays != 1 || ws.BalancedDays != 1 || ws.UndertrainedDays != 2 {
+		t.Errorf("balance counts = %d/%d/%d, want 1/1/2", ws.OverreachedDays, ws.BalancedDays, ws.UndertrainedDays)
+	}
+}
+
+func TestBuildWeekComparison(t *testing.T) {
+	days := []fetch.DayData{
+		{Date: time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC), Recovery: makeRecovery(70), Cycle: makeCycle(12.1)},
+	}
+	prev := []fetch.DayData{
+		{Date: time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC), Recovery: makeRecovery(60), Cycle: makeCycle(10.4)},
+	}
+	ws := BuildWeekComparison(days, prev)
+	if ws.Previous == nil || ws.Previous.AvgStrain != 10.4 {
+		t.Fatalf("Previous = %+v", ws.Previous)
+	}
+	got, err := RenderWeeklyFromStats(ws, filepath.Join("..", "..", "templates", "weekly.md.tmpl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "| Avg Strain | 12.1 · last week 10.4 (+1.7) |"
+	if !strings.Contains(got, want) {
+		t.Errorf("output missing %q:\n%s", want, got)
+	}
+
+	empty := []fetch.DayData{{Date: time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC)}}
+	if ws := BuildWeekComparison(days, empty); ws.Previous != nil {
+		t.Error("Previous should be nil when the week before has no data")
+	}
+}
+
 func TestBuildWeekStats_SkipsUnscored(t *testing.T) {
 	days := []fetch.DayData{
 		{
@@ -362,18 +1021,49 @@ func TestBuildWeekStats_NapsExcludedFromSleep(t *testing.T) {
 	}
 }
 
-// --- RenderDaily (integration: minimal template) ---
-
-const minimalDailyTmpl = `{{define "daily.md.tmpl"}}date: {{.Date.Format "2006-01-02"}}{{end}}`
-
-func TestRenderDaily(t *testing.T) {
-	dir := t.TempDir()
-	tmplPath := filepath.Join(dir, "daily.md.tmpl")
+func TestWeekStats_ZeroMissing(t *testing.T) {
+	days := []fetch.DayData{
+		{
+			Date:     time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC),
+			Cycle:    makeCycle(12),
+			Recovery: makeRecovery(90),
+			Sleeps:   []models.Sleep{makeSleep(28_800_000)},
+		},
+		{Date: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)},
+	}
+	ws := BuildWeekStats(days)
+	if ws.RecoveryDays != 1 || ws.StrainDays != 1 || ws.SleepDays != 1 {
+		t.Errorf("coverage = %d/%d/%d, want 1/1/1", ws.RecoveryDays, ws.StrainDays, ws.SleepDays)
+	}
+
+	ws.Missing = []string{"2026-02-10"}
+	ws.ZeroMissing()
+	if ws.AvgRecovery != 45 {
+		t.Errorf("AvgRecovery = %.1f, want 45 with the missing day as zero", ws.AvgRecovery)
+	}
+	if ws.AvgStrain != 6 {
+		t.Errorf("AvgStrain = %.1f, want 6", ws.AvgStrain)
+	}
+	if ws.AvgSleepMillis != 14_400_000 {
+		t.Errorf("AvgSleepMillis = %d, want 14_400_000", ws.AvgSleepMillis)
+	}
+	if !ws.MissingZeroed {
+		t.Error("MissingZeroed should be set")
+	}
+}
+
+// --- RenderDaily (integration: minimal template) ---
+
+const minimalDailyTmpl = `{{define "daily.md.tmpl"}}date: {{.Date.Format "2006-01-02"}}{{end}}`
+
+func TestRenderDaily(t *testing.T) {
+	dir := t.TempDir()
+	tmplPath := filepath.Join(dir, "daily.md.tmpl")
 	if err := os.WriteFile(tmplPath, []byte(minimalDailyTmpl), 0644); err != nil {
 		t.Fatal(err)
 	}
 
-	data := fetch.DayData{Date: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)}
+	data := Day{DayData: fetch.DayData{Date: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)}}
 	got, err := RenderDaily(data, tmplPath)
 	if err != nil {
 		t.Fatal(err)
@@ -383,8 +1073,213 @@ func TestRenderDaily(t *testing.T) {
 	}
 }
 
+func TestRenderDaily_Strava(t *testing.T) {
+	tmplPath := filepath.Join("..", "..", "templates", "daily.md.tmpl")
+	data := Day{
+		DayData: fetch.DayData{
+			Date:     time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC),
+			Workouts: []models.Workout{{ID: "w1", SportName: "running"}, {ID: "w2", SportName: "weightlifting"}},
+		},
+		Strava: map[string]*strava.Activity{"w1": {ID: 42, Name: "Morning Run", Distance: 10210}},
+	}
+	got, err := RenderDaily(data, tmplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "| Strava | [Morning Run](https://www.strava.com/activities/42) · 10.21 km |"
+	if strings.Count(got, want) != 1 || strings.Count(got, "| Strava |") != 1 {
+		t.Errorf("want one Strava row %q in:\n%s", want, got)
+	}
+}
+
+func TestRenderDaily_Body(t *testing.T) {
+	tmplPath := filepath.Join("..", "..", "templates", "daily.md.tmpl")
+	data := Day{DayData: fetch.DayData{Date: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)}}
+	got, err := RenderDaily(data, tmplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "weight_kg") {
+		t.Errorf("frontmatter has body measurements without Body set:\n%s", got)
+	}
+
+	data.Body = &models.BodyMeasurements{HeightMeter: 1.8, WeightKilogram: 74.25, MaxHeartRate: 191}
+	if got, err = RenderDaily(data, tmplPath); err != nil {
+		t.Fatal(err)
+	}
+	want := "generator: whoop-garden dev\nworkouts: 0\nheight_m: 1.80\nweight_kg: 74.2\nmax_hr: 191\n---\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("frontmatter missing %q:\n%s", want, got)
+	}
+}
+
+func TestRenderDaily_Rolling7(t *testing.T) {
+	tmplPath := filepath.Join("..", "..", "templates", "daily.md.tmpl")
+	data := Day{
+		DayData:  fetch.DayData{Date: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)},
+		Rolling7: &fetch.Rolling{Days: 7, AvgRecovery: 64.4, AvgHRV: 45.25, AvgSleep: 26_400_000},
+	}
+	got, err := RenderDaily(data, tmplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "\n> 7-day avg: Recovery 64% | HRV 45.2 ms | Sleep 7h 20m\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("summary missing %q:\n%s", want, got)
+	}
+}
+
+func TestRenderDaily_Previous(t *testing.T) {
+	tmplPath := filepath.Join("..", "..", "templates", "daily.md.tmpl")
+	prev := fetch.DayData{Recovery: makeRecovery(65), Cycle: makeCycle(12.4)}
+	prev.Recovery.Score.RestingHeartRate = 55
+	data := Day{
+		DayData: fetch.DayData{
+			Date:     time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC),
+			Recovery: makeRecovery(72),
+			Cycle:    makeCycle(12.4),
+		},
+		Previous: &prev,
+	}
+	data.Recovery.Score.RestingHeartRate = 54
+	got, err := RenderDaily(data, tmplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"| Recovery Score | **72%** (↑7 from 65%) |",
+		"| Resting Heart Rate | 54 bpm (↓1) |",
+		"(Moderate) (→) |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderDaily_HRVBaseline(t *testing.T) {
+	tmplPath := filepath.Join("..", "..", "templates", "daily.md.tmpl")
+	data := Day{
+		DayData:     fetch.DayData{Date: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), Recovery: makeRecovery(40)},
+		HRVBaseline: &fetch.Baseline{Days: 60, Mean: 50, Std: 5, Value: 41, Z: -1.8, Percentile: 4},
+	}
+	got, err := RenderDaily(data, tmplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "| HRV (RMSSD) | 50.0 ms · -1.8σ below baseline (4th percentile, 60-day baseline) |"
+	if !strings.Contains(got, want) {
+		t.Errorf("output missing %q:\n%s", want, got)
+	}
+}
+
+func TestRenderDaily_Streaks(t *testing.T) {
+	tmplPath := filepath.Join("..", "..", "templates", "daily.md.tmpl")
+	data := Day{
+		DayData: fetch.DayData{Date: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)},
+		Streaks: &fetch.Streaks{
+			Green:   fetch.Streak{Current: 3, Best: 9},
+			Sleep:   fetch.Streak{Current: 0, Best: 4},
+			Workout: fetch.Streak{Current: 2, Best: 2},
+		},
+	}
+	got, err := RenderDaily(data, tmplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "\n> Streaks: green 3 (best 9) | 7h+ sleep 0 (best 4) | workouts 2 (best 2)\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("summary missing %q:\n%s", want, got)
+	}
+}
+
+func TestRenderDaily_Respiratory(t *testing.T) {
+	tmplPath := filepath.Join("..", "..", "templates", "daily.md.tmpl")
+	s := makeSleep(8 * 3_600_000)
+	s.Score.RespiratoryRate = 16.2
+	data := Day{
+		DayData:     fetch.DayData{Date: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), Sleeps: []models.Sleep{s}},
+		Respiratory: &fetch.Baseline{Days: 30, Mean: 14.5, Std: 0.5, Value: 16.2, Flagged: true},
+	}
+	got, err := RenderDaily(data, tmplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"> [!warning] Respiratory rate\n> 16.2 rpm is +1.7 rpm from the 30-night baseline of 14.5 ± 0.5 rpm.",
+		"| Respiratory Rate | 16.2 rpm · +1.7 vs 30-night baseline |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+
+	data.Respiratory.Flagged = false
+	if got, _ := RenderDaily(data, tmplPath); strings.Contains(got, "[!warning]") {
+		t.Error("unflagged night should have no warning")
+	}
+}
+
+func TestRenderDaily_Vitals(t *testing.T) {
+	tmplPath := filepath.Join("..", "..", "templates", "daily.md.tmpl")
+	rec := makeRecovery(60)
+	rec.Score.Spo2Percentage = 92
+	rec.Score.SkinTempCelsius = 33.9
+	data := Day{
+		DayData:  fetch.DayData{Date: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), Recovery: rec},
+		SpO2:     &fetch.Baseline{Days: 30, Mean: 96.5, Std: 0.5, Value: 92, Flagged: true},
+		SkinTemp: &fetch.Baseline{Days: 30, Mean: 33.6, Std: 0.1, Value: 33.9},
+	}
+	got, err := RenderDaily(data, tmplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"> [!warning] SpO₂\n> 92.0% is -4.5 points from the 30-day baseline of 96.5 ± 0.5%.",
+		"| SpO₂ | 92.0% · -4.5 vs 30-day baseline ⚠️ |",
+		"| Skin Temp | 33.9°C · +0.3 vs 30-day baseline |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "[!warning] Skin temperature") {
+		t.Error("unflagged skin temperature should have no warning")
+	}
+}
+
+func TestRenderDaily_SleepDebt(t *testing.T) {
+	tmplPath := filepath.Join("..", "..", "templates", "daily.md.tmpl")
+	hour := int64(3_600_000)
+	night := fetch.SleepNight{Need: 8 * hour, Asleep: 7 * hour, Debt: 3 * hour}
+	data := Day{
+		DayData: fetch.DayData{Date: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)},
+		SleepDebt: &fetch.SleepDebt{
+			Nights:       []fetch.SleepNight{night, night},
+			Latest:       &night,
+			Debt:         3 * hour,
+			PayoffNights: 3,
+			PayoffMillis: hour,
+		},
+	}
+	got, err := RenderDaily(data, tmplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"## Sleep Debt",
+		"| Last Night | 7h 0m asleep of 8h 0m needed (-1h 0m) |",
+		"| Debt | **3h 0m** over 2 nights |",
+		"> Sleep about 1h 0m more than needed on each of the next 3 nights to clear it.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q:\n%s", want, got)
+		}
+	}
+}
+
 func TestRenderDaily_MissingTemplate(t *testing.T) {
-	_, err := RenderDaily(fetch.DayData{}, "/nonexistent/daily.md.tmpl")
+	_, err := RenderDaily(Day{}, "/nonexistent/daily.md.tmpl")
 	if err == nil {
 		t.Error("expected error for missing template")
 	}
@@ -429,3 +1324,353 @@ func TestRenderPersonaSection_Smoke(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderPersona_Locale(t *testing.T) {
+	defer func(l locale.Locale) { Locale = l }(Locale)
+	Locale = locale.Get("de")
+	days := []fetch.DayData{{
+		Date:     time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC),
+		Recovery: makeRecovery(75),
+		Sleeps:   []models.Sleep{makeSleep(28_800_000)},
+		Cycle:    makeCycle(10.5),
+	}}
+	got, err := RenderPersonaSection(days)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# WHOOP-Gesundheitsprofil", "- Durchschnittliche HRV: **50,0 ms**", "- Durchschnittliche Tagesbelastung: **10,5**", "- Grün (67–100): 1 Tage"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderTemplateSets(t *testing.T) {
+	defer func(l locale.Locale) { Locale = l }(Locale)
+	day := fetch.DayData{
+		Date:     time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC),
+		Recovery: makeRecovery(75),
+		Sleeps:   []models.Sleep{makeSleep(28_800_000), {Nap: true}},
+		Cycle:    makeCycle(15.2),
+		Workouts: []models.Workout{{SportName: "Running"}},
+	}
+	for _, set := range locale.Supported {
+		dir := filepath.Join("..", "..", "templates", set)
+		if set == "en" {
+			dir = filepath.Join("..", "..", "templates")
+		}
+		Locale = locale.Get(set)
+		if _, err := RenderDaily(Day{DayData: day}, filepath.Join(dir, "daily.md.tmpl")); err != nil {
+			t.Errorf("%s daily: %v", set, err)
+		}
+		if _, err := RenderWeeklyFromStats(BuildWeekStats([]fetch.DayData{day}), filepath.Join(dir, "weekly.md.tmpl")); err != nil {
+			t.Errorf("%s weekly: %v", set, err)
+		}
+	}
+	got, err := RenderDaily(Day{DayData: day}, filepath.Join("..", "..", "templates", "es", "daily.md.tmpl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# WHOOP diario — 10 de febrero de 2026", "**75%** (verde)", "**15,2** (Intenso)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("es daily missing %q", want)
+		}
+	}
+}
+
+func TestSamples_RenderEveryTemplateSet(t *testing.T) {
+	defer func(l locale.Locale) { Locale = l }(Locale)
+	days, prev := SampleWeek()
+	week := BuildWeekComparison(days, prev)
+	for _, set := range locale.Supported {
+		dir := filepath.Join("..", "..", "templates", set)
+		if set == "en" {
+			dir = filepath.Join("..", "..", "templates")
+		}
+		Locale = locale.Get(set)
+		for _, s := range Samples() {
+			if _, err := RenderDaily(s.Day, filepath.Join(dir, "daily.md.tmpl")); err != nil {
+				t.Errorf("%s daily, %s sample: %v", set, s.Name, err)
+			}
+		}
+		if _, err := RenderWeeklyFromStats(week, filepath.Join(dir, "weekly.md.tmpl")); err != nil {
+			t.Errorf("%s weekly: %v", set, err)
+		}
+	}
+}
+
+func TestRenderPersona_Prior(t *testing.T) {
+	day := func(date int, recovery, strain float64) fetch.DayData {
+		return fetch.DayData{
+			Date:     time.Date(2026, 2, date, 0, 0, 0, 0, time.UTC),
+			Recovery: makeRecovery(recovery),
+			Cycle:    makeCycle(strain),
+		}
+	}
+	days := []fetch.DayData{day(3, 60, 10), day(4, 64, 12)}
+	prior := []fetch.DayData{day(1, 70, 9), day(2, 62, 11)}
+
+	got, err := RenderPersona(days, prior)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"2-Day Rolling Summary",
+		"**Compared with:** 2026-02-01 → 2026-02-02",
+		"**62%** (-4 vs prior 2d)",
+		"**11.0** (+1.0)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+
+	if strings.Contains(got, "### Profile") {
+		t.Errorf("profile rendered without Profile or Body set:\n%s", got)
+	}
+
+	// A prior window without cycles has nothing to compare against.
+	got, err = RenderPersona(days, []fetch.DayData{{Date: prior[0].Date}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "vs prior") {
+		t.Errorf("deltas rendered without prior data:\n%s", got)
+	}
+}
+
+func TestRenderPersonaFromStats_HRVBaseline(t *testing.T) {
+	pd := BuildPersonaStats([]fetch.DayData{{Date: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)}})
+	pd.HRVBaseline = &fetch.Baseline{Days: 30, Mean: 48.2, Std: 4.1, Value: 41, Z: -1.76, Percentile: 3.3}
+	got, err := RenderPersonaFromStats(pd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "- Latest HRV vs Baseline: **-1.8σ below baseline** (41.0 ms vs 48.2 ± 4.1 ms over 30 days, 3rd percentile)\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("output missing %q:\n%s", want, got)
+	}
+}
+
+func TestRenderPersonaFromStats_TrainingLoad(t *testing.T) {
+	pd := BuildPersonaStats([]fetch.DayData{{Date: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)}})
+	pd.TrainingLoad = &fetch.TrainingLoad{Acute: 16, Chronic: 10, Ratio: 1.6, Spike: true}
+	got, err := RenderPersonaFromStats(pd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "- Acute:Chronic Workload Ratio: **1.60** (7-day strain 16.0 vs 28-day 10.0) ⚠️ load spike\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("output missing %q:\n%s", want, got)
+	}
+}
+
+func TestRenderPersonaFromStats_Schedule(t *testing.T) {
+	days := []fetch.DayData{{
+		Date:   time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC),
+		Sleeps: []models.Sleep{{Start: "2026-02-09T04:10:00Z", End: "2026-02-09T12:10:00Z", TimezoneOffset: "-05:00"}},
+	}}
+	got, err := RenderPersonaFromStats(BuildPersonaStats(days))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "- Sleep Midpoint: **03:10** (bed 23:10, wake 07:10)\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("output missing %q:\n%s", want, got)
+	}
+}
+
+func TestRenderPersonaFromStats_Profile(t *testing.T) {
+	pd := BuildPersonaStats([]fetch.DayData{{Date: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)}})
+	pd.Profile = &models.UserProfile{FirstName: "Ada", LastName: "Lovelace"}
+	pd.Body = &models.BodyMeasurements{HeightMeter: 1.65, WeightKilogram: 58, MaxHeartRate: 188}
+	got, err := RenderPersonaFromStats(pd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "### Profile\n- Name: **Ada Lovelace**\n- Height: **1.65 m**\n- Weight: **58.0 kg**\n- Max Heart Rate: **188 bpm**\n\n### Recovery\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("output missing %q:\n%s", want, got)
+	}
+}
+
+// --- RenderSummary (bundled template) ---
+
+func TestRenderSummary(t *testing.T) {
+	rec := &models.Recovery{ScoreState: "SCORED"}
+	rec.Score.RecoveryScore = 28
+	rec.Score.HrvRmssdMilli = 41.4
+	rec.Score.RestingHeartRate = 58
+	cycle := &models.Cycle{ScoreState: "SCORED"}
+	cycle.Score.Strain = 11.24
+	data := Day{DayData: fetch.DayData{Date: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), Recovery: rec, Cycle: cycle}}
+
+	got, err := RenderSummary(data, filepath.Join("..", "..", "templates", "summary.txt.tmpl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "WHOOP · Tue 10 Feb 2026\n" +
+		"Recovery 28% (red) · HRV 41 ms · RHR 58 bpm\n" +
+		"No sleep recorded\n" +
+		"Strain 11.2 (Moderate)\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	data.Bedtime = &fetch.Bedtime{Bedtime: -1.5, Wake: 6.5, Need: 28_800_000}
+	got, err = RenderSummary(data, filepath.Join("..", "..", "templates", "summary.txt.tmpl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Strain 11.2 (Moderate)\nBed by 22:30 for 8h 0m of sleep, waking 06:30\n"; !strings.HasSuffix(got, want) {
+		t.Errorf("got:\n%s\nwant it to end with:\n%s", got, want)
+	}
+}
+
+func TestRenderCorrelations(t *testing.T) {
+	tmplPath := filepath.Join("..", "..", "templates", "correlate.md.tmpl")
+	rep := CorrelationReport{
+		Start: "2026-01-01",
+		End:   "2026-01-30",
+		Days:  30,
+		Correlations: []analytics.Correlation{
+			{X: "Day strain", Y: "Next-day recovery (%)", R: -0.62, Points: []analytics.Point{{X: 12.5, Y: 48}, {X: 8, Y: 71}}},
+		},
+	}
+	got, err := RenderCorrelations(rep, tmplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"| Day strain → Next-day recovery (%) | -0.62 | strong, negative | 2 |",
+		"```csv\nDay strain,Next-day recovery (%)\n12.50,48.00\n8.00,71.00\n```",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q:\n%s", want, got)
+		}
+	}
+
+	rep.Correlations = nil
+	got, err = RenderCorrelations(rep, tmplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "*Not enough data.") {
+		t.Errorf("expected a not-enough-data note:\n%s", got)
+	}
+}
+
+func TestRenderRecords(t *testing.T) {
+	tmplPath := filepath.Join("..", "..", "templates", "records.md.tmpl")
+	date := time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
+	data := RecordsData{
+		Since:   "2025-01-01",
+		Through: "2026-02-15",
+		Records: analytics.Records{
+			Days:          400,
+			DayStrain:     &analytics.Record{Date: date, Value: 19.4},
+			WorkoutStrain: &analytics.Record{Date: date, Value: 17.2, Workout: &models.Workout{SportID: 0}},
+			WeekTraining:  &analytics.Record{Date: time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC), Millis: 34_200_000},
+		},
+	}
+	got, err := RenderRecords(data, tmplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"All-time bests across 400 days of data, 2025-01-01 → 2026-02-15.",
+		"| 🔥 Highest day strain | **19.4** | [[Health/WHOOP/2026/daily-2026-02-10|Feb 10, 2026]] |\n",
+		"| 🏋️ Biggest workout strain | **17.2** (Running) |",
+		"| 📅 Most training in a week | **9h 30m** | [[Health/WHOOP/2026/weekly-2026-W07|Week 2026-W07]] |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Longest sleep") {
+		t.Error("records without data should be left out")
+	}
+
+	got, err = RenderRecords(RecordsData{}, tmplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "*No data yet.*") {
+		t.Errorf("expected a no-data note:\n%s", got)
+	}
+}
+
+func TestRenderIndex(t *testing.T) {
+	tmplDir := filepath.Join("..", "..", "templates")
+	year, err := RenderYearIndex(YearIndexData{
+		Year:   "2026",
+		Home:   "Health/WHOOP/WHOOP",
+		Prev:   "2025",
+		Months: []time.Time{time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		Weeks:  []IndexWeek{{Key: "2026-W07", Start: time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC), Last: time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)}},
+	}, filepath.Join(tmplDir, "index.md.tmpl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# WHOOP 2026\n\n[[Health/WHOOP/2025/index-2025|← 2025]] | [[Health/WHOOP/WHOOP|WHOOP]]\n",
+		"## Months\n\n- [[Health/WHOOP/2026/monthly-2026-02|February]]\n\n## Weeks",
+		"- [[Health/WHOOP/2026/weekly-2026-W07|Week 2026-W07]] · Feb 9 – Feb 15\n",
+	} {
+		if !strings.Contains(year, want) {
+			t.Errorf("year index missing %q:\n%s", want, year)
+		}
+	}
+
+	home, err := RenderHome(HomeData{
+		Records: "Health/WHOOP/Records",
+		Years:   []IndexYear{{Year: "2026", Days: 40, Weeks: 6}},
+	}, filepath.Join(tmplDir, "home.md.tmpl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# WHOOP\n\n- [[Health/WHOOP/Records|Personal Records]]\n\n## Years",
+		"| [[Health/WHOOP/2026/index-2026|2026]] | 6 | 40 |\n",
+	} {
+		if !strings.Contains(home, want) {
+			t.Errorf("home missing %q:\n%s", want, home)
+		}
+	}
+	if strings.Contains(home, "Health Persona") {
+		t.Error("persona link without a persona note")
+	}
+}
+
+func TestRenderDaily_StrainTarget(t *testing.T) {
+	tmplPath := filepath.Join("..", "..", "templates", "daily.md.tmpl")
+	day := Day{DayData: fetch.DayData{
+		Date:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Recovery: makeRecovery(80),
+		Cycle:    makeCycle(15),
+	}}
+	got, err := RenderDaily(day, tmplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "| Strain Target |") {
+		t.Errorf("scored day: no strain target in\n%s", got)
+	}
+
+	day.Recovery.Score.UserCalibrating = true
+	if got, err = RenderDaily(day, tmplPath); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "| Strain Target |") {
+		t.Error("calibrating recovery: strain target shown")
+	}
+
+	day.Recovery.Score.UserCalibrating = false
+	day.Cycle.ScoreState = "PENDING_SCORE"
+	if got, err = RenderDaily(day, tmplPath); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "| Strain Target |") {
+		t.Error("unscored cycle: strain target shown")
+	}
+}
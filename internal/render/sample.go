@@ -0,0 +1,175 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/models"
+	"github.com/benstraw/whoop-garden/internal/strava"
+)
+
+// Sample is a made-up day for checking templates without API calls.
+type Sample struct {
+	Name string
+	Day  Day
+}
+
+// sampleDate is the date of the sample days.
+var sampleDate = time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
+
+// Samples returns the days templates must handle: a fully scored day with
+// every optional section filled in, a day WHOOP has not scored yet, a day
+// with several workouts, a time zone change, and flagged vitals, and a day
+// with a nap.
+func Samples() []Sample {
+	scored := Day{DayData: sampleDay(sampleDate, 74, 52.3, 12.4)}
+	prev := sampleDay(sampleDate.AddDate(0, 0, -1), 58, 47.1, 15.8)
+	scored.Previous = &prev
+	scored.Body = &models.BodyMeasurements{HeightMeter: 1.8, WeightKilogram: 74.2, MaxHeartRate: 191}
+	scored.Rolling7 = &fetch.Rolling{Days: 7, AvgRecovery: 63, AvgHRV: 49.6, AvgRHR: 53, AvgStrain: 13.1, AvgSleep: 27_300_000}
+	scored.HRVBaseline = &fetch.Baseline{Days: 60, Mean: 48.4, Std: 3.2, Value: 52.3, Z: 1.2, Percentile: 86}
+	scored.Respiratory = &fetch.Baseline{Days: 30, Mean: 14.9, Std: 0.4, Value: 15.1}
+	scored.Streaks = &fetch.Streaks{
+		Green:   fetch.Streak{Current: 2, Best: 9},
+		Sleep:   fetch.Streak{Current: 4, Best: 12},
+		Workout: fetch.Streak{Current: 3, Best: 6},
+	}
+	scored.SleepDebt = sampleSleepDebt(sampleDate)
+	scored.Bedtime = &fetch.Bedtime{Bedtime: -1.5, Wake: 6.5, Need: 28_800_000, InBed: 32_400_000, Efficiency: 88.9}
+
+	unscored := Day{DayData: fetch.DayData{
+		Date:     sampleDate,
+		Cycle:    &models.Cycle{Start: "2026-02-10T11:02:00.000Z", TimezoneOffset: "-05:00", ScoreState: "PENDING_SCORE"},
+		Recovery: &models.Recovery{ScoreState: "PENDING_SCORE"},
+		Sleeps:   []models.Sleep{{Start: "2026-02-10T04:10:00.000Z", End: "2026-02-10T11:02:00.000Z", TimezoneOffset: "-05:00", ScoreState: "PENDING_SCORE"}},
+	}}
+
+	workouts := Day{DayData: sampleDay(sampleDate, 41, 44.0, 17.9)}
+	workouts.Workouts = append(workouts.Workouts,
+		sampleWorkout("w-2", "Weightlifting", "2026-02-10T22:30:00.000Z", 50*time.Minute, 7.2, 0),
+		sampleWorkout("w-3", "", "2026-02-11T01:00:00.000Z", 25*time.Minute, 3.1, 2100),
+	)
+	workouts.Workouts[2].SportID = 63
+	workouts.DuplicateWorkouts = []models.Workout{sampleWorkout("w-4", "Running", "2026-02-10T12:31:00.000Z", 44*time.Minute, 10.9, 8020)}
+	workouts.Strava = map[string]*strava.Activity{"w-1": {ID: 1234567890, Name: "Morning Run", Distance: 8046.7}}
+	workouts.Travel = &fetch.Shift{Date: sampleDate, From: "-08:00", To: "-05:00", Hours: 3}
+	workouts.Respiratory = &fetch.Baseline{Days: 30, Mean: 14.9, Std: 0.4, Value: 16.6, Z: 4.3, Percentile: 100, Flagged: true}
+	workouts.SkinTemp = &fetch.Baseline{Days: 30, Mean: 33.6, Std: 0.3, Value: 34.9, Z: 4.3, Percentile: 100, Flagged: true}
+	workouts.SpO2 = &fetch.Baseline{Days: 30, Mean: 96.8, Std: 0.6, Value: 93.2, Z: -6, Flagged: true}
+
+	nap := Day{DayData: sampleDay(sampleDate, 66, 50.2, 9.8)}
+	nap.Workouts = nil
+	nap.Sleeps = append(nap.Sleeps, models.Sleep{
+		ID: "s-nap", Start: "2026-02-10T19:15:00.000Z", End: "2026-02-10T19:52:00.000Z", TimezoneOffset: "-05:00",
+		Nap: true, ScoreState: "SCORED",
+		Score: models.SleepScore{StageSummary: models.SleepStageSummary{TotalInBedTimeMilli: 2_220_000, TotalAwakeTimeMilli: 240_000, TotalLightSleepTimeMilli: 1_560_000, TotalSlowWaveSleepTimeMilli: 420_000}},
+	})
+
+	return []Sample{
+		{"scored", scored},
+		{"unscored", unscored},
+		{"workouts", workouts},
+		{"nap", nap},
+	}
+}
+
+// SampleWeek returns the Monday-to-Sunday week holding the sample date and
+// the week before it, for the weekly and other multi-day templates.
+func SampleWeek() (days, prev []fetch.DayData) {
+	sunday := sampleDate.AddDate(0, 0, 6-int((sampleDate.Weekday()+6)%7))
+	all := SampleDays(sunday, 14)
+	return all[7:], all[:7]
+}
+
+// SampleWeekStats returns the stats of SampleWeek, compared with the week
+// before and with four weeks of mileage.
+func SampleWeekStats() WeekStats {
+	days, prev := SampleWeek()
+	ws := BuildWeekComparison(days, prev)
+	ws.Mileage = BuildMileage(days, SampleDays(prev[len(prev)-1].Date, 21))
+	return ws
+}
+
+// SampleDays returns n scored sample days ending on last. Recovery and
+// strain vary from day to day, and every third day has no workout.
+func SampleDays(last time.Time, n int) []fetch.DayData {
+	recovery := []float64{72, 35, 81, 58, 22, 67, 74, 49, 90, 61, 44, 70, 63, 29}
+	strain := []float64{11.2, 16.4, 8.9, 14.1, 18.3, 10.2, 12.4, 13.3, 7.5, 15.6, 9.9, 12.8, 11.7, 17.2}
+	days := make([]fetch.DayData, 0, n)
+	for i := n - 1; i >= 0; i-- {
+		date := last.AddDate(0, 0, -i)
+		k := date.YearDay() % len(recovery)
+		d := sampleDay(date, recovery[k], 40+recovery[k]/6, strain[k])
+		if date.YearDay()%3 == 1 {
+			d.Workouts = nil
+		}
+		days = append(days, d)
+	}
+	return days
+}
+
+// sampleDay returns a scored day with one run.
+func sampleDay(date time.Time, recovery, hrv, strain float64) fetch.DayData {
+	ts := func(d time.Duration) string { return date.Add(d).Format("2006-01-02T15:04:05.000Z") }
+	return fetch.DayData{
+		Date: date,
+		Cycle: &models.Cycle{
+			Start: ts(11 * time.Hour), End: ts(35 * time.Hour), TimezoneOffset: "-05:00", ScoreState: "SCORED",
+			Score: models.CycleScore{Strain: strain, Kilojoule: 8800 + strain*200, AverageHeartRate: 68, MaxHeartRate: 171},
+		},
+		Recovery: &models.Recovery{
+			ScoreState: "SCORED",
+			Score:      models.RecoveryScore{RecoveryScore: recovery, RestingHeartRate: 58 - recovery/10, HrvRmssdMilli: hrv, Spo2Percentage: 96.5, SkinTempCelsius: 33.7},
+		},
+		Sleeps: []models.Sleep{{
+			ID: "s-" + date.Format("0102"), Start: ts(3*time.Hour + 40*time.Minute), End: ts(11 * time.Hour), TimezoneOffset: "-05:00",
+			ScoreState: "SCORED",
+			Score: models.SleepScore{
+				StageSummary: models.SleepStageSummary{
+					TotalInBedTimeMilli: 26_400_000, TotalAwakeTimeMilli: 2_100_000,
+					TotalLightSleepTimeMilli: 11_800_000, TotalSlowWaveSleepTimeMilli: 6_100_000, TotalRemSleepTimeMilli: 6_400_000,
+					SleepCycleCount: 5, DisturbanceCount: 11,
+				},
+				SleepNeeded:      models.SleepNeeded{BaselineMillis: 27_000_000, NeedFromRecentStrainMillis: 900_000},
+				RespiratoryRate:  15.1,
+				SleepPerformance: 84, SleepConsistency: 77, SleepEfficiency: 92,
+			},
+		}},
+		Workouts: []models.Workout{sampleWorkout("w-1", "Running", date.Add(12*time.Hour+30*time.Minute).Format("2006-01-02T15:04:05.000Z"), 46*time.Minute, 11.2, 8046.7)},
+	}
+}
+
+// sampleWorkout returns a scored workout starting at start.
+func sampleWorkout(id, sport, start string, length time.Duration, strain, meters float64) models.Workout {
+	end := start
+	if t, err := fetch.ParseWhoopTime(start); err == nil {
+		end = t.Add(length).Format("2006-01-02T15:04:05.000Z")
+	}
+	return models.Workout{
+		ID: id, Start: start, End: end, TimezoneOffset: "-05:00", SportID: -1, SportName: sport, ScoreState: "SCORED",
+		Score: models.WorkoutScore{
+			Strain: strain, AverageHeartRate: 142, MaxHeartRate: 176, Kilojoule: 2100, PercentRecorded: 100, DistanceMeter: meters, AltitudeGainMeter: math.Round(meters / 100),
+			ZoneDuration: models.ZoneDuration{ZoneOneMillis: 420_000, ZoneTwoMillis: 1_140_000, ZoneThreeMillis: 960_000, ZoneFourMillis: 240_000},
+		},
+	}
+}
+
+// sampleSleepDebt returns a week of nights ending on date with debt left
+// to pay off.
+func sampleSleepDebt(date time.Time) *fetch.SleepDebt {
+	debt := &fetch.SleepDebt{PayoffNights: 3, PayoffMillis: 1_500_000}
+	for i := 6; i >= 0; i-- {
+		asleep := int64(24_300_000 + (i%3)*1_200_000)
+		debt.Debt += 27_900_000 - asleep
+		debt.Nights = append(debt.Nights, fetch.SleepNight{Date: date.AddDate(0, 0, -i), Need: 27_900_000, Asleep: asleep, Debt: debt.Debt})
+	}
+	debt.Latest = &debt.Nights[len(debt.Nights)-1]
+	return debt
+}
+
+// String names the sample for listings.
+func (s Sample) String() string {
+	return fmt.Sprintf("%s (%s)", s.Name, s.Day.Date.Format("2006-01-02"))
+}
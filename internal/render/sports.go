@@ -0,0 +1,151 @@
+package render
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/benstraw/whoop-garden/internal/models"
+)
+
+// SportMeta is how notes present a sport: an emoji, an Obsidian tag
+// (without #), and a category from config.SportCategories. Any may be
+// empty.
+type SportMeta struct {
+	Name     string
+	Emoji    string
+	Tag      string
+	Category string
+}
+
+// Sports sets the emoji, tag, and category of sports by name, on top of
+// the built-in ones; empty fields keep the built-in value. main sets it
+// from config.
+var Sports map[string]SportMeta
+
+// builtinSports gives the sports WHOOP knows an emoji and a category.
+// None has a tag, so notes only get sport tags that were configured.
+var builtinSports = map[string]SportMeta{
+	"Running":                {Emoji: "🏃", Category: "cardio"},
+	"Cycling":                {Emoji: "🚴", Category: "cardio"},
+	"Spin":                   {Emoji: "🚴", Category: "cardio"},
+	"Mountain Biking":        {Emoji: "🚵", Category: "cardio"},
+	"Rowing":                 {Emoji: "🚣", Category: "cardio"},
+	"Swimming":               {Emoji: "🏊", Category: "cardio"},
+	"Walking":                {Emoji: "🚶", Category: "cardio"},
+	"Hiking/Rucking":         {Emoji: "🥾", Category: "cardio"},
+	"Kayaking":               {Emoji: "🛶", Category: "cardio"},
+	"Paddleboarding":         {Emoji: "🏄", Category: "cardio"},
+	"Cross Country Skiing":   {Emoji: "⛷️", Category: "cardio"},
+	"Elliptical":             {Emoji: "🏃", Category: "cardio"},
+	"Stairmaster":            {Emoji: "🪜", Category: "cardio"},
+	"Jumping Rope":           {Emoji: "🪢", Category: "cardio"},
+	"HIIT":                   {Emoji: "🔥", Category: "cardio"},
+	"Dance":                  {Emoji: "💃", Category: "cardio"},
+	"Track & Field":          {Emoji: "🏃", Category: "cardio"},
+	"Duathlon":               {Emoji: "🏅", Category: "cardio"},
+	"Triathlon":              {Emoji: "🏅", Category: "cardio"},
+	"Obstacle Course Racing": {Emoji: "🏅", Category: "cardio"},
+	"Weightlifting":          {Emoji: "🏋️", Category: "strength"},
+	"Powerlifting":           {Emoji: "🏋️", Category: "strength"},
+	"Functional Fitness":     {Emoji: "🏋️", Category: "strength"},
+	"Rock Climbing":          {Emoji: "🧗", Category: "strength"},
+	"Climber":                {Emoji: "🧗", Category: "strength"},
+	"Gymnastics":             {Emoji: "🤸", Category: "strength"},
+	"Pilates":                {Emoji: "🤸", Category: "strength"},
+	"Yoga":                   {Emoji: "🧘", Category: "recovery"},
+	"Meditation":             {Emoji: "🧘", Category: "recovery"},
+	"Ice Bath":               {Emoji: "🧊", Category: "recovery"},
+	"Baseball":               {Emoji: "⚾", Category: "sport"},
+	"Softball":               {Emoji: "🥎", Category: "sport"},
+	"Basketball":             {Emoji: "🏀", Category: "sport"},
+	"Football":               {Emoji: "🏈", Category: "sport"},
+	"Australian Football":    {Emoji: "🏉", Category: "sport"},
+	"Rugby":                  {Emoji: "🏉", Category: "sport"},
+	"Soccer":                 {Emoji: "⚽", Category: "sport"},
+	"Volleyball":             {Emoji: "🏐", Category: "sport"},
+	"Ultimate":               {Emoji: "🥏", Category: "sport"},
+	"Cricket":                {Emoji: "🏏", Category: "sport"},
+	"Field Hockey":           {Emoji: "🏑", Category: "sport"},
+	"Ice Hockey":             {Emoji: "🏒", Category: "sport"},
+	"Lacrosse":               {Emoji: "🥍", Category: "sport"},
+	"Water Polo":             {Emoji: "🤽", Category: "sport"},
+	"Tennis":                 {Emoji: "🎾", Category: "sport"},
+	"Squash":                 {Emoji: "🎾", Category: "sport"},
+	"Badminton":              {Emoji: "🏸", Category: "sport"},
+	"Pickleball":             {Emoji: "🏓", Category: "sport"},
+	"Golf":                   {Emoji: "⛳", Category: "sport"},
+	"Boxing":                 {Emoji: "🥊", Category: "sport"},
+	"Wrestling":              {Emoji: "🤼", Category: "sport"},
+	"Fencing":                {Emoji: "🤺", Category: "sport"},
+	"Martial Arts":           {Emoji: "🥋", Category: "sport"},
+	"Jiu Jitsu":              {Emoji: "🥋", Category: "sport"},
+	"Skiing":                 {Emoji: "⛷️", Category: "sport"},
+	"Snowboarding":           {Emoji: "🏂", Category: "sport"},
+	"Surfing":                {Emoji: "🏄", Category: "sport"},
+	"Skateboarding":          {Emoji: "🛹", Category: "sport"},
+	"Sailing":                {Emoji: "⛵", Category: "sport"},
+	"Diving":                 {Emoji: "🤿", Category: "sport"},
+	"Horseback Riding":       {Emoji: "🏇", Category: "sport"},
+	"Archery":                {Emoji: "🏹", Category: "sport"},
+	"Motocross":              {Emoji: "🏍️", Category: "sport"},
+	"Motor Racing":           {Emoji: "🏎️", Category: "sport"},
+}
+
+// SportInfo returns the emoji, tag, and category of a sport, given as a
+// workout or a sport name. Names match case-insensitively.
+func SportInfo(sport any) (SportMeta, error) {
+	var name string
+	switch v := sport.(type) {
+	case string:
+		name = v
+	case models.Workout:
+		name = WorkoutSport(v)
+	case *models.Workout:
+		if v == nil {
+			return SportMeta{}, nil
+		}
+		name = WorkoutSport(*v)
+	default:
+		return SportMeta{}, fmt.Errorf("sportInfo: want a workout or sport name, got %T", sport)
+	}
+	m := lookupSport(builtinSports, name)
+	c := lookupSport(Sports, name)
+	if c.Emoji != "" {
+		m.Emoji = c.Emoji
+	}
+	if c.Tag != "" {
+		m.Tag = c.Tag
+	}
+	if c.Category != "" {
+		m.Category = c.Category
+	}
+	m.Name = name
+	return m, nil
+}
+
+// lookupSport returns the entry of sports for name, ignoring case.
+func lookupSport(sports map[string]SportMeta, name string) SportMeta {
+	if m, ok := sports[name]; ok {
+		return m
+	}
+	for k, m := range sports {
+		if strings.EqualFold(k, name) {
+			return m
+		}
+	}
+	return SportMeta{}
+}
+
+// SportTags returns the tags of the sports in ws, each once, in workout
+// order.
+func SportTags(ws []models.Workout) []string {
+	var tags []string
+	for _, w := range ws {
+		m, _ := SportInfo(w)
+		if m.Tag != "" && !slices.Contains(tags, m.Tag) {
+			tags = append(tags, m.Tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,81 @@
+package render
+
+import (
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/analytics"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+)
+
+// StrainTarget suggests a strain range, Low to High, for days whose
+// recovery is from MinRecovery up to the next target's.
+type StrainTarget struct {
+	MinRecovery float64
+	Low         float64
+	High        float64
+}
+
+// StrainTargets map recovery to a suggested strain, lowest recovery
+// first. main replaces them when config sets strain_targets.
+var StrainTargets = []StrainTarget{
+	{0, 0, 10},
+	{34, 10, 14},
+	{67, 14, 18},
+}
+
+// TargetStrain returns the strain target for a recovery score, or nil
+// when there are no targets.
+func TargetStrain(recovery float64) *StrainTarget {
+	var t *StrainTarget
+	for i, st := range StrainTargets {
+		if i == 0 || recovery >= st.MinRecovery {
+			t = &StrainTargets[i]
+		}
+	}
+	return t
+}
+
+// Compare places strain against the target: -1 below Low, 1 above High,
+// and 0 within the range.
+func (t StrainTarget) Compare(strain float64) int {
+	switch {
+	case strain < t.Low:
+		return -1
+	case strain > t.High:
+		return 1
+	}
+	return 0
+}
+
+// TargetDay is a day's strain against the target its recovery suggested.
+type TargetDay struct {
+	Date     time.Time
+	Recovery float64
+	Strain   float64
+	Target   StrainTarget
+	Result   int // see StrainTarget.Compare
+}
+
+// StrainTargetDays compares each day with a scored recovery and strain
+// against its target.
+func StrainTargetDays(days []fetch.DayData) []TargetDay {
+	var out []TargetDay
+	for _, d := range days {
+		r := analytics.ScoredRecovery(d)
+		if r == nil || d.Cycle == nil || d.Cycle.ScoreState != "SCORED" {
+			continue
+		}
+		t := TargetStrain(r.Score.RecoveryScore)
+		if t == nil {
+			continue
+		}
+		out = append(out, TargetDay{
+			Date:     d.Date,
+			Recovery: r.Score.RecoveryScore,
+			Strain:   d.Cycle.Score.Strain,
+			Target:   *t,
+			Result:   t.Compare(d.Cycle.Score.Strain),
+		})
+	}
+	return out
+}
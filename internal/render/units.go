@@ -0,0 +1,38 @@
+package render
+
+import "strings"
+
+// Units is the unit system distances and elevations are shown in, one of
+// config.UnitSystems. main sets it from config.
+var Units = "metric"
+
+const feetPerMeter = 3.28084
+
+// Elevation formats an altitude in meters in Units: "412 m" or "1352 ft".
+func Elevation(meters float64) string {
+	if Units == "imperial" {
+		return Locale.Number(meters*feetPerMeter, 0) + " ft"
+	}
+	return Locale.Number(meters, 0) + " m"
+}
+
+const (
+	metersPerMile = 1609.344
+	metersPerYard = 0.9144
+)
+
+// Distance formats a distance in meters in Units. Swimming is measured in
+// meters or yards, other sports in kilometers or miles: "8.05 km",
+// "1500 m".
+func Distance(meters float64, sport string) string {
+	swim := strings.EqualFold(sport, "Swimming")
+	switch {
+	case Units == "imperial" && swim:
+		return Locale.Number(meters/metersPerYard, 0) + " yd"
+	case Units == "imperial":
+		return Locale.Number(meters/metersPerMile, 2) + " mi"
+	case swim:
+		return Locale.Number(meters, 0) + " m"
+	}
+	return Locale.Number(meters/1000, 2) + " km"
+}
@@ -0,0 +1,157 @@
+// Package rpc is a minimal JSON-RPC 2.0 server over HTTP POST. It supports
+// single requests with named (object) params; batches are rejected.
+package rpc
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// Standard JSON-RPC 2.0 error codes, plus ServerError for failures inside
+// a method.
+const (
+	ParseError     = -32700
+	InvalidRequest = -32600
+	MethodNotFound = -32601
+	InvalidParams  = -32602
+	ServerError    = -32000
+)
+
+// Error is a JSON-RPC error object. Methods may return one to control the
+// code; any other error is reported as ServerError.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Handler runs a method. params is the raw "params" member, or nil.
+type Handler func(params json.RawMessage) (any, error)
+
+// Server dispatches JSON-RPC requests to registered methods.
+type Server struct {
+	// Token, if set, must be sent as "Authorization: Bearer <token>".
+	Token string
+
+	mu      sync.RWMutex
+	methods map[string]Handler
+}
+
+// Handle registers h for method.
+func (s *Server) Handle(method string, h Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.methods == nil {
+		s.methods = map[string]Handler{}
+	}
+	s.methods[method] = h
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// response is a JSON-RPC response. Result holds the encoded result, which
+// may be null or empty; it is left nil, and so omitted, only for errors.
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// ServeHTTP handles one JSON-RPC request per POST. Notifications (requests
+// without an id) are executed and answered with 204 No Content.
+//
+// Requests must be sent as application/json and, from a browser, from the
+// server's own origin. A web page can send a cross-site text/plain POST to
+// localhost without a CORS preflight, but not one with a JSON body.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "JSON-RPC requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+	if mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mt != "application/json" {
+		http.Error(w, "JSON-RPC requires Content-Type: application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if u, err := url.Parse(origin); err != nil || u.Host != r.Host {
+			http.Error(w, "cross-origin requests are not allowed", http.StatusForbidden)
+			return
+		}
+	}
+	if s.Token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+s.Token)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, response{Error: &Error{ParseError, "parse error: " + err.Error()}, ID: json.RawMessage("null")})
+		return
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		writeResponse(w, response{Error: &Error{InvalidRequest, "invalid request"}, ID: idOrNull(req.ID)})
+		return
+	}
+
+	s.mu.RLock()
+	h, ok := s.methods[req.Method]
+	s.mu.RUnlock()
+
+	resp := response{ID: idOrNull(req.ID)}
+	if !ok {
+		resp.Error = &Error{MethodNotFound, "method not found: " + req.Method}
+	} else if result, err := h(req.Params); err != nil {
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &Error{ServerError, err.Error()}
+		}
+		resp.Error = rpcErr
+	} else if resp.Result, err = json.Marshal(result); err != nil {
+		resp.Error = &Error{ServerError, "encode result: " + err.Error()}
+		resp.Result = nil
+	}
+
+	if req.ID == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeResponse(w, resp)
+}
+
+func idOrNull(id json.RawMessage) json.RawMessage {
+	if id == nil {
+		return json.RawMessage("null")
+	}
+	return id
+}
+
+func writeResponse(w http.ResponseWriter, resp response) {
+	resp.JSONRPC = "2.0"
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// DecodeParams unmarshals params into v. Missing params leave v unchanged;
+// malformed params yield an InvalidParams error.
+func DecodeParams(params json.RawMessage, v any) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &Error{InvalidParams, "invalid params: " + err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func call(t *testing.T, s *Server, body, token string) (int, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	var out map[string]any
+	if rec.Body.Len() > 0 && rec.Header().Get("Content-Type") == "application/json" {
+		if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+			t.Fatalf("bad response %q: %v", rec.Body.String(), err)
+		}
+	}
+	return rec.Code, out
+}
+
+func newServer() *Server {
+	s := &Server{}
+	s.Handle("echo", func(params json.RawMessage) (any, error) {
+		var p struct{ Msg string }
+		if err := DecodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return map[string]string{"msg": p.Msg}, nil
+	})
+	s.Handle("fail", func(json.RawMessage) (any, error) { return nil, errors.New("boom") })
+	s.Handle("none", func(json.RawMessage) (any, error) { return []string{}, nil })
+	return s
+}
+
+func TestServer_Call(t *testing.T) {
+	_, out := call(t, newServer(), `{"jsonrpc":"2.0","method":"echo","params":{"msg":"hi"},"id":7}`, "")
+	if out["id"] != float64(7) || out["result"].(map[string]any)["msg"] != "hi" {
+		t.Errorf("unexpected response: %v", out)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	tests := []struct {
+		body string
+		code float64
+	}{
+		{`{not json`, ParseError},
+		{`{"method":"echo","id":1}`, InvalidRequest},
+		{`{"jsonrpc":"2.0","method":"nope","id":1}`, MethodNotFound},
+		{`{"jsonrpc":"2.0","method":"echo","params":[1],"id":1}`, InvalidParams},
+		{`{"jsonrpc":"2.0","method":"fail","id":1}`, ServerError},
+	}
+	for _, tc := range tests {
+		_, out := call(t, newServer(), tc.body, "")
+		e, _ := out["error"].(map[string]any)
+		if e == nil || e["code"] != tc.code {
+			t.Errorf("%s: got %v, want error code %v", tc.body, out, tc.code)
+		}
+	}
+}
+
+func TestServer_Notification(t *testing.T) {
+	code, _ := call(t, newServer(), `{"jsonrpc":"2.0","method":"echo"}`, "")
+	if code != http.StatusNoContent {
+		t.Errorf("notification status = %d, want 204", code)
+	}
+}
+
+func TestServer_Token(t *testing.T) {
+	s := newServer()
+	s.Token = "secret"
+	if code, _ := call(t, s, `{"jsonrpc":"2.0","method":"echo","id":1}`, ""); code != http.StatusUnauthorized {
+		t.Errorf("missing token status = %d, want 401", code)
+	}
+	if code, _ := call(t, s, `{"jsonrpc":"2.0","method":"echo","id":1}`, "secret"); code != http.StatusOK {
+		t.Errorf("valid token status = %d, want 200", code)
+	}
+}
+
+func TestServer_EmptyResult(t *testing.T) {
+	_, out := call(t, newServer(), `{"jsonrpc":"2.0","method":"none","id":1}`, "")
+	if got, ok := out["result"]; !ok || len(got.([]any)) != 0 {
+		t.Errorf("empty result: got %v, want \"result\": []", out)
+	}
+	_, out = call(t, newServer(), `{"jsonrpc":"2.0","method":"fail","id":1}`, "")
+	if _, ok := out["result"]; ok {
+		t.Errorf("error response has a result: %v", out)
+	}
+}
+
+func TestServer_CrossSite(t *testing.T) {
+	body := `{"jsonrpc":"2.0","method":"echo","id":1}`
+	tests := []struct {
+		name, contentType, origin string
+		want                      int
+	}{
+		{"text/plain", "text/plain", "", http.StatusUnsupportedMediaType},
+		{"no content type", "", "", http.StatusUnsupportedMediaType},
+		{"charset", "application/json; charset=utf-8", "", http.StatusOK},
+		{"foreign origin", "application/json", "https://evil.example", http.StatusForbidden},
+		{"own origin", "application/json", "http://127.0.0.1:8766", http.StatusOK},
+	}
+	for _, tc := range tests {
+		req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:8766/rpc", strings.NewReader(body))
+		if tc.contentType != "" {
+			req.Header.Set("Content-Type", tc.contentType)
+		}
+		if tc.origin != "" {
+			req.Header.Set("Origin", tc.origin)
+		}
+		rec := httptest.NewRecorder()
+		newServer().ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.name, rec.Code, tc.want)
+		}
+	}
+}
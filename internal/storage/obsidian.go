@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultObsidianURL is where the Local REST API plugin listens by default.
+const defaultObsidianURL = "https://127.0.0.1:27124"
+
+// Obsidian stores notes through the Obsidian Local REST API plugin, so a
+// running Obsidian re-indexes them at once, and a vault on another machine
+// can be updated. Keys are placed under Prefix, a folder in the vault.
+type Obsidian struct {
+	BaseURL    string
+	APIKey     string
+	Prefix     string
+	HTTPClient *http.Client
+}
+
+// NewObsidian returns a Local REST API backend. The plugin serves HTTPS
+// with a self-signed certificate; with insecure set it is not verified.
+func NewObsidian(baseURL, apiKey, prefix string, insecure bool) *Obsidian {
+	if baseURL == "" {
+		baseURL = defaultObsidianURL
+	}
+	hc := &http.Client{Timeout: 30 * time.Second}
+	if insecure {
+		hc.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+	return &Obsidian{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		APIKey:     apiKey,
+		Prefix:     strings.Trim(prefix, "/"),
+		HTTPClient: hc,
+	}
+}
+
+func (o *Obsidian) url(key string) string {
+	key = cleanKey(key)
+	if o.Prefix != "" {
+		key = o.Prefix + "/" + key
+	}
+	parts := strings.Split(key, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return o.BaseURL + "/vault/" + strings.Join(parts, "/")
+}
+
+func (o *Obsidian) do(method, key string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, o.url(key), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+o.APIKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "text/markdown")
+	}
+	return o.HTTPClient.Do(req)
+}
+
+// Read fetches the note for key.
+func (o *Obsidian) Read(key string) ([]byte, error) {
+	resp, err := o.do(http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("obsidian %s: %w", key, fs.ErrNotExist)
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("obsidian GET %s returned %d", key, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Write creates or replaces the note for key with PUT. The plugin creates
+// missing folders itself.
+func (o *Obsidian) Write(key string, data []byte) error {
+	resp, err := o.do(http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("obsidian PUT %s returned %d", key, resp.StatusCode)
+	}
+	return nil
+}
+
+// Exists reports whether the note for key exists. The plugin does not
+// answer HEAD, so this is a GET.
+func (o *Obsidian) Exists(key string) (bool, error) {
+	resp, err := o.do(http.MethodGet, key, nil)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode/100 == 2:
+		return true, nil
+	default:
+		return false, fmt.Errorf("obsidian GET %s returned %d", key, resp.StatusCode)
+	}
+}
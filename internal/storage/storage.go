@@ -0,0 +1,292 @@
+// Package storage abstracts where generated notes are written: the local
+// filesystem (default), a WebDAV share, an S3-compatible bucket, or a vault
+// through the Obsidian Local REST API plugin. Notes are
+// addressed by slash-separated keys relative to the output root, e.g.
+// "2026/daily-2026-02-10.md".
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Storage reads and writes notes by key. Read returns an error satisfying
+// errors.Is(err, fs.ErrNotExist) for missing keys.
+type Storage interface {
+	Read(key string) ([]byte, error)
+	Write(key string, data []byte) error
+	Exists(key string) (bool, error)
+}
+
+// Config selects and configures a backend. The zero value is local storage.
+type Config struct {
+	// Type is "local" (default), "webdav", "s3", or "obsidian".
+	Type string `json:"type"`
+
+	// WebDAV: URL of the output root collection and basic-auth credentials.
+	// Obsidian: URL of the Local REST API, https://127.0.0.1:27124 by
+	// default.
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// S3: bucket and region, plus Endpoint for S3-compatible services
+	// (MinIO, R2, B2). PathStyle addresses the bucket in the URL path
+	// instead of the host name, which most non-AWS services require.
+	Bucket          string `json:"bucket"`
+	Region          string `json:"region"`
+	Endpoint        string `json:"endpoint"`
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	PathStyle       bool   `json:"path_style"`
+
+	// Obsidian: the plugin's API key. InsecureSkipVerify accepts its
+	// self-signed certificate.
+	APIKey             string `json:"api_key"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+
+	// Prefix is prepended to every S3 object key, e.g. "vault/Health/WHOOP",
+	// or for Obsidian is the vault folder notes go in, e.g. "Health/WHOOP".
+	Prefix string `json:"prefix"`
+
+	// Targets, when set instead of Type, writes every note to each of
+	// several backends, each configured like a single one with its own
+	// credentials. Reads come from the first target.
+	Targets []Config `json:"targets"`
+}
+
+// Remote reports whether cfg selects a backend other than the local
+// filesystem.
+func (cfg Config) Remote() bool {
+	for _, t := range cfg.Targets {
+		if t.Remote() {
+			return true
+		}
+	}
+	return cfg.Type != "" && cfg.Type != "local"
+}
+
+// String names the backend, or the targets joined with "+".
+func (cfg Config) String() string {
+	if len(cfg.Targets) > 0 {
+		names := make([]string, len(cfg.Targets))
+		for i, t := range cfg.Targets {
+			names[i] = t.String()
+		}
+		return strings.Join(names, "+")
+	}
+	if cfg.Type == "" {
+		return "local"
+	}
+	return cfg.Type
+}
+
+// Validate checks that cfg has the fields its backend needs.
+func (cfg Config) Validate() error {
+	if len(cfg.Targets) > 0 {
+		if cfg.Type != "" {
+			return errors.New("storage: set either type or targets, not both")
+		}
+		for i, t := range cfg.Targets {
+			if len(t.Targets) > 0 {
+				return fmt.Errorf("storage: target %d: targets cannot be nested", i+1)
+			}
+			if err := t.Validate(); err != nil {
+				return fmt.Errorf("target %d: %w", i+1, err)
+			}
+		}
+		return nil
+	}
+	switch cfg.Type {
+	case "", "local":
+	case "webdav":
+		if cfg.URL == "" {
+			return errors.New("storage: webdav requires url")
+		}
+	case "s3":
+		if cfg.Bucket == "" || cfg.Region == "" {
+			return errors.New("storage: s3 requires bucket and region")
+		}
+		if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+			return errors.New("storage: s3 requires access_key_id and secret_access_key")
+		}
+	case "obsidian":
+		if cfg.APIKey == "" {
+			return errors.New("storage: obsidian requires api_key")
+		}
+	default:
+		return fmt.Errorf("storage: unknown type %q (want local, webdav, s3, or obsidian)", cfg.Type)
+	}
+	return nil
+}
+
+// New returns the backend selected by cfg. localRoot is the output
+// directory used by local storage.
+func New(cfg Config, localRoot string) (Storage, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if len(cfg.Targets) > 0 {
+		var m Multi
+		for _, t := range cfg.Targets {
+			s, err := New(t, localRoot)
+			if err != nil {
+				return nil, err
+			}
+			m = append(m, s)
+		}
+		return m, nil
+	}
+	switch cfg.Type {
+	case "webdav":
+		return NewWebDAV(cfg.URL, cfg.Username, cfg.Password), nil
+	case "s3":
+		return NewS3(cfg), nil
+	case "obsidian":
+		return NewObsidian(cfg.URL, cfg.APIKey, cfg.Prefix, cfg.InsecureSkipVerify), nil
+	default:
+		return Local{Root: localRoot}, nil
+	}
+}
+
+// Local stores notes as files under Root. With an empty Root, keys are
+// used as file paths directly.
+type Local struct {
+	Root string
+}
+
+func (l Local) path(key string) string {
+	return filepath.Join(l.Root, filepath.FromSlash(key))
+}
+
+// Read returns the contents of the file for key.
+func (l Local) Read(key string) ([]byte, error) {
+	return os.ReadFile(l.path(key))
+}
+
+// Write writes data to the file for key, creating parent directories.
+func (l Local) Write(key string, data []byte) error {
+	p := l.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(p, data, 0644)
+}
+
+// Exists reports whether the file for key exists.
+func (l Local) Exists(key string) (bool, error) {
+	_, err := os.Stat(l.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Multi writes every note to each of its backends, so a vault and a
+// remote copy stay in step. Reads and Exists use the first backend.
+type Multi []Storage
+
+// Read returns the note for key from the first backend.
+func (m Multi) Read(key string) ([]byte, error) {
+	return m[0].Read(key)
+}
+
+// Write writes data to every backend, continuing past failures, and
+// returns their errors joined.
+func (m Multi) Write(key string, data []byte) error {
+	var errs []error
+	for _, s := range m {
+		if err := s.Write(key, data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Exists reports whether the first backend has a note for key.
+func (m Multi) Exists(key string) (bool, error) {
+	return m[0].Exists(key)
+}
+
+// Change is how writing a note would change storage.
+type Change int
+
+const (
+	Create Change = iota
+	Update
+	Unchanged
+)
+
+func (c Change) String() string {
+	switch c {
+	case Create:
+		return "create"
+	case Update:
+		return "update"
+	}
+	return "unchanged"
+}
+
+// Compare reports how writing content to key would change s, and returns
+// the existing content, which is nil for Create.
+func Compare(s Storage, key string, content []byte) (Change, []byte, error) {
+	existing, err := s.Read(key)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return Create, nil, nil
+	case err != nil:
+		return 0, nil, err
+	case bytes.Equal(existing, content):
+		return Unchanged, existing, nil
+	}
+	return Update, existing, nil
+}
+
+// Memory stores notes in memory. It is meant for tests.
+type Memory struct {
+	mu    sync.Mutex
+	notes map[string][]byte
+}
+
+// NewMemory returns an empty Memory.
+func NewMemory() *Memory {
+	return &Memory{notes: map[string][]byte{}}
+}
+
+// Read returns a copy of the note for key.
+func (m *Memory) Read(key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.notes[cleanKey(key)]
+	if !ok {
+		return nil, fmt.Errorf("memory %s: %w", key, fs.ErrNotExist)
+	}
+	return bytes.Clone(data), nil
+}
+
+// Write stores a copy of data for key.
+func (m *Memory) Write(key string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notes[cleanKey(key)] = bytes.Clone(data)
+	return nil
+}
+
+// Exists reports whether a note is stored for key.
+func (m *Memory) Exists(key string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.notes[cleanKey(key)]
+	return ok, nil
+}
+
+// cleanKey normalises key to a relative slash path without a leading slash.
+func cleanKey(key string) string {
+	return strings.TrimPrefix(filepath.ToSlash(key), "/")
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// WebDAV stores notes on a WebDAV share such as Nextcloud, which
+// remotely-save can sync into Obsidian.
+type WebDAV struct {
+	BaseURL    string
+	Username   string
+	Password   string
+	HTTPClient *http.Client
+}
+
+// NewWebDAV returns a WebDAV backend rooted at baseURL.
+func NewWebDAV(baseURL, username, password string) *WebDAV {
+	return &WebDAV{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		Username:   username,
+		Password:   password,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (w *WebDAV) url(key string) string {
+	parts := strings.Split(cleanKey(key), "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return w.BaseURL + "/" + strings.Join(parts, "/")
+}
+
+func (w *WebDAV) do(method, key string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, w.url(key), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if w.Username != "" {
+		req.SetBasicAuth(w.Username, w.Password)
+	}
+	return w.HTTPClient.Do(req)
+}
+
+// Read fetches key with GET.
+func (w *WebDAV) Read(key string) ([]byte, error) {
+	resp, err := w.do(http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("webdav %s: %w", key, fs.ErrNotExist)
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("webdav GET %s returned %d", key, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Write uploads data with PUT. If the server reports a missing parent
+// collection (409 Conflict), the parents are created with MKCOL and the PUT
+// is retried once.
+func (w *WebDAV) Write(key string, data []byte) error {
+	status, err := w.put(key, data)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusConflict {
+		if err := w.mkdirs(key); err != nil {
+			return err
+		}
+		if status, err = w.put(key, data); err != nil {
+			return err
+		}
+	}
+	if status/100 != 2 {
+		return fmt.Errorf("webdav PUT %s returned %d", key, status)
+	}
+	return nil
+}
+
+func (w *WebDAV) put(key string, data []byte) (int, error) {
+	resp, err := w.do(http.MethodPut, key, data)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// mkdirs creates every parent collection of key, outermost first. 405
+// Method Not Allowed means the collection already exists.
+func (w *WebDAV) mkdirs(key string) error {
+	parts := strings.Split(cleanKey(key), "/")
+	for i := 1; i < len(parts); i++ {
+		dir := strings.Join(parts[:i], "/") + "/"
+		resp, err := w.do("MKCOL", dir, nil)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusMethodNotAllowed {
+			return fmt.Errorf("webdav MKCOL %s returned %d", dir, resp.StatusCode)
+		}
+	}
+	return nil
+}
+
+// Exists checks key with HEAD.
+func (w *WebDAV) Exists(key string) (bool, error) {
+	resp, err := w.do(http.MethodHead, key, nil)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode/100 == 2:
+		return true, nil
+	default:
+		return false, fmt.Errorf("webdav HEAD %s returned %d", key, resp.StatusCode)
+	}
+}
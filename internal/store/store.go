@@ -0,0 +1,204 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/client"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/models"
+)
+
+// settleAfter is how long after a day ends its data is treated as final.
+// WHOOP may still score sleeps and workouts for a while after the cycle
+// closes, so anything fetched sooner is refetched on the next read.
+const settleAfter = 48 * time.Hour
+
+// probeInterval is how often a settled day is checked against WHOOP for
+// corrections made after it was stored.
+const probeInterval = 24 * time.Hour
+
+// Store persists fetched DayData on disk as one JSON file per calendar day.
+type Store struct {
+	dir string
+
+	// OnRevision, if set, is called when a settled day is refetched because
+	// WHOOP changed it after it was stored.
+	OnRevision func(old, revised fetch.DayData)
+
+	// Offline makes GetDayData serve stored days as they are, settled or
+	// not, without probing WHOOP for corrections.
+	Offline bool
+}
+
+// entry is the on-disk representation of a stored day.
+type entry struct {
+	FetchedAt time.Time     `json:"fetched_at"`
+	CheckedAt time.Time     `json:"checked_at"`
+	Day       fetch.DayData `json:"day"`
+}
+
+// Open returns a Store rooted at dir, creating the directory if needed.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("create store dir %s: %w", dir, err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Dir returns the directory backing the store.
+func (s *Store) Dir() string { return s.dir }
+
+// Empty reports whether no days have been stored yet.
+func (s *Store) Empty() (bool, error) {
+	matches, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return false, err
+	}
+	return len(matches) == 0, nil
+}
+
+// All returns every stored day, oldest first.
+func (s *Store) All() ([]fetch.DayData, error) {
+	matches, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	var days []fetch.DayData
+	for _, m := range matches {
+		date, err := time.Parse("2006-01-02", strings.TrimSuffix(filepath.Base(m), ".json"))
+		if err != nil {
+			continue
+		}
+		e, ok, err := s.load(date)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			days = append(days, e.Day)
+		}
+	}
+	return days, nil
+}
+
+func (s *Store) path(date time.Time) string {
+	return filepath.Join(s.dir, date.Format("2006-01-02")+".json")
+}
+
+// Load returns the stored day for date and when it was fetched.
+// ok is false when nothing is stored for that date.
+func (s *Store) Load(date time.Time) (day fetch.DayData, fetchedAt time.Time, ok bool, err error) {
+	e, ok, err := s.load(date)
+	return e.Day, e.FetchedAt, ok, err
+}
+
+func (s *Store) load(date time.Time) (entry, bool, error) {
+	var e entry
+	data, err := os.ReadFile(s.path(date))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return e, false, nil
+		}
+		return e, false, fmt.Errorf("read stored day: %w", err)
+	}
+	if err := json.Unmarshal(data, &e); err != nil {
+		return e, false, fmt.Errorf("parse stored day %s: %w", date.Format("2006-01-02"), err)
+	}
+	// Days stored before duplicate detection still hold both copies.
+	var dupes []models.Workout
+	e.Day.Workouts, dupes = fetch.SplitDuplicateWorkouts(e.Day.Workouts)
+	e.Day.DuplicateWorkouts = append(e.Day.DuplicateWorkouts, dupes...)
+	return e, true, nil
+}
+
+func (s *Store) write(e entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path(e.Day.Date), data, 0600)
+}
+
+// Save writes day to the store, stamped with the current time.
+func (s *Store) Save(day fetch.DayData) error {
+	now := time.Now().UTC()
+	return s.write(entry{FetchedAt: now, CheckedAt: now, Day: day})
+}
+
+// GetDayData is a read-through wrapper around fetch.GetDayData. Settled days
+// are served from disk. Once a day, a settled day is also probed for
+// WHOOP-side corrections (see fetch.DayChanged) and refetched if any are
+// found. Anything else is fetched from the API and stored.
+func (s *Store) GetDayData(c *client.Client, date time.Time) (fetch.DayData, error) {
+	// The store decides when to refetch, so its requests skip any
+	// response cache that would answer them with an older copy.
+	c = c.NoCache()
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	var revised *fetch.DayData
+	if s.Offline {
+		if e, ok, err := s.load(day); err != nil || ok {
+			return e.Day, err
+		}
+	}
+	if e, ok, err := s.load(day); err == nil && ok && Settled(e.Day, e.FetchedAt) {
+		if time.Since(e.CheckedAt) < probeInterval {
+			return e.Day, nil
+		}
+		changed, err := fetch.DayChanged(c, e.Day)
+		if err != nil {
+			// A failed probe is no reason to discard good data.
+			return e.Day, nil
+		}
+		if !changed {
+			e.CheckedAt = time.Now().UTC()
+			_ = s.write(e)
+			return e.Day, nil
+		}
+		revised = &e.Day
+	}
+	data, err := fetch.GetDayData(c, day)
+	if err != nil {
+		return data, err
+	}
+	if err := s.Save(data); err != nil {
+		return data, fmt.Errorf("store %s: %w", day.Format("2006-01-02"), err)
+	}
+	if revised != nil && s.OnRevision != nil {
+		s.OnRevision(*revised, data)
+	}
+	return data, nil
+}
+
+// Settled reports whether a day fetched at fetchedAt can be reused without
+// refetching: it was fetched well after the day ended and no record is still
+// waiting to be scored.
+func Settled(d fetch.DayData, fetchedAt time.Time) bool {
+	if fetchedAt.Sub(d.Date.AddDate(0, 0, 1)) < settleAfter {
+		return false
+	}
+	if d.Cycle != nil && d.Cycle.ScoreState == "PENDING_SCORE" {
+		return false
+	}
+	if d.Recovery != nil && d.Recovery.ScoreState == "PENDING_SCORE" {
+		return false
+	}
+	for _, sl := range d.Sleeps {
+		if sl.ScoreState == "PENDING_SCORE" {
+			return false
+		}
+	}
+	for _, w := range d.Workouts {
+		if w.ScoreState == "PENDING_SCORE" {
+			return false
+		}
+	}
+	return true
+}
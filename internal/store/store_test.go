@@ -0,0 +1,196 @@
+package store
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/client"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/models"
+)
+
+func TestSaveLoad_RoundTrip(t *testing.T) {
+	s, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	date := time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
+	in := fetch.DayData{
+		Date:     date,
+		Cycle:    &models.Cycle{ID: 7, ScoreState: "SCORED"},
+		Workouts: []models.Workout{{ID: "w1"}},
+	}
+	if err := s.Save(in); err != nil {
+		t.Fatal(err)
+	}
+	got, fetchedAt, ok, err := s.Load(date)
+	if err != nil || !ok {
+		t.Fatalf("Load: ok=%v err=%v", ok, err)
+	}
+	if got.Cycle == nil || got.Cycle.ID != 7 || len(got.Workouts) != 1 {
+		t.Errorf("round trip mismatch: %+v", got)
+	}
+	if fetchedAt.IsZero() {
+		t.Error("fetchedAt should be set")
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	s, _ := Open(t.TempDir())
+	_, _, ok, err := s.Load(time.Now())
+	if err != nil || ok {
+		t.Errorf("missing day: ok=%v err=%v, want false,nil", ok, err)
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	s, _ := Open(t.TempDir())
+	if empty, err := s.Empty(); err != nil || !empty {
+		t.Errorf("new store: empty=%v err=%v, want true,nil", empty, err)
+	}
+	s.Save(fetch.DayData{Date: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)})
+	if empty, _ := s.Empty(); empty {
+		t.Error("store with a saved day should not be empty")
+	}
+}
+
+func TestAll(t *testing.T) {
+	s, _ := Open(t.TempDir())
+	for _, d := range []int{12, 10, 11} {
+		s.Save(fetch.DayData{Date: time.Date(2026, 2, d, 0, 0, 0, 0, time.UTC)})
+	}
+	days, err := s.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 3 || days[0].Date.Day() != 10 || days[2].Date.Day() != 12 {
+		t.Errorf("All = %d days starting %v, want 3 from Feb 10", len(days), days[0].Date)
+	}
+}
+
+func TestSettled(t *testing.T) {
+	day := time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
+	late := day.AddDate(0, 0, 4)
+
+	if Settled(fetch.DayData{Date: day}, day.Add(30*time.Hour)) {
+		t.Error("day fetched within the settle window should not be settled")
+	}
+	if !Settled(fetch.DayData{Date: day}, late) {
+		t.Error("day fetched days later should be settled")
+	}
+	pending := fetch.DayData{Date: day, Recovery: &models.Recovery{ScoreState: "PENDING_SCORE"}}
+	if Settled(pending, late) {
+		t.Error("pending recovery should not be settled")
+	}
+}
+
+func TestGetDayData_ServesSettledFromDisk(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(models.PaginatedResponse[models.Cycle]{})
+	}))
+	defer srv.Close()
+	c := client.NewClientWithBaseURL("tok", srv.URL)
+
+	s, _ := Open(t.TempDir())
+	date := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := s.GetDayData(c, date); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Fatalf("first read made %d calls, want 1", calls)
+	}
+	if _, err := s.GetDayData(c, date); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("settled day should be served from disk, made %d calls", calls)
+	}
+}
+
+func TestGetDayData_ProbesStaleCheck(t *testing.T) {
+	var cycleUpdated string
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path+"?limit="+r.URL.Query().Get("limit"))
+		if r.URL.Path != "/cycle" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(models.PaginatedResponse[models.Cycle]{
+			Records: []models.Cycle{{ID: 1, Start: "2020-01-01T07:00:00.000Z", UpdatedAt: cycleUpdated, ScoreState: "SCORED"}},
+		})
+	}))
+	defer srv.Close()
+	c := client.NewClientWithBaseURL("tok", srv.URL)
+
+	s, _ := Open(t.TempDir())
+	var revisions int
+	s.OnRevision = func(old, revised fetch.DayData) { revisions++ }
+	date := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	cycleUpdated = "2020-01-02T00:00:00.000Z"
+	if _, err := s.GetDayData(c, date); err != nil {
+		t.Fatal(err)
+	}
+
+	// Age the check stamp so the next read probes.
+	e, _, _ := s.load(date)
+	e.CheckedAt = e.CheckedAt.Add(-2 * probeInterval)
+	s.write(e)
+
+	calls = nil
+	if _, err := s.GetDayData(c, date); err != nil {
+		t.Fatal(err)
+	}
+	for _, call := range calls {
+		if call[len(call)-1] != '1' {
+			t.Errorf("unchanged day should only be probed with limit=1, got call %s", call)
+		}
+	}
+
+	// A WHOOP-side correction bumps updated_at: the probe triggers a refetch.
+	e, _, _ = s.load(date)
+	e.CheckedAt = e.CheckedAt.Add(-2 * probeInterval)
+	s.write(e)
+	cycleUpdated = "2020-01-05T00:00:00.000Z"
+	got, err := s.GetDayData(c, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Cycle == nil || got.Cycle.UpdatedAt != cycleUpdated {
+		t.Errorf("expected refetched cycle with new updated_at, got %+v", got.Cycle)
+	}
+	if revisions != 1 {
+		t.Errorf("OnRevision called %d times, want 1", revisions)
+	}
+}
+
+func TestGetDayData_Offline(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+	c := client.NewClientWithBaseURL("tok", srv.URL)
+
+	s, _ := Open(t.TempDir())
+	s.Offline = true
+	// Fetched moments after the day, so it is not settled.
+	date := time.Now().UTC().Truncate(24 * time.Hour)
+	if err := s.Save(fetch.DayData{Date: date, Cycle: &models.Cycle{ScoreState: "PENDING_SCORE"}}); err != nil {
+		t.Fatal(err)
+	}
+	d, err := s.GetDayData(c, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Cycle == nil || calls != 0 {
+		t.Errorf("offline read of an unsettled day: cycle %v, %d calls; want the stored day and no calls", d.Cycle, calls)
+	}
+}
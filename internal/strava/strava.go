@@ -0,0 +1,210 @@
+// Package strava fetches Strava activities and matches them to WHOOP
+// workouts by time overlap.
+package strava
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/models"
+)
+
+const (
+	defaultBaseURL = "https://www.strava.com/api/v3"
+	tokenURL       = "https://www.strava.com/oauth/token"
+)
+
+// Activity is the subset of a Strava summary activity rendered in notes.
+type Activity struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	SportType   string    `json:"sport_type"`
+	StartDate   time.Time `json:"start_date"`
+	ElapsedTime int       `json:"elapsed_time"` // seconds
+	Distance    float64   `json:"distance"`     // meters
+}
+
+// timeLayout is how Strava writes start_date, always in UTC. Its
+// start_date_local has the same form, but its "Z" is wrong: the time is in
+// the athlete's time zone.
+const timeLayout = "2006-01-02T15:04:05Z"
+
+// UnmarshalJSON reads an activity from the Strava API. StartDate comes from
+// start_date, or failing that from start_date_local taken as local time.
+func (a *Activity) UnmarshalJSON(b []byte) error {
+	type plain Activity
+	var v struct {
+		plain
+		StartDate      string `json:"start_date"`
+		StartDateLocal string `json:"start_date_local"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*a = Activity(v.plain)
+	var err error
+	switch {
+	case v.StartDate != "":
+		if a.StartDate, err = time.Parse(timeLayout, v.StartDate); err != nil {
+			// Saved activities carry fractional seconds or an offset.
+			a.StartDate, err = time.Parse(time.RFC3339Nano, v.StartDate)
+		}
+	case v.StartDateLocal != "":
+		a.StartDate, err = time.ParseInLocation(timeLayout, v.StartDateLocal, time.Local)
+	}
+	if err != nil {
+		return fmt.Errorf("activity %d start: %w", a.ID, err)
+	}
+	return nil
+}
+
+// URL returns the activity's page on strava.com.
+func (a Activity) URL() string {
+	return "https://www.strava.com/activities/" + strconv.FormatInt(a.ID, 10)
+}
+
+// Kilometers returns the distance in kilometers.
+func (a Activity) Kilometers() float64 { return a.Distance / 1000 }
+
+// End returns when the activity ended.
+func (a Activity) End() time.Time {
+	return a.StartDate.Add(time.Duration(a.ElapsedTime) * time.Second)
+}
+
+// Client is an authenticated Strava API client.
+type Client struct {
+	token      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a Client with the given access token.
+func NewClient(token string) *Client {
+	return NewClientWithBaseURL(token, defaultBaseURL)
+}
+
+// NewClientWithBaseURL creates a Client with a custom base URL. Intended for tests.
+func NewClientWithBaseURL(token, baseURL string) *Client {
+	return &Client{token: token, baseURL: baseURL, httpClient: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Activities returns the athlete's activities that started between after
+// and before. At most 200 are returned, which is plenty for a day.
+func (c *Client) Activities(after, before time.Time) ([]Activity, error) {
+	q := url.Values{
+		"after":    {strconv.FormatInt(after.Unix(), 10)},
+		"before":   {strconv.FormatInt(before.Unix(), 10)},
+		"per_page": {"200"},
+	}
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/athlete/activities?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("strava request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("strava returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var as []Activity
+	if err := json.Unmarshal(body, &as); err != nil {
+		return nil, fmt.Errorf("parse strava activities: %w", err)
+	}
+	return as, nil
+}
+
+// Token is an OAuth token pair returned by Strava.
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresAt    int64  `json:"expires_at"` // unix seconds
+}
+
+// Expired reports whether the access token expires within a minute.
+func (t Token) Expired() bool {
+	return time.Now().Add(time.Minute).Unix() >= t.ExpiresAt
+}
+
+// Refresh exchanges a refresh token for a new token pair. Strava may rotate
+// the refresh token, so callers should keep the returned one.
+func Refresh(clientID, clientSecret, refreshToken string) (Token, error) {
+	return refresh(&http.Client{Timeout: 30 * time.Second}, tokenURL, clientID, clientSecret, refreshToken)
+}
+
+func refresh(hc *http.Client, endpoint, clientID, clientSecret, refreshToken string) (Token, error) {
+	resp, err := hc.PostForm(endpoint, url.Values{
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+	if err != nil {
+		return Token{}, fmt.Errorf("strava token refresh failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Token{}, fmt.Errorf("strava token refresh returned %d", resp.StatusCode)
+	}
+	var t Token
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return Token{}, fmt.Errorf("parse strava token: %w", err)
+	}
+	return t, nil
+}
+
+// Match pairs each workout with the activity it overlaps most, keyed by
+// workout ID. Each activity is matched at most once; workouts with no
+// overlapping activity are left out.
+func Match(ws []models.Workout, as []Activity) map[string]*Activity {
+	matches := make(map[string]*Activity)
+	used := make([]bool, len(as))
+	for _, w := range ws {
+		start, err1 := time.Parse(time.RFC3339Nano, w.Start)
+		end, err2 := time.Parse(time.RFC3339Nano, w.End)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		best, bestOverlap := -1, time.Duration(0)
+		for i, a := range as {
+			if used[i] {
+				continue
+			}
+			if o := overlap(start, end, a.StartDate, a.End()); o > bestOverlap {
+				best, bestOverlap = i, o
+			}
+		}
+		if best >= 0 {
+			used[best] = true
+			matches[w.ID] = &as[best]
+		}
+	}
+	return matches
+}
+
+// overlap returns how long [s1, e1) and [s2, e2) overlap.
+func overlap(s1, e1, s2, e2 time.Time) time.Duration {
+	s, e := s1, e1
+	if s2.After(s) {
+		s = s2
+	}
+	if e2.Before(e) {
+		e = e2
+	}
+	if !e.After(s) {
+		return 0
+	}
+	return e.Sub(s)
+}
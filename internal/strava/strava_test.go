@@ -0,0 +1,118 @@
+package strava
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/models"
+)
+
+func TestMatch(t *testing.T) {
+	at := func(s string) time.Time {
+		t, _ := time.Parse(time.RFC3339, s)
+		return t
+	}
+	ws := []models.Workout{
+		{ID: "run", Start: "2026-02-10T07:00:00Z", End: "2026-02-10T08:00:00Z"},
+		{ID: "lift", Start: "2026-02-10T18:00:00Z", End: "2026-02-10T19:00:00Z"},
+		{ID: "bad", Start: "not a time"},
+	}
+	as := []Activity{
+		{ID: 1, Name: "Walk to the track", StartDate: at("2026-02-10T06:40:00Z"), ElapsedTime: 30 * 60},
+		{ID: 2, Name: "Morning Run", StartDate: at("2026-02-10T07:02:00Z"), ElapsedTime: 55 * 60, Distance: 10210},
+		{ID: 3, Name: "Evening Ride", StartDate: at("2026-02-10T20:00:00Z"), ElapsedTime: 3600},
+	}
+	m := Match(ws, as)
+	if len(m) != 1 {
+		t.Fatalf("got %d matches, want 1: %v", len(m), m)
+	}
+	if a := m["run"]; a == nil || a.ID != 2 {
+		t.Errorf("run matched %+v, want the activity with the most overlap", a)
+	}
+	if got := m["run"].Kilometers(); got != 10.21 {
+		t.Errorf("Kilometers() = %v, want 10.21", got)
+	}
+	if got := m["run"].URL(); got != "https://www.strava.com/activities/2" {
+		t.Errorf("URL() = %q", got)
+	}
+}
+
+func TestMatchUsesActivityOnce(t *testing.T) {
+	ws := []models.Workout{
+		{ID: "a", Start: "2026-02-10T07:00:00Z", End: "2026-02-10T08:00:00Z"},
+		{ID: "b", Start: "2026-02-10T07:00:00Z", End: "2026-02-10T08:00:00Z"},
+	}
+	as := []Activity{{ID: 1, StartDate: time.Date(2026, 2, 10, 7, 0, 0, 0, time.UTC), ElapsedTime: 3600}}
+	if m := Match(ws, as); len(m) != 1 || m["a"] == nil {
+		t.Errorf("Match() = %v, want only the first workout matched", m)
+	}
+}
+
+func TestActivity_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name, json string
+		want       time.Time
+	}{
+		{"start_date", `{"id":1,"start_date":"2026-02-10T07:02:00Z","start_date_local":"2026-02-09T23:02:00Z"}`, time.Date(2026, 2, 10, 7, 2, 0, 0, time.UTC)},
+		{"saved", `{"id":1,"start_date":"2026-02-09T23:02:00.5-08:00"}`, time.Date(2026, 2, 10, 7, 2, 0, 5e8, time.UTC)},
+		{"local only", `{"id":1,"start_date_local":"2026-02-09T23:02:00Z"}`, time.Date(2026, 2, 9, 23, 2, 0, 0, time.Local)},
+	}
+	for _, tc := range tests {
+		var a Activity
+		if err := json.Unmarshal([]byte(tc.json), &a); err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if !a.StartDate.Equal(tc.want) || a.ID != 1 {
+			t.Errorf("%s: got %+v, want start %v", tc.name, a, tc.want)
+		}
+	}
+	var a Activity
+	if err := json.Unmarshal([]byte(`{"id":1,"start_date":"yesterday"}`), &a); err == nil {
+		t.Error("invalid start_date: want an error")
+	}
+}
+
+func TestActivities(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/athlete/activities" || r.Header.Get("Authorization") != "Bearer tok" {
+			t.Errorf("unexpected request %s %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		if r.URL.Query().Get("after") != "1770681600" {
+			t.Errorf("after = %q", r.URL.Query().Get("after"))
+		}
+		w.Write([]byte(`[{"id":7,"name":"Morning Run","sport_type":"Run","start_date":"2026-02-10T07:02:00Z","elapsed_time":3300,"distance":10210.5}]`))
+	}))
+	defer srv.Close()
+
+	c := NewClientWithBaseURL("tok", srv.URL)
+	as, err := c.Activities(time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(as) != 1 || as[0].Name != "Morning Run" || as[0].Distance != 10210.5 {
+		t.Errorf("Activities() = %+v", as)
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "old" {
+			t.Errorf("form = %v", r.Form)
+		}
+		w.Write([]byte(`{"access_token":"a","refresh_token":"new","expires_at":4102444800}`))
+	}))
+	defer srv.Close()
+
+	tok, err := refresh(srv.Client(), srv.URL, "1", "s", "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.AccessToken != "a" || tok.RefreshToken != "new" || tok.Expired() {
+		t.Errorf("refresh() = %+v", tok)
+	}
+}
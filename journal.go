@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/analytics"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/note"
+	"github.com/benstraw/whoop-garden/internal/render"
+)
+
+// journalBlock names the managed block holding the link in journal notes.
+const journalBlock = "whoop-garden"
+
+// linkJournal adds or updates the link to day's note in the journal note of
+// the same date, when journal.path is set. Journal notes that do not exist
+// are left alone unless journal.create is set. Failures are only logged.
+func linkJournal(day fetch.DayData) {
+	if cfg.Journal.Path == "" || opts.stdout {
+		return
+	}
+	path, err := journalPath(day.Date)
+	if err != nil {
+		slog.Warn("could not link journal", "err", err)
+		return
+	}
+	existing, err := readNote(path)
+	if errors.Is(err, fs.ErrNotExist) && !cfg.Journal.Create {
+		return
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		slog.Warn("could not read journal note", "path", path, "err", err)
+		return
+	}
+
+	l := noteLinker()
+	target := l.Link(journalVaultPath(path), l.Path(render.NoteRel("daily", day.Date.Format("2006-01-02"))))
+	line := fmt.Sprintf("WHOOP: [[%s|%s]]", target, journalSummary(day))
+	content := note.SetBlock(string(existing), journalBlock, line)
+	if content == string(existing) {
+		return
+	}
+	if err := writeNote(path, content); err != nil {
+		slog.Warn("could not link journal", "path", path, "err", err)
+	}
+}
+
+// journalPath returns the journal note for date from journal.path.
+func journalPath(date time.Time) (string, error) {
+	p := strings.NewReplacer("YYYY", date.Format("2006"), "MM", date.Format("01"), "DD", date.Format("02")).Replace(cfg.Journal.Path)
+	if filepath.IsAbs(p) {
+		return p, nil
+	}
+	vault := os.Getenv("OBSIDIAN_VAULT_PATH")
+	if vault == "" {
+		return "", fmt.Errorf("journal.path %s needs OBSIDIAN_VAULT_PATH, or an absolute path", cfg.Journal.Path)
+	}
+	return filepath.Join(vault, p), nil
+}
+
+// journalVaultPath returns the journal note's path in the vault without
+// .md, or "" when it is outside the vault.
+func journalVaultPath(path string) string {
+	rel, err := filepath.Rel(os.Getenv("OBSIDIAN_VAULT_PATH"), path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	return strings.TrimSuffix(filepath.ToSlash(rel), ".md")
+}
+
+// journalSummary is the link text: the day's recovery and strain once
+// scored, the date until then.
+func journalSummary(day fetch.DayData) string {
+	var parts []string
+	if r := analytics.ScoredRecovery(day); r != nil {
+		parts = append(parts, fmt.Sprintf("Recovery %.0f%%", r.Score.RecoveryScore))
+	}
+	if c := day.Cycle; c != nil && c.ScoreState == "SCORED" {
+		parts = append(parts, fmt.Sprintf("Strain %.1f", c.Score.Strain))
+	}
+	if len(parts) == 0 {
+		return day.Date.Format("2006-01-02")
+	}
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// logLevel is the minimum level logged: info by default, debug under
+// --verbose, error under --quiet.
+var logLevel = new(slog.LevelVar)
+
+// configureLogging installs the default slog logger for the current opts.
+// It runs at startup and again whenever a logging flag is parsed.
+func configureLogging() {
+	switch {
+	case opts.quiet:
+		logLevel.Set(slog.LevelError)
+	case opts.verbose:
+		logLevel.Set(slog.LevelDebug)
+	default:
+		logLevel.Set(slog.LevelInfo)
+	}
+	var h slog.Handler = &cliHandler{level: logLevel, w: stderrAboveBar{}}
+	if opts.logJSON {
+		h = slog.NewJSONHandler(stderrAboveBar{}, &slog.HandlerOptions{Level: logLevel})
+	}
+	slog.SetDefault(slog.New(h))
+}
+
+// fatal logs err and exits 1.
+func fatal(err error) {
+	slog.Error(err.Error())
+	removeRunCache()
+	os.Exit(1)
+}
+
+// fatalf logs a formatted error and exits 1.
+func fatalf(format string, a ...any) {
+	fatal(fmt.Errorf(format, a...))
+}
+
+// stderrAboveBar writes to stderr, above the progress bar if one is showing.
+type stderrAboveBar struct{}
+
+func (stderrAboveBar) Write(p []byte) (int, error) {
+	if activeProgress == nil {
+		return os.Stderr.Write(p)
+	}
+	var n int
+	var err error
+	activeProgress.Above(func() { n, err = os.Stderr.Write(p) })
+	return n, err
+}
+
+// cliHandler formats records for a person reading a terminal:
+//
+//	warning: could not fetch date=2026-02-10: request failed
+//
+// Attributes follow the message as key=value, except err, which ends the
+// line. Groups are flattened.
+type cliHandler struct {
+	level slog.Leveler
+	w     io.Writer
+	attrs []slog.Attr
+}
+
+func (h *cliHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= h.level.Level()
+}
+
+func (h *cliHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	switch {
+	case r.Level >= slog.LevelError:
+	case r.Level >= slog.LevelWarn:
+		b.WriteString("warning: ")
+	case r.Level < slog.LevelInfo:
+		b.WriteString("debug: ")
+	}
+	b.WriteString(r.Message)
+
+	var errVal string
+	write := func(a slog.Attr) bool {
+		if a.Key == "err" {
+			errVal = a.Value.String()
+			return true
+		}
+		v := a.Value.String()
+		if strings.ContainsAny(v, " \"=") {
+			v = fmt.Sprintf("%q", v)
+		}
+		fmt.Fprintf(&b, " %s=%s", a.Key, v)
+		return true
+	}
+	for _, a := range h.attrs {
+		write(a)
+	}
+	r.Attrs(write)
+	if errVal != "" {
+		b.WriteString(": " + errVal)
+	}
+	b.WriteByte('\n')
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func (h *cliHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &cliHandler{level: h.level, w: h.w, attrs: append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...)}
+}
+
+func (h *cliHandler) WithGroup(string) slog.Handler { return h }
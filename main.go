@@ -2,25 +2,141 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/benstraw/whoop-garden/internal/analytics"
 	"github.com/benstraw/whoop-garden/internal/auth"
 	"github.com/benstraw/whoop-garden/internal/client"
+	"github.com/benstraw/whoop-garden/internal/config"
+	"github.com/benstraw/whoop-garden/internal/demo"
+	"github.com/benstraw/whoop-garden/internal/diff"
 	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/links"
+	"github.com/benstraw/whoop-garden/internal/locale"
+	"github.com/benstraw/whoop-garden/internal/period"
+	"github.com/benstraw/whoop-garden/internal/progress"
 	"github.com/benstraw/whoop-garden/internal/render"
+	"github.com/benstraw/whoop-garden/internal/storage"
+	"github.com/benstraw/whoop-garden/internal/store"
 )
 
 // version is set at build time via -ldflags "-X main.version=vX.Y.Z".
 var version = "dev"
 
+// cfg holds settings from the optional config file, loaded at startup.
+var cfg config.Config
+
+// opts holds flags shared by every subcommand. See addGlobalFlags.
+var opts struct {
+	output    string
+	dryRun    bool
+	diff      bool
+	stdout    bool
+	quiet     bool
+	verbose   bool
+	logJSON   bool
+	maxCalls  int
+	demo      bool
+	record    string
+	replay    string
+	noCache   bool
+	traceHTTP bool
+}
+
+// notes is the note storage backend; see noteStorage.
+var notes storage.Storage
+
+// runCache is the throwaway cache directory of a --record or --replay run;
+// see cacheDir.
+var runCache string
+
+// removeRunCache deletes runCache, if this run made one.
+func removeRunCache() {
+	if runCache != "" {
+		os.RemoveAll(runCache)
+	}
+}
+
+// activeProgress is the progress bar of the running backfill, if any.
+// Messages printed while it is set go above the bar; see printTo.
+var activeProgress *progress.Bar
+
 func main() {
+	configureLogging()
+	buildInfo()
+	render.Version = noteVersion()
 	loadDotEnv(".env")
 
+	var err error
+	if cfg, err = config.Load(); err != nil {
+		fatal(err)
+	}
+	if on, _ := strconv.ParseBool(os.Getenv("WHOOP_DEMO")); on {
+		setDemo(true)
+	}
+	render.Links = noteLinker
+	if cfg.Locale == "" && locale.Valid(cfg.TemplateSet) {
+		cfg.Locale = cfg.TemplateSet
+	}
+	if cfg.TemplateSet == "" && cfg.Locale != "en" {
+		cfg.TemplateSet = cfg.Locale
+	}
+	render.Locale = locale.Get(cfg.Locale)
+	if cfg.Units != "" {
+		render.Units = cfg.Units
+	}
+	if cfg.SleepTime != "" {
+		render.SleepTime = cfg.SleepTime
+	}
+	render.Properties = cfg.Properties
+	render.SportNames = cfg.SportNames
+	render.Sports = map[string]render.SportMeta{}
+	for name, sp := range cfg.Sports {
+		render.Sports[name] = render.SportMeta{Emoji: sp.Emoji, Tag: sp.Tag, Category: sp.Category}
+	}
+	if len(cfg.StrainBands) > 0 {
+		render.StrainBands = cfg.StrainBands
+	}
+	if len(cfg.StrainTargets) > 0 {
+		render.StrainTargets = nil
+		for _, t := range cfg.StrainTargets {
+			render.StrainTargets = append(render.StrainTargets, render.StrainTarget{MinRecovery: t.MinRecovery, Low: t.Low, High: t.High})
+		}
+	}
+	if cfg.HTTP != (config.HTTP{}) {
+		// Every request goes through the default transport unless a command
+		// sets its own, so this covers token refresh and integrations too.
+		t, err := client.NewTransport(client.TransportConfig{
+			Proxy:              cfg.HTTP.Proxy,
+			CAFile:             cfg.HTTP.CAFile,
+			MinTLSVersion:      cfg.HTTP.MinTLSVersion,
+			InsecureSkipVerify: cfg.HTTP.InsecureSkipVerify,
+		})
+		if err != nil {
+			fatalf("config http: %w", err)
+		}
+		http.DefaultTransport = t
+		if cfg.HTTP.InsecureSkipVerify {
+			slog.Warn("TLS certificate verification is off (http.insecure_skip_verify)")
+		}
+	}
+	if cfg.TemplateSet != "" {
+		if _, err := os.Stat(filepath.Join(templatesDir(), cfg.TemplateSet)); err != nil {
+			slog.Warn("template set not found, using default templates", "template_set", cfg.TemplateSet)
+		}
+	}
+
 	if len(os.Args) < 2 {
 		printUsage()
 		os.Exit(1)
@@ -31,47 +147,235 @@ func main() {
 
 	switch cmd {
 	case "version", "--version", "-v":
-		fmt.Println("whoop-garden", version)
+		printVersion()
 	case "help", "--help", "-h":
 		printUsage()
+	case "init":
+		runInit(args)
 	case "auth":
-		runAuth()
+		runAuth(args)
 	case "daily":
 		runDaily(args)
 	case "weekly":
 		runWeekly(args)
+	case "monthly":
+		runMonthly(args)
+	case "quarterly":
+		runQuarterly(args)
 	case "persona":
 		runPersona(args)
 	case "fetch-all":
 		runFetchAll(args)
 	case "catch-up":
 		runCatchUp(args)
+	case "sync":
+		runSync(args)
+	case "compare":
+		runCompare(args)
+	case "correlate":
+		runCorrelate(args)
+	case "records":
+		runRecords(args)
+	case "alerts":
+		runAlerts(args)
+	case "notify":
+		runNotify(args)
+	case "check-links":
+		runCheckLinks(args)
+	case "export":
+		runExport(args)
+	case "import":
+		runImport(args)
+	case "recovery":
+		runRecovery(args)
+	case "stats":
+		runStats(args)
+	case "serve":
+		runServe(args)
+	case "daemon":
+		runDaemon(args)
+	case "doctor":
+		runDoctor(args)
+	case "audit":
+		runAudit(args)
+	case "verify":
+		runVerify(args)
+	case "rerender":
+		runRerender(args)
+	case "purge":
+		runPurge(args)
+	case "migrate":
+		runMigrate(args)
+	case "index":
+		runIndex(args)
+	case "templates":
+		runTemplates(args)
+	case "cache":
+		runCacheCmd(args)
 	default:
 		fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", cmd)
 		printUsage()
 		os.Exit(1)
 	}
+	flushRecords()
+	flushIndex()
+	commitNotes()
+	removeRunCache()
 }
 
 func printUsage() {
 	fmt.Printf(`whoop-garden %s — WHOOP data → Obsidian markdown
 
 Usage:
+  whoop-garden init                  Interactive first-time setup
   whoop-garden auth                  Authenticate with WHOOP via OAuth
+  whoop-garden auth status           Show tokens, scopes, expiry, and user; exit 1 if re-auth is needed
+  whoop-garden auth logout [--local] Revoke the tokens at WHOOP and delete tokens.json
+  whoop-garden doctor                Check setup and print fixes for problems
+  whoop-garden templates validate    Render every template against sample data and report errors
+  whoop-garden templates preview T   Print the daily, weekly, or persona template rendered with sample data
+  whoop-garden templates test        Compare rendered templates with golden files (--update writes them)
   whoop-garden daily [--date DATE]   Generate daily note (default: today)
   whoop-garden weekly [--date DATE]  Generate weekly note for DATE's week
+  whoop-garden monthly [--month M]   Generate monthly note with a "what changed" log
+  whoop-garden quarterly [--quarter Q] Generate quarterly note with a table per month
+  whoop-garden recovery [--date DATE] Update only the recovery section of a daily note
+  whoop-garden stats [--days N]      Print a terminal summary (no files written)
+  whoop-garden serve [--addr A]      Serve a local dashboard from the local store
+  whoop-garden daemon [--interval D] Refresh today's note and serve the plugin JSON-RPC endpoint
   whoop-garden persona [--days N]    Generate 30-day persona section
   whoop-garden fetch-all [--days N]  Fetch and write notes for last N days
+                [--from D --to D]  ...or for an explicit date range
+                [--resume]         ...or continue the last run from its checkpoint
   whoop-garden catch-up [--days N]   Fetch only missing notes in last N days
-  whoop-garden version               Print version and exit
+  whoop-garden sync                  Write notes since the last sync (guided on first run)
+  whoop-garden compare --a P --b P   Compare two periods side by side
+  whoop-garden correlate [--days N]  Report how sleep, strain, and recovery relate
+  whoop-garden records               Rebuild Records.md from the local store
+  whoop-garden index                 Write WHOOP.md and a note per year listing the weekly notes
+  whoop-garden alerts                Evaluate configured alert rules
+  whoop-garden notify [--channel C]  Post today's summary to telegram, slack, or discord
+  whoop-garden check-links [--fix]   Verify prev/next links between notes
+  whoop-garden audit [--days N]      List days with missing or unscored data or notes
+                [--from D --to D]  ...or for an explicit date range
+  whoop-garden verify [--days N]     Report daily notes that no longer match the API
+                [--fix]            ...and rewrite them
+  whoop-garden rerender [--from D]   Rewrite daily notes from the local store, without API calls
+  whoop-garden purge [--notes]       Delete generated notes, after confirmation
+                [--cache --tokens] ...and/or the local cache and stored tokens
+  whoop-garden cache info|clear      Show or delete cached WHOOP API responses
+  whoop-garden migrate [--from DIR]  Move notes to the configured layout and update their links
+  whoop-garden export [--format F]   Export day data (json, csv, parquet)
+  whoop-garden import --file ZIP     Write notes from a WHOOP data export, without API calls
+  whoop-garden version               Print version, commit, build date, and API version
   whoop-garden help                  Show this help
 
 Flags:
-  --date   Date in YYYY-MM-DD format (default: today)
-  --days   Number of days (default: 30)
+  --date    Date in YYYY-MM-DD format (default: today)
+  --days    Number of days (default: 30)
+  --open    With daily or weekly, open the written note in Obsidian
+  --output  Output directory (overrides config and OBSIDIAN_VAULT_PATH)
+  --dry-run Fetch and render, but only report which files would change
+  --diff    With --dry-run, print a unified diff for each changed file
+  --stdout  Print rendered markdown instead of writing files
+  --max-calls N  Stop after N WHOOP API requests (0: unlimited)
+  --no-cache  Send every WHOOP API request, ignoring cached responses
+  --offline Make no network requests; use only stored days and cached responses
+  --trace-http  Log each WHOOP API request with its status, latency, and rate-limit headers
+  --quiet   Print only errors and dry-run reports
+  --verbose Log HTTP requests, pagination, and retries
+  --log-json  Write logs to stderr as JSON lines
 `, version)
 }
 
+// addGlobalFlags registers the flags shared by every subcommand on fs.
+func addGlobalFlags(fs *flag.FlagSet) {
+	fs.StringVar(&opts.output, "output", "", "output directory (overrides config and OBSIDIAN_VAULT_PATH)")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "fetch and render but only report which files would change")
+	fs.BoolVar(&opts.diff, "diff", false, "with --dry-run, print a unified diff for each changed file")
+	fs.BoolVar(&opts.stdout, "stdout", false, "print rendered markdown to stdout instead of writing files")
+	fs.IntVar(&opts.maxCalls, "max-calls", 0, "stop after this many WHOOP API requests (overrides config max_api_calls)")
+	fs.BoolFunc("quiet", "print only errors and dry-run reports", logFlag(&opts.quiet))
+	fs.BoolFunc("verbose", "log HTTP requests, pagination, and retries", logFlag(&opts.verbose))
+	fs.BoolFunc("log-json", "write logs to stderr as JSON lines", logFlag(&opts.logJSON))
+	fs.BoolFunc("demo", "use generated sample data instead of the WHOOP API, writing notes to a throwaway directory (or set WHOOP_DEMO=1)", func(s string) error {
+		on, err := strconv.ParseBool(s)
+		if err == nil {
+			setDemo(on)
+		}
+		return err
+	})
+	fs.StringVar(&opts.record, "record", "", "save every WHOOP API response to this directory")
+	fs.StringVar(&opts.replay, "replay", "", "answer WHOOP API requests from responses saved with --record, without network access")
+	fs.BoolVar(&opts.noCache, "no-cache", false, "send every WHOOP API request, ignoring the response cache")
+	fs.BoolVar(&opts.traceHTTP, "trace-http", false, "log each WHOOP API request: path, query, status, latency, retries, and rate-limit headers")
+	fs.BoolVar(&offline, "offline", offline, "make no network requests: read WHOOP data from the local store and response cache only")
+}
+
+// vaultStorage is the configured note storage while demo mode replaces it
+// with local files.
+var vaultStorage *storage.Config
+
+// setDemo turns demo mode on or off. Demo notes are written as local files
+// to a throwaway directory (see outputDir), never to the configured
+// storage, and dayWritten skips notifications, MQTT, journal links, and git
+// commits for them.
+func setDemo(on bool) {
+	switch {
+	case on && vaultStorage == nil:
+		s := cfg.Storage
+		vaultStorage = &s
+		cfg.Storage = storage.Config{}
+	case !on && vaultStorage != nil:
+		cfg.Storage = *vaultStorage
+		vaultStorage = nil
+	}
+	opts.demo = on
+}
+
+// logFlag returns a flag setter that stores into v and reconfigures logging.
+func logFlag(v *bool) func(string) error {
+	return func(s string) error {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		*v = b
+		configureLogging()
+		return nil
+	}
+}
+
+// infof prints a progress message. Under --stdout it goes to stderr so that
+// only rendered markdown reaches stdout; under --log-json it is logged
+// instead, and under --quiet it is dropped.
+func infof(format string, a ...any) {
+	switch {
+	case opts.quiet:
+	case opts.logJSON:
+		slog.Info(strings.TrimSpace(fmt.Sprintf(format, a...)))
+	default:
+		printTo(infoWriter(), format, a...)
+	}
+}
+
+// infoWriter is where progress output goes.
+func infoWriter() *os.File {
+	if opts.stdout {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// printTo writes to w, above the progress bar if one is showing.
+func printTo(w io.Writer, format string, a ...any) {
+	if activeProgress == nil {
+		fmt.Fprintf(w, format, a...)
+		return
+	}
+	activeProgress.Above(func() { fmt.Fprintf(w, format, a...) })
+}
+
 // loadDotEnv reads a .env file and sets environment variables.
 func loadDotEnv(path string) {
 	f, err := os.Open(path)
@@ -102,39 +406,428 @@ func loadDotEnv(path string) {
 	}
 }
 
-// outputDir returns the output directory, preferring $OBSIDIAN_VAULT_PATH/Health/WHOOP/.
+// outputDir returns the output directory. Precedence: --output flag, then
+// whoop-garden-demo in the temp directory for demo runs, then output_dir
+// from the config file, then $OBSIDIAN_VAULT_PATH/Health/WHOOP/, then
+// ./output.
 func outputDir() string {
+	if opts.output != "" {
+		return opts.output
+	}
+	if opts.demo {
+		return filepath.Join(os.TempDir(), "whoop-garden-demo")
+	}
+	if cfg.OutputDir != "" {
+		return cfg.OutputDir
+	}
 	if vault := os.Getenv("OBSIDIAN_VAULT_PATH"); vault != "" {
 		return filepath.Join(vault, "Health", "WHOOP")
 	}
 	return "./output"
 }
 
+// noteStorage returns the backend notes are written to. Local storage has
+// no root, so its keys are plain file paths, unless it is one of several
+// targets alongside a remote one; then keys are relative to outputDir().
+func noteStorage() storage.Storage {
+	if notes == nil {
+		root := ""
+		if cfg.Storage.Remote() {
+			root = outputDir()
+		}
+		if offline && cfg.Storage.Remote() {
+			fatalf("--offline cannot write notes to %s storage, which needs the network", cfg.Storage)
+		}
+		var err error
+		if notes, err = storage.New(cfg.Storage, root); err != nil {
+			fatal(err)
+		}
+	}
+	return notes
+}
+
+// noteKey returns the storage key for path. Remote backends address notes
+// relative to outputDir(), so paths outside it cannot be stored remotely.
+func noteKey(path string) (string, error) {
+	if !cfg.Storage.Remote() {
+		return path, nil
+	}
+	rel, err := filepath.Rel(outputDir(), path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the output directory and cannot be written to %s storage", path, cfg.Storage)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// readNote returns the existing contents of the note at path.
+func readNote(path string) ([]byte, error) {
+	key, err := noteKey(path)
+	if err != nil {
+		return nil, err
+	}
+	return noteStorage().Read(key)
+}
+
+// noteExists reports whether the note at path exists.
+func noteExists(path string) (bool, error) {
+	key, err := noteKey(path)
+	if err != nil {
+		return false, err
+	}
+	return noteStorage().Exists(key)
+}
+
 // ensureOutputDir creates the output directory if it doesn't exist.
+// Under --dry-run, or with remote storage, the directory is returned without
+// being created.
 func ensureOutputDir() (string, error) {
 	dir := outputDir()
+	if opts.dryRun || opts.stdout || cfg.Storage.Remote() {
+		return dir, nil
+	}
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return "", fmt.Errorf("create output dir %s: %w", dir, err)
 	}
 	return dir, nil
 }
 
-// ensureYearDir creates a year subdirectory under baseDir if it doesn't exist.
-func ensureYearDir(baseDir string, year int) (string, error) {
-	dir := filepath.Join(baseDir, fmt.Sprintf("%d", year))
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return "", fmt.Errorf("create year dir %s: %w", dir, err)
+// notePath returns the path of the note of kind ("daily", "weekly", or
+// "monthly") and key under dir, following the configured layout. Writing
+// the note creates its folder.
+func notePath(dir, kind, key string) string {
+	return filepath.Join(dir, filepath.FromSlash(links.Rel(cfg.Layout, kind, key))+".md")
+}
+
+// noteLinker returns how wikilinks between notes are written, following
+// the layout and link_style settings and the output directory.
+func noteLinker() links.Linker {
+	return links.Linker{Layout: cfg.Layout, Folder: noteFolder(), Style: cfg.LinkStyle}
+}
+
+// noteFolder returns the output directory's path in the vault, which
+// vault-absolute wikilinks start with: relative to $OBSIDIAN_VAULT_PATH when
+// the output directory is inside it, Health/WHOOP otherwise.
+func noteFolder() string {
+	vault, err := filepath.Abs(os.Getenv("OBSIDIAN_VAULT_PATH"))
+	if err != nil || os.Getenv("OBSIDIAN_VAULT_PATH") == "" || cfg.Storage.Remote() {
+		return "Health/WHOOP"
 	}
-	return dir, nil
+	dir, err := filepath.Abs(outputDir())
+	if err != nil {
+		return "Health/WHOOP"
+	}
+	rel, err := filepath.Rel(vault, dir)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "Health/WHOOP"
+	}
+	return filepath.ToSlash(rel)
+}
+
+// writeNote writes content to path and reports it. Under --stdout the
+// content is printed instead. Under --dry-run nothing is written; instead it
+// reports whether the file would be created, updated, or left unchanged,
+// plus a unified diff when --diff is set. Generated notes get the
+// frontmatter fields and tags from config first.
+func writeNote(path, content string) error {
+	content = addFrontmatter(path, content)
+	if opts.stdout {
+		fmt.Print(content)
+		return nil
+	}
+	key, err := noteKey(path)
+	if err != nil {
+		return err
+	}
+	if !opts.dryRun {
+		trackChanges()
+		if err := noteStorage().Write(key, []byte(content)); err != nil {
+			return err
+		}
+		notesWritten.Add(1)
+		queueCommit(path)
+		// A running backfill reports a count at the end instead.
+		if activeProgress == nil {
+			infof("Written: %s\n", path)
+		}
+		return nil
+	}
+
+	change, existing, err := storage.Compare(noteStorage(), key, []byte(content))
+	if err != nil {
+		return err
+	}
+	switch change {
+	case storage.Create:
+		printTo(os.Stdout, "Would create: %s\n", path)
+	case storage.Unchanged:
+		printTo(os.Stdout, "Unchanged: %s\n", path)
+		return nil
+	case storage.Update:
+		printTo(os.Stdout, "Would update: %s\n", path)
+	}
+	if opts.diff {
+		old := path
+		if existing == nil {
+			old = "/dev/null"
+		}
+		printTo(os.Stdout, "%s", diff.Unified(old, path, string(existing), content))
+	}
+	return nil
 }
 
 // getClient loads tokens (refreshing if needed) and returns an API client.
 func getClient() (*client.Client, error) {
-	token, err := auth.RefreshIfNeeded()
+	// rt stays nil, the default transport, for real API calls.
+	var rt http.RoundTripper
+	var token string
+	switch {
+	case opts.replay != "":
+		infof("Replaying WHOOP API responses from %s.\n", opts.replay)
+		token, rt = "replay", client.Replayer{Dir: opts.replay}
+	case opts.demo:
+		infof("Demo mode: using generated sample data, not a WHOOP account.\n")
+		token, rt = "demo", demo.Transport{}
+	case offline:
+		if opts.noCache {
+			return nil, errors.New("--offline reads only cached responses and cannot be combined with --no-cache")
+		}
+		token, rt = "offline", client.Cache{Dir: httpCacheDir(), Offline: true}
+	default:
+		var err error
+		if token, err = auth.RefreshIfNeeded(); err != nil {
+			return nil, fmt.Errorf("authentication error: %w\nRun 'whoop-garden auth' to authenticate.", err)
+		}
+	}
+	if opts.record != "" {
+		rt = client.Recorder{Dir: opts.record, Next: rt}
+	}
+	if ttl := httpCacheTTL(); ttl > 0 && !opts.noCache && !opts.demo && !offline && opts.replay == "" {
+		rt = client.Cache{Dir: httpCacheDir(), TTL: ttl, Next: rt}
+	}
+	c := client.NewClient(token)
+	if rt != nil {
+		c.SetTransport(rt)
+	}
+	c.SetTrace(opts.traceHTTP)
+	budget := cfg.MaxAPICalls
+	if opts.maxCalls > 0 {
+		budget = opts.maxCalls
+	}
+	c.SetBudget(budget)
+	return c, nil
+}
+
+// cacheClient returns a client that answers only from the response cache,
+// for store-only commands that make no API calls.
+func cacheClient() *client.Client {
+	c := client.NewClient("offline")
+	c.SetTransport(client.Cache{Dir: httpCacheDir(), Offline: true})
+	return c
+}
+
+// exitBudget stops a backfill whose API call budget ran out. Days written so
+// far are kept; resume is the command that picks up where this run stopped.
+func exitBudget(c *client.Client, resume string) {
+	if activeProgress != nil {
+		activeProgress.Done()
+	}
+	slog.Warn("API call budget reached; stopping", "calls", c.Calls())
+	flushRecords()
+	flushIndex()
+	commitNotes()
+	fmt.Fprintf(os.Stderr, "Resume with:\n  %s\n", resume)
+	os.Exit(0)
+}
+
+// backfill tracks a command that writes one daily note per day and shows
+// its progress: a redrawn bar on a terminal, a line every 10% otherwise, and
+// nothing under --quiet or --log-json.
+type backfill struct {
+	bar                      *progress.Bar
+	c                        *client.Client
+	written, skipped, failed int
+}
+
+// startBackfill begins reporting progress over days days. c may be nil
+// when the days come from somewhere other than the API.
+func startBackfill(c *client.Client, days int) *backfill {
+	w := infoWriter()
+	mode := progress.ModeLines
+	switch {
+	case opts.quiet, opts.logJSON:
+		mode = progress.ModeQuiet
+	case isTerminal(w):
+		mode = progress.ModeBar
+	}
+	b := &backfill{bar: progress.New(w, days, "days", mode), c: c}
+	if c != nil {
+		b.bar.Status = func() string {
+			return fmt.Sprintf("%s · %s", plural(c.Calls(), "API call"), plural(c.RateLimitPauses(), "rate-limit pause"))
+		}
+	}
+	activeProgress = b.bar
+	return b
+}
+
+func (b *backfill) wrote() { b.written++; b.bar.Step() }
+func (b *backfill) skip()  { b.skipped++; b.bar.Step() }
+func (b *backfill) fail()  { b.failed++; b.bar.Step() }
+
+// finish removes the bar and prints a summary of the run.
+func (b *backfill) finish() {
+	b.bar.Done()
+	activeProgress = nil
+	verb := "written"
+	if opts.dryRun || opts.stdout {
+		verb = "rendered"
+	}
+	summary := fmt.Sprintf("Done: %d %s, %d skipped (no data), %d failed in %s",
+		b.written, verb, b.skipped, b.failed, b.bar.Elapsed().Round(time.Second))
+	if b.c != nil {
+		summary += fmt.Sprintf(" (%s, %s)", plural(b.c.Calls(), "API call"), plural(b.c.RateLimitPauses(), "rate-limit pause"))
+	}
+	infof("%s.\n", summary)
+}
+
+// plural formats n with noun, adding an s unless n is 1.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// cacheDir returns the directory for local state: config cache_dir, then
+// $WHOOP_CACHE_DIR, then whoop-garden under the user cache directory. Demo
+// mode uses a demo directory inside it. Under --record and --replay it is a
+// fresh temporary directory, so every day is requested from the API (or the
+// recording) rather than read from earlier runs.
+func cacheDir() string {
+	if opts.record != "" || opts.replay != "" {
+		if runCache == "" {
+			var err error
+			if runCache, err = os.MkdirTemp("", "whoop-garden-"); err != nil {
+				fatal(err)
+			}
+		}
+		return runCache
+	}
+	dir := cfg.CacheDir
+	if dir == "" {
+		dir = os.Getenv("WHOOP_CACHE_DIR")
+	}
+	if dir == "" {
+		base, err := os.UserCacheDir()
+		if err != nil {
+			base = ".cache"
+		}
+		dir = filepath.Join(base, "whoop-garden")
+	}
+	if opts.demo {
+		dir = filepath.Join(dir, "demo")
+	}
+	return dir
+}
+
+// openStore opens the local day store under cacheDir().
+func openStore() (*store.Store, error) {
+	st, err := store.Open(filepath.Join(cacheDir(), "days"))
+	if err != nil {
+		return nil, err
+	}
+	st.OnRevision = recordRevision
+	st.Offline = offline
+	return st, nil
+}
+
+// fetchDay fetches one day from the API and saves it in the local store, so
+// rerender, serve, and sync see every day a command wrote. Under --offline
+// it reads the store instead, and the response cache for days the store
+// lacks.
+func fetchDay(c *client.Client, d time.Time) (fetch.DayData, error) {
+	st, err := openStore()
+	if err != nil {
+		return fetch.DayData{}, err
+	}
+	if !offline {
+		day, err := fetch.GetDayData(dayClient(c, d), d)
+		if err == nil {
+			if err := st.Save(day); err != nil {
+				slog.Warn("could not save to the local store", "date", d.Format("2006-01-02"), "err", err)
+			}
+		}
+		return day, err
+	}
+	day, err := st.GetDayData(c, d)
+	if errors.Is(err, client.ErrOffline) {
+		return day, fmt.Errorf("%s is in neither the local store nor the response cache; fetch it once online first", d.Format("2006-01-02"))
+	}
+	return day, err
+}
+
+// dayClient returns the client to fetch day d with: c itself once d's data
+// is final, or a copy that skips the response cache while WHOOP may still
+// be scoring it, so a morning run never gets an hour-old "no recovery yet".
+func dayClient(c *client.Client, d time.Time) *client.Client {
+	if client.Final(d.AddDate(0, 0, 1)) {
+		return c
+	}
+	return c.NoCache()
+}
+
+// fetchRange loads every day in p through the local store. Future days and
+// days that fail to load are included as empty placeholders.
+func fetchRange(c *client.Client, st *store.Store, p period.Period) []fetch.DayData {
+	today := time.Now()
+	var days []fetch.DayData
+	for d := p.Start; d.Before(p.End); d = d.AddDate(0, 0, 1) {
+		if d.After(today) {
+			days = append(days, fetch.DayData{Date: d})
+			continue
+		}
+		dd, err := st.GetDayData(c, d)
+		if errors.Is(err, client.ErrBudgetExhausted) {
+			fatalf("API call budget reached after %d requests at %s; raise --max-calls or max_api_calls.", c.Calls(), d.Format("2006-01-02"))
+		}
+		if err != nil {
+			slog.Warn("could not fetch", "date", d.Format("2006-01-02"), "err", err)
+			dd = fetch.DayData{Date: d}
+		}
+		days = append(days, dd)
+	}
+	return days
+}
+
+// resolveRange returns the window selected by --days or --from/--to. With
+// neither --from nor --to it covers the last days days, ending yesterday.
+func resolveRange(days int, fromStr, toStr string) (period.Period, error) {
+	if fromStr != "" || toStr != "" {
+		return parseDateRange(fromStr, toStr)
+	}
+	yesterday := time.Now().AddDate(0, 0, -1)
+	return period.Range(yesterday.AddDate(0, 0, -(days-1)), yesterday), nil
+}
+
+// parseDateRange builds the inclusive period from --from to --to. --from is
+// required; --to defaults to yesterday, the last day with a finished cycle.
+func parseDateRange(fromStr, toStr string) (period.Period, error) {
+	if fromStr == "" {
+		return period.Period{}, fmt.Errorf("--to requires --from")
+	}
+	from, err := parseDate(fromStr)
 	if err != nil {
-		return nil, fmt.Errorf("authentication error: %w\nRun 'whoop-garden auth' to authenticate.", err)
+		return period.Period{}, err
+	}
+	to := time.Now().AddDate(0, 0, -1)
+	if toStr != "" {
+		if to, err = parseDate(toStr); err != nil {
+			return period.Period{}, err
+		}
+	}
+	if to.Before(from) {
+		return period.Period{}, fmt.Errorf("--to %s is before --from %s", to.Format("2006-01-02"), from.Format("2006-01-02"))
 	}
-	return client.NewClient(token), nil
+	return period.Range(from, to), nil
 }
 
 // parseDate parses a YYYY-MM-DD date string or returns today.
@@ -163,78 +856,145 @@ func templatesDir() string {
 	return filepath.Join(filepath.Dir(exe), "templates")
 }
 
-// --- Subcommands ---
-
-func runAuth() {
-	if err := auth.StartAuthFlow(); err != nil {
-		fmt.Fprintln(os.Stderr, "auth failed:", err)
-		os.Exit(1)
+// templatePath returns the path of the named template, preferring the
+// configured template set and falling back to the default template.
+func templatePath(name string) string {
+	dir := templatesDir()
+	if cfg.TemplateSet != "" {
+		p := filepath.Join(dir, cfg.TemplateSet, name)
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
 	}
+	return filepath.Join(dir, name)
 }
 
+// --- Subcommands ---
+
 func runDaily(args []string) {
 	fs := flag.NewFlagSet("daily", flag.ExitOnError)
+	addGlobalFlags(fs)
 	dateStr := fs.String("date", "", "date in YYYY-MM-DD format (default: today)")
+	open := fs.Bool("open", false, "open the note in Obsidian after writing it")
 	_ = fs.Parse(args)
 
 	date, err := parseDate(*dateStr)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		fatal(err)
 	}
 
 	c, err := getClient()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		fatal(err)
 	}
 
-	fmt.Printf("Fetching data for %s...\n", date.Format("2006-01-02"))
-	dayData, err := fetch.GetDayData(c, date)
+	infof("Fetching data for %s...\n", date.Format("2006-01-02"))
+	path, err := writeDaily(c, date)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "fetch error:", err)
-		os.Exit(1)
+		fatal(err)
 	}
+	if *open {
+		if err := openNote(path); err != nil {
+			slog.Warn("could not open note in Obsidian", "err", err)
+		}
+	}
+}
 
-	tmplPath := filepath.Join(templatesDir(), "daily.md.tmpl")
-	content, err := render.RenderDaily(dayData, tmplPath)
+// writeDaily fetches, renders, and writes the daily note for date and
+// returns the note's path.
+func writeDaily(c *client.Client, date time.Time) (string, error) {
+	dayData, err := fetchDay(c, date)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "render error:", err)
-		os.Exit(1)
+		return "", fmt.Errorf("fetch error: %w", err)
 	}
+	return writeDay(c, dayData)
+}
 
-	dir, err := ensureOutputDir()
+// writeDay prepares and renders the daily note for day, writes it, and runs
+// dayWritten. It returns the note's path.
+func writeDay(c *client.Client, data fetch.DayData) (string, error) {
+	day := prepareDay(c, data)
+	content, err := render.RenderDaily(day, templatePath("daily.md.tmpl"))
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		return "", fmt.Errorf("render error: %w", err)
 	}
 
-	yearDir, err := ensureYearDir(dir, date.Year())
+	dir, err := ensureOutputDir()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		return "", err
 	}
-
-	outPath := filepath.Join(yearDir, fmt.Sprintf("daily-%s.md", date.Format("2006-01-02")))
-	if err := os.WriteFile(outPath, []byte(content), 0644); err != nil {
-		fmt.Fprintln(os.Stderr, "write error:", err)
-		os.Exit(1)
+	outPath := notePath(dir, "daily", day.Date.Format("2006-01-02"))
+	if err := writeNote(outPath, content); err != nil {
+		return "", fmt.Errorf("write error: %w", err)
 	}
+	dayWritten(day)
+	return outPath, nil
+}
 
-	fmt.Println("Written:", outPath)
+// dayWritten runs the hooks due after a daily note is written:
+// notifications and MQTT publishing, which act only on today, and the
+// journal link and records and index updates, which are left out of
+// --stdout output. Demo days get only the records and index updates, and
+// store-only runs skip notifications and MQTT.
+func dayWritten(day render.Day) {
+	// Sample data must not reach the phone, the broker, or the journal, and
+	// a note rebuilt from the store has nothing new to announce.
+	if !opts.demo && !storeOnly {
+		announceDay(day)
+		publishDay(day.DayData)
+	}
+	if !opts.demo {
+		linkJournal(day.DayData)
+	}
+	if cfg.Records && !opts.stdout {
+		seeDay(day.DayData)
+		recordsDue = true
+	}
+	if cfg.Index && !opts.stdout {
+		indexDue = true
+	}
+	if activeMetrics != nil {
+		activeMetrics.observeDay(day.DayData)
+	}
 }
 
 func runWeekly(args []string) {
 	fs := flag.NewFlagSet("weekly", flag.ExitOnError)
+	addGlobalFlags(fs)
 	dateStr := fs.String("date", "", "any date within the target week (default: this week)")
+	onMissing := fs.String("on-missing", "skip", "days that fail to fetch: skip (leave out of averages), zero (count as zero), or fail")
+	open := fs.Bool("open", false, "open the note in Obsidian after writing it")
 	_ = fs.Parse(args)
 
 	date, err := parseDate(*dateStr)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		fatal(err)
+	}
+	switch *onMissing {
+	case "skip", "zero", "fail":
+	default:
+		fatalf("invalid --on-missing %q: want skip, zero, or fail", *onMissing)
+	}
+
+	c, err := getClient()
+	if err != nil {
+		fatal(err)
+	}
+
+	outPath, err := writeWeekly(c, date, *onMissing)
+	if err != nil {
+		fatal(err)
 	}
+	if *open {
+		if err := openNote(outPath); err != nil {
+			slog.Warn("could not open note in Obsidian", "err", err)
+		}
+	}
+}
 
+// writeWeekly writes the weekly note for the week containing date and
+// returns its path. onMissing is as for weekly's --on-missing flag.
+func writeWeekly(c *client.Client, date time.Time, onMissing string) (string, error) {
 	// Find Monday of the week.
 	weekday := int(date.Weekday())
 	if weekday == 0 {
@@ -244,236 +1004,352 @@ func runWeekly(args []string) {
 	monday = time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, monday.Location())
 	sunday := monday.AddDate(0, 0, 7)
 
-	c, err := getClient()
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
-	}
-
-	fmt.Printf("Fetching week %s → %s...\n", monday.Format("2006-01-02"), sunday.AddDate(0, 0, -1).Format("2006-01-02"))
+	infof("Fetching week %s → %s...\n", monday.Format("2006-01-02"), sunday.AddDate(0, 0, -1).Format("2006-01-02"))
 
 	today := time.Now()
 	var days []fetch.DayData
+	var missing []string
 	for d := monday; d.Before(sunday); d = d.AddDate(0, 0, 1) {
 		if d.After(today) {
 			days = append(days, fetch.DayData{Date: d})
 			continue
 		}
-		dayData, err := fetch.GetDayData(c, d)
+		dayData, err := fetchDay(c, d)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "warning: could not fetch %s: %v\n", d.Format("2006-01-02"), err)
+			if onMissing == "fail" {
+				return "", fmt.Errorf("could not fetch %s: %w", d.Format("2006-01-02"), err)
+			}
+			slog.Warn("could not fetch", "date", d.Format("2006-01-02"), "err", err)
 			dayData = fetch.DayData{Date: d}
+			missing = append(missing, d.Format("2006-01-02"))
 		}
 		days = append(days, dayData)
 	}
 
-	stats := render.BuildWeekStats(days)
-	tmplPath := filepath.Join(templatesDir(), "weekly.md.tmpl")
+	// The three weeks before are read through the local store: the last
+	// for comparison, all three for the mileage trend.
+	history := dayHistory(c, monday, 21)
+	prev := history
+	if len(prev) > 7 {
+		prev = prev[len(prev)-7:]
+	}
+	stats := render.BuildWeekComparison(days, prev)
+	stats.Mileage = render.BuildMileage(days, history)
+	stats.Missing = missing
+	if cfg.Streaks {
+		// As of the week's last day so far. Days that failed to fetch keep
+		// their stored copy.
+		last := monday
+		for _, d := range days {
+			if d.Date.After(today) {
+				break
+			}
+			if !slices.Contains(missing, d.Date.Format("2006-01-02")) {
+				seeDay(d)
+			}
+			last = d.Date
+		}
+		stats.Streaks = streaksAsOf(last)
+	}
+	if cfg.TrainingLoad {
+		// As of the week's last day so far, over the 28 days ending on it.
+		n := 0
+		for n < len(days) && !days[n].Date.After(today) {
+			n++
+		}
+		if l, ok := analytics.TrainingLoad(append(history, days[:n]...)); ok {
+			stats.TrainingLoad = &l
+		}
+	}
+	if onMissing == "zero" {
+		stats.ZeroMissing()
+	}
+	tmplPath := templatePath("weekly.md.tmpl")
 	content, err := render.RenderWeeklyFromStats(stats, tmplPath)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "render error:", err)
-		os.Exit(1)
+		return "", fmt.Errorf("render error: %w", err)
 	}
 
 	dir, err := ensureOutputDir()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		return "", err
 	}
 
-	isoYear, isoWeek := monday.ISOWeek()
-	yearDir, err := ensureYearDir(dir, isoYear)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	outPath := notePath(dir, "weekly", render.ISOWeekStr(monday))
+	if err := writeNote(outPath, content); err != nil {
+		return "", fmt.Errorf("write error: %w", err)
 	}
-
-	outPath := filepath.Join(yearDir, fmt.Sprintf("weekly-%d-W%02d.md", isoYear, isoWeek))
-	if err := os.WriteFile(outPath, []byte(content), 0644); err != nil {
-		fmt.Fprintln(os.Stderr, "write error:", err)
-		os.Exit(1)
+	if cfg.Index && !opts.stdout {
+		indexDue = true
 	}
-
-	fmt.Println("Written:", outPath)
+	return outPath, nil
 }
 
 func runPersona(args []string) {
 	fs := flag.NewFlagSet("persona", flag.ExitOnError)
+	addGlobalFlags(fs)
 	days := fs.Int("days", 30, "number of days to include")
+	dateStr := fs.String("date", "", "build the persona as of this date, YYYY-MM-DD, from the days before it (default: today)")
+	write := fs.Bool("write", false, "write the persona to the vault (persona_path) instead of printing it")
 	_ = fs.Parse(args)
 
+	if *days < 1 {
+		fatal(errors.New("--days must be at least 1"))
+	}
+	end, err := parseDate(*dateStr)
+	if err != nil {
+		fatal(err)
+	}
+	var outPath string
+	if *write {
+		if outPath, err = personaPath(); err != nil {
+			fatal(err)
+		}
+	}
+
 	c, err := getClient()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		fatal(err)
+	}
+	st, err := openStore()
+	if err != nil {
+		fatal(err)
 	}
 
-	end := time.Now()
-	start := end.AddDate(0, 0, -(*days))
+	// The window ends the day before --date; the one before it is fetched
+	// too so the persona can show which way each metric is moving.
+	last := end.AddDate(0, 0, -1)
+	p := period.Range(last.AddDate(0, 0, -(*days-1)), last)
+	prior := period.Range(p.Start.AddDate(0, 0, -*days), p.Start.AddDate(0, 0, -1))
 
-	fmt.Printf("Fetching %d days of data (%s → %s)...\n",
-		*days, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	infof("Fetching %d days of data (%s → %s) and the %d before...\n",
+		*days, p.Start.Format("2006-01-02"), p.Last().Format("2006-01-02"), *days)
 
-	var dayData []fetch.DayData
-	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
-		dd, err := fetch.GetDayData(c, d)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "warning: could not fetch %s: %v\n", d.Format("2006-01-02"), err)
-			dd = fetch.DayData{Date: d}
+	data, priorData := fetchRange(c, st, p), fetchRange(c, st, prior)
+	pd := render.BuildPersonaComparison(data, priorData)
+	window := cfg.HRVBaselineDays
+	if window == 0 {
+		window = 30
+	}
+	if b, ok := analytics.HRVBaseline(append(priorData, data...), window); ok {
+		pd.HRVBaseline = &b
+	}
+	if l, ok := analytics.TrainingLoad(append(priorData, data...)); ok {
+		pd.TrainingLoad = &l
+	}
+	if cfg.IncludeProfile {
+		if pd.Profile, err = fetch.GetUserProfile(c); err != nil {
+			slog.Warn("could not fetch profile", "err", err)
+		}
+		if pd.Body, err = fetch.GetBodyMeasurements(c); err != nil {
+			slog.Warn("could not fetch body measurements", "err", err)
 		}
-		dayData = append(dayData, dd)
 	}
-
-	content, err := render.RenderPersonaSection(dayData)
+	content, err := render.RenderPersonaFromStats(pd)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "render error:", err)
-		os.Exit(1)
+		fatalf("render error: %w", err)
 	}
 
-	if vault := os.Getenv("OBSIDIAN_VAULT_PATH"); vault != "" {
-		outPath := filepath.Join(vault, "01-ai-brain", "context-packs", "WHOOP Health Persona.md")
-		if err := os.WriteFile(outPath, []byte(content), 0644); err != nil {
-			fmt.Fprintln(os.Stderr, "write error:", err)
-			os.Exit(1)
-		}
-		fmt.Println("Written:", outPath)
-	} else {
+	if outPath == "" {
 		fmt.Println(content)
+		return
+	}
+	if err := writeNote(outPath, content); err != nil {
+		fatalf("write error: %w", err)
+	}
+}
+
+// personaPath returns the file persona --write writes to.
+func personaPath() (string, error) {
+	p := cfg.PersonaPath
+	if p == "" {
+		p = filepath.Join("Health", "WHOOP", "Persona.md")
+	}
+	if filepath.IsAbs(p) {
+		return p, nil
+	}
+	vault := os.Getenv("OBSIDIAN_VAULT_PATH")
+	if vault == "" {
+		return "", fmt.Errorf("persona --write needs OBSIDIAN_VAULT_PATH to place %s, or an absolute persona_path in config.json", p)
 	}
+	return filepath.Join(vault, p), nil
 }
 
 func runFetchAll(args []string) {
 	fs := flag.NewFlagSet("fetch-all", flag.ExitOnError)
+	addGlobalFlags(fs)
 	days := fs.Int("days", 30, "number of days to fetch")
+	fromStr := fs.String("from", "", "first date to fetch, YYYY-MM-DD (overrides --days)")
+	toStr := fs.String("to", "", "last date to fetch, YYYY-MM-DD (default: yesterday; requires --from)")
+	resume := fs.Bool("resume", false, "continue the last interrupted run, retrying its failed days first")
 	_ = fs.Parse(args)
 
+	var cp fetchCheckpoint
+	if *resume {
+		var found bool
+		var err error
+		if cp, found, err = loadFetchCheckpoint(); err != nil {
+			fatal(err)
+		}
+		if !found {
+			fatalf("nothing to resume: no checkpoint at %s", fetchCheckpointPath())
+		}
+	} else {
+		p, err := resolveRange(*days, *fromStr, *toStr)
+		if err != nil {
+			fatal(err)
+		}
+		cp = fetchCheckpoint{From: p.Start.Format("2006-01-02"), To: p.Last().Format("2006-01-02")}
+	}
+	dates, err := cp.dates()
+	if err != nil {
+		fatal(err)
+	}
+	if len(dates) == 0 {
+		infof("Nothing left to fetch.\n")
+		cp.save()
+		return
+	}
+
 	c, err := getClient()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		fatal(err)
 	}
 
 	dir, err := ensureOutputDir()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		fatal(err)
 	}
 
-	tmplPath := filepath.Join(templatesDir(), "daily.md.tmpl")
-	end := time.Now()
-	start := end.AddDate(0, 0, -(*days))
+	tmplPath := templatePath("daily.md.tmpl")
 
-	fmt.Printf("Fetching and writing %d daily notes...\n", *days)
+	if *resume {
+		infof("Resuming %s → %s: %d failed day(s) to retry, %d not yet fetched...\n", cp.From, cp.To, len(cp.Failed), len(dates)-len(cp.Failed))
+	} else {
+		infof("Fetching and writing %d daily notes...\n", len(dates))
+	}
+	cp.save()
+	b := startBackfill(c, len(dates))
 
-	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
-		dayData, err := fetch.GetDayData(c, d)
+	for _, d := range dates {
+		dayData, err := fetchDay(c, d)
+		if errors.Is(err, client.ErrBudgetExhausted) {
+			exitBudget(c, "whoop-garden fetch-all --resume")
+		}
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "warning: could not fetch %s: %v\n", d.Format("2006-01-02"), err)
+			slog.Warn("could not fetch", "date", d.Format("2006-01-02"), "err", err)
+			b.fail()
+			cp.done(d, false)
 			continue
 		}
 		if dayData.Cycle == nil {
-			fmt.Printf("Skipped: %s (no data)\n", d.Format("2006-01-02"))
-			time.Sleep(500 * time.Millisecond)
-			continue
-		}
-
-		content, err := render.RenderDaily(dayData, tmplPath)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "warning: could not render %s: %v\n", d.Format("2006-01-02"), err)
+			infof("Skipped: %s (no data)\n", d.Format("2006-01-02"))
+			b.skip()
+			cp.done(d, true)
 			continue
 		}
 
-		yearDir, err := ensureYearDir(dir, d.Year())
+		prepared := prepareDay(c, dayData)
+		content, err := render.RenderDaily(prepared, tmplPath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "warning: could not create year dir for %s: %v\n", d.Format("2006-01-02"), err)
+			slog.Warn("could not render", "date", d.Format("2006-01-02"), "err", err)
+			b.fail()
+			cp.done(d, false)
 			continue
 		}
 
-		outPath := filepath.Join(yearDir, fmt.Sprintf("daily-%s.md", d.Format("2006-01-02")))
-		if err := os.WriteFile(outPath, []byte(content), 0644); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: could not write %s: %v\n", outPath, err)
+		outPath := notePath(dir, "daily", d.Format("2006-01-02"))
+		if err := writeNote(outPath, content); err != nil {
+			slog.Warn("could not write", "path", outPath, "err", err)
+			b.fail()
+			cp.done(d, false)
 			continue
 		}
-
-		fmt.Println("Written:", outPath)
-		time.Sleep(500 * time.Millisecond)
+		b.wrote()
+		dayWritten(prepared)
+		cp.done(d, true)
 	}
 
-	fmt.Println("Done.")
+	b.finish()
+	if len(cp.Failed) > 0 && !opts.dryRun && !opts.stdout {
+		infof("Retry the %d failed day(s) with:\n  whoop-garden fetch-all --resume\n", len(cp.Failed))
+	}
 }
 
 func runCatchUp(args []string) {
 	fs := flag.NewFlagSet("catch-up", flag.ExitOnError)
+	addGlobalFlags(fs)
 	days := fs.Int("days", 30, "number of days to check")
 	_ = fs.Parse(args)
 
 	dir, err := ensureOutputDir()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		fatal(err)
 	}
 
-	tmplPath := filepath.Join(templatesDir(), "daily.md.tmpl")
+	tmplPath := templatePath("daily.md.tmpl")
 	end := time.Now()
 	start := end.AddDate(0, 0, -(*days))
 
 	// Collect missing dates first so we can report the plan.
 	var missing []time.Time
 	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
-		yearDir := filepath.Join(dir, fmt.Sprintf("%d", d.Year()))
-		outPath := filepath.Join(yearDir, fmt.Sprintf("daily-%s.md", d.Format("2006-01-02")))
-		if _, err := os.Stat(outPath); os.IsNotExist(err) {
+		outPath := notePath(dir, "daily", d.Format("2006-01-02"))
+		exists, err := noteExists(outPath)
+		if err != nil {
+			fatal(err)
+		}
+		if !exists {
 			missing = append(missing, d)
 		}
 	}
 
 	if len(missing) == 0 {
-		fmt.Println("All caught up — no missing notes.")
+		infof("All caught up — no missing notes.\n")
 		return
 	}
 
-	fmt.Printf("Found %d missing note(s), fetching...\n", len(missing))
+	infof("Found %d missing note(s), fetching...\n", len(missing))
 
 	c, err := getClient()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		fatal(err)
 	}
 
+	b := startBackfill(c, len(missing))
 	for _, d := range missing {
-		dayData, err := fetch.GetDayData(c, d)
+		dayData, err := fetchDay(c, d)
+		if errors.Is(err, client.ErrBudgetExhausted) {
+			exitBudget(c, fmt.Sprintf("whoop-garden catch-up --days %d", *days))
+		}
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "warning: could not fetch %s: %v\n", d.Format("2006-01-02"), err)
-			time.Sleep(500 * time.Millisecond)
+			slog.Warn("could not fetch", "date", d.Format("2006-01-02"), "err", err)
+			b.fail()
 			continue
 		}
 		if dayData.Cycle == nil {
-			fmt.Printf("Skipped: %s (no data)\n", d.Format("2006-01-02"))
-			time.Sleep(500 * time.Millisecond)
+			infof("Skipped: %s (no data)\n", d.Format("2006-01-02"))
+			b.skip()
 			continue
 		}
 
-		content, err := render.RenderDaily(dayData, tmplPath)
+		prepared := prepareDay(c, dayData)
+		content, err := render.RenderDaily(prepared, tmplPath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "warning: could not render %s: %v\n", d.Format("2006-01-02"), err)
+			slog.Warn("could not render", "date", d.Format("2006-01-02"), "err", err)
+			b.fail()
 			continue
 		}
 
-		yearDir, err := ensureYearDir(dir, d.Year())
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "warning: could not create year dir for %s: %v\n", d.Format("2006-01-02"), err)
+		outPath := notePath(dir, "daily", d.Format("2006-01-02"))
+		if err := writeNote(outPath, content); err != nil {
+			slog.Warn("could not write", "path", outPath, "err", err)
+			b.fail()
 			continue
 		}
-
-		outPath := filepath.Join(yearDir, fmt.Sprintf("daily-%s.md", d.Format("2006-01-02")))
-		if err := os.WriteFile(outPath, []byte(content), 0644); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: could not write %s: %v\n", outPath, err)
-			continue
-		}
-
-		fmt.Println("Written:", outPath)
-		time.Sleep(500 * time.Millisecond)
+		b.wrote()
+		dayWritten(prepared)
 	}
-
-	fmt.Println("Done.")
+	b.finish()
+	alertAfterWrite(c)
 }
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/config"
+	"github.com/benstraw/whoop-garden/internal/storage"
+)
+
+const generated = "---\ndate: 2026-02-10\ngenerator: whoop-garden v1.0.0\n---\n\n# Tuesday\n"
+
+// useMemoryNotes points writeNote and readNote at a fresh in-memory backend
+// and resets the config and global flags for the length of the test.
+func useMemoryNotes(t *testing.T) *storage.Memory {
+	t.Helper()
+	savedCfg, savedOpts, savedNotes := cfg, opts, notes
+	savedVault, savedOffline, savedStoreOnly := vaultStorage, offline, storeOnly
+	t.Cleanup(func() {
+		cfg, opts, notes = savedCfg, savedOpts, savedNotes
+		vaultStorage, offline, storeOnly = savedVault, savedOffline, savedStoreOnly
+	})
+
+	cfg = config.Config{CacheDir: t.TempDir(), OutputDir: t.TempDir()}
+	opts.quiet, opts.dryRun, opts.stdout = true, false, false
+	m := storage.NewMemory()
+	notes = m
+	return m
+}
+
+func TestWriteNote_ReadNote(t *testing.T) {
+	m := useMemoryNotes(t)
+	path := notePath(outputDir(), "daily", "2026-02-10")
+
+	if _, err := readNote(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("readNote before write: got %v, want fs.ErrNotExist", err)
+	}
+	if err := writeNote(path, generated); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readNote(path)
+	if err != nil || string(got) != generated {
+		t.Errorf("readNote = %q, %v; want the note as written", got, err)
+	}
+	if ok, _ := m.Exists(path); !ok {
+		t.Errorf("note not stored under its path %s", path)
+	}
+}
+
+func TestWriteNote_DryRun(t *testing.T) {
+	m := useMemoryNotes(t)
+	opts.dryRun = true
+	path := notePath(outputDir(), "daily", "2026-02-10")
+
+	if err := writeNote(path, generated); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := m.Exists(path); ok {
+		t.Error("--dry-run wrote the note")
+	}
+}
+
+func TestWriteNote_Frontmatter(t *testing.T) {
+	useMemoryNotes(t)
+	cfg.Frontmatter.Fields = map[string]any{"source": "whoop"}
+	cfg.Frontmatter.Tags = []string{"fitness/whoop"}
+	path := notePath(outputDir(), "daily", "2026-02-10")
+
+	if err := writeNote(path, generated); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := readNote(path)
+	want := "---\ndate: 2026-02-10\ngenerator: whoop-garden v1.0.0\ntags:\n  - fitness/whoop\nsource: \"whoop\"\n---\n\n# Tuesday\n"
+	if string(got) != want {
+		t.Errorf("written note =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFetchAll_Rerender(t *testing.T) {
+	useMemoryNotes(t)
+	runFetchAll([]string{"--demo", "--days", "3"})
+
+	// Rerender into empty storage, so any note found was written from the
+	// days fetch-all stored.
+	m := storage.NewMemory()
+	notes = m
+	runRerender([]string{"--demo"})
+	day := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	path := notePath(outputDir(), "daily", day)
+	if ok, _ := m.Exists(path); !ok {
+		t.Errorf("rerender after fetch-all did not write %s", path)
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/analytics"
+	"github.com/benstraw/whoop-garden/internal/client"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/prom"
+)
+
+// dayMetric is a headline number of a day, published to MQTT (announced
+// to Home Assistant with name, unit, and icon) and exported as a
+// Prometheus gauge.
+type dayMetric struct {
+	key   string
+	name  string
+	unit  string
+	icon  string
+	prom  string
+	help  string
+	value func(fetch.DayData) (float64, bool)
+}
+
+// dayMetrics lists every dayMetric. value reports false until WHOOP has
+// scored the metric.
+var dayMetrics = []dayMetric{
+	{"recovery", "Recovery", "%", "mdi:battery-heart-variant", "whoop_recovery_percent", "Latest recovery score.", func(d fetch.DayData) (float64, bool) {
+		if r := d.Recovery; r != nil && r.ScoreState == "SCORED" {
+			return r.Score.RecoveryScore, true
+		}
+		return 0, false
+	}},
+	{"hrv", "HRV", "ms", "mdi:heart-pulse", "whoop_hrv_milliseconds", "Latest heart rate variability (RMSSD).", func(d fetch.DayData) (float64, bool) {
+		if r := d.Recovery; r != nil && r.ScoreState == "SCORED" {
+			return r.Score.HrvRmssdMilli, true
+		}
+		return 0, false
+	}},
+	{"rhr", "Resting heart rate", "bpm", "mdi:heart", "whoop_resting_heart_rate_bpm", "Latest resting heart rate.", func(d fetch.DayData) (float64, bool) {
+		if r := d.Recovery; r != nil && r.ScoreState == "SCORED" {
+			return r.Score.RestingHeartRate, true
+		}
+		return 0, false
+	}},
+	{"strain", "Strain", "", "mdi:run", "whoop_strain", "Latest day strain.", func(d fetch.DayData) (float64, bool) {
+		if c := d.Cycle; c != nil && c.ScoreState == "SCORED" {
+			return c.Score.Strain, true
+		}
+		return 0, false
+	}},
+	{"sleep_performance", "Sleep performance", "%", "mdi:sleep", "whoop_sleep_performance_percent", "Latest main sleep performance.", func(d fetch.DayData) (float64, bool) {
+		if s := analytics.PrimarySleep(d.Sleeps); s != nil && s.ScoreState == "SCORED" {
+			return s.Score.SleepPerformance, true
+		}
+		return 0, false
+	}},
+	{"sleep_hours", "Sleep", "h", "mdi:bed-clock", "whoop_sleep_hours", "Latest main sleep time asleep.", func(d fetch.DayData) (float64, bool) {
+		if s := analytics.PrimarySleep(d.Sleeps); s != nil && s.ScoreState == "SCORED" {
+			return float64(analytics.AsleepMillis(*s)) / float64(time.Hour/time.Millisecond), true
+		}
+		return 0, false
+	}},
+}
+
+// notesWritten counts notes written by this process; see writeNote.
+var notesWritten atomic.Int64
+
+// daemonMetrics accumulates the pipeline counters exported on /metrics,
+// which are served only to requests bearing token.
+type daemonMetrics struct {
+	token           string
+	mu              sync.Mutex
+	apiCalls        int
+	rateLimitPauses int
+	refreshes       int
+	failures        int
+	lastSuccess     time.Time
+	latest          *fetch.DayData
+}
+
+// observeRefresh adds one refresh made with c to the counters.
+func (m *daemonMetrics) observeRefresh(c *client.Client, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.refreshes++
+	if c != nil {
+		m.apiCalls += c.Calls()
+		m.rateLimitPauses += c.RateLimitPauses()
+	}
+	if err != nil {
+		m.failures++
+		return
+	}
+	m.lastSuccess = time.Now()
+}
+
+// observeDay records day as the latest data when it is today's.
+func (m *daemonMetrics) observeDay(day fetch.DayData) {
+	if day.Date.Format("2006-01-02") != time.Now().Format("2006-01-02") {
+		return
+	}
+	m.mu.Lock()
+	m.latest = &day
+	m.mu.Unlock()
+}
+
+// ServeHTTP implements http.Handler, serving the metrics in the Prometheus
+// text format. Gauges for metrics WHOOP has not scored yet are left out.
+func (m *daemonMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+m.token)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	m.mu.Lock()
+	var ms []prom.Metric
+	if m.latest != nil {
+		for _, dm := range dayMetrics {
+			if v, ok := dm.value(*m.latest); ok {
+				ms = append(ms, prom.Metric{Name: dm.prom, Help: dm.help, Type: prom.Gauge, Value: v})
+			}
+		}
+	}
+	ms = append(ms,
+		prom.Metric{Name: "whoop_garden_api_calls_total", Help: "WHOOP API requests made, including retries.", Type: prom.Counter, Value: float64(m.apiCalls)},
+		prom.Metric{Name: "whoop_garden_api_retries_total", Help: "WHOOP API requests retried after a 429 response.", Type: prom.Counter, Value: float64(m.rateLimitPauses)},
+		prom.Metric{Name: "whoop_garden_notes_written_total", Help: "Notes written.", Type: prom.Counter, Value: float64(notesWritten.Load())},
+		prom.Metric{Name: "whoop_garden_refreshes_total", Help: "Refreshes attempted.", Type: prom.Counter, Value: float64(m.refreshes)},
+		prom.Metric{Name: "whoop_garden_refresh_failures_total", Help: "Refreshes that failed.", Type: prom.Counter, Value: float64(m.failures)},
+	)
+	if !m.lastSuccess.IsZero() {
+		ms = append(ms, prom.Metric{Name: "whoop_garden_last_success_timestamp_seconds", Help: "Unix time of the last successful refresh.", Type: prom.Gauge, Value: float64(m.lastSuccess.Unix())})
+	}
+	m.mu.Unlock()
+
+	w.Header().Set("Content-Type", prom.ContentType)
+	prom.Write(w, ms)
+}
+
+// activeMetrics collects daemon metrics while the daemon runs; nil
+// otherwise.
+var activeMetrics *daemonMetrics
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDaemonMetrics_Token(t *testing.T) {
+	m := &daemonMetrics{token: "secret"}
+	for auth, want := range map[string]int{
+		"":              http.StatusUnauthorized,
+		"Bearer ":       http.StatusUnauthorized,
+		"Bearer wrong":  http.StatusUnauthorized,
+		"Bearer secret": http.StatusOK,
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if auth != "" {
+			r.Header.Set("Authorization", auth)
+		}
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, r)
+		if w.Code != want {
+			t.Errorf("Authorization %q: status %d, want %d", auth, w.Code, want)
+		}
+		if want == http.StatusOK && !strings.Contains(w.Body.String(), "whoop_garden_refreshes_total 0") {
+			t.Errorf("metrics missing refresh counter:\n%s", w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/links"
+)
+
+// runMigrate moves generated notes to where the configured layout and
+// output directory put them, and rewrites the wikilinks between them to
+// match, in the configured link style.
+func runMigrate(args []string) {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	addGlobalFlags(fs)
+	from := fs.String("from", "", "previous output directory to move notes out of (default: the output directory)")
+	_ = fs.Parse(args)
+
+	if cfg.Storage.Remote() {
+		fatalf("migrate moves files in a local directory and does not support %s storage", cfg.Storage)
+	}
+	dir := outputDir()
+	src := dir
+	if *from != "" {
+		src = *from
+		if _, err := os.Stat(src); err != nil {
+			fatal(err)
+		}
+	}
+
+	moves, err := planMoves(src, dir)
+	if err != nil {
+		fatalf("scan notes: %w", err)
+	}
+	moved, failed := 0, 0
+	var emptied []string
+	for _, m := range moves {
+		if m.conflict {
+			fmt.Printf("skip %s: %s already exists\n", m.from, m.to)
+			failed++
+			continue
+		}
+		if opts.dryRun {
+			fmt.Printf("move %s → %s\n", m.from, m.to)
+			moved++
+			continue
+		}
+		if err := moveFile(m.from, m.to); err != nil {
+			slog.Warn("could not move note", "path", m.from, "err", err)
+			failed++
+			continue
+		}
+		emptied = append(emptied, filepath.Dir(m.from))
+		moved++
+	}
+	removeEmptyParents(emptied, src)
+
+	// Under --dry-run nothing moved; the links are checked where they are.
+	scan := dir
+	if opts.dryRun {
+		scan = src
+	}
+	paths, err := generatedNotes(scan)
+	if err != nil {
+		fatalf("scan notes: %w", err)
+	}
+	l := noteLinker()
+	// The notes to relink, by path, with their vault paths.
+	relink := map[string]string{}
+	for _, p := range paths {
+		rel, err := filepath.Rel(scan, p)
+		if err != nil {
+			fatal(err)
+		}
+		relink[p] = l.Path(strings.TrimSuffix(filepath.ToSlash(rel), ".md"))
+	}
+	// Journal notes link to their day's daily note; see linkJournal.
+	if cfg.Journal.Path != "" {
+		named, err := links.Scan(scan)
+		if err != nil {
+			fatalf("scan notes: %w", err)
+		}
+		for _, n := range named {
+			date, err := time.Parse("2006-01-02", n.Key)
+			if n.Kind != "daily" || err != nil {
+				continue
+			}
+			p, err := journalPath(date)
+			if _, seen := relink[p]; err != nil || seen || !exists(p) {
+				continue
+			}
+			relink[p] = journalVaultPath(p)
+			paths = append(paths, p)
+		}
+	}
+
+	relinked := 0
+	for _, p := range paths {
+		data, err := readNote(p)
+		if err != nil {
+			fatal(err)
+		}
+		content := links.Retarget(string(data), relink[p], l)
+		if content == string(data) {
+			continue
+		}
+		relinked++
+		if opts.dryRun {
+			continue
+		}
+		if err := writeNote(p, content); err != nil {
+			slog.Warn("could not update links", "path", p, "err", err)
+			failed++
+		}
+	}
+
+	if opts.dryRun {
+		fmt.Printf("Would move %s and update links in %s.\n", plural(moved, "note"), plural(relinked, "note"))
+		return
+	}
+	fmt.Printf("Moved %s and updated links in %s.\n", plural(moved, "note"), plural(relinked, "note"))
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// move is a generated note that is not where it belongs.
+type move struct {
+	from, to string
+	conflict bool // another file is already at to
+}
+
+// planMoves lists the generated notes under src that belong elsewhere under
+// dir: daily, weekly, and monthly notes go where the configured layout puts
+// them, and other notes, such as Records.md, keep their path relative to
+// the output directory. Those already in dir, as when src contains it, stay
+// where they are.
+func planMoves(src, dir string) ([]move, error) {
+	paths, err := generatedNotes(src)
+	if err != nil {
+		return nil, err
+	}
+	named, err := links.Scan(src)
+	if err != nil {
+		return nil, err
+	}
+	byPath := map[string]links.Note{}
+	for _, n := range named {
+		byPath[n.Path] = n
+	}
+
+	var moves []move
+	for _, p := range paths {
+		to := p
+		if n, ok := byPath[p]; ok {
+			to = notePath(dir, n.Kind, n.Key)
+		} else if rel, err := filepath.Rel(src, p); err == nil && !within(dir, p) {
+			to = filepath.Join(dir, rel)
+		}
+		if filepath.Clean(to) == filepath.Clean(p) {
+			continue
+		}
+		_, err := os.Stat(to)
+		moves = append(moves, move{from: p, to: to, conflict: err == nil})
+	}
+	return moves, nil
+}
+
+// within reports whether path is inside dir.
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// removeEmptyParents removes each of dirs that is now empty, and then its
+// parents in turn, stopping at root, which is kept. Directories migrate did
+// not empty are left alone, even if they were empty before.
+func removeEmptyParents(dirs []string, root string) {
+	root = filepath.Clean(root)
+	for _, d := range dirs {
+		for d = filepath.Clean(d); d != root && within(root, d); d = filepath.Dir(d) {
+			if os.Remove(d) != nil { // fails unless d is empty
+				break
+			}
+		}
+	}
+}
+
+// moveFile renames from to to, creating to's folder. Across file systems,
+// where renaming fails, it copies the file and removes the original.
+func moveFile(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(from, to); err == nil {
+		return nil
+	}
+	data, err := os.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(to, data, 0644); err != nil {
+		return err
+	}
+	return os.Remove(from)
+}
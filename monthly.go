@@ -0,0 +1,213 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/changelog"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/period"
+	"github.com/benstraw/whoop-garden/internal/render"
+)
+
+// noteTemplates are the file templates whose edits are recorded in the
+// changelog.
+var noteTemplates = []string{"daily.md.tmpl", "weekly.md.tmpl", "monthly.md.tmpl", "quarterly.md.tmpl", "compare.md.tmpl", "correlate.md.tmpl", "records.md.tmpl", "index.md.tmpl", "home.md.tmpl"}
+
+func runMonthly(args []string) {
+	fs := flag.NewFlagSet("monthly", flag.ExitOnError)
+	addGlobalFlags(fs)
+	monthStr := fs.String("month", "", "month in YYYY-MM format (default: this month)")
+	_ = fs.Parse(args)
+
+	month := time.Now()
+	if *monthStr != "" {
+		t, err := time.Parse("2006-01", *monthStr)
+		if err != nil {
+			fatalf("invalid month %q (expected YYYY-MM)", *monthStr)
+		}
+		month = t
+	}
+	p := period.Month(month)
+
+	c, err := getClient()
+	if err != nil {
+		fatal(err)
+	}
+	st, err := openStore()
+	if err != nil {
+		fatal(err)
+	}
+
+	infof("Fetching %s (%d days)...\n", p.Label, p.Days())
+	days := fetchRange(c, st, p)
+
+	log, err := openChangelog()
+	if err != nil {
+		fatal(err)
+	}
+	data := render.MonthlyData{
+		Month:         p.Start.Format("2006-01"),
+		Start:         p.Start,
+		Stats:         render.BuildWeekStats(days),
+		Changes:       log.Between(p.Start, p.End),
+		TrackingSince: log.Since,
+	}
+	content, err := render.RenderMonthly(data, templatePath("monthly.md.tmpl"))
+	if err != nil {
+		fatalf("render error: %w", err)
+	}
+
+	dir, err := ensureOutputDir()
+	if err != nil {
+		fatal(err)
+	}
+	outPath := notePath(dir, "monthly", data.Month)
+	if err := writeNote(outPath, content); err != nil {
+		fatalf("write error: %w", err)
+	}
+	if cfg.Index && !opts.stdout {
+		indexDue = true
+	}
+}
+
+// changesMu serializes read-modify-write cycles of the changelog file.
+var changesMu sync.Mutex
+
+// trackOnce limits template and config tracking to once per run.
+var trackOnce sync.Once
+
+func openChangelog() (*changelog.Log, error) {
+	if err := os.MkdirAll(cacheDir(), 0700); err != nil {
+		return nil, fmt.Errorf("create cache dir: %w", err)
+	}
+	return changelog.Open(filepath.Join(cacheDir(), "changelog.json"))
+}
+
+// updateChangelog applies fn to the changelog and saves it. Failures are
+// only warned about: the changelog must never stop a note being written.
+func updateChangelog(fn func(*changelog.Log)) {
+	changesMu.Lock()
+	defer changesMu.Unlock()
+	log, err := openChangelog()
+	if err == nil {
+		fn(log)
+		err = log.Save()
+	}
+	if err != nil {
+		slog.Warn("could not update changelog", "err", err)
+	}
+}
+
+// trackChanges records template edits and alert or template-set config
+// changes since the last run that wrote notes.
+func trackChanges() {
+	trackOnce.Do(func() {
+		updateChangelog(func(log *changelog.Log) {
+			for _, name := range noteTemplates {
+				path := templatePath(name)
+				data, err := os.ReadFile(path)
+				if err != nil {
+					continue
+				}
+				sum := sha256.Sum256(data)
+				fp := path + ":" + hex.EncodeToString(sum[:8])
+				log.Track(changelog.KindTemplate, "template:"+name, fp, fmt.Sprintf("%s changed (%s)", name, path))
+			}
+
+			rules, _ := json.Marshal(cfg.Alerts.Rules)
+			var desc []string
+			for _, r := range cfg.Alerts.Rules {
+				desc = append(desc, r.String())
+			}
+			detail := "alert rules removed"
+			if len(desc) > 0 {
+				detail = "alert rules now " + strings.Join(desc, "; ")
+			}
+			log.Track(changelog.KindConfig, "config:alerts", string(rules), detail)
+
+			set := cfg.TemplateSet
+			if set == "" {
+				set = "default"
+			}
+			log.Track(changelog.KindConfig, "config:template_set", set, "template set now "+set)
+		})
+	})
+}
+
+// recordRevision notes that WHOOP changed a day after it was stored.
+func recordRevision(old, revised fetch.DayData) {
+	updateChangelog(func(log *changelog.Log) {
+		log.Record(changelog.KindData, describeRevision(old, revised))
+	})
+}
+
+// describeRevision summarizes how the headline metrics of a day changed.
+func describeRevision(old, revised fetch.DayData) string {
+	parts := revisionParts(old, revised)
+	day := revised.Date.Format("2006-01-02")
+	if len(parts) == 0 {
+		return fmt.Sprintf("WHOOP revised [[%s|%s]] (summary metrics unchanged)", revisionLink(day), day)
+	}
+	return fmt.Sprintf("WHOOP revised [[%s|%s]]: %s", revisionLink(day), day, strings.Join(parts, ", "))
+}
+
+// revisionLink returns the link target of day's note from this month's
+// monthly note, where the revision is listed.
+func revisionLink(day string) string {
+	l := noteLinker()
+	return l.Note(l.Path(render.NoteRel("monthly", time.Now().Format("2006-01"))), "daily", day)
+}
+
+// revisionParts lists the headline metrics that differ between two copies
+// of a day, e.g. "recovery 55% → 61%".
+func revisionParts(old, revised fetch.DayData) []string {
+	var parts []string
+	diff := func(name, format string, a, b float64, okA, okB bool) {
+		switch {
+		case okA && okB && fmt.Sprintf(format, a) != fmt.Sprintf(format, b):
+			parts = append(parts, fmt.Sprintf("%s "+format+" → "+format, name, a, b))
+		case okA != okB:
+			from, to := "none", "none"
+			if okA {
+				from = fmt.Sprintf(format, a)
+			}
+			if okB {
+				to = fmt.Sprintf(format, b)
+			}
+			parts = append(parts, fmt.Sprintf("%s %s → %s", name, from, to))
+		}
+	}
+	rec := func(d fetch.DayData) (float64, float64, bool) {
+		if r := d.Recovery; r != nil && r.ScoreState == "SCORED" {
+			return r.Score.RecoveryScore, r.Score.HrvRmssdMilli, true
+		}
+		return 0, 0, false
+	}
+	strain := func(d fetch.DayData) (float64, bool) {
+		if c := d.Cycle; c != nil && c.ScoreState == "SCORED" {
+			return c.Score.Strain, true
+		}
+		return 0, false
+	}
+	oldRec, oldHRV, okOld := rec(old)
+	newRec, newHRV, okNew := rec(revised)
+	diff("recovery", "%.0f%%", oldRec, newRec, okOld, okNew)
+	diff("HRV", "%.1f ms", oldHRV, newHRV, okOld, okNew)
+	oldStrain, okOld := strain(old)
+	newStrain, okNew := strain(revised)
+	diff("strain", "%.1f", oldStrain, newStrain, okOld, okNew)
+	if a, b := len(old.Workouts), len(revised.Workouts); a != b {
+		parts = append(parts, fmt.Sprintf("workouts %d → %d", a, b))
+	}
+	return parts
+}
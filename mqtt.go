@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/mqtt"
+)
+
+// publishDay publishes today's metrics to the configured MQTT broker as
+// retained messages, along with Home Assistant discovery configs. Earlier
+// days are skipped so a backfill never overwrites the current values.
+func publishDay(day fetch.DayData) {
+	m := cfg.MQTT
+	if m.Broker == "" || opts.dryRun || opts.stdout || offline {
+		return
+	}
+	if day.Date.Format("2006-01-02") != time.Now().Format("2006-01-02") {
+		return
+	}
+	if err := publishMQTT(m.Broker, day); err != nil {
+		slog.Warn("could not publish to MQTT", "broker", m.Broker, "err", err)
+	}
+}
+
+func publishMQTT(broker string, day fetch.DayData) error {
+	prefix := cfg.MQTT.TopicPrefix
+	if prefix == "" {
+		prefix = "whoop-garden"
+	}
+	discovery := cfg.MQTT.DiscoveryPrefix
+	if discovery == "" {
+		discovery = "homeassistant"
+	}
+	host, _ := os.Hostname()
+	c, err := mqtt.Dial(mqtt.Options{
+		Broker:   broker,
+		ClientID: "whoop-garden-" + host,
+		Username: cfg.MQTT.Username,
+		Password: cfg.MQTT.Password,
+	})
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	device := map[string]any{
+		"identifiers":  []string{"whoop_garden"},
+		"name":         "WHOOP",
+		"manufacturer": "whoop-garden",
+		"sw_version":   noteVersion(),
+	}
+	n := 0
+	for _, s := range dayMetrics {
+		v, ok := s.value(day)
+		if !ok {
+			continue
+		}
+		stateTopic := fmt.Sprintf("%s/%s", prefix, s.key)
+		config := map[string]any{
+			"name":        s.name,
+			"unique_id":   "whoop_garden_" + s.key,
+			"state_topic": stateTopic,
+			"state_class": "measurement",
+			"icon":        s.icon,
+			"device":      device,
+		}
+		if s.unit != "" {
+			config["unit_of_measurement"] = s.unit
+		}
+		payload, err := json.Marshal(config)
+		if err != nil {
+			return err
+		}
+		if err := c.Publish(fmt.Sprintf("%s/sensor/whoop_garden/%s/config", discovery, s.key), payload, true); err != nil {
+			return err
+		}
+		if err := c.Publish(stateTopic, []byte(strconv.FormatFloat(v, 'f', 1, 64)), true); err != nil {
+			return err
+		}
+		n++
+	}
+	slog.Debug("published to MQTT", "broker", broker, "metrics", n)
+	return nil
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/notify"
+	"github.com/benstraw/whoop-garden/internal/render"
+)
+
+// chatChannels are the channels the daily summary can be posted to.
+var chatChannels = []string{"telegram", "slack", "discord"}
+
+// runNotify posts the compact summary of a day to chat channels.
+func runNotify(args []string) {
+	fs := flag.NewFlagSet("notify", flag.ExitOnError)
+	addGlobalFlags(fs)
+	channel := fs.String("channel", "", "telegram, slack, or discord (default: every configured channel)")
+	dateStr := fs.String("date", "", "date in YYYY-MM-DD format (default: today)")
+	_ = fs.Parse(args)
+	if offline {
+		fatalf("notify posts to chat services and cannot run with --offline")
+	}
+
+	date, err := parseDate(*dateStr)
+	if err != nil {
+		fatal(err)
+	}
+	channels := configuredChannels()
+	if *channel != "" {
+		channels = []string{*channel}
+	}
+	if len(channels) == 0 && !opts.dryRun && !opts.stdout {
+		fatal(errors.New("no chat channel configured; set notify.telegram, notify.slack_webhook_url, or notify.discord_webhook_url in config.json"))
+	}
+	var ns []notify.Notifier
+	for _, ch := range channels {
+		n, err := chatNotifier(ch)
+		if err != nil {
+			fatal(err)
+		}
+		ns = append(ns, n)
+	}
+
+	c, err := getClient()
+	if err != nil {
+		fatal(err)
+	}
+	st, err := openStore()
+	if err != nil {
+		fatal(err)
+	}
+	day, err := st.GetDayData(c, date)
+	if err != nil {
+		fatalf("fetch error: %w", err)
+	}
+	title, message, err := renderSummary(prepareDay(c, day))
+	if err != nil {
+		fatalf("render error: %w", err)
+	}
+	if opts.dryRun || opts.stdout {
+		fmt.Printf("%s\n%s\n", title, message)
+		return
+	}
+	failed := false
+	for i, n := range ns {
+		if err := n.Notify(title, message); err != nil {
+			slog.Error("could not post summary", "channel", channels[i], "err", err)
+			failed = true
+			continue
+		}
+		infof("Posted to %s\n", channels[i])
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// configuredChannels returns the chat channels that have settings.
+func configuredChannels() []string {
+	var chs []string
+	for _, ch := range chatChannels {
+		if _, err := chatNotifier(ch); err == nil {
+			chs = append(chs, ch)
+		}
+	}
+	return chs
+}
+
+// chatNotifier returns the notifier for a chat channel, or an error if the
+// channel is unknown or not configured.
+func chatNotifier(channel string) (notify.Notifier, error) {
+	n := cfg.Notify
+	switch channel {
+	case "telegram":
+		if n.Telegram.BotToken == "" || n.Telegram.ChatID == "" {
+			return nil, errors.New("telegram is not configured; set notify.telegram.bot_token and chat_id in config.json")
+		}
+		return notify.NewTelegram(n.Telegram.BotToken, n.Telegram.ChatID), nil
+	case "slack":
+		if n.Slack == "" {
+			return nil, errors.New("slack is not configured; set notify.slack_webhook_url in config.json")
+		}
+		return notify.NewWebhook(n.Slack), nil
+	case "discord":
+		if n.Discord == "" {
+			return nil, errors.New("discord is not configured; set notify.discord_webhook_url in config.json")
+		}
+		return notify.NewDiscord(n.Discord), nil
+	}
+	return nil, fmt.Errorf("unknown channel %q (want %s)", channel, strings.Join(chatChannels, ", "))
+}
+
+// renderSummary renders summary.txt.tmpl for day. The first line becomes
+// the title; a link to the note is appended when the vault is known.
+func renderSummary(day render.Day) (string, string, error) {
+	text, err := render.RenderSummary(day, templatePath("summary.txt.tmpl"))
+	if err != nil {
+		return "", "", err
+	}
+	title, message, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	if link := obsidianURI(day.Date); link != "" {
+		message += "\n" + string(link)
+	}
+	return title, message, nil
+}
+
+// notifyState remembers which day was last announced on each path, so a
+// day refreshed every hour by the daemon is only announced once.
+type notifyState struct {
+	LowRecovery string `json:"low_recovery"`
+	Respiratory string `json:"respiratory"`
+	Summary     string `json:"summary"`
+}
+
+func notifyStatePath() string { return filepath.Join(cacheDir(), "notify-state.json") }
+
+func loadNotifyState() (notifyState, error) {
+	var state notifyState
+	data, err := os.ReadFile(notifyStatePath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	return state, json.Unmarshal(data, &state)
+}
+
+func saveNotifyState(state notifyState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cacheDir(), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(notifyStatePath(), data, 0600)
+}
+
+// announceDay runs the notifications due once a day's note is written:
+// the low-recovery and respiratory rate notifications and the automatic
+// daily summary. Each fires only for today and at most once a day; the
+// low-recovery notification and summary wait for the recovery to be scored,
+// and a recovery scored while WHOOP is calibrating is never low.
+func announceDay(day render.Day) {
+	if opts.dryRun || opts.stdout || offline {
+		return
+	}
+	date := day.Date.Format("2006-01-02")
+	if date != time.Now().Format("2006-01-02") {
+		return
+	}
+	r := day.Recovery
+	scored := r != nil && r.ScoreState == "SCORED"
+	low := scored && !r.Score.UserCalibrating && cfg.Notify.LowRecovery > 0 && r.Score.RecoveryScore < cfg.Notify.LowRecovery
+	summary := scored && len(cfg.Notify.SummaryChannels) > 0
+	resp := cfg.Notify.Respiratory && day.Respiratory != nil && day.Respiratory.Flagged
+	if !low && !summary && !resp {
+		return
+	}
+
+	state, err := loadNotifyState()
+	if err != nil {
+		slog.Warn("could not read notification state", "err", err)
+		return
+	}
+	if low && state.LowRecovery != date {
+		notifyLowRecovery(day.DayData)
+		state.LowRecovery = date
+	}
+	if resp && state.Respiratory != date {
+		notifyRespiratory(day)
+		state.Respiratory = date
+	}
+	if summary && state.Summary != date {
+		postSummary(day)
+		state.Summary = date
+	}
+	if err := saveNotifyState(state); err != nil {
+		slog.Warn("could not save notification state", "err", err)
+	}
+}
+
+// notifiers returns every configured low-recovery and respiratory rate
+// notification channel.
+func notifiers() []notify.Notifier {
+	var ns []notify.Notifier
+	if cfg.Notify.Ntfy != "" {
+		ns = append(ns, notify.NewNtfy(cfg.Notify.Ntfy, cfg.Notify.NtfyToken))
+	}
+	if cfg.Notify.Desktop {
+		ns = append(ns, notify.Desktop{})
+	}
+	if cfg.Notify.WebhookURL != "" {
+		ns = append(ns, notify.NewWebhook(cfg.Notify.WebhookURL))
+	}
+	return ns
+}
+
+// notifyLowRecovery sends "Red recovery: 28%" with the day's HRV, RHR, and
+// a link to its note.
+func notifyLowRecovery(day fetch.DayData) {
+	r := day.Recovery
+	color := render.RecoveryColor(r.Score.RecoveryScore)
+	title := fmt.Sprintf("%s%s recovery: %.0f%%", strings.ToUpper(color[:1]), color[1:], r.Score.RecoveryScore)
+	message := fmt.Sprintf("%s · HRV %.0f ms · RHR %.0f bpm", day.Date.Format("2006-01-02"), r.Score.HrvRmssdMilli, r.Score.RestingHeartRate)
+	if link := obsidianURI(day.Date); link != "" {
+		message += "\n" + string(link)
+	}
+	send(title, message, "low-recovery")
+}
+
+// notifyRespiratory sends "Respiratory rate 1.6 rpm above baseline" with
+// the night's rate, the baseline, and a link to the note.
+func notifyRespiratory(day render.Day) {
+	b := day.Respiratory
+	dir := "above"
+	if b.Value < b.Mean {
+		dir = "below"
+	}
+	title := fmt.Sprintf("Respiratory rate %.1f rpm %s baseline", math.Abs(b.Value-b.Mean), dir)
+	message := fmt.Sprintf("%s · %.1f rpm vs %.1f ± %.1f rpm over %d nights", day.Date.Format("2006-01-02"), b.Value, b.Mean, b.Std, b.Days)
+	if link := obsidianURI(day.Date); link != "" {
+		message += "\n" + string(link)
+	}
+	send(title, message, "respiratory rate")
+}
+
+// send delivers a notification to every configured channel, or prints it
+// when there are none. kind names the notification in warnings.
+func send(title, message, kind string) {
+	ns := notifiers()
+	if len(ns) == 0 {
+		infof("%s\n%s\n", title, message)
+	}
+	for _, n := range ns {
+		if err := n.Notify(title, message); err != nil {
+			slog.Warn("could not send "+kind+" notification", "channel", fmt.Sprintf("%T", n), "err", err)
+		}
+	}
+}
+
+// postSummary posts the day's summary to notify.summary_channels.
+func postSummary(day render.Day) {
+	title, message, err := renderSummary(day)
+	if err != nil {
+		slog.Warn("could not render summary", "err", err)
+		return
+	}
+	for _, ch := range cfg.Notify.SummaryChannels {
+		n, err := chatNotifier(ch)
+		if err == nil {
+			err = n.Notify(title, message)
+		}
+		if err != nil {
+			slog.Warn("could not post summary", "channel", ch, "err", err)
+		}
+	}
+}
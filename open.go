@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// vaultName returns the Obsidian vault name: config vault, else the last
+// element of $OBSIDIAN_VAULT_PATH, else "".
+func vaultName() string {
+	if cfg.Vault != "" {
+		return cfg.Vault
+	}
+	if vault := os.Getenv("OBSIDIAN_VAULT_PATH"); vault != "" {
+		return filepath.Base(vault)
+	}
+	return ""
+}
+
+// noteURI returns an obsidian:// URI that opens the note written to path.
+// Notes in $OBSIDIAN_VAULT_PATH or written through the Obsidian storage
+// backend are addressed by vault and file; other local notes by absolute
+// path, which Obsidian resolves to whichever vault contains it.
+func noteURI(path string) (string, error) {
+	fileURI := func(file string) (string, error) {
+		vault := vaultName()
+		if vault == "" {
+			return "", fmt.Errorf("vault name unknown; set vault in config.json")
+		}
+		file = strings.TrimSuffix(filepath.ToSlash(file), ".md")
+		return "obsidian://open?vault=" + uriEscape(vault) + "&file=" + uriEscape(file), nil
+	}
+
+	switch {
+	case cfg.Storage.Type == "obsidian":
+		key, err := noteKey(path)
+		if err != nil {
+			return "", err
+		}
+		if prefix := strings.Trim(cfg.Storage.Prefix, "/"); prefix != "" {
+			key = prefix + "/" + key
+		}
+		return fileURI(key)
+	case cfg.Storage.Remote():
+		return "", fmt.Errorf("cannot open notes written to %s storage", cfg.Storage)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if vault := os.Getenv("OBSIDIAN_VAULT_PATH"); vault != "" {
+		if root, err := filepath.Abs(vault); err == nil {
+			if rel, err := filepath.Rel(root, abs); err == nil && !strings.HasPrefix(rel, "..") {
+				return fileURI(rel)
+			}
+		}
+	}
+	return "obsidian://open?path=" + uriEscape(abs), nil
+}
+
+// uriEscape escapes s for an obsidian:// query. Obsidian decodes + as a
+// literal plus, so spaces must be %20.
+func uriEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// openNote opens the note written to path in Obsidian. It does nothing
+// under --dry-run or --stdout, when no note was written.
+func openNote(path string) error {
+	if opts.dryRun || opts.stdout {
+		return nil
+	}
+	uri, err := noteURI(path)
+	if err != nil {
+		return err
+	}
+	name, args, err := openCommand(runtime.GOOS, uri)
+	if err != nil {
+		return err
+	}
+	if err := exec.Command(name, args...).Run(); err != nil {
+		return fmt.Errorf("open %s: %w", uri, err)
+	}
+	return nil
+}
+
+// openCommand returns the command that opens uri with the desktop's
+// handler for obsidian:// links.
+func openCommand(goos, uri string) (string, []string, error) {
+	switch goos {
+	case "darwin":
+		return "open", []string{uri}, nil
+	case "windows":
+		// start would need the & in the URI escaped for cmd.exe.
+		return "rundll32", []string{"url.dll,FileProtocolHandler", uri}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "xdg-open", []string{uri}, nil
+	}
+	return "", nil, fmt.Errorf("opening notes is not supported on %s", goos)
+}
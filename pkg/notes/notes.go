@@ -0,0 +1,83 @@
+// Package notes renders WHOOP data as the Obsidian markdown notes
+// whoop-garden writes.
+//
+// Rendering is driven by text/template files such as those in the
+// repository's templates/ directory. Each function takes the path to its
+// template, and the file must keep its conventional name (daily.md.tmpl,
+// weekly.md.tmpl, summary.txt.tmpl) because the template is looked up by
+// it. Templates can use the helpers in FuncMap; see docs/templates.md.
+//
+// Notes are rendered with whoop-garden's default settings: English labels,
+// metric units, sleep measured as time in bed, and the built-in strain
+// bands, strain targets, and sport names. The config.json settings that
+// change these for the CLI cannot be set here yet, and the API is not yet
+// stable; it may change in any release before v1.
+//
+//	day, err := client.Day(ctx, date)
+//	if err != nil { ... }
+//	md, err := notes.Daily(day, "templates/daily.md.tmpl")
+package notes
+
+import (
+	"text/template"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/render"
+	"github.com/benstraw/whoop-garden/pkg/whoop"
+)
+
+// dayData converts day for the renderers.
+func dayData(day whoop.Day) fetch.DayData {
+	return fetch.DayData{
+		Date:              day.Date,
+		Cycle:             day.Cycle,
+		Recovery:          day.Recovery,
+		Sleeps:            day.Sleeps,
+		Workouts:          day.Workouts,
+		DuplicateWorkouts: day.DuplicateWorkouts,
+	}
+}
+
+// daysData converts days for the renderers.
+func daysData(days []whoop.Day) []fetch.DayData {
+	out := make([]fetch.DayData, len(days))
+	for i, d := range days {
+		out[i] = dayData(d)
+	}
+	return out
+}
+
+// Daily renders a daily note for day with the template at tmplPath.
+func Daily(day whoop.Day, tmplPath string) (string, error) {
+	return render.RenderDaily(render.Day{DayData: dayData(day)}, tmplPath)
+}
+
+// Weekly renders a weekly note for days, normally the seven days of an
+// ISO week starting on Monday, with the template at tmplPath.
+func Weekly(days []whoop.Day, tmplPath string) (string, error) {
+	return render.RenderWeeklyFromStats(render.BuildWeekStats(daysData(days)), tmplPath)
+}
+
+// Summary renders the compact plain-text summary of day posted to chat
+// channels, with the template at tmplPath.
+func Summary(day whoop.Day, tmplPath string) (string, error) {
+	return render.RenderSummary(render.Day{DayData: dayData(day)}, tmplPath)
+}
+
+// Persona renders the persona section, a profile of baselines and trends
+// over days meant as context for an AI assistant. Its template is built in.
+func Persona(days []whoop.Day) (string, error) {
+	return render.RenderPersonaSection(daysData(days))
+}
+
+// PersonaWithPrior renders the persona section for days with each metric's
+// change since prior, normally the window of the same length before it.
+func PersonaWithPrior(days, prior []whoop.Day) (string, error) {
+	return render.RenderPersona(daysData(days), daysData(prior))
+}
+
+// FuncMap returns the helpers available to note templates, for callers
+// executing templates of their own.
+func FuncMap() template.FuncMap {
+	return render.FuncMap()
+}
@@ -0,0 +1,44 @@
+package notes_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benstraw/whoop-garden/pkg/notes"
+	"github.com/benstraw/whoop-garden/pkg/whoop"
+)
+
+func templatePath(name string) string {
+	return filepath.Join("..", "..", "templates", name)
+}
+
+func TestDaily(t *testing.T) {
+	day := whoop.Day{
+		Date:     time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC),
+		Recovery: &whoop.Recovery{ScoreState: "SCORED", Score: whoop.RecoveryScore{RecoveryScore: 71}},
+	}
+	md, err := notes.Daily(day, templatePath("daily.md.tmpl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(md, "# WHOOP Daily — 2026-02-10") || !strings.Contains(md, "**71%**") {
+		t.Errorf("unexpected daily note:\n%s", md)
+	}
+}
+
+func TestWeekly(t *testing.T) {
+	monday := time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC)
+	var days []whoop.Day
+	for i := 0; i < 7; i++ {
+		days = append(days, whoop.Day{Date: monday.AddDate(0, 0, i)})
+	}
+	md, err := notes.Weekly(days, templatePath("weekly.md.tmpl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(md, "# WHOOP Weekly Summary — 2026-02-09 → 2026-02-15") {
+		t.Errorf("weekly note does not name its dates:\n%s", md)
+	}
+}
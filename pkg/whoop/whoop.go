@@ -0,0 +1,139 @@
+// Package whoop is a client for the WHOOP developer API (v2), with the
+// day aggregation whoop-garden uses to build daily notes.
+//
+// The API is not yet stable and may change in any release before v1.
+//
+// The client needs an OAuth access token with the read scopes; obtaining
+// and refreshing one is left to the caller. Every method takes a context
+// that cancels in-flight requests and any wait between rate-limit retries.
+//
+//	c := whoop.NewClient(token)
+//	day, err := c.Day(ctx, time.Now())
+//	if err != nil { ... }
+//	if day.Recovery != nil {
+//		fmt.Printf("recovery %.0f%%\n", day.Recovery.Score.RecoveryScore)
+//	}
+package whoop
+
+import (
+	"context"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/client"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/models"
+	"github.com/benstraw/whoop-garden/internal/render"
+)
+
+// APIVersion is the WHOOP developer API version the client targets.
+const APIVersion = client.APIVersion
+
+// ErrNotFound is returned when the API responds with 404.
+var ErrNotFound = client.ErrNotFound
+
+// Records returned by the API. Field names follow the API's JSON.
+type (
+	UserProfile       = models.UserProfile
+	BodyMeasurements  = models.BodyMeasurements
+	Cycle             = models.Cycle
+	CycleScore        = models.CycleScore
+	Recovery          = models.Recovery
+	RecoveryScore     = models.RecoveryScore
+	Sleep             = models.Sleep
+	SleepScore        = models.SleepScore
+	SleepStageSummary = models.SleepStageSummary
+	SleepNeeded       = models.SleepNeeded
+	Workout           = models.Workout
+	WorkoutScore      = models.WorkoutScore
+	ZoneDuration      = models.ZoneDuration
+)
+
+// Day is everything WHOOP recorded for one calendar day: the cycle that
+// started on it, that cycle's recovery, the sleeps leading into it, and its
+// workouts. Overlapping duplicate workouts are moved to DuplicateWorkouts.
+type Day struct {
+	Date              time.Time `json:"date"`
+	Cycle             *Cycle    `json:"cycle"`
+	Recovery          *Recovery `json:"recovery"`
+	Sleeps            []Sleep   `json:"sleeps"`
+	Workouts          []Workout `json:"workouts"`
+	DuplicateWorkouts []Workout `json:"duplicate_workouts,omitempty"`
+}
+
+// newDay copies the WHOOP records of d.
+func newDay(d fetch.DayData) Day {
+	return Day{
+		Date:              d.Date,
+		Cycle:             d.Cycle,
+		Recovery:          d.Recovery,
+		Sleeps:            d.Sleeps,
+		Workouts:          d.Workouts,
+		DuplicateWorkouts: d.DuplicateWorkouts,
+	}
+}
+
+// Client is an authenticated WHOOP API client. It is safe for concurrent
+// use.
+type Client struct {
+	c *client.Client
+}
+
+// NewClient returns a Client that authenticates with accessToken.
+func NewClient(accessToken string) *Client {
+	return &Client{c: client.NewClient(accessToken)}
+}
+
+// NewClientWithBaseURL returns a Client for an API at baseURL, such as a
+// test server.
+func NewClientWithBaseURL(accessToken, baseURL string) *Client {
+	return &Client{c: client.NewClientWithBaseURL(accessToken, baseURL)}
+}
+
+// SetBudget limits the client to n HTTP requests, retries included. Zero
+// means unlimited.
+func (c *Client) SetBudget(n int) { c.c.SetBudget(n) }
+
+// Calls returns the number of HTTP requests made so far.
+func (c *Client) Calls() int { return c.c.Calls() }
+
+// Profile returns the authenticated user's profile.
+func (c *Client) Profile(ctx context.Context) (*UserProfile, error) {
+	return fetch.GetUserProfile(c.c.WithContext(ctx))
+}
+
+// BodyMeasurements returns the user's height, weight, and max heart rate.
+func (c *Client) BodyMeasurements(ctx context.Context) (*BodyMeasurements, error) {
+	return fetch.GetBodyMeasurements(c.c.WithContext(ctx))
+}
+
+// Cycles returns the cycles that started in [start, end), following
+// pagination.
+func (c *Client) Cycles(ctx context.Context, start, end time.Time) ([]Cycle, error) {
+	return fetch.GetCycles(c.c.WithContext(ctx), start, end)
+}
+
+// Recoveries returns the recoveries created in [start, end).
+func (c *Client) Recoveries(ctx context.Context, start, end time.Time) ([]Recovery, error) {
+	return fetch.GetRecoveries(c.c.WithContext(ctx), start, end)
+}
+
+// Sleeps returns the sleeps, naps included, that started in [start, end).
+func (c *Client) Sleeps(ctx context.Context, start, end time.Time) ([]Sleep, error) {
+	return fetch.GetSleeps(c.c.WithContext(ctx), start, end)
+}
+
+// Workouts returns the workouts that started in [start, end).
+func (c *Client) Workouts(ctx context.Context, start, end time.Time) ([]Workout, error) {
+	return fetch.GetWorkouts(c.c.WithContext(ctx), start, end)
+}
+
+// Day returns the data for date's UTC calendar day. A day without a cycle
+// is returned with only Date set.
+func (c *Client) Day(ctx context.Context, date time.Time) (Day, error) {
+	d, err := fetch.GetDayData(c.c.WithContext(ctx), date)
+	return newDay(d), err
+}
+
+// SportName returns the display name for a WHOOP sport ID, for workouts
+// without a SportName.
+func SportName(id int) string { return render.SportName(id) }
@@ -0,0 +1,63 @@
+package whoop_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benstraw/whoop-garden/pkg/whoop"
+)
+
+// page writes records as one page of a paginated response.
+func page(w http.ResponseWriter, records any) {
+	json.NewEncoder(w).Encode(map[string]any{"records": records})
+}
+
+func TestDay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cycle":
+			page(w, []whoop.Cycle{{ID: 7, Start: "2026-02-10T06:30:00.000Z", End: "2026-02-11T06:00:00.000Z", ScoreState: "SCORED"}})
+		case "/recovery":
+			page(w, []whoop.Recovery{{CycleID: 7, ScoreState: "SCORED", Score: whoop.RecoveryScore{RecoveryScore: 71}}})
+		case "/activity/sleep":
+			page(w, []whoop.Sleep{{ID: "s1"}})
+		case "/activity/workout":
+			page(w, []whoop.Workout{})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := whoop.NewClientWithBaseURL("tok", srv.URL)
+	day, err := c.Day(context.Background(), time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if day.Cycle == nil || day.Cycle.ID != 7 || day.Recovery == nil || day.Recovery.Score.RecoveryScore != 71 || len(day.Sleeps) != 1 {
+		t.Errorf("Day() = %+v", day)
+	}
+	if c.Calls() != 4 {
+		t.Errorf("Calls() = %d, want 4", c.Calls())
+	}
+}
+
+func TestCyclesCancelled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c := whoop.NewClientWithBaseURL("tok", srv.URL)
+	start := time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
+	if _, err := c.Cycles(ctx, start, start.AddDate(0, 0, 1)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Cycles() with a cancelled context = %v, want context.Canceled", err)
+	}
+}
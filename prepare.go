@@ -0,0 +1,171 @@
+package main
+
+import (
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/analytics"
+	"github.com/benstraw/whoop-garden/internal/client"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/period"
+	"github.com/benstraw/whoop-garden/internal/render"
+	"github.com/benstraw/whoop-garden/internal/store"
+)
+
+// vitalsBaselineDays is the window each night's respiratory rate, skin
+// temperature, and SpO2 are compared with.
+const vitalsBaselineDays = 30
+
+var (
+	historyOnce  sync.Once
+	historyStore *store.Store // nil when the store could not be opened
+)
+
+// offline is set by --offline: no network requests at all. The API client
+// answers only from the response cache, and prepareDay reads history and
+// Strava activities as under storeOnly.
+var offline bool
+
+// storeOnly limits prepareDay to local data: history is read from the
+// store without fetching, Strava activities from the matches saved when
+// they were fetched, and body measurements through a cacheClient. rerender
+// and import set it; unlike offline, notes are still written to remote
+// storage, and dayWritten skips notifications and MQTT.
+var storeOnly bool
+
+// prepareDay returns data with the details a daily note shows that are
+// not part of the stored day: matching Strava activities and, as
+// configured, body measurements, streaks, rolling averages, sleep debt,
+// tonight's recommended bedtime (today's note only), the previous day, the
+// HRV, respiratory rate, skin temperature, and SpO2 baselines, and a time
+// zone change.
+func prepareDay(c *client.Client, data fetch.DayData) render.Day {
+	day := render.Day{DayData: data}
+	linkStrava(&day)
+	if cfg.IncludeProfile && c != nil {
+		addBody(c, &day)
+	}
+	if cfg.Streaks {
+		seeDay(data)
+		day.Streaks = streaksAsOf(day.Date)
+	}
+	if !cfg.RollingAverages && !cfg.SleepDebt && !cfg.Bedtime && !cfg.DayOverDay && cfg.HRVBaselineDays == 0 && cfg.RespiratoryThreshold == 0 && !cfg.Vitals && !cfg.Travel {
+		return day
+	}
+	n := 1
+	if cfg.RollingAverages || cfg.SleepDebt || cfg.Bedtime {
+		n = 6
+	}
+	if cfg.RespiratoryThreshold > 0 || cfg.Vitals {
+		n = vitalsBaselineDays
+	}
+	n = max(n, cfg.HRVBaselineDays)
+	history := dayHistory(c, day.Date, n)
+	if cfg.DayOverDay && len(history) > 0 {
+		day.Previous = &history[len(history)-1]
+	}
+
+	// days is the history through the note's own day, oldest first.
+	days := append(slices.Clip(history), data)
+	if cfg.Travel && len(history) > 0 {
+		if s, ok := analytics.TimezoneShift(history[len(history)-1], data); ok {
+			day.Travel = &s
+		}
+	}
+	// baseDays feeds the baselines, without days distorted by jetlag.
+	baseDays := days
+	if cfg.Travel {
+		baseDays = analytics.WithoutJetlag(days)
+	}
+	if cfg.RollingAverages {
+		r := analytics.RollingAverages(days[max(len(days)-7, 0):])
+		if cfg.SleepTime == "asleep" {
+			r.AvgSleep = r.AvgAsleep
+		}
+		day.Rolling7 = &r
+	}
+	if cfg.SleepDebt {
+		if d, ok := analytics.SleepDebt(days[max(len(days)-7, 0):]); ok {
+			day.SleepDebt = &d
+		}
+	}
+	if cfg.Bedtime && isToday(day.Date) {
+		if b, ok := analytics.RecommendBedtime(days, wakeTarget()); ok {
+			day.Bedtime = &b
+		}
+	}
+	if cfg.HRVBaselineDays > 0 {
+		if b, ok := analytics.HRVBaseline(baseDays, cfg.HRVBaselineDays); ok {
+			day.HRVBaseline = &b
+		}
+	}
+	if cfg.RespiratoryThreshold > 0 {
+		if b, ok := analytics.RespiratoryBaseline(baseDays, vitalsBaselineDays, cfg.RespiratoryThreshold); ok {
+			day.Respiratory = &b
+		}
+	}
+	if cfg.Vitals {
+		if b, ok := analytics.SkinTempBaseline(baseDays, vitalsBaselineDays); ok {
+			day.SkinTemp = &b
+		}
+		if b, ok := analytics.SpO2Baseline(baseDays, vitalsBaselineDays); ok {
+			day.SpO2 = &b
+		}
+	}
+	return day
+}
+
+// isToday reports whether date, a day's calendar date, is today.
+func isToday(date time.Time) bool {
+	return date.Format("2006-01-02") == time.Now().Format("2006-01-02")
+}
+
+// wakeTarget returns the configured wake time in hours from midnight, or
+// -1 to recommend bedtimes for the average recent wake time.
+func wakeTarget() float64 {
+	t, err := time.Parse("15:04", cfg.WakeTime)
+	if err != nil {
+		return -1
+	}
+	return float64(t.Hour()) + float64(t.Minute())/60
+}
+
+// dayHistory returns the n days before date, oldest first, read through the
+// local store. Offline or store-only, days missing from the store are
+// empty. It returns nil if the store cannot be opened.
+func dayHistory(c *client.Client, date time.Time, n int) []fetch.DayData {
+	historyOnce.Do(func() {
+		var err error
+		if historyStore, err = openStore(); err != nil {
+			slog.Warn("could not open the local store; notes will lack history", "err", err)
+		}
+	})
+	if historyStore == nil {
+		return nil
+	}
+	last := date.AddDate(0, 0, -1)
+	p := period.Range(last.AddDate(0, 0, -(n-1)), last)
+	if offline || storeOnly {
+		return storedRange(historyStore, p)
+	}
+	return fetchRange(c, historyStore, p)
+}
+
+// storedRange returns the days of p from the store alone. Days it lacks, or
+// cannot read, are empty.
+func storedRange(st *store.Store, p period.Period) []fetch.DayData {
+	var days []fetch.DayData
+	for d := p.Start; d.Before(p.End); d = d.AddDate(0, 0, 1) {
+		dd, _, ok, err := st.Load(d)
+		if err != nil {
+			slog.Warn("could not read stored day", "date", d.Format("2006-01-02"), "err", err)
+		}
+		if !ok || err != nil {
+			dd = fetch.DayData{Date: d}
+		}
+		days = append(days, dd)
+	}
+	return days
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/benstraw/whoop-garden/internal/client"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/models"
+	"github.com/benstraw/whoop-garden/internal/render"
+)
+
+var (
+	bodyOnce sync.Once
+	body     *models.BodyMeasurements // nil when off or unavailable
+)
+
+// addBody sets day.Body to the current body measurements, fetched once per
+// run, when day is today. WHOOP keeps no history of them, so notes for past
+// days, from backfills and rerenders, are left without. After an error it
+// warns once and leaves notes without them.
+func addBody(c *client.Client, day *render.Day) {
+	if !isToday(day.Date) {
+		return
+	}
+	bodyOnce.Do(func() {
+		var err error
+		body, err = fetch.GetBodyMeasurements(c)
+		switch {
+		case errors.Is(err, client.ErrOffline):
+			slog.Debug("body measurements are not cached; continuing without them")
+		case err != nil:
+			slog.Warn("could not fetch body measurements; continuing without them", "err", err)
+		}
+	})
+	day.Body = body
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/benstraw/whoop-garden/internal/auth"
+	"github.com/benstraw/whoop-garden/internal/note"
+)
+
+// runPurge deletes generated notes, the local cache, and/or stored tokens
+// after listing them and asking for confirmation.
+func runPurge(args []string) {
+	fs := flag.NewFlagSet("purge", flag.ExitOnError)
+	addGlobalFlags(fs)
+	notes := fs.Bool("notes", false, "delete notes written by whoop-garden from the output directory")
+	cache := fs.Bool("cache", false, "delete the local cache: stored days, sync and alert state, Strava token")
+	tokens := fs.Bool("tokens", false, "delete stored WHOOP and Strava tokens")
+	yes := fs.Bool("yes", false, "delete without asking for confirmation")
+	_ = fs.Parse(args)
+
+	if !*notes && !*cache && !*tokens {
+		fatal(errors.New("nothing to purge: pass --notes, --cache, and/or --tokens"))
+	}
+	if *notes && cfg.Storage.Remote() {
+		fatalf("purge --notes scans a local directory and does not support %s storage", cfg.Storage)
+	}
+
+	var paths []string
+	if *notes {
+		found, err := generatedNotes(outputDir())
+		if err != nil {
+			fatalf("scan notes: %w", err)
+		}
+		fmt.Printf("%s in %s\n", plural(len(found), "generated note"), outputDir())
+		paths = append(paths, found...)
+	}
+	if *cache {
+		if dir := cacheDir(); exists(dir) {
+			fmt.Printf("Local cache %s\n", dir)
+			paths = append(paths, dir)
+		}
+	}
+	if *tokens {
+		for _, p := range []string{auth.TokenPath(), stravaTokenPath()} {
+			if exists(p) {
+				fmt.Printf("Token %s\n", p)
+				paths = append(paths, p)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		fmt.Println("Nothing to purge.")
+		return
+	}
+	if opts.dryRun {
+		return
+	}
+	if !*yes {
+		if !isTerminal(os.Stdin) {
+			fatal(errors.New("purge asks for confirmation; pass --yes to run it without a terminal"))
+		}
+		if !newPrompter().confirm("Delete them? This cannot be undone.", false) {
+			fmt.Println("Nothing deleted.")
+			return
+		}
+	}
+
+	failed := 0
+	for _, p := range paths {
+		remove := os.RemoveAll
+		if p == auth.TokenPath() {
+			remove = func(string) error { return auth.DeleteTokens() }
+		}
+		if err := remove(p); err != nil {
+			slog.Warn("could not delete", "path", p, "err", err)
+			failed++
+		}
+	}
+	if *notes {
+		removeEmptyDirs(outputDir())
+	}
+	if failed > 0 {
+		fatalf("%d of %d could not be deleted", failed, len(paths))
+	}
+	fmt.Println("Purged.")
+}
+
+// generatedNotes returns the markdown files under dir written by
+// whoop-garden, recognized by the generator line in their frontmatter.
+// Other files, such as notes of your own, are left out.
+func generatedNotes(dir string) ([]string, error) {
+	var found []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && path == dir {
+			return filepath.SkipDir
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".md" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if note.Generated(string(data)) {
+			found = append(found, path)
+		}
+		return nil
+	})
+	return found, err
+}
+
+// removeEmptyDirs removes the directories under dir, such as year folders,
+// that purging left empty. dir itself is kept.
+func removeEmptyDirs(dir string) {
+	var dirs []string
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() && path != dir {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	// Deepest first, so a parent is empty once its children are gone.
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if entries, err := os.ReadDir(dirs[i]); err == nil && len(entries) == 0 {
+			_ = os.Remove(dirs[i])
+		}
+	}
+}
+
+// exists reports whether path exists.
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"strings"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/period"
+	"github.com/benstraw/whoop-garden/internal/render"
+)
+
+func runQuarterly(args []string) {
+	fs := flag.NewFlagSet("quarterly", flag.ExitOnError)
+	addGlobalFlags(fs)
+	quarterStr := fs.String("quarter", "", "quarter in YYYY-Qn format (default: this quarter)")
+	_ = fs.Parse(args)
+
+	p := period.Quarter(time.Now())
+	if *quarterStr != "" {
+		q, err := period.Parse(*quarterStr)
+		if err != nil || !strings.Contains(q.Label, "-Q") {
+			fatalf("invalid quarter %q (expected YYYY-Qn)", *quarterStr)
+		}
+		p = q
+	}
+
+	c, err := getClient()
+	if err != nil {
+		fatal(err)
+	}
+	st, err := openStore()
+	if err != nil {
+		fatal(err)
+	}
+
+	infof("Fetching %s (%d days)...\n", p.Label, p.Days())
+	days := fetchRange(c, st, p)
+
+	log, err := openChangelog()
+	if err != nil {
+		fatal(err)
+	}
+	data := render.QuarterlyData{
+		Quarter:       p.Label,
+		Prev:          period.Quarter(p.Start.AddDate(0, -3, 0)).Label,
+		Next:          period.Quarter(p.End).Label,
+		Start:         p.Start,
+		Stats:         render.BuildWeekStats(days),
+		Changes:       log.Between(p.Start, p.End),
+		TrackingSince: log.Since,
+	}
+	for _, m := range p.Months() {
+		data.Months = append(data.Months, render.MonthStats{
+			Month: m.Label,
+			Start: m.Start,
+			Stats: render.BuildWeekStats(daysIn(days, m)),
+		})
+	}
+	content, err := render.RenderQuarterly(data, templatePath("quarterly.md.tmpl"))
+	if err != nil {
+		fatalf("render error: %w", err)
+	}
+
+	dir, err := ensureOutputDir()
+	if err != nil {
+		fatal(err)
+	}
+	if err := writeNote(notePath(dir, "quarterly", data.Quarter), content); err != nil {
+		fatalf("write error: %w", err)
+	}
+	if cfg.Index && !opts.stdout {
+		indexDue = true
+	}
+}
+
+// daysIn returns the days that fall within p.
+func daysIn(days []fetch.DayData, p period.Period) []fetch.DayData {
+	var in []fetch.DayData
+	for _, d := range days {
+		if !d.Date.Before(p.Start) && d.Date.Before(p.End) {
+			in = append(in, d)
+		}
+	}
+	return in
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/analytics"
+	"github.com/benstraw/whoop-garden/internal/render"
+)
+
+// recordsDue is set when a daily note was written and Records.md should
+// be brought up to date before the run ends.
+var recordsDue bool
+
+// runRecords rebuilds Records.md from the local store without API calls.
+func runRecords(args []string) {
+	fs := flag.NewFlagSet("records", flag.ExitOnError)
+	addGlobalFlags(fs)
+	_ = fs.Parse(args)
+
+	if err := writeRecords(); err != nil {
+		fatal(err)
+	}
+}
+
+// flushRecords rewrites Records.md when daily notes were written since the
+// last call. Failures are only logged.
+func flushRecords() {
+	if !recordsDue {
+		return
+	}
+	recordsDue = false
+	if err := writeRecords(); err != nil {
+		slog.Warn("could not update records", "err", err)
+	}
+}
+
+// writeRecords renders the all-time bests across every known day to
+// Records.md in the output directory.
+func writeRecords() error {
+	days := knownDays()
+	data := render.RecordsData{
+		GeneratedDate: time.Now().Format("2006-01-02"),
+		Records:       analytics.ComputeRecords(days),
+	}
+	if len(days) > 0 {
+		data.Since = days[0].Date.Format("2006-01-02")
+		data.Through = days[len(days)-1].Date.Format("2006-01-02")
+	}
+	content, err := render.RenderRecords(data, templatePath("records.md.tmpl"))
+	if err != nil {
+		return fmt.Errorf("render error: %w", err)
+	}
+	dir, err := ensureOutputDir()
+	if err != nil {
+		return err
+	}
+	if err := writeNote(filepath.Join(dir, "Records.md"), content); err != nil {
+		return fmt.Errorf("write error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/note"
+	"github.com/benstraw/whoop-garden/internal/render"
+)
+
+// runRecovery is the lightweight morning update: it fetches only the
+// recovery and sleep for a day and rewrites the recovery section of the
+// existing daily note, leaving the rest for a later full sync.
+func runRecovery(args []string) {
+	fs := flag.NewFlagSet("recovery", flag.ExitOnError)
+	addGlobalFlags(fs)
+	dateStr := fs.String("date", "", "date in YYYY-MM-DD format (default: today)")
+	_ = fs.Parse(args)
+
+	date, err := parseDate(*dateStr)
+	if err != nil {
+		fatal(err)
+	}
+
+	c, err := getClient()
+	if err != nil {
+		fatal(err)
+	}
+
+	infof("Fetching recovery for %s...\n", date.Format("2006-01-02"))
+	dayData, err := fetch.GetRecoveryData(dayClient(c, date), date)
+	if err != nil {
+		fatalf("fetch error: %w", err)
+	}
+	if dayData.Recovery == nil {
+		infof("No recovery yet for %s.\n", date.Format("2006-01-02"))
+	}
+
+	// The recovery section shows baselines and comparisons as daily does.
+	day := prepareDay(c, dayData)
+
+	rendered, err := render.RenderDaily(day, templatePath("daily.md.tmpl"))
+	if err != nil {
+		fatalf("render error: %w", err)
+	}
+
+	dir, err := ensureOutputDir()
+	if err != nil {
+		fatal(err)
+	}
+	outPath := notePath(dir, "daily", date.Format("2006-01-02"))
+
+	content, err := spliceRecovery(outPath, rendered)
+	if err != nil {
+		fatal(err)
+	}
+	if err := writeNote(outPath, content); err != nil {
+		fatalf("write error: %w", err)
+	}
+}
+
+// spliceRecovery returns the note at path with its recovery section replaced
+// by the one in rendered. The recovery section is the first "## " section of
+// the daily template. When no note exists yet, rendered is returned whole.
+func spliceRecovery(path, rendered string) (string, error) {
+	existing, err := readNote(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return rendered, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	heading := note.FirstHeading(rendered)
+	section, ok := note.Section(rendered, heading)
+	if !ok {
+		return "", fmt.Errorf("daily template has no \"## \" section to update")
+	}
+	content, ok := note.ReplaceSection(string(existing), heading, section)
+	if !ok {
+		return "", fmt.Errorf("%s has no %q section; run daily to regenerate it", path, heading)
+	}
+	return content, nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+)
+
+// runRerender rewrites daily notes from the local store alone, so template
+// and settings changes reach old notes without any API calls. Strava
+// activities and body measurements come from what earlier runs saved.
+func runRerender(args []string) {
+	fs := flag.NewFlagSet("rerender", flag.ExitOnError)
+	addGlobalFlags(fs)
+	fromStr := fs.String("from", "", "first date to rerender, YYYY-MM-DD (default: every stored day)")
+	toStr := fs.String("to", "", "last date to rerender, YYYY-MM-DD (default: yesterday; requires --from)")
+	_ = fs.Parse(args)
+
+	st, err := openStore()
+	if err != nil {
+		fatal(err)
+	}
+	stored, err := st.All()
+	if err != nil {
+		fatalf("read local store: %w", err)
+	}
+	if *fromStr != "" || *toStr != "" {
+		p, err := parseDateRange(*fromStr, *toStr)
+		if err != nil {
+			fatal(err)
+		}
+		kept := stored[:0]
+		for _, d := range stored {
+			if !d.Date.Before(p.Start) && d.Date.Before(p.End) {
+				kept = append(kept, d)
+			}
+		}
+		stored = kept
+	}
+	// Notes are never written for days without a cycle.
+	var days []fetch.DayData
+	for _, d := range stored {
+		if d.Cycle != nil {
+			days = append(days, d)
+		}
+	}
+	if len(days) == 0 {
+		infof("No stored days to rerender. Run fetch-all first.\n")
+		return
+	}
+
+	storeOnly = true
+	c := cacheClient()
+
+	infof("Rerendering %s (%s to %s)...\n", plural(len(days), "day"), days[0].Date.Format("2006-01-02"), days[len(days)-1].Date.Format("2006-01-02"))
+	b := startBackfill(nil, len(days))
+	for _, day := range days {
+		if _, err := writeDay(c, day); err != nil {
+			slog.Warn("could not rerender", "date", day.Date.Format("2006-01-02"), "err", err)
+			b.fail()
+			continue
+		}
+		b.wrote()
+	}
+	b.finish()
+}
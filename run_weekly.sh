@@ -8,4 +8,4 @@ source "$HOME/.zshrc" 2>/dev/null || true
 
 cd "$(dirname "$0")"
 ./whoop-garden weekly
-./whoop-garden persona
+./whoop-garden persona --write
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/analytics"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/render"
+	"github.com/benstraw/whoop-garden/internal/store"
+)
+
+// maxDashboardDays bounds the dashboard window, which is read from disk on
+// every request.
+const maxDashboardDays = 730
+
+// runServe hosts a read-only dashboard of recent days from the local store.
+// It makes no API calls; every command that fetches days, such as sync or
+// fetch-all, fills the store.
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addGlobalFlags(fs)
+	addr := fs.String("addr", "127.0.0.1:8765", "address to listen on")
+	days := fs.Int("days", 30, "number of recent days to show")
+	_ = fs.Parse(args)
+
+	st, err := openStore()
+	if err != nil {
+		fatal(err)
+	}
+
+	tmpl := template.Must(template.New("dashboard").Funcs(template.FuncMap{
+		"millisToMinutes": render.MillisToMinutes,
+		"recoveryColor":   render.RecoveryColor,
+		"noteURI":         obsidianURI,
+	}).Parse(dashboardTemplate))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		n := *days
+		if v := r.URL.Query().Get("days"); v != "" {
+			var err error
+			if n, err = strconv.Atoi(v); err != nil {
+				http.Error(w, "days must be a number", http.StatusBadRequest)
+				return
+			}
+		}
+		n = min(max(n, 1), maxDashboardDays)
+		data, err := dashboardData(st, n)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := tmpl.Execute(w, data); err != nil {
+			slog.Error("render error", "path", r.URL.Path, "err", err)
+		}
+	})
+
+	fmt.Printf("Serving dashboard on http://%s/ (Ctrl-C to stop)\n", *addr)
+	if err := http.ListenAndServe(*addr, localOnly(*addr, mux)); err != nil {
+		fatal(err)
+	}
+}
+
+// localOnly passes on requests whose Host header is addr or a loopback name,
+// and refuses the rest. A page whose domain was rebound to 127.0.0.1 still
+// sends its own domain as Host, so it cannot read what h serves.
+func localOnly(addr string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host != addr && !loopbackHost(r.Host) {
+			http.Error(w, "requests must be addressed to "+addr+" or localhost", http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// loopbackHost reports whether host, with or without a port, names the
+// loopback interface.
+func loopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// dashboardRow is one day in the dashboard table.
+type dashboardRow struct {
+	Day    fetch.DayData
+	Sleep  int64
+	Stored bool
+}
+
+// dashboard is passed to the dashboard template.
+type dashboard struct {
+	Stats       render.PersonaStats
+	Rows        []dashboardRow
+	Stored      int
+	Recovery    template.HTML
+	HRV         template.HTML
+	Strain      template.HTML
+	GeneratedAt string
+}
+
+// dashboardData loads the last n days, ending yesterday, from the store.
+func dashboardData(st *store.Store, n int) (dashboard, error) {
+	if n < 1 {
+		n = 1
+	}
+	p, err := resolveRange(n, "", "")
+	if err != nil {
+		return dashboard{}, err
+	}
+	var d dashboard
+	var all []fetch.DayData
+	var rec, hrv, strain []float64
+	for day := p.Start; day.Before(p.End); day = day.AddDate(0, 0, 1) {
+		dd, _, ok, err := st.Load(day)
+		if err != nil {
+			return dashboard{}, err
+		}
+		if !ok {
+			dd = fetch.DayData{Date: day}
+		} else {
+			d.Stored++
+		}
+		all = append(all, dd)
+
+		row := dashboardRow{Day: dd, Stored: ok}
+		if s := analytics.PrimarySleep(dd.Sleeps); s != nil {
+			row.Sleep = s.Score.StageSummary.TotalInBedTimeMilli
+		}
+		d.Rows = append(d.Rows, row)
+
+		if r := analytics.ScoredRecovery(dd); r != nil {
+			rec = append(rec, r.Score.RecoveryScore)
+			hrv = append(hrv, r.Score.HrvRmssdMilli)
+		}
+		if dd.Cycle != nil && dd.Cycle.ScoreState == "SCORED" {
+			strain = append(strain, dd.Cycle.Score.Strain)
+		}
+	}
+	d.Stats = render.BuildPersonaStats(all)
+	d.Recovery = sparkline(rec, "#16a34a")
+	d.HRV = sparkline(hrv, "#2563eb")
+	d.Strain = sparkline(strain, "#ea580c")
+	d.GeneratedAt = time.Now().Format("2006-01-02 15:04")
+
+	// Newest first in the table.
+	for i, j := 0, len(d.Rows)-1; i < j; i, j = i+1, j-1 {
+		d.Rows[i], d.Rows[j] = d.Rows[j], d.Rows[i]
+	}
+	return d, nil
+}
+
+// sparkline returns an inline SVG polyline of vals scaled to their range.
+func sparkline(vals []float64, color string) template.HTML {
+	const w, h = 240.0, 48.0
+	if len(vals) < 2 {
+		return ""
+	}
+	lo, hi := vals[0], vals[0]
+	for _, v := range vals {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	span := hi - lo
+	if span == 0 {
+		span = 1
+	}
+	pts := make([]string, len(vals))
+	for i, v := range vals {
+		x := float64(i) / float64(len(vals)-1) * w
+		y := h - (v-lo)/span*(h-4) - 2
+		pts[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+	return template.HTML(fmt.Sprintf(
+		`<svg width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f"><polyline fill="none" stroke="%s" stroke-width="2" points="%s"/></svg>`,
+		w, h, w, h, color, strings.Join(pts, " ")))
+}
+
+// obsidianURI returns an obsidian:// link that opens the daily note for t,
+// or "" when the vault name is unknown (see vaultName).
+func obsidianURI(t time.Time) template.URL {
+	vault := vaultName()
+	if vault == "" {
+		return ""
+	}
+	file := noteLinker().Path(render.NoteRel("daily", t.Format("2006-01-02")))
+	return template.URL("obsidian://open?vault=" + uriEscape(vault) + "&file=" + uriEscape(file))
+}
+
+const dashboardTemplate = `<!doctype html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>WHOOP dashboard</title>
+<style>
+body { font: 14px/1.4 system-ui, sans-serif; margin: 2rem auto; max-width: 960px; color: #1f2937; }
+h1 { font-size: 1.4rem; margin-bottom: .2rem; }
+.muted { color: #6b7280; }
+.cards { display: flex; gap: 1rem; flex-wrap: wrap; margin: 1.5rem 0; }
+.card { border: 1px solid #e5e7eb; border-radius: 8px; padding: .8rem 1rem; min-width: 150px; }
+.card .v { font-size: 1.4rem; font-weight: 600; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: .35rem .6rem; border-bottom: 1px solid #f3f4f6; }
+.green { color: #16a34a; } .yellow { color: #ca8a04; } .red { color: #dc2626; }
+tr.missing td { color: #9ca3af; }
+</style>
+</head>
+<body>
+<h1>WHOOP dashboard</h1>
+<div class="muted">{{.Stats.PeriodStart}} → {{.Stats.PeriodEnd}} · {{.Stored}} of {{len .Rows}} days in the local store · generated {{.GeneratedAt}}</div>
+
+<div class="cards">
+  <div class="card"><div class="muted">Recovery</div><div class="v">{{printf "%.0f" .Stats.AvgRecovery}}%</div>{{.Recovery}}</div>
+  <div class="card"><div class="muted">HRV</div><div class="v">{{printf "%.1f" .Stats.AvgHRV}} ms</div>{{.HRV}}<div class="muted">{{.Stats.HRVTrend}}</div></div>
+  <div class="card"><div class="muted">Strain</div><div class="v">{{printf "%.1f" .Stats.AvgStrain}}</div>{{.Strain}}</div>
+  <div class="card"><div class="muted">Resting HR</div><div class="v">{{printf "%.0f" .Stats.AvgRHR}} bpm</div></div>
+  <div class="card"><div class="muted">Sleep</div><div class="v">{{millisToMinutes .Stats.AvgSleepMillis}}</div></div>
+  <div class="card"><div class="muted">Recovery days</div><div class="v"><span class="green">{{.Stats.GreenDays}}</span> / <span class="yellow">{{.Stats.YellowDays}}</span> / <span class="red">{{.Stats.RedDays}}</span></div></div>
+</div>
+
+<table>
+<tr><th>Date</th><th>Recovery</th><th>HRV</th><th>RHR</th><th>Strain</th><th>Sleep</th><th>Workouts</th></tr>
+{{range .Rows}}{{$d := .Day}}
+<tr{{if not .Stored}} class="missing"{{end}}>
+  <td>{{with noteURI $d.Date}}<a href="{{.}}">{{$d.Date.Format "Mon 2006-01-02"}}</a>{{else}}{{$d.Date.Format "Mon 2006-01-02"}}{{end}}</td>
+  {{if and $d.Recovery (eq $d.Recovery.ScoreState "SCORED")}}
+  <td class="{{recoveryColor $d.Recovery.Score.RecoveryScore}}">{{printf "%.0f" $d.Recovery.Score.RecoveryScore}}%</td>
+  <td>{{printf "%.1f" $d.Recovery.Score.HrvRmssdMilli}} ms</td>
+  <td>{{printf "%.0f" $d.Recovery.Score.RestingHeartRate}}</td>
+  {{else}}<td>—</td><td>—</td><td>—</td>{{end}}
+  <td>{{if $d.Cycle}}{{printf "%.1f" $d.Cycle.Score.Strain}}{{else}}—{{end}}</td>
+  <td>{{if .Sleep}}{{millisToMinutes .Sleep}}{{else}}—{{end}}</td>
+  <td>{{len $d.Workouts}}</td>
+</tr>{{end}}
+</table>
+</body>
+</html>
+`
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLocalOnly(t *testing.T) {
+	h := localOnly("192.168.1.5:8765", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for host, want := range map[string]int{
+		"127.0.0.1:8765":        http.StatusOK,
+		"localhost:8765":        http.StatusOK,
+		"[::1]:8765":            http.StatusOK,
+		"192.168.1.5:8765":      http.StatusOK,
+		"attacker.example":      http.StatusForbidden,
+		"attacker.example:8765": http.StatusForbidden,
+		"192.168.1.6:8765":      http.StatusForbidden,
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Host = host
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != want {
+			t.Errorf("Host %s: status %d, want %d", host, w.Code, want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/benstraw/whoop-garden/internal/render"
+)
+
+// ANSI escape sequences used by the stats command.
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiDim    = "\033[2m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+)
+
+// runStats prints a terminal summary of the last N days without writing any
+// files. It aggregates like persona and compares the first and second half
+// of the window for trends.
+func runStats(args []string) {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	addGlobalFlags(fs)
+	days := fs.Int("days", 30, "number of days to summarise, ending yesterday")
+	noColor := fs.Bool("no-color", false, "disable ANSI colors")
+	_ = fs.Parse(args)
+
+	if *days < 2 {
+		fatal(errors.New("--days must be at least 2"))
+	}
+	p, err := resolveRange(*days, "", "")
+	if err != nil {
+		fatal(err)
+	}
+
+	c, err := getClient()
+	if err != nil {
+		fatal(err)
+	}
+	st, err := openStore()
+	if err != nil {
+		fatal(err)
+	}
+
+	slog.Info(fmt.Sprintf("Loading %d days...", p.Days()))
+	data := fetchRange(c, st, p)
+
+	color := !*noColor && os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout)
+	printStats(os.Stdout, render.BuildPersonaStats(data),
+		render.BuildPersonaStats(data[:len(data)/2]),
+		render.BuildPersonaStats(data[len(data)/2:]),
+		len(data), color)
+}
+
+// printStats writes the stats summary for all, with trends from the first
+// to the second half of the window.
+func printStats(w io.Writer, all, first, second render.PersonaStats, days int, color bool) {
+	paint := func(code, s string) string {
+		if !color {
+			return s
+		}
+		return code + s + ansiReset
+	}
+
+	fmt.Fprintf(w, "%s  %s → %s (%d days)\n\n", paint(ansiBold, "WHOOP stats"), all.PeriodStart, all.PeriodEnd, days)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	// Escape codes would skew tabwriter's column widths, so only the last
+	// cell of a row is ever painted.
+	fmt.Fprintln(tw, "Metric\tAverage\tTrend")
+	rows := []struct {
+		name, value, trend string
+	}{
+		{"Recovery", fmt.Sprintf("%.0f%%", all.AvgRecovery), render.Delta(first.AvgRecovery, second.AvgRecovery, "%.0f")},
+		{"HRV", fmt.Sprintf("%.1f ms", all.AvgHRV), render.Delta(first.AvgHRV, second.AvgHRV, "%.1f") + "  " + paint(ansiDim, all.HRVTrend)},
+		{"Resting HR", fmt.Sprintf("%.0f bpm", all.AvgRHR), render.Delta(first.AvgRHR, second.AvgRHR, "%.0f")},
+		{"Sleep (in bed)", render.MillisToMinutes(all.AvgSleepMillis), render.DeltaMillis(first.AvgSleepMillis, second.AvgSleepMillis)},
+		{"Sleep performance", fmt.Sprintf("%.0f%%", all.AvgSleepPerf), render.Delta(first.AvgSleepPerf, second.AvgSleepPerf, "%.0f")},
+		{"Day strain", fmt.Sprintf("%.1f", all.AvgStrain), render.Delta(first.AvgStrain, second.AvgStrain, "%.1f")},
+		{"Workouts", fmt.Sprintf("%d", all.TotalWorkouts), render.DeltaInt(first.TotalWorkouts, second.TotalWorkouts)},
+	}
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.name, r.value, r.trend)
+	}
+	tw.Flush()
+	fmt.Fprintln(w, paint(ansiDim, "Trend: second half of the window vs the first."))
+
+	fmt.Fprintf(w, "\n%s\n", paint(ansiBold, "Recovery distribution"))
+	dist := []struct {
+		label, code string
+		n           int
+	}{
+		{"Green ", ansiGreen, all.GreenDays},
+		{"Yellow", ansiYellow, all.YellowDays},
+		{"Red   ", ansiRed, all.RedDays},
+	}
+	for _, d := range dist {
+		fmt.Fprintf(w, "%s %3d  %s\n", d.label, d.n, paint(d.code, strings.Repeat("█", d.n)))
+	}
+}
+
+// isTerminal reports whether f is a character device such as a terminal.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	// /dev/null is a character device too; cron and launchd attach it.
+	null, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(fi, null)
+}
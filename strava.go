@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/render"
+	"github.com/benstraw/whoop-garden/internal/strava"
+)
+
+// stravaRetry is how long linkStrava leaves Strava alone after a failure.
+const stravaRetry = 15 * time.Minute
+
+// stravaAuth holds the Strava client between days; see stravaClient.
+var stravaAuth struct {
+	mu      sync.Mutex
+	api     *strava.Client // nil until authenticated, and after a failure
+	expires time.Time      // zero for a configured access token, which is not refreshed
+	retry   time.Time      // after a failure, when to try again
+}
+
+// linkStrava matches day's workouts to Strava activities so the daily
+// template can show their titles, links, and distances. It is a no-op
+// unless Strava is configured; after an error it warns and leaves notes
+// without Strava details until stravaRetry has passed.
+// Matches are saved, and offline or store-only they are read back instead.
+func linkStrava(day *render.Day) {
+	if len(day.Workouts) == 0 || opts.demo {
+		return
+	}
+	if offline || storeOnly {
+		day.Strava = loadStravaMatches(day.Date)
+		return
+	}
+	api := stravaClient()
+	if api == nil {
+		return
+	}
+
+	// Strava filters by start time, so look back far enough to catch an
+	// activity that started well before the workout WHOOP detected.
+	var first, last time.Time
+	for _, w := range day.Workouts {
+		start, err1 := fetch.ParseWhoopTime(w.Start)
+		end, err2 := fetch.ParseWhoopTime(w.End)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		if first.IsZero() || start.Before(first) {
+			first = start
+		}
+		if end.After(last) {
+			last = end
+		}
+	}
+	if first.IsZero() {
+		return
+	}
+	as, err := api.Activities(first.Add(-6*time.Hour), last)
+	if err != nil {
+		slog.Warn("could not fetch Strava activities; continuing without them", "date", day.Date.Format("2006-01-02"), "err", err)
+		stravaFailed()
+		return
+	}
+	day.Strava = strava.Match(day.Workouts, as)
+	slog.Debug("matched Strava activities", "date", day.Date.Format("2006-01-02"), "activities", len(as), "matched", len(day.Strava))
+	saveStravaMatches(day.Date, day.Strava)
+}
+
+// stravaMatchesPath is where the Strava activities matched to date's
+// workouts are kept, for notes rendered without API calls.
+func stravaMatchesPath(date time.Time) string {
+	return filepath.Join(cacheDir(), "strava", date.Format("2006-01-02")+".json")
+}
+
+func saveStravaMatches(date time.Time, matches map[string]*strava.Activity) {
+	data, err := json.Marshal(matches)
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(stravaMatchesPath(date)), 0700); err == nil {
+			err = os.WriteFile(stravaMatchesPath(date), data, 0600)
+		}
+	}
+	if err != nil {
+		slog.Warn("could not save Strava activities", "date", date.Format("2006-01-02"), "err", err)
+	}
+}
+
+// loadStravaMatches returns the Strava activities last matched to date's
+// workouts, or nil if there are none.
+func loadStravaMatches(date time.Time) map[string]*strava.Activity {
+	data, err := os.ReadFile(stravaMatchesPath(date))
+	if err != nil {
+		return nil
+	}
+	var matches map[string]*strava.Activity
+	if err := json.Unmarshal(data, &matches); err != nil {
+		slog.Warn("could not read saved Strava activities", "date", date.Format("2006-01-02"), "err", err)
+		return nil
+	}
+	return matches
+}
+
+// stravaClient returns a Strava client with a current access token, or nil
+// when Strava is not configured or failed less than stravaRetry ago. The
+// token is refreshed as it expires, so a long-running daemon keeps working.
+func stravaClient() *strava.Client {
+	a := &stravaAuth
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now()
+	if a.api != nil && (a.expires.IsZero() || now.Add(time.Minute).Before(a.expires)) {
+		return a.api
+	}
+	if now.Before(a.retry) {
+		return nil
+	}
+	tok, err := stravaToken()
+	if err != nil {
+		slog.Warn("could not authenticate with Strava", "err", err)
+		a.api, a.retry = nil, now.Add(stravaRetry)
+		return nil
+	}
+	if tok.AccessToken == "" {
+		return nil
+	}
+	a.api, a.expires = strava.NewClient(tok.AccessToken), time.Time{}
+	if tok.ExpiresAt > 0 {
+		a.expires = time.Unix(tok.ExpiresAt, 0)
+	}
+	return a.api
+}
+
+// stravaFailed drops the Strava client after an error, so stravaClient
+// tries again, with a fresh token, once stravaRetry has passed.
+func stravaFailed() {
+	stravaAuth.mu.Lock()
+	stravaAuth.api, stravaAuth.retry = nil, time.Now().Add(stravaRetry)
+	stravaAuth.mu.Unlock()
+}
+
+func stravaTokenPath() string { return filepath.Join(cacheDir(), "strava-token.json") }
+
+// stravaToken returns a current Strava token, whose access token is "" if
+// Strava is not configured. With a refresh token configured, the current
+// token pair is kept in the cache directory, since Strava rotates refresh
+// tokens; a configured access token alone has no expiry.
+func stravaToken() (strava.Token, error) {
+	s := cfg.Strava
+	if s.RefreshToken == "" {
+		return strava.Token{AccessToken: s.AccessToken}, nil
+	}
+	var tok strava.Token
+	data, err := os.ReadFile(stravaTokenPath())
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &tok); err != nil {
+			return strava.Token{}, err
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return strava.Token{}, err
+	}
+	if tok.AccessToken != "" && !tok.Expired() {
+		return tok, nil
+	}
+	refreshToken := tok.RefreshToken
+	if refreshToken == "" {
+		refreshToken = s.RefreshToken
+	}
+	tok, err = strava.Refresh(s.ClientID, s.ClientSecret, refreshToken)
+	if err != nil {
+		return strava.Token{}, err
+	}
+	data, err = json.MarshalIndent(tok, "", "  ")
+	if err != nil {
+		return strava.Token{}, err
+	}
+	if err := os.MkdirAll(cacheDir(), 0700); err != nil {
+		return strava.Token{}, err
+	}
+	if err := os.WriteFile(stravaTokenPath(), data, 0600); err != nil {
+		return strava.Token{}, err
+	}
+	return tok, nil
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/analytics"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+)
+
+// streaksAsOf returns the streaks running on date, computed over the local
+// store's history and the days recorded with seeDay this run.
+func streaksAsOf(date time.Time) *fetch.Streaks {
+	days := knownDays()
+	n := 0
+	for n < len(days) && !days[n].Date.After(date) {
+		n++
+	}
+	s := analytics.Streaks(days[:n])
+	return &s
+}
@@ -0,0 +1,445 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/client"
+	"github.com/benstraw/whoop-garden/internal/links"
+	"github.com/benstraw/whoop-garden/internal/render"
+	"github.com/benstraw/whoop-garden/internal/store"
+)
+
+// callsPerDay is the number of API requests GetDayData makes for a day with
+// a single page of each record type.
+const callsPerDay = 4
+
+// whoopRateLimit is WHOOP's per-minute request limit; backfills are paced
+// to stay under it.
+const whoopRateLimit = 100
+
+// syncRewriteDays is how many days before the last sync are rewritten on
+// each run, since WHOOP keeps scoring recent days for a while.
+const syncRewriteDays = 2
+
+// syncState is persisted between sync runs.
+type syncState struct {
+	// LastSynced is the last day whose note was written, with every day
+	// before it (back to the backfill start) also written.
+	LastSynced string `json:"last_synced"`
+	// Start is the first day of the initial backfill, kept so an
+	// interrupted backfill resumes without asking again.
+	Start    string `json:"start"`
+	Schedule string `json:"schedule,omitempty"`
+}
+
+func syncStatePath() string { return filepath.Join(cacheDir(), "sync.json") }
+
+func loadSyncState() (syncState, bool, error) {
+	var st syncState
+	data, err := os.ReadFile(syncStatePath())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return st, false, nil
+		}
+		return st, false, fmt.Errorf("read sync state: %w", err)
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return st, false, fmt.Errorf("parse sync state: %w", err)
+	}
+	return st, true, nil
+}
+
+func saveSyncState(st syncState) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(syncStatePath(), data, 0600)
+}
+
+// runSync writes daily notes from the last synced day through today, fills
+// missing or stale days back to the earliest note, and refreshes the weekly
+// notes of the weeks it wrote. On the first run (no sync state and an empty
+// store) it offers a guided backfill and a daily schedule instead of
+// guessing a window.
+func runSync(args []string) {
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	addGlobalFlags(fs)
+	backfill := fs.Int("backfill", 0, "on first run, backfill this many days without asking")
+	schedule := fs.String("schedule", "", "on first run, set up a daily sync at HH:MM without asking (\"off\" to skip)")
+	_ = fs.Parse(args)
+
+	state, found, err := loadSyncState()
+	if err != nil {
+		fatal(err)
+	}
+	st, err := openStore()
+	if err != nil {
+		fatal(err)
+	}
+
+	c, err := getClient()
+	if err != nil {
+		fatal(err)
+	}
+
+	today := time.Now()
+	var start time.Time
+	switch {
+	case found && state.LastSynced != "":
+		last, err := time.Parse("2006-01-02", state.LastSynced)
+		if err != nil {
+			fatalf("invalid last_synced %q in %s", state.LastSynced, syncStatePath())
+		}
+		start = last.AddDate(0, 0, -syncRewriteDays)
+	case found && state.Start != "":
+		if start, err = time.Parse("2006-01-02", state.Start); err != nil {
+			fatalf("invalid start %q in %s", state.Start, syncStatePath())
+		}
+	default:
+		empty, err := firstSync(st)
+		if err != nil {
+			fatal(err)
+		}
+		days := *backfill
+		if empty {
+			days = firstRunBackfill(*backfill)
+		} else if days == 0 {
+			// Stored days or daily notes exist, so this is not a fresh
+			// install; sync the default window quietly.
+			days = 30
+		}
+		start = today.AddDate(0, 0, -(days - 1))
+		state.Start = start.Format("2006-01-02")
+		if *schedule == "" && empty && isTerminal(os.Stdin) {
+			*schedule = askSchedule()
+		}
+		if *schedule != "" && *schedule != "off" {
+			if err := installSchedule(*schedule); err != nil {
+				fatalf("schedule: %w", err)
+			}
+			state.Schedule = *schedule
+		}
+	}
+
+	dir, err := ensureOutputDir()
+	if err != nil {
+		fatal(err)
+	}
+
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	gaps, err := syncGaps(dir, st, start)
+	if err != nil {
+		fatal(err)
+	}
+	if len(gaps) > 0 {
+		infof("Found %d missing or stale day(s) before %s.\n", len(gaps), start.Format("2006-01-02"))
+	}
+	dates := gaps
+	for d := start; !d.After(today); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d)
+	}
+
+	infof("Syncing %s → %s...\n", start.Format("2006-01-02"), today.Format("2006-01-02"))
+	tmplPath := templatePath("daily.md.tmpl")
+	b := startBackfill(c, len(dates))
+	contiguous := true
+	weeks := map[string]time.Time{}
+	for _, d := range dates {
+		day := d.Format("2006-01-02")
+		dayData, err := st.GetDayData(c, d)
+		if errors.Is(err, client.ErrBudgetExhausted) {
+			saveSyncStateOrWarn(state)
+			exitBudget(c, "whoop-garden sync")
+		}
+		written := false
+		if err == nil && dayData.Cycle != nil {
+			var content string
+			prepared := prepareDay(c, dayData)
+			if content, err = render.RenderDaily(prepared, tmplPath); err == nil {
+				if err = writeNote(notePath(dir, "daily", day), content); err == nil {
+					written = true
+					dayWritten(prepared)
+				}
+			}
+		} else if err == nil {
+			infof("Skipped: %s (no data)\n", day)
+		}
+		switch {
+		case err != nil:
+			slog.Warn("could not sync", "date", day, "err", err)
+			if !d.Before(start) {
+				contiguous = false
+			}
+			b.fail()
+		case written:
+			weeks[render.ISOWeekStr(d)] = d
+			b.wrote()
+		default:
+			b.skip()
+		}
+		// Only advance past days that succeeded, so the next run retries
+		// from the first failure. Gap days are found again by the scan.
+		if contiguous && !d.Before(start) {
+			state.LastSynced = day
+		}
+	}
+	b.finish()
+	saveSyncStateOrWarn(state)
+	if len(weeks) > 0 {
+		alertAfterWrite(c)
+	}
+
+	// Refresh the weekly notes of every week a daily note was written in.
+	keys := make([]string, 0, len(weeks))
+	for k := range weeks {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		if _, err := writeWeekly(c, weeks[k], "skip"); err != nil {
+			slog.Warn("could not update weekly note", "week", k, "err", err)
+		}
+	}
+}
+
+// firstSync reports whether whoop-garden has not been used here before: the
+// local store is empty and the output directory holds no daily notes, as
+// after an upgrade from a release without the store.
+func firstSync(st *store.Store) (bool, error) {
+	empty, err := st.Empty()
+	if err != nil || !empty || cfg.Storage.Remote() {
+		return empty, err
+	}
+	notes, err := links.Scan(outputDir())
+	if errors.Is(err, fs.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("scan notes: %w", err)
+	}
+	for _, n := range notes {
+		if n.Kind == "daily" {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// syncGaps returns the days from the earliest daily note in dir up to
+// start that have no note, or whose stored data was fetched before WHOOP
+// finished scoring it. Days already stored as having no data are not gaps.
+func syncGaps(dir string, st *store.Store, start time.Time) ([]time.Time, error) {
+	if cfg.Storage.Remote() {
+		return nil, nil
+	}
+	notes, err := links.Scan(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan notes: %w", err)
+	}
+	have := map[string]bool{}
+	first := ""
+	for _, n := range notes {
+		if n.Kind != "daily" {
+			continue
+		}
+		have[n.Key] = true
+		if first == "" || n.Key < first {
+			first = n.Key
+		}
+	}
+	from, err := time.Parse("2006-01-02", first)
+	if err != nil {
+		return nil, nil
+	}
+	var gaps []time.Time
+	for d := from; d.Before(start); d = d.AddDate(0, 0, 1) {
+		day, fetchedAt, ok, err := st.Load(d)
+		if err != nil {
+			return nil, err
+		}
+		settled := ok && store.Settled(day, fetchedAt)
+		switch {
+		case !have[d.Format("2006-01-02")]:
+			if !settled || day.Cycle != nil {
+				gaps = append(gaps, d)
+			}
+		case ok && !settled:
+			gaps = append(gaps, d)
+		}
+	}
+	return gaps, nil
+}
+
+func saveSyncStateOrWarn(st syncState) {
+	if opts.dryRun || opts.stdout {
+		return
+	}
+	err := os.MkdirAll(cacheDir(), 0700)
+	if err == nil {
+		err = saveSyncState(st)
+	}
+	if err != nil {
+		slog.Warn("could not save sync state", "err", err)
+	}
+}
+
+// firstRunBackfill explains the first sync and returns how many days to
+// backfill: n if set, otherwise the user's answer, or 30 when stdin is not
+// a terminal.
+func firstRunBackfill(n int) int {
+	if n > 0 {
+		return n
+	}
+	if !isTerminal(os.Stdin) {
+		slog.Info("First sync: backfilling 30 days. Use --backfill N to choose another window.")
+		return 30
+	}
+	fmt.Println("This looks like the first sync: nothing has been fetched yet.")
+	fmt.Println()
+	fmt.Println("  Days   API calls   Time")
+	for _, d := range []int{7, 30, 90, 365} {
+		calls, dur := estimateBackfill(d)
+		fmt.Printf("  %4d   %9d   %s\n", d, calls, formatEstimate(dur))
+	}
+	if cfg.MaxAPICalls > 0 || opts.maxCalls > 0 {
+		fmt.Println("\nAn API call budget is set; a long backfill stops at it and the next sync continues.")
+	}
+	fmt.Println()
+
+	p := newPrompter()
+	for {
+		answer := p.ask("How many days to backfill?", "30", true)
+		var days int
+		if _, err := fmt.Sscanf(answer, "%d", &days); err == nil && days > 0 {
+			calls, dur := estimateBackfill(days)
+			fmt.Printf("   About %d API calls, %s.\n", calls, formatEstimate(dur))
+			return days
+		}
+		fmt.Println("   Enter a positive number of days.")
+	}
+}
+
+// estimateBackfill returns the API calls and time needed to fetch days
+// days, paced to WHOOP's per-minute rate limit.
+func estimateBackfill(days int) (int, time.Duration) {
+	calls := days * callsPerDay
+	return calls, time.Duration(calls) * time.Minute / whoopRateLimit
+}
+
+func formatEstimate(d time.Duration) string {
+	if d < time.Minute {
+		return "under a minute"
+	}
+	return fmt.Sprintf("~%d min", int(d.Round(time.Minute).Minutes()))
+}
+
+// askSchedule offers to run sync every day and returns the chosen HH:MM,
+// or "off".
+func askSchedule() string {
+	p := newPrompter()
+	fmt.Println()
+	if !p.confirm("Run sync automatically every day?", true) {
+		return "off"
+	}
+	for {
+		at := p.ask("At what time (HH:MM)?", "07:00", true)
+		if _, err := time.Parse("15:04", at); err == nil {
+			return at
+		}
+		fmt.Println("   Use 24-hour HH:MM, e.g. 07:00.")
+	}
+}
+
+// installSchedule sets up a daily sync at HH:MM. On macOS it writes a
+// LaunchAgent; elsewhere it prints the crontab line to add.
+func installSchedule(at string) error {
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		return fmt.Errorf("invalid time %q (expected HH:MM)", at)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	logPath := filepath.Join(cacheDir(), "sync.log")
+
+	if runtime.GOOS != "darwin" {
+		fmt.Println("\nAdd this line with 'crontab -e' to sync daily:")
+		fmt.Printf("  %d %d * * * cd %s && %s sync >> %s 2>&1\n", t.Minute(), t.Hour(), shellQuote(wd), shellQuote(exe), shellQuote(logPath))
+		return nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	plist := filepath.Join(home, "Library", "LaunchAgents", "com.whoop-garden.sync.plist")
+	if err := os.MkdirAll(filepath.Dir(plist), 0755); err != nil {
+		return err
+	}
+	content := fmt.Sprintf(launchAgentTemplate, xmlEscape(exe), xmlEscape(wd), t.Hour(), t.Minute(), xmlEscape(logPath), xmlEscape(logPath))
+	if err := os.WriteFile(plist, []byte(content), 0644); err != nil {
+		return err
+	}
+	fmt.Println("\nWritten:", plist)
+	fmt.Printf("Load it with:\n  launchctl load %s\n", plist)
+	return nil
+}
+
+func shellQuote(s string) string {
+	if !strings.ContainsAny(s, " '\"$\\") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+const launchAgentTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+  <key>Label</key>
+  <string>com.whoop-garden.sync</string>
+  <key>ProgramArguments</key>
+  <array>
+    <string>%s</string>
+    <string>sync</string>
+  </array>
+  <key>WorkingDirectory</key>
+  <string>%s</string>
+  <key>StartCalendarInterval</key>
+  <dict>
+    <key>Hour</key>
+    <integer>%d</integer>
+    <key>Minute</key>
+    <integer>%d</integer>
+  </dict>
+  <key>StandardOutPath</key>
+  <string>%s</string>
+  <key>StandardErrorPath</key>
+  <string>%s</string>
+</dict>
+</plist>
+`
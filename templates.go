@@ -0,0 +1,281 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/benstraw/whoop-garden/internal/analytics"
+	"github.com/benstraw/whoop-garden/internal/diff"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/period"
+	"github.com/benstraw/whoop-garden/internal/render"
+)
+
+// runTemplates runs a templates subcommand.
+func runTemplates(args []string) {
+	if len(args) == 0 {
+		fatal(errors.New("usage: whoop-garden templates validate|preview|test"))
+	}
+	switch args[0] {
+	case "validate":
+		runTemplatesValidate(args[1:])
+	case "preview":
+		runTemplatesPreview(args[1:])
+	case "test":
+		runTemplatesTest(args[1:])
+	default:
+		fatalf("unknown templates command %q (want validate, preview, or test)", args[0])
+	}
+}
+
+// runTemplatesPreview renders the daily, weekly, or persona template
+// against sample data and prints the result.
+func runTemplatesPreview(args []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fatal(errors.New("usage: whoop-garden templates preview daily|weekly|persona [--sample NAME]"))
+	}
+	kind := args[0]
+	fs := flag.NewFlagSet("templates preview", flag.ExitOnError)
+	addGlobalFlags(fs)
+	sample := fs.String("sample", "scored", "sample day for the daily template: scored, unscored, workouts, or nap")
+	_ = fs.Parse(args[1:])
+
+	var content string
+	var err error
+	switch kind {
+	case "daily":
+		samples := render.Samples()
+		i := slices.IndexFunc(samples, func(s render.Sample) bool { return s.Name == *sample })
+		if i < 0 {
+			var names []string
+			for _, s := range samples {
+				names = append(names, s.Name)
+			}
+			fatalf("unknown sample %q (want %s)", *sample, strings.Join(names, ", "))
+		}
+		content, err = render.RenderDaily(samples[i].Day, templatePath("daily.md.tmpl"))
+	case "weekly":
+		content, err = render.RenderWeeklyFromStats(render.SampleWeekStats(), templatePath("weekly.md.tmpl"))
+	case "persona":
+		week, _ := render.SampleWeek()
+		days := render.SampleDays(week[len(week)-1].Date, 60)
+		prior, data := days[:30], days[30:]
+		pd := render.BuildPersonaComparison(data, prior)
+		if b, ok := analytics.HRVBaseline(days, 30); ok {
+			pd.HRVBaseline = &b
+		}
+		if l, ok := analytics.TrainingLoad(days); ok {
+			pd.TrainingLoad = &l
+		}
+		content, err = render.RenderPersonaFromStats(pd)
+	default:
+		fatalf("unknown template %q (want daily, weekly, or persona)", kind)
+	}
+	if err != nil {
+		fatalf("render error: %w", err)
+	}
+	fmt.Print(content)
+}
+
+// templateCheck renders one template file against one fixture.
+type templateCheck struct {
+	name    string // file name under the templates directory
+	fixture string
+	render  func(path string) (string, error)
+}
+
+// golden returns the name of the check's golden file: the template's name
+// without .tmpl, with the fixture added for templates rendered against
+// more than one: "daily-scored.md", "weekly.md".
+func (c templateCheck) golden() string {
+	base, ext, _ := strings.Cut(strings.TrimSuffix(c.name, ".tmpl"), ".")
+	if c.fixture != "week" {
+		base += "-" + c.fixture
+	}
+	return base + "." + ext
+}
+
+// runTemplatesTest renders every template against the sample fixtures and
+// compares each result with its golden file, or writes the golden files
+// under --update.
+func runTemplatesTest(args []string) {
+	fs := flag.NewFlagSet("templates test", flag.ExitOnError)
+	addGlobalFlags(fs)
+	dir := fs.String("golden", "", "directory of golden files (default: golden in the templates directory)")
+	update := fs.Bool("update", false, "write the current output as the golden files")
+	_ = fs.Parse(args)
+	if *dir == "" {
+		*dir = filepath.Join(templatesDir(), "golden")
+	}
+
+	// Pin what would otherwise change the output between runs and releases.
+	render.Version = "golden"
+	render.Now = func() time.Time { return render.Samples()[0].Day.Date }
+
+	if *update {
+		if err := os.MkdirAll(*dir, 0755); err != nil {
+			fatal(err)
+		}
+	}
+	passed, failed := 0, 0
+	for _, c := range templateChecks() {
+		path := templatePath(c.name)
+		golden := filepath.Join(*dir, c.golden())
+		got, err := c.render(path)
+		if err != nil {
+			failed++
+			fmt.Printf("FAIL %s\n  %s\n", c.golden(), templateError(path, err))
+			continue
+		}
+		if *update {
+			if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+				fatal(err)
+			}
+			passed++
+			continue
+		}
+		want, err := os.ReadFile(golden)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			failed++
+			fmt.Printf("FAIL %s\n  no golden file; run with --update to create it\n", c.golden())
+		case err != nil:
+			fatal(err)
+		case string(want) != got:
+			failed++
+			fmt.Printf("FAIL %s\n%s", c.golden(), diff.Unified(golden, path+" (rendered)", string(want), got))
+		default:
+			passed++
+		}
+	}
+	if *update {
+		fmt.Printf("Wrote %d golden file(s) to %s.\n", passed, *dir)
+		return
+	}
+	fmt.Printf("\n%d passed, %d failed.\n", passed, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// runTemplatesValidate renders every template the configuration uses
+// against the sample fixtures and reports each parse or execution error
+// with its file and line.
+func runTemplatesValidate(args []string) {
+	fs := flag.NewFlagSet("templates validate", flag.ExitOnError)
+	addGlobalFlags(fs)
+	_ = fs.Parse(args)
+
+	failed := 0
+	seen := map[string]bool{}
+	for _, c := range templateChecks() {
+		path := templatePath(c.name)
+		_, err := c.render(path)
+		if err == nil {
+			continue
+		}
+		msg := templateError(path, err)
+		// The same mistake usually fails every fixture; report it once.
+		if seen[msg] {
+			continue
+		}
+		seen[msg] = true
+		failed++
+		fmt.Printf("%s (%s fixture)\n", msg, c.fixture)
+	}
+	if failed > 0 {
+		fmt.Printf("\n%d template error(s).\n", failed)
+		os.Exit(1)
+	}
+	fmt.Println("All templates OK.")
+}
+
+// templateChecks lists each template with the fixtures it is rendered
+// against: every sample day for the daily and summary templates, and a
+// sample week for the rest.
+func templateChecks() []templateCheck {
+	var checks []templateCheck
+	for _, s := range render.Samples() {
+		day := s.Day
+		checks = append(checks,
+			templateCheck{"daily.md.tmpl", s.Name, func(p string) (string, error) {
+				return render.RenderDaily(day, p)
+			}},
+			templateCheck{"summary.txt.tmpl", s.Name, func(p string) (string, error) {
+				return render.RenderSummary(day, p)
+			}},
+		)
+	}
+
+	days, prev := render.SampleWeek()
+	week := render.SampleWeekStats()
+	start, last := days[0].Date, days[len(days)-1].Date
+	generated := last.Format("2006-01-02")
+	all := append(append([]fetch.DayData{}, prev...), days...)
+	checks = append(checks,
+		templateCheck{"weekly.md.tmpl", "week", func(p string) (string, error) {
+			return render.RenderWeeklyFromStats(week, p)
+		}},
+		templateCheck{"monthly.md.tmpl", "week", func(p string) (string, error) {
+			return render.RenderMonthly(render.MonthlyData{Month: last.Format("2006-01"), Start: start, Stats: week}, p)
+		}},
+		templateCheck{"quarterly.md.tmpl", "week", func(p string) (string, error) {
+			q := period.Quarter(start)
+			month := render.MonthStats{Month: start.Format("2006-01"), Start: start, Stats: week}
+			return render.RenderQuarterly(render.QuarterlyData{Quarter: q.Label, Prev: q.Label, Next: q.Label, Start: q.Start, Stats: week, Months: []render.MonthStats{month}}, p)
+		}},
+		templateCheck{"compare.md.tmpl", "week", func(p string) (string, error) {
+			a := render.CompareSide{Label: "A", Start: prev[0].Date.Format("2006-01-02"), End: prev[len(prev)-1].Date.Format("2006-01-02"), Stats: render.BuildWeekStats(prev)}
+			b := render.CompareSide{Label: "B", Start: start.Format("2006-01-02"), End: generated, Stats: render.BuildWeekStats(days)}
+			return render.RenderCompare(a, b, p)
+		}},
+		templateCheck{"correlate.md.tmpl", "week", func(p string) (string, error) {
+			rep := render.CorrelationReport{GeneratedDate: generated, Start: all[0].Date.Format("2006-01-02"), End: generated, Days: len(all), Correlations: analytics.Correlations(all)}
+			return render.RenderCorrelations(rep, p)
+		}},
+		templateCheck{"records.md.tmpl", "week", func(p string) (string, error) {
+			data := render.RecordsData{GeneratedDate: generated, Since: all[0].Date.Format("2006-01-02"), Through: generated, Records: analytics.ComputeRecords(all)}
+			return render.RenderRecords(data, p)
+		}},
+		templateCheck{"index.md.tmpl", "week", func(p string) (string, error) {
+			monday := start.AddDate(0, 0, -int((start.Weekday()+6)%7))
+			y, w := monday.ISOWeek()
+			data := render.YearIndexData{
+				GeneratedDate: generated, Year: last.Format("2006"), Home: "WHOOP", Prev: last.AddDate(-1, 0, 0).Format("2006"),
+				Months: []time.Time{time.Date(last.Year(), last.Month(), 1, 0, 0, 0, 0, time.UTC)},
+				Weeks:  []render.IndexWeek{{Key: fmt.Sprintf("%d-W%02d", y, w), Start: monday, Last: monday.AddDate(0, 0, 6)}},
+			}
+			return render.RenderYearIndex(data, p)
+		}},
+		templateCheck{"home.md.tmpl", "week", func(p string) (string, error) {
+			data := render.HomeData{GeneratedDate: generated, Persona: "Persona", Records: "Records", Years: []render.IndexYear{{Year: last.Format("2006"), Days: len(all), Weeks: 2}}}
+			return render.RenderHome(data, p)
+		}},
+	)
+	return checks
+}
+
+// templateErrorPos matches the position text/template puts in its errors:
+// "template: daily.md.tmpl:12:5: ...", where the column is only given for
+// execution errors.
+var templateErrorPos = regexp.MustCompile(`template: [^:]+:(\d+)(?::(\d+))?: (.*)$`)
+
+// templateError formats err as "path:line:col: message" when it carries a
+// template position, and "path: error" otherwise.
+func templateError(path string, err error) string {
+	m := templateErrorPos.FindStringSubmatch(err.Error())
+	if m == nil {
+		return fmt.Sprintf("%s: %v", path, err)
+	}
+	if m[2] != "" {
+		return fmt.Sprintf("%s:%s:%s: %s", path, m[1], m[2], m[3])
+	}
+	return fmt.Sprintf("%s:%s: %s", path, m[1], m[3])
+}
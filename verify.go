@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/benstraw/whoop-garden/internal/client"
+	"github.com/benstraw/whoop-garden/internal/diff"
+	"github.com/benstraw/whoop-garden/internal/fetch"
+	"github.com/benstraw/whoop-garden/internal/render"
+)
+
+// runVerify re-renders existing daily notes from fresh API data and reports
+// those that no longer match, rewriting them only with --fix.
+func runVerify(args []string) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	addGlobalFlags(fs)
+	days := fs.Int("days", 30, "number of days to verify, ending yesterday")
+	from := fs.String("from", "", "first day to verify (YYYY-MM-DD)")
+	to := fs.String("to", "", "last day to verify (YYYY-MM-DD, default: yesterday)")
+	cached := fs.Bool("cached", false, "compare with the local store instead of fetching from the API")
+	fix := fs.Bool("fix", false, "rewrite stale notes and update the local store")
+	_ = fs.Parse(args)
+
+	p, err := resolveRange(*days, *from, *to)
+	if err != nil {
+		fatal(err)
+	}
+	c, err := getClient()
+	if err != nil {
+		fatal(err)
+	}
+	st, err := openStore()
+	if err != nil {
+		fatal(err)
+	}
+	dir := outputDir()
+	tmplPath := templatePath("daily.md.tmpl")
+
+	checked, stale, fixed := 0, 0, 0
+	for d := p.Start; d.Before(p.End); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		path := notePath(dir, "daily", date)
+		if ok, err := noteExists(path); err != nil || !ok {
+			continue
+		}
+		existing, err := readNote(path)
+		if err != nil {
+			slog.Warn("could not read note", "path", path, "err", err)
+			continue
+		}
+
+		old, _, stored, err := st.Load(d)
+		if err != nil {
+			slog.Warn("could not read stored day", "date", date, "err", err)
+		}
+		fresh := old
+		if !*cached {
+			fresh, err = fetch.GetDayData(c.NoCache(), d)
+			if errors.Is(err, client.ErrBudgetExhausted) {
+				exitBudget(c, fmt.Sprintf("whoop-garden verify --from %s --to %s", date, p.Last().Format("2006-01-02")))
+			}
+			if err != nil {
+				slog.Warn("could not fetch", "date", date, "err", err)
+				continue
+			}
+		} else if !stored {
+			continue
+		}
+		checked++
+
+		day := prepareDay(c, fresh)
+		content, err := render.RenderDaily(day, tmplPath)
+		if err != nil {
+			slog.Warn("could not render", "date", date, "err", err)
+			continue
+		}
+		want, current := noteCurrent(path, existing, content)
+		if current {
+			continue
+		}
+		stale++
+
+		reason := "differs from a fresh render (template or settings changed?)"
+		if parts := revisionParts(old, fresh); stored && len(parts) > 0 {
+			reason = strings.Join(parts, ", ")
+		} else if !stored {
+			reason = "not in the local store; " + reason
+		}
+		fmt.Printf("%s: %s\n", date, reason)
+		if opts.diff {
+			fmt.Print(diff.Unified(path, path, string(existing), want))
+		}
+		if !*fix || opts.dryRun {
+			continue
+		}
+		if !*cached {
+			if err := st.Save(fresh); err != nil {
+				slog.Warn("could not store day", "date", date, "err", err)
+			}
+		}
+		if err := writeNote(path, content); err != nil {
+			slog.Warn("could not write", "path", path, "err", err)
+			continue
+		}
+		dayWritten(day)
+		fixed++
+	}
+
+	switch {
+	case stale == 0:
+		fmt.Printf("All %d notes match.\n", checked)
+	case *fix && !opts.dryRun:
+		fmt.Printf("\nRewrote %d of %d stale note(s) out of %d checked.\n", fixed, stale, checked)
+		if fixed < stale {
+			os.Exit(1)
+		}
+	default:
+		fmt.Printf("\n%d of %d notes are stale. Run with --fix to rewrite them.\n", stale, checked)
+		os.Exit(1)
+	}
+}
+
+// noteCurrent returns content as writeNote would write it to path, and
+// whether existing already matches it.
+func noteCurrent(path string, existing []byte, content string) (string, bool) {
+	want := addFrontmatter(path, content)
+	return want, sameNote(string(existing), want)
+}
+
+// sameNote reports whether two renderings of a note match, ignoring the
+// generator line, which changes with every release.
+func sameNote(a, b string) bool {
+	strip := func(s string) string {
+		lines := strings.Split(s, "\n")
+		out := lines[:0]
+		for _, l := range lines {
+			if !strings.HasPrefix(l, "generator: ") {
+				out = append(out, l)
+			}
+		}
+		return strings.Join(out, "\n")
+	}
+	return strip(a) == strip(b)
+}
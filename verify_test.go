@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestNoteCurrent_Frontmatter(t *testing.T) {
+	useMemoryNotes(t)
+	cfg.Frontmatter.Fields = map[string]any{"source": "whoop"}
+	cfg.Frontmatter.Tags = []string{"daily/health"}
+	path := notePath(outputDir(), "daily", "2026-02-10")
+
+	if err := writeNote(path, generated); err != nil {
+		t.Fatal(err)
+	}
+	existing, err := readNote(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := noteCurrent(path, existing, generated); !ok {
+		t.Error("a note just written from the same render is reported stale")
+	}
+	if _, ok := noteCurrent(path, existing, generated+"\nchanged\n"); ok {
+		t.Error("a different render is reported current")
+	}
+}
+
+func TestSameNote_IgnoresGenerator(t *testing.T) {
+	a := "---\ngenerator: whoop-garden v1.0.0\n---\nbody\n"
+	b := "---\ngenerator: whoop-garden v1.1.0\n---\nbody\n"
+	if !sameNote(a, b) {
+		t.Error("notes differing only in generator should match")
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/benstraw/whoop-garden/internal/client"
+)
+
+// commit and buildDate are set at build time alongside version:
+//
+//	go build -ldflags "-X main.version=v1.2.0 -X main.commit=$(git rev-parse HEAD) \
+//	  -X main.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// When they are empty, the VCS stamp Go embeds in builds from a git checkout
+// is used instead.
+var (
+	commit    = ""
+	buildDate = ""
+)
+
+// buildInfo fills commit and buildDate from the embedded VCS stamp when
+// ldflags did not set them.
+func buildInfo() {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+	if commit != "" {
+		return
+	}
+	dirty := false
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			commit = s.Value
+		case "vcs.time":
+			if buildDate == "" {
+				buildDate = s.Value
+			}
+		case "vcs.modified":
+			dirty = s.Value == "true"
+		}
+	}
+	if dirty && commit != "" {
+		commit += "-dirty"
+	}
+}
+
+// noteVersion is the version recorded in note frontmatter: the release
+// tag, or dev plus the short commit for untagged builds.
+func noteVersion() string {
+	if version == "dev" && commit != "" {
+		return "dev-" + shortCommit()
+	}
+	return version
+}
+
+func shortCommit() string {
+	c, dirty := strings.CutSuffix(commit, "-dirty")
+	if len(c) > 7 {
+		c = c[:7]
+	}
+	if dirty {
+		c += "-dirty"
+	}
+	return c
+}
+
+func printVersion() {
+	orUnknown := func(s string) string {
+		if s == "" {
+			return "unknown"
+		}
+		return s
+	}
+	fmt.Println("whoop-garden", version)
+	fmt.Printf("  commit:     %s\n", orUnknown(commit))
+	fmt.Printf("  built:      %s\n", orUnknown(buildDate))
+	fmt.Printf("  WHOOP API:  %s\n", client.APIVersion)
+	fmt.Printf("  go:         %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}